@@ -0,0 +1,206 @@
+package orm
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func isScalarArray(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() != reflect.Array || t.Len() == 0 {
+		return false
+	}
+	hasColumns := false
+	for _, attribute := range strings.Split(field.Tag.Get("orm"), ";") {
+		if attribute == "columns" {
+			hasColumns = true
+			break
+		}
+	}
+	if !hasColumns {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.Uint8:
+		// byte arrays (hashes, uuids) are still stored as one json column
+		return false
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Int, reflect.Int8, reflect.Int16,
+		reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+func getArrayColumnName(name string, k int) string {
+	return name + "_" + strconv.Itoa(k+1)
+}
+
+func getArrayElementKind(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint64
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+	return kind
+}
+
+func getArrayColumnsCount(fields *tableFields) int {
+	count := 0
+	for _, i := range fields.arrays {
+		count += fields.fields[i].Type.Len()
+	}
+	return count
+}
+
+func prepareScanForArrays(fields *tableFields, start int, pointers []interface{}) int {
+	for _, i := range fields.arrays {
+		t := fields.fields[i].Type
+		kind := getArrayElementKind(t.Elem().Kind())
+		for k := 0; k < t.Len(); k++ {
+			switch kind {
+			case reflect.Uint64:
+				v := uint64(0)
+				pointers[start] = &v
+			case reflect.Int64:
+				v := int64(0)
+				pointers[start] = &v
+			case reflect.Float64:
+				v := float64(0)
+				pointers[start] = &v
+			case reflect.Bool:
+				v := false
+				pointers[start] = &v
+			default:
+				v := sql.NullString{}
+				pointers[start] = &v
+			}
+			start++
+		}
+	}
+	return start
+}
+
+func convertScanForArrays(fields *tableFields, start int, pointers []interface{}) int {
+	for _, i := range fields.arrays {
+		for k := 0; k < fields.fields[i].Type.Len(); k++ {
+			switch v := pointers[start].(type) {
+			case *uint64:
+				pointers[start] = *v
+			case *int64:
+				pointers[start] = *v
+			case *float64:
+				pointers[start] = *v
+			case *bool:
+				pointers[start] = *v
+			case *sql.NullString:
+				if v.Valid {
+					pointers[start] = v.String
+				} else {
+					pointers[start] = nil
+				}
+			}
+			start++
+		}
+	}
+	return start
+}
+
+func convertArraysFromJSON(fields *tableFields, start int, encoded []interface{}) int {
+	for _, i := range fields.arrays {
+		t := fields.fields[i].Type
+		kind := getArrayElementKind(t.Elem().Kind())
+		for k := 0; k < t.Len(); k++ {
+			v := encoded[start]
+			if v != nil {
+				switch kind {
+				case reflect.Uint64:
+					encoded[start] = uint64(v.(float64))
+				case reflect.Int64:
+					encoded[start] = int64(v.(float64))
+				}
+			}
+			start++
+		}
+	}
+	return start
+}
+
+func fillArrays(data []interface{}, index uint16, fields *tableFields, value reflect.Value) uint16 {
+	for _, i := range fields.arrays {
+		field := value.Field(i)
+		for k := 0; k < field.Len(); k++ {
+			element := field.Index(k)
+			switch getArrayElementKind(element.Kind()) {
+			case reflect.Uint64:
+				element.SetUint(data[index].(uint64))
+			case reflect.Int64:
+				element.SetInt(data[index].(int64))
+			case reflect.Float64:
+				element.SetFloat(data[index].(float64))
+			case reflect.Bool:
+				element.SetBool(data[index].(bool))
+			default:
+				if data[index] == nil {
+					element.SetString("")
+				} else {
+					element.SetString(data[index].(string))
+				}
+			}
+			index++
+		}
+	}
+	return index
+}
+
+func (orm *ORM) fillArraysBind(bind Bind, updateBind map[string]string, tableSchema *tableSchema,
+	fields *tableFields, value reflect.Value, oldData []interface{}, prefix string) {
+	hasOld := orm.inDB
+	hasUpdate := updateBind != nil
+	for _, i := range fields.arrays {
+		field := value.Field(i)
+		for k := 0; k < field.Len(); k++ {
+			name := getArrayColumnName(prefix+fields.fields[i].Name, k)
+			var old interface{}
+			if hasOld {
+				old = oldData[tableSchema.columnMapping[name]]
+			}
+			var val interface{}
+			sqlValue := "NULL"
+			element := field.Index(k)
+			switch getArrayElementKind(element.Kind()) {
+			case reflect.Uint64:
+				val = element.Uint()
+				sqlValue = strconv.FormatUint(element.Uint(), 10)
+			case reflect.Int64:
+				val = element.Int()
+				sqlValue = strconv.FormatInt(element.Int(), 10)
+			case reflect.Float64:
+				val = element.Float()
+				sqlValue = strconv.FormatFloat(element.Float(), 'f', -1, 64)
+			case reflect.Bool:
+				val = element.Bool()
+				sqlValue = "0"
+				if element.Bool() {
+					sqlValue = "1"
+				}
+			default:
+				if element.String() != "" || tableSchema.tags[name]["required"] == "true" {
+					val = element.String()
+					sqlValue = orm.escapeSQLParam(element.String())
+				}
+			}
+			if hasOld && old == val {
+				continue
+			}
+			bind[name] = val
+			if hasUpdate {
+				updateBind[name] = sqlValue
+			}
+		}
+	}
+}
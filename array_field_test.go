@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type arrayFieldEntity struct {
+	ORM     `orm:"localCache;redisCache"`
+	ID      uint
+	Name    string
+	Prices  [3]float64 `orm:"columns"`
+	Counts  [2]uint32  `orm:"columns"`
+	Deltas  [2]int     `orm:"columns"`
+	Labels  [2]string  `orm:"columns;length=20"`
+	Flags   [2]bool    `orm:"columns"`
+	Scores  [2]int
+	Payload [4]byte
+}
+
+func TestArrayFields(t *testing.T) {
+	var entity *arrayFieldEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	assert.Equal(t, []string{"ID", "Name", "Scores", "Payload", "Prices_1", "Prices_2", "Prices_3", "Counts_1", "Counts_2", "Deltas_1",
+		"Deltas_2", "Labels_1", "Labels_2", "Flags_1", "Flags_2"}, schema.GetColumns())
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+	var createTable string
+	engine.GetMysql().QueryRow(NewWhere("SHOW CREATE TABLE `arrayFieldEntity`"), &createTable, &createTable)
+	assert.Contains(t, createTable, "`Prices_3` double NOT NULL")
+	assert.Contains(t, createTable, "`Counts_1` int(10) unsigned NOT NULL")
+	assert.Contains(t, createTable, "`Labels_2` varchar(20)")
+	assert.Contains(t, createTable, "`Scores` json")
+
+	entity = &arrayFieldEntity{Name: "a", Prices: [3]float64{1.5, 2, 3.25}, Counts: [2]uint32{1, 2}, Deltas: [2]int{-1, 1},
+		Labels: [2]string{"x", ""}, Flags: [2]bool{true, false}, Scores: [2]int{7, 8}, Payload: [4]byte{1, 2, 3, 4}}
+	engine.Flush(entity)
+	var price float64
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Prices_3` FROM `arrayFieldEntity` WHERE `ID` = 1"), &price)
+	assert.Equal(t, 3.25, price)
+
+	entity = &arrayFieldEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, [3]float64{1.5, 2, 3.25}, entity.Prices)
+	assert.Equal(t, [2]uint32{1, 2}, entity.Counts)
+	assert.Equal(t, [2]int{-1, 1}, entity.Deltas)
+	assert.Equal(t, [2]string{"x", ""}, entity.Labels)
+	assert.Equal(t, [2]bool{true, false}, entity.Flags)
+	assert.Equal(t, [2]int{7, 8}, entity.Scores)
+	assert.Equal(t, [4]byte{1, 2, 3, 4}, entity.Payload)
+	assert.False(t, entity.IsDirty())
+
+	entity.Prices[1] = 4
+	entity.Labels[1] = "y"
+	bind, _ := entity.GetDirtyBind()
+	assert.Equal(t, Bind{"Prices_2": float64(4), "Labels_2": "y"}, bind)
+	engine.Flush(entity)
+
+	engine.GetLocalCache().Clear()
+	entity = &arrayFieldEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, [3]float64{1.5, 4, 3.25}, entity.Prices)
+	assert.Equal(t, [2]uint32{1, 2}, entity.Counts)
+	assert.Equal(t, [2]int{-1, 1}, entity.Deltas)
+	assert.Equal(t, [2]string{"x", "y"}, entity.Labels)
+	assert.False(t, entity.IsDirty())
+
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	var rows []*arrayFieldEntity
+	engine.Search(NewWhere("`Counts_2` = ?", 2), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, [2]int{-1, 1}, rows[0].Deltas)
+
+	assert.NoError(t, entity.SetField("Flags", [2]bool{false, true}))
+	bind, _ = entity.GetDirtyBind()
+	assert.Equal(t, Bind{"Flags_1": false, "Flags_2": true}, bind)
+}
@@ -35,9 +35,10 @@ type dirtyQueueValue struct {
 
 type BackgroundConsumer struct {
 	eventConsumerBase
-	engine       *Engine
-	logLogger    func(log *LogQueueValue)
-	redisFlusher RedisFlusher
+	engine          *Engine
+	logLogger       func(log *LogQueueValue)
+	redisFlusher    RedisFlusher
+	lazyInsertBatch int
 }
 
 func NewBackgroundConsumer(engine *Engine) *BackgroundConsumer {
@@ -56,6 +57,10 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) {
 	consumer := r.engine.GetEventBroker().Consumer("default-consumer", asyncConsumerGroupName).(*eventsConsumer)
 	consumer.eventConsumerBase = r.eventConsumerBase
 	consumer.Consume(ctx, 100, true, func(events []Event) {
+		if r.lazyInsertBatch > 0 {
+			r.handleEventsInBatches(events)
+			return
+		}
 		for _, event := range events {
 			switch event.Stream() {
 			case lazyChannelName:
@@ -64,6 +69,8 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) {
 				r.handleLogEvent(event)
 			case redisSearchIndexerChannelName:
 				r.handleRedisIndexerEvent(event)
+			case redisSearchReferenceChannelName:
+				r.handleRedisSearchReferenceEvent(event)
 			}
 		}
 	})
@@ -76,12 +83,12 @@ func (r *BackgroundConsumer) handleLogEvent(event Event) {
 		event.Ack()
 		return
 	}
-	r.handleLog(&value)
+	r.handleLog(r.engine, &value)
 	event.Ack()
 }
 
-func (r *BackgroundConsumer) handleLog(value *LogQueueValue) {
-	poolDB := r.engine.GetMysql(value.PoolName)
+func (r *BackgroundConsumer) handleLog(engine *Engine, value *LogQueueValue) {
+	poolDB := engine.GetMysql(value.PoolName)
 	/* #nosec */
 	query := "INSERT INTO `" + value.TableName + "`(`entity_id`, `added_at`, `meta`, `before`, `changes`) VALUES(?, ?, ?, ?, ?)"
 	var meta, before, changes interface{}
@@ -115,8 +122,20 @@ func (r *BackgroundConsumer) handleLazy(event Event) {
 		event.Ack()
 		return
 	}
-	ids := r.handleQueries(r.engine, data)
+	data, blobKey, valid := r.loadLazyBlob(data)
+	if !valid {
+		event.Ack()
+		return
+	}
+	ids := r.handleQueries(r.engine.withEventTenant(data["t"]), data)
+	r.finishLazy(event, data, blobKey, ids)
+}
+
+func (r *BackgroundConsumer) finishLazy(event Event, data map[string]interface{}, blobKey string, ids []uint64) {
 	r.handleCache(data, ids)
+	if blobKey != "" {
+		getRedisForStream(r.engine, lazyChannelName).Del(blobKey)
+	}
 	event.Ack()
 }
 
@@ -140,26 +159,57 @@ func (r *BackgroundConsumer) handleQueries(engine *Engine, validMap map[string]i
 			res = db.Exec(sql, attributes.([]interface{})...)
 		}
 		if sql[0:11] == "INSERT INTO" {
-			id := res.LastInsertId()
 			ids[i] = res.LastInsertId()
-			logEvents, has := validMap["l"]
-			if has {
-				for _, row := range logEvents.([]interface{}) {
-					row.(map[string]interface{})["ID"] = id
-					id += db.GetPoolConfig().getAutoincrement()
-				}
-			}
-			dirtyEvents, has := validMap["d"]
-			if has {
-				for _, row := range dirtyEvents.([]interface{}) {
-					row.(map[string]interface{})["Event"].(map[string]interface{})["I"] = id
-					id += db.GetPoolConfig().getAutoincrement()
-				}
-			}
+			r.assignInsertID(db, validMap, ids[i])
 		} else {
 			ids[i] = 0
 		}
 	}
+	r.handleQueryEvents(engine, validMap)
+	r.handleSearchSuggestions(engine, validMap, ids)
+	return ids
+}
+
+func (r *BackgroundConsumer) handleSearchSuggestions(engine *Engine, validMap map[string]interface{}, ids []uint64) {
+	suggestions, has := validMap["s"]
+	if !has {
+		return
+	}
+	queries := validMap["q"].([]interface{})
+	flusher := &redisFlusher{engine: engine}
+	for _, row := range suggestions.([]interface{}) {
+		asMap := row.(map[string]interface{})
+		query, _ := strconv.Atoi(fmt.Sprintf("%v", asMap["Q"]))
+		position, _ := strconv.ParseUint(fmt.Sprintf("%v", asMap["N"]), 10, 64)
+		if query >= len(ids) || ids[query] == 0 {
+			continue
+		}
+		code := queries[query].([]interface{})[0].(string)
+		id := ids[query] + position*engine.GetMysql(code).GetPoolConfig().getAutoincrement()
+		flusher.suggest(asMap["P"].(string), redisSuggestCommand{key: asMap["K"].(string), value: asMap["V"].(string),
+			payload: strconv.FormatUint(id, 10)})
+	}
+	flusher.Flush()
+}
+
+func (r *BackgroundConsumer) assignInsertID(db *DB, validMap map[string]interface{}, id uint64) {
+	logEvents, has := validMap["l"]
+	if has {
+		for _, row := range logEvents.([]interface{}) {
+			row.(map[string]interface{})["ID"] = id
+			id += db.GetPoolConfig().getAutoincrement()
+		}
+	}
+	dirtyEvents, has := validMap["d"]
+	if has {
+		for _, row := range dirtyEvents.([]interface{}) {
+			row.(map[string]interface{})["Event"].(map[string]interface{})["I"] = id
+			id += db.GetPoolConfig().getAutoincrement()
+		}
+	}
+}
+
+func (r *BackgroundConsumer) handleQueryEvents(engine *Engine, validMap map[string]interface{}) {
 	logEvents, has := validMap["l"]
 	if has {
 		for _, row := range logEvents.([]interface{}) {
@@ -168,7 +218,7 @@ func (r *BackgroundConsumer) handleQueries(engine *Engine, validMap map[string]i
 			logEvent.ID, _ = strconv.ParseUint(fmt.Sprintf("%v", asMap["ID"]), 10, 64)
 			logEvent.PoolName = asMap["PoolName"].(string)
 			logEvent.TableName = asMap["TableName"].(string)
-			logEvent.Updated = time.Now()
+			logEvent.Updated = engine.GetClock().Now()
 			if asMap["Meta"] != nil {
 				logEvent.Meta = asMap["Meta"].(map[string]interface{})
 			}
@@ -178,7 +228,7 @@ func (r *BackgroundConsumer) handleQueries(engine *Engine, validMap map[string]i
 			if asMap["Changes"] != nil {
 				logEvent.Changes = asMap["Changes"].(map[string]interface{})
 			}
-			r.handleLog(logEvent)
+			r.handleLog(engine, logEvent)
 		}
 	}
 	dirtyEvents, has := validMap["d"]
@@ -192,7 +242,6 @@ func (r *BackgroundConsumer) handleQueries(engine *Engine, validMap map[string]i
 		}
 		r.redisFlusher.Flush()
 	}
-	return ids
 }
 
 func (r *BackgroundConsumer) handleCache(validMap map[string]interface{}, ids []uint64) {
@@ -0,0 +1,160 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func (e *Engine) DeleteByQuery(where *Where, entity Entity, chunkSize int) int {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	db := schema.GetMysql(e)
+	total := 0
+	batchQueryIDs(e, where, schema, chunkSize, func(ids []uint64) {
+		rows := batchQueryLoad(e, schema, ids)
+		if len(rows) == 0 {
+			return
+		}
+		deleteBinds := make(map[uint64]Entity, len(rows))
+		rowIDs := make([]uint64, len(rows))
+		for i, row := range rows {
+			rowIDs[i] = row.GetID()
+			deleteBinds[rowIDs[i]] = row
+		}
+		checkRowPolicy(e, schema, rowIDs...)
+		idsWhere := NewWhere("`ID` IN ?", rowIDs)
+		f := &flusher{engine: e}
+		if schema.hasFakeDelete {
+			/* #nosec */
+			db.Exec("UPDATE `"+schema.tableName+"` SET `FakeDelete` = `ID` WHERE "+idsWhere.String(), idsWhere.GetParameters()...)
+			for id, row := range deleteBinds {
+				f.updateCacheAfterUpdate(row.getORM().dBData, row, Bind{"FakeDelete": id}, schema, id, false)
+			}
+		} else {
+			(&flusher{engine: e}).deleteCascade(schema, idsWhere.GetParameters(), db.inTransaction, false)
+			/* #nosec */
+			db.Exec("DELETE FROM `"+schema.tableName+"` WHERE "+idsWhere.String(), idsWhere.GetParameters()...)
+			f.updateCacheAfterDelete(schema, deleteBinds, false)
+		}
+		f.flush(true, false, db.inTransaction)
+		total += len(rows)
+	})
+	return total
+}
+
+func (e *Engine) UpdateByQuery(where *Where, entity Entity, bind Bind, chunkSize int) int {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	if len(bind) == 0 {
+		return 0
+	}
+	columns := make([]string, 0, len(bind))
+	for column := range bind {
+		if column == "ID" {
+			panic(fmt.Errorf("column `ID` can't be updated in entity '%s'", schema.t.String()))
+		}
+		_, has := schema.columnMapping[column]
+		if !has {
+			panic(fmt.Errorf("unknown column '%s' in entity '%s'", column, schema.t.String()))
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	converted := batchQueryConvertBind(e, schema, bind)
+	fields := make([]string, 0, len(columns))
+	values := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		fields = append(fields, "`"+column+"` = "+schema.getBindPlaceholder(column))
+		values = append(values, converted[column])
+		if old, has := schema.renamedColumns[column]; has {
+			fields = append(fields, "`"+old+"` = "+schema.getBindPlaceholder(column))
+			values = append(values, converted[column])
+		}
+	}
+	db := schema.GetMysql(e)
+	total := 0
+	batchQueryIDs(e, where, schema, chunkSize, func(ids []uint64) {
+		rows := batchQueryLoad(e, schema, ids)
+		if len(rows) == 0 {
+			return
+		}
+		rowIDs := make([]uint64, len(rows))
+		for i, row := range rows {
+			rowIDs[i] = row.GetID()
+		}
+		idsWhere := NewWhere("`ID` IN ?", rowIDs)
+		/* #nosec */
+		sql := "UPDATE `" + schema.tableName + "` SET " + strings.Join(fields, ",") + " WHERE " + idsWhere.String()
+		args := make([]interface{}, 0, len(values)+len(rowIDs))
+		args = append(args, values...)
+		db.Exec(sql, append(args, idsWhere.GetParameters()...)...)
+		f := &flusher{engine: e}
+		for _, row := range rows {
+			rowBind := make(Bind, len(columns))
+			for _, column := range columns {
+				rowBind[column] = converted[column]
+			}
+			f.updateCacheAfterUpdate(row.getORM().dBData, row, rowBind, schema, row.GetID(), false)
+		}
+		f.flush(true, false, db.inTransaction)
+		total += len(rows)
+	})
+	return total
+}
+
+func batchQueryConvertBind(engine *Engine, schema *tableSchema, bind Bind) Bind {
+	entity := reflect.New(schema.t).Interface().(Entity)
+	orm := initIfNeeded(engine.registry, entity)
+	for column, value := range bind {
+		if _, has := schema.t.FieldByName(column); !has {
+			continue
+		}
+		if err := orm.SetField(column, value); err != nil {
+			panic(err)
+		}
+	}
+	converted, _ := orm.GetDirtyBind()
+	for column, value := range bind {
+		if _, has := schema.t.FieldByName(column); !has {
+			converted[column] = value
+		}
+	}
+	return converted
+}
+
+func batchQueryIDs(engine *Engine, where *Where, schema *tableSchema, chunkSize int, handler func(ids []uint64)) {
+	if chunkSize <= 0 {
+		panic(fmt.Errorf("invalid chunk size %d", chunkSize))
+	}
+	condition, tail := splitWhereTail(where.query)
+	if strings.TrimSpace(tail) != "" {
+		panic(fmt.Errorf("batch query condition does not support ORDER BY, GROUP BY, HAVING or LIMIT: %s", where.query))
+	}
+	if strings.TrimSpace(condition) == "" {
+		condition = "1"
+	}
+	lastID := uint64(0)
+	for {
+		chunkWhere := NewWhere("`ID` > ?", lastID)
+		chunkWhere.query += " AND (" + strings.TrimSpace(condition) + ") ORDER BY `ID`"
+		chunkWhere.merge(where)
+		ids, _ := searchIDs(true, engine, chunkWhere, NewPager(1, chunkSize), false, schema.t)
+		if len(ids) > 0 {
+			lastID = ids[len(ids)-1]
+			handler(ids)
+		}
+		if len(ids) < chunkSize {
+			return
+		}
+	}
+}
+
+func batchQueryLoad(engine *Engine, schema *tableSchema, ids []uint64) []Entity {
+	rows := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t))).Elem()
+	search(false, engine, NewWhere("`ID` IN ?", ids), NewPager(1, len(ids)), false, false, false, rows)
+	entities := make([]Entity, rows.Len())
+	for i := range entities {
+		entities[i] = rows.Index(i).Interface().(Entity)
+	}
+	return entities
+}
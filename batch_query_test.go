@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type batchQueryEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Name     string
+	Age      uint16
+	IndexAge *CachedQuery `query:":Age = ?"`
+}
+
+type batchQueryFakeDeleteEntity struct {
+	ORM        `orm:"redisCache"`
+	ID         uint
+	Age        uint16
+	FakeDelete bool
+	IndexAge   *CachedQuery `query:":Age = ?"`
+}
+
+func TestUpdateByQuery(t *testing.T) {
+	var entity *batchQueryEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 10; i++ {
+		flusher.Track(&batchQueryEntity{Name: "name " + strconv.Itoa(i), Age: uint16(i % 2)})
+	}
+	flusher.Flush()
+
+	var rows []*batchQueryEntity
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexAge", nil, 1))
+	entity = &batchQueryEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+
+	total := engine.UpdateByQuery(NewWhere("`Age` = ?", 1), entity, Bind{"Age": 20, "Name": "updated"}, 2)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, 0, engine.CachedSearch(&rows, "IndexAge", nil, 1))
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexAge", nil, 20))
+	assert.Equal(t, "updated", rows[0].Name)
+	entity = &batchQueryEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "updated", entity.Name)
+	assert.Equal(t, uint16(20), entity.Age)
+	assert.Equal(t, 0, engine.UpdateByQuery(NewWhere("`Age` = ?", 1), entity, Bind{"Age": 20}, 2))
+	assert.Equal(t, 5, engine.UpdateByQuery(NewWhere("`Age` = ?", 20), entity, Bind{"Age": "30"}, 3))
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexAge", nil, 30))
+
+	assert.PanicsWithError(t, "unknown column 'Invalid' in entity 'orm.batchQueryEntity'", func() {
+		engine.UpdateByQuery(NewWhere("1"), entity, Bind{"Invalid": 1}, 2)
+	})
+	assert.PanicsWithError(t, "invalid chunk size 0", func() {
+		engine.UpdateByQuery(NewWhere("1"), entity, Bind{"Age": 1}, 0)
+	})
+	assert.PanicsWithError(t, "batch query condition does not support ORDER BY, GROUP BY, HAVING or LIMIT: `Age` = ? LIMIT ?", func() {
+		engine.UpdateByQuery(NewWhere("`Age` = ? LIMIT ?", 30, 2), entity, Bind{"Age": 1}, 2)
+	})
+}
+
+func TestDeleteByQuery(t *testing.T) {
+	var entity *batchQueryEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 10; i++ {
+		flusher.Track(&batchQueryEntity{Name: "name " + strconv.Itoa(i), Age: uint16(i % 2)})
+	}
+	flusher.Flush()
+
+	var rows []*batchQueryEntity
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexAge", nil, 0))
+	assert.True(t, engine.LoadByID(2, &batchQueryEntity{}))
+
+	total := engine.DeleteByQuery(NewWhere("`Age` = ?", 0), entity, 3)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, 0, engine.CachedSearch(&rows, "IndexAge", nil, 0))
+	assert.False(t, engine.LoadByID(2, &batchQueryEntity{}))
+	assert.Equal(t, 5, engine.Count(NewWhere("1"), entity))
+	assert.Equal(t, 0, engine.DeleteByQuery(NewWhere("`Age` = ?", 0), entity, 3))
+}
+
+func TestDeleteByQueryFakeDelete(t *testing.T) {
+	var entity *batchQueryFakeDeleteEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 10; i++ {
+		flusher.Track(&batchQueryFakeDeleteEntity{Age: uint16(i % 2)})
+	}
+	flusher.Flush()
+
+	var rows []*batchQueryFakeDeleteEntity
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexAge", nil, 0))
+	assert.Equal(t, 5, engine.DeleteByQuery(NewWhere("`Age` = ?", 0), entity, 2))
+	assert.Equal(t, 0, engine.CachedSearch(&rows, "IndexAge", nil, 0))
+	assert.Equal(t, 5, engine.Count(NewWhere("`FakeDelete` = 0"), entity))
+	entity = &batchQueryFakeDeleteEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.True(t, entity.FakeDelete)
+	assert.Equal(t, 0, engine.DeleteByQuery(NewWhere("`Age` = ?", 0), entity, 2))
+}
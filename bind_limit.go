@@ -0,0 +1,54 @@
+package orm
+
+import "fmt"
+
+type BindError struct {
+	Entity  string
+	Field   string
+	Rule    string
+	Pattern string
+	Size    int
+	Limit   int
+}
+
+func (err *BindError) Error() string {
+	switch err.Rule {
+	case "email":
+		return fmt.Sprintf("value of field '%s' in entity '%s' is not valid email", err.Field, err.Entity)
+	case "url":
+		return fmt.Sprintf("value of field '%s' in entity '%s' is not valid url", err.Field, err.Entity)
+	case "pattern":
+		return fmt.Sprintf("value of field '%s' in entity '%s' does not match pattern '%s'", err.Field, err.Entity, err.Pattern)
+	}
+	return fmt.Sprintf("value of field '%s' in entity '%s' has %d bytes and exceeds bind size limit of %d bytes, use orm:\"length=max\" or []byte field to allow larger values",
+		err.Field, err.Entity, err.Size, err.Limit)
+}
+
+func (r *Registry) SetMaxBindSize(bytes int) {
+	r.maxBindSize = bytes
+}
+
+func checkBindSize(engine *Engine, schema *tableSchema, bind Bind) {
+	limit := engine.registry.registry.maxBindSize
+	if limit <= 0 {
+		return
+	}
+	for column, value := range bind {
+		size := 0
+		switch v := value.(type) {
+		case string:
+			size = len(v)
+		case []byte:
+			size = len(v)
+		default:
+			continue
+		}
+		if size <= limit {
+			continue
+		}
+		if schema.largeColumns[column] {
+			continue
+		}
+		panic(&BindError{Entity: schema.t.String(), Field: column, Rule: "size", Size: size, Limit: limit})
+	}
+}
@@ -0,0 +1,50 @@
+package orm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindLimitEntity struct {
+	ORM
+	ID      uint
+	Name    string `orm:"length=100"`
+	Content string `orm:"length=max"`
+	Data    []byte
+}
+
+func TestMaxBindSize(t *testing.T) {
+	registry := &Registry{}
+	registry.SetMaxBindSize(10)
+	engine := PrepareTables(t, registry, 5, &bindLimitEntity{})
+
+	entity := &bindLimitEntity{Name: "0123456789", Content: strings.Repeat("a", 100)}
+	engine.Flush(entity)
+	assert.Equal(t, uint(1), entity.ID)
+
+	entity.Name = "01234567890"
+	assert.PanicsWithError(t, "value of field 'Name' in entity 'orm.bindLimitEntity' has 11 bytes and exceeds bind size limit of 10 bytes, use orm:\"length=max\" or []byte field to allow larger values", func() {
+		engine.Flush(entity)
+	})
+	err := engine.FlushE(entity)
+	var bindError *BindError
+	assert.True(t, errors.As(err, &bindError))
+	assert.Equal(t, "Name", bindError.Field)
+	assert.Equal(t, 11, bindError.Size)
+	assert.Equal(t, 10, bindError.Limit)
+
+	entity.Name = "a"
+	entity.Content = strings.Repeat("c", 200)
+	entity.Data = []byte(strings.Repeat("b", 20))
+	engine.Flush(entity)
+
+	entity = &bindLimitEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, strings.Repeat("c", 200), entity.Content)
+	assert.Equal(t, []byte(strings.Repeat("b", 20)), entity.Data)
+	engine.Delete(entity)
+}
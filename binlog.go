@@ -0,0 +1,149 @@
+package orm
+
+import (
+	"context"
+	"strconv"
+)
+
+const (
+	BinlogInsert = "insert"
+	BinlogUpdate = "update"
+	BinlogDelete = "delete"
+)
+
+type BinlogRowEvent struct {
+	Database string
+	Table    string
+	Action   string
+	Before   map[string]interface{}
+	After    map[string]interface{}
+}
+
+type BinlogSource interface {
+	Next(ctx context.Context) ([]*BinlogRowEvent, error)
+}
+
+type BinlogListener struct {
+	engine *Engine
+	source BinlogSource
+	tables map[string]*tableSchema
+}
+
+func NewBinlogListener(engine *Engine, source BinlogSource) *BinlogListener {
+	listener := &BinlogListener{engine: engine, source: source, tables: make(map[string]*tableSchema)}
+	for _, schema := range engine.registry.tableSchemas {
+		database := engine.registry.mySQLServers[schema.mysqlPoolName].GetDatabase()
+		listener.tables[database+"."+schema.tableName] = schema
+		listener.tables["."+schema.tableName] = schema
+	}
+	return listener
+}
+
+func (l *BinlogListener) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		events, err := l.source.Next(ctx)
+		if err != nil {
+			return err
+		}
+		l.Handle(events...)
+	}
+}
+
+func (l *BinlogListener) Handle(events ...*BinlogRowEvent) {
+	f := &flusher{engine: l.engine}
+	for _, event := range events {
+		schema, has := l.tables[event.Database+"."+event.Table]
+		if !has {
+			continue
+		}
+		id := binlogRowID(event.After)
+		if id == 0 {
+			id = binlogRowID(event.Before)
+		}
+		if id == 0 {
+			continue
+		}
+		clearByIDs(l.engine, schema.newEntity(), id)
+		l.clearCachedIndexes(f, schema, event)
+		if schema.hasSearchCache {
+			entity := schema.newEntity()
+			found, _ := loadByIDFromSource(l.engine, id, entity, false, false)
+			if event.Action == BinlogDelete || !found {
+				f.getRedisFlusher().Del(schema.searchCacheName, schema.redisSearchPrefix+strconv.FormatUint(id, 10))
+			} else {
+				f.fillRedisSearchFromBind(schema, entity, f.convertDBDataToMap(schema, entity.getORM().dBData), id)
+			}
+		}
+	}
+	f.getRedisFlusher().Flush()
+}
+
+func (l *BinlogListener) clearCachedIndexes(f *flusher, schema *tableSchema, event *BinlogRowEvent) {
+	if len(schema.cachedIndexesAll) == 0 {
+		return
+	}
+	bind := make(map[string]interface{})
+	for column, value := range event.After {
+		old, has := event.Before[column]
+		if event.Action != BinlogUpdate || !has || old != value {
+			bind[column] = value
+		}
+	}
+	if event.Action == BinlogDelete {
+		for column, value := range event.Before {
+			bind[column] = value
+		}
+	}
+	keys := make([]string, 0)
+	for _, row := range []map[string]interface{}{event.Before, event.After} {
+		if row == nil {
+			continue
+		}
+		data := make([]interface{}, len(schema.columnNames))
+		for column, value := range row {
+			index, has := schema.columnMapping[column]
+			if has {
+				data[index] = value
+			}
+		}
+		keys = append(keys, f.getCacheQueriesKeys(schema, bind, data, event.Action != BinlogUpdate)...)
+		keys = append(keys, schema.getSortedIndexKeys(data)...)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	localCache, has := schema.GetLocalCache(l.engine)
+	if has {
+		localCache.Remove(keys...)
+	}
+	redisCache, has := schema.GetRedisCache(l.engine)
+	if has {
+		redisCache.Del(keys...)
+	}
+}
+
+func binlogRowID(row map[string]interface{}) uint64 {
+	switch id := row["ID"].(type) {
+	case uint64:
+		return id
+	case int64:
+		return uint64(id)
+	case int:
+		return uint64(id)
+	case uint:
+		return uint64(id)
+	case uint32:
+		return uint64(id)
+	case int32:
+		return uint64(id)
+	case string:
+		value, _ := strconv.ParseUint(id, 10, 64)
+		return value
+	}
+	return 0
+}
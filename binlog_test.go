@@ -0,0 +1,66 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type binlogEntity struct {
+	ORM       `orm:"localCache;redisCache"`
+	ID        uint
+	Name      string
+	Age       uint
+	IndexAge  *CachedQuery `query:":Age = ?"`
+	IndexName *CachedQuery `queryOne:":Name = ?"`
+}
+
+type binlogTestSource struct {
+	events [][]*BinlogRowEvent
+}
+
+func (s *binlogTestSource) Next(_ context.Context) ([]*BinlogRowEvent, error) {
+	if len(s.events) == 0 {
+		return nil, errors.New("end of binlog")
+	}
+	events := s.events[0]
+	s.events = s.events[1:]
+	return events, nil
+}
+
+func TestBinlogListener(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &binlogEntity{})
+	engine.FlushMany(&binlogEntity{Name: "a", Age: 10}, &binlogEntity{Name: "b", Age: 10})
+
+	entity := &binlogEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	var rows []*binlogEntity
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "IndexAge", nil, 10))
+
+	engine.GetMysql().Exec("UPDATE `binlogEntity` SET `Name` = 'c', `Age` = 20 WHERE `ID` = 1")
+	entity = &binlogEntity{}
+	engine.LoadByID(1, entity)
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "IndexAge", nil, 10))
+
+	listener := NewBinlogListener(engine, nil)
+	listener.Handle(&BinlogRowEvent{Database: "test", Table: "binlogEntity", Action: BinlogUpdate,
+		Before: map[string]interface{}{"ID": uint64(1), "Name": "a", "Age": uint64(10)},
+		After:  map[string]interface{}{"ID": uint64(1), "Name": "c", "Age": uint64(20)}})
+	listener.Handle(&BinlogRowEvent{Table: "unknown", Action: BinlogDelete, Before: map[string]interface{}{"ID": 1}})
+	entity = &binlogEntity{}
+	engine.LoadByID(1, entity)
+	assert.Equal(t, "c", entity.Name)
+	assert.Equal(t, 1, engine.CachedSearch(&rows, "IndexAge", nil, 10))
+	assert.True(t, engine.CachedSearchOne(&binlogEntity{}, "IndexName", "c"))
+
+	engine.GetMysql().Exec("DELETE FROM `binlogEntity` WHERE `ID` = 2")
+	source := &binlogTestSource{events: [][]*BinlogRowEvent{{{Database: "test", Table: "binlogEntity", Action: BinlogDelete,
+		Before: map[string]interface{}{"ID": "2", "Name": "b", "Age": uint64(10)}}}}}
+	err := NewBinlogListener(engine, source).Run(context.Background())
+	assert.EqualError(t, err, "end of binlog")
+	assert.False(t, engine.LoadByID(2, &binlogEntity{}))
+	assert.Equal(t, 0, engine.CachedSearch(&rows, "IndexAge", nil, 10))
+}
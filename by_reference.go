@@ -0,0 +1,53 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func (e *Engine) GetByReference(entities interface{}, refField string, id uint64, pager *Pager, orderBy ...string) (totalRows int) {
+	value := reflect.ValueOf(entities)
+	entityType, has, name := getEntityTypeForSlice(e.registry, value.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	schema := getTableSchema(e.registry, entityType)
+	isReference := false
+	for _, ref := range schema.refOne {
+		if ref == refField {
+			isReference = true
+			break
+		}
+	}
+	if !isReference {
+		panic(fmt.Errorf("field '%s' is not a reference in entity '%s'", refField, entityType.String()))
+	}
+	if len(orderBy) == 0 {
+		if _, cached := schema.cachedIndexes[refField]; cached {
+			totalRows, _ = cachedSearch(e, entities, refField, pager, []interface{}{id}, false, true, nil)
+			return totalRows
+		}
+		orderBy = []string{"ID"}
+	}
+	orderColumns := make([]string, len(orderBy))
+	for i, order := range orderBy {
+		parts := strings.Fields(order)
+		if len(parts) == 0 || len(parts) > 2 {
+			panic(fmt.Errorf("invalid order '%s' in entity '%s'", order, entityType.String()))
+		}
+		if _, has = schema.columnMapping[parts[0]]; !has {
+			panic(fmt.Errorf("unknown field '%s' in entity '%s'", parts[0], entityType.String()))
+		}
+		orderColumns[i] = "`" + parts[0] + "`"
+		if len(parts) == 2 {
+			direction := strings.ToUpper(parts[1])
+			if direction != "ASC" && direction != "DESC" {
+				panic(fmt.Errorf("invalid order '%s' in entity '%s'", order, entityType.String()))
+			}
+			orderColumns[i] += " " + direction
+		}
+	}
+	where := NewWhere("`"+refField+"` = ? ORDER BY "+strings.Join(orderColumns, ","), id)
+	return e.SearchWithCount(where, pager, entities)
+}
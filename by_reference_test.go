@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type byReferenceParentEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type byReferenceEntity struct {
+	ORM         `orm:"localCache"`
+	ID          uint
+	Name        string
+	Parent      *byReferenceParentEntity `orm:"cached"`
+	OtherParent *byReferenceParentEntity
+}
+
+func TestGetByReference(t *testing.T) {
+	var entity *byReferenceEntity
+	var parent *byReferenceParentEntity
+	engine := PrepareTables(t, &Registry{}, 5, parent, entity)
+	parent1 := &byReferenceParentEntity{Name: "p1"}
+	parent2 := &byReferenceParentEntity{Name: "p2"}
+	engine.FlushMany(parent1, parent2)
+	engine.FlushMany(&byReferenceEntity{Name: "c", Parent: parent1, OtherParent: parent2},
+		&byReferenceEntity{Name: "a", Parent: parent1, OtherParent: parent2},
+		&byReferenceEntity{Name: "b", Parent: parent2, OtherParent: parent2})
+
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	var rows []*byReferenceEntity
+	total := engine.GetByReference(&rows, "Parent", 1, nil)
+	assert.Equal(t, 2, total)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "c", rows[0].Name)
+	assert.Equal(t, "a", rows[1].Name)
+	dbLogger.Entries = nil
+	total = engine.GetByReference(&rows, "Parent", 1, nil)
+	assert.Equal(t, 2, total)
+	assert.Len(t, dbLogger.Entries, 0)
+
+	total = engine.GetByReference(&rows, "Parent", 1, NewPager(1, 10), "Name")
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "c", rows[1].Name)
+
+	total = engine.GetByReference(&rows, "OtherParent", 2, NewPager(1, 2), "Name DESC")
+	assert.Equal(t, 3, total)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "c", rows[0].Name)
+	assert.Equal(t, "b", rows[1].Name)
+
+	assert.PanicsWithError(t, "field 'Name' is not a reference in entity 'orm.byReferenceEntity'", func() {
+		engine.GetByReference(&rows, "Name", 1, nil)
+	})
+	assert.PanicsWithError(t, "unknown field 'Invalid' in entity 'orm.byReferenceEntity'", func() {
+		engine.GetByReference(&rows, "Parent", 1, nil, "Invalid")
+	})
+	assert.PanicsWithError(t, "invalid order 'Name UP' in entity 'orm.byReferenceEntity'", func() {
+		engine.GetByReference(&rows, "Parent", 1, nil, "Name UP")
+	})
+}
@@ -0,0 +1,41 @@
+package orm
+
+import (
+	"strings"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func (tableSchema *tableSchema) GetCacheCorruptions() uint64 {
+	return atomic.LoadUint64(&tableSchema.cacheCorruptions)
+}
+
+func fillFromRedisValue(engine *Engine, schema *tableSchema, value string, lazy bool, entities ...Entity) (decoded []interface{}, valid bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			decoded = nil
+			valid = false
+		}
+	}()
+	if !strings.HasPrefix(value, schema.cacheFormat) {
+		return nil, false
+	}
+	decoded = make([]interface{}, len(schema.columnNames))
+	err := jsoniter.ConfigFastest.UnmarshalFromString(value[len(schema.cacheFormat):], &decoded)
+	if err != nil || len(decoded) != len(schema.columnNames) {
+		return nil, false
+	}
+	convertDataFromJSON(schema.fields, 0, decoded)
+	for _, entity := range entities {
+		fillFromDBRow(decoded[0].(uint64), engine, decoded, entity, lazy)
+	}
+	return decoded, true
+}
+
+func (tableSchema *tableSchema) evictInvalidCache(redisCache *RedisCache, cacheKey, value string) {
+	if strings.HasPrefix(value, tableSchema.cacheFormat) {
+		atomic.AddUint64(&tableSchema.cacheCorruptions, 1)
+	}
+	redisCache.Del(cacheKey)
+}
@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheCorruptionEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+	Age  uint
+}
+
+type cacheCorruptionReferenceEntity struct {
+	ORM       `orm:"redisCache"`
+	ID        uint
+	Reference *cacheCorruptionEntity
+}
+
+func TestCacheCorruptionFallback(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &cacheCorruptionEntity{}, &cacheCorruptionReferenceEntity{})
+	engine.FlushMany(&cacheCorruptionEntity{Name: "a", Age: 1}, &cacheCorruptionEntity{Name: "b", Age: 2})
+	first := &cacheCorruptionEntity{}
+	engine.LoadByID(1, first)
+	engine.Flush(&cacheCorruptionReferenceEntity{Reference: first})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&cacheCorruptionEntity{}).(*tableSchema)
+	redis := engine.GetRedis()
+	assert.Equal(t, uint64(0), schema.GetCacheCorruptions())
+
+	redis.Set(schema.getCacheKey(1), schema.cacheFormat+"not-json", 0)
+	entity := &cacheCorruptionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, uint64(1), schema.GetCacheCorruptions())
+	entity = &cacheCorruptionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint64(1), schema.GetCacheCorruptions())
+
+	redis.Set(schema.getCacheKey(1), schema.cacheFormat+"[1,\"a\"]", 0)
+	redis.Set(schema.getCacheKey(2), schema.cacheFormat+"[2,true,\"x\"]", 0)
+	var rows []*cacheCorruptionEntity
+	engine.LoadByIDs([]uint64{1, 2}, &rows)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "b", rows[1].Name)
+	assert.Equal(t, uint(2), rows[1].Age)
+	assert.Equal(t, uint64(3), schema.GetCacheCorruptions())
+
+	redis.Set(schema.getCacheKey(1), schema.cacheFormat+"[]", 0)
+	reference := &cacheCorruptionReferenceEntity{}
+	assert.True(t, engine.LoadByID(1, reference, "Reference"))
+	assert.Equal(t, "a", reference.Reference.Name)
+	assert.Equal(t, uint64(4), schema.GetCacheCorruptions())
+	value, has := redis.Get(schema.getCacheKey(1))
+	assert.True(t, has)
+	assert.NotEqual(t, "[]", value)
+}
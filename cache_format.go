@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+func getCacheFormat(fields *tableFields) string {
+	h := fnv.New32a()
+	writeCacheFormat(h, fields)
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+func writeCacheFormat(h hash.Hash32, fields *tableFields) {
+	groups := [][]int{fields.uintegers, fields.uintegersNullable, fields.integers, fields.integersNullable, fields.strings,
+		fields.sliceStrings, fields.bytes, {fields.fakeDelete}, fields.booleans, fields.booleansNullable, fields.floats,
+		fields.floatsNullable, fields.timesNullable, fields.times, fields.jsons, fields.spatials, fields.decimals, fields.customs, fields.refs,
+		fields.refsMany, fields.arrays}
+	for _, group := range groups {
+		for _, i := range group {
+			field, has := fields.fields[i]
+			if !has {
+				_, _ = h.Write([]byte(strconv.Itoa(i)))
+				continue
+			}
+			_, _ = h.Write([]byte(field.Name + " " + field.Type.String() + ";"))
+		}
+		_, _ = h.Write([]byte("|"))
+	}
+	keys := make([]int, 0, len(fields.structs))
+	for i := range fields.structs {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+	for _, i := range keys {
+		_, _ = h.Write([]byte(fields.fields[i].Name + "{"))
+		writeCacheFormat(h, fields.structs[i])
+		_, _ = h.Write([]byte("}"))
+	}
+}
@@ -0,0 +1,53 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheFormatEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+	Age  uint
+}
+
+type cacheFormatChangedEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+	Age  int
+}
+
+func TestCacheFormat(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &cacheFormatEntity{}, &cacheFormatChangedEntity{})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&cacheFormatEntity{}).(*tableSchema)
+	changed := engine.GetRegistry().GetTableSchemaForEntity(&cacheFormatChangedEntity{}).(*tableSchema)
+	assert.Len(t, schema.cacheFormat, 8)
+	assert.NotEqual(t, schema.cacheFormat, changed.cacheFormat)
+
+	engine.Flush(&cacheFormatEntity{Name: "a", Age: 1})
+	entity := &cacheFormatEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	redis := engine.GetRedis()
+	value, has := redis.Get(schema.getCacheKey(1))
+	assert.True(t, has)
+	assert.Equal(t, schema.cacheFormat+"[1,1,\"a\"]", value)
+
+	redis.Set(schema.getCacheKey(1), "[1,7,\"old\"]", 0)
+	entity = &cacheFormatEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, uint(1), entity.Age)
+	assert.Equal(t, uint64(0), schema.GetCacheCorruptions())
+
+	redis.Set(schema.getCacheKey(1), changed.cacheFormat+"[1,7,\"old\"]", 0)
+	var rows []*cacheFormatEntity
+	engine.LoadByIDs([]uint64{1}, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, uint64(0), schema.GetCacheCorruptions())
+	value, _ = redis.Get(schema.getCacheKey(1))
+	assert.Equal(t, schema.cacheFormat+"[1,1,\"a\"]", value)
+}
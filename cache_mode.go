@@ -0,0 +1,96 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	apexLog "github.com/apex/log"
+)
+
+const (
+	CacheModeWriteThrough = "writeThrough"
+	CacheModeInvalidate   = "invalidate"
+	CacheModeDoubleDelete = "doubleDelete"
+)
+
+const defaultCacheDelay = time.Millisecond * 500
+
+type delayedCacheDelete struct {
+	localCache *LocalCache
+	redisCache *RedisCache
+	keys       []string
+	delay      time.Duration
+}
+
+func initCacheMode(tags map[string]string, entityType reflect.Type) (mode string, delay time.Duration, err error) {
+	mode, has := tags["cacheMode"]
+	if !has {
+		mode = CacheModeWriteThrough
+	}
+	if mode != CacheModeWriteThrough && mode != CacheModeInvalidate && mode != CacheModeDoubleDelete {
+		return "", 0, fmt.Errorf("invalid cache mode '%s' in entity '%s'", mode, entityType.String())
+	}
+	delay = defaultCacheDelay
+	userValue, has := tags["cacheDelay"]
+	if has {
+		delay, err = time.ParseDuration(userValue)
+		if err != nil || delay <= 0 {
+			return "", 0, fmt.Errorf("invalid cache delay '%s' in entity '%s'", userValue, entityType.String())
+		}
+	}
+	return mode, delay, nil
+}
+
+func (f *flusher) setLocalCacheEntity(schema *tableSchema, localCache *LocalCache, cacheKey string, dbData []interface{}) {
+	if schema.cacheMode == CacheModeWriteThrough {
+		f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, buildLocalCacheValue(dbData))
+		return
+	}
+	f.addLocalCacheDeletes(localCache.config.GetCode(), cacheKey)
+	if schema.cacheMode == CacheModeDoubleDelete {
+		f.delayedDeletes = append(f.delayedDeletes, &delayedCacheDelete{localCache: localCache, keys: []string{cacheKey}, delay: schema.cacheDelay})
+	}
+}
+
+func (f *flusher) deleteRedisCacheEntity(schema *tableSchema, redisCache *RedisCache, cacheKey string) {
+	f.getRedisFlusher().Del(redisCache.config.GetCode(), cacheKey)
+	if schema.cacheMode == CacheModeDoubleDelete {
+		f.delayedDeletes = append(f.delayedDeletes, &delayedCacheDelete{redisCache: redisCache, keys: []string{cacheKey}, delay: schema.cacheDelay})
+	}
+}
+
+func (f *flusher) scheduleDelayedDeletes(transaction bool) {
+	if len(f.delayedDeletes) == 0 {
+		return
+	}
+	if transaction {
+		f.engine.afterCommitDelayedDeletes = append(f.engine.afterCommitDelayedDeletes, f.delayedDeletes...)
+	} else {
+		scheduleDelayedDeletes(f.engine, f.delayedDeletes)
+	}
+	f.delayedDeletes = nil
+}
+
+func scheduleDelayedDeletes(engine *Engine, delayedDeletes []*delayedCacheDelete) {
+	logger := engine.Log()
+	for _, toDelete := range delayedDeletes {
+		d := toDelete
+		time.AfterFunc(d.delay, func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					logger.Error(rec, apexLog.Fields{"Keys": d.keys})
+				}
+			}()
+			if d.localCache != nil {
+				d.localCache.Remove(d.keys...)
+			}
+			if d.redisCache != nil {
+				redisCache := *d.redisCache
+				redisCache.ctx = context.Background()
+				redisCache.Del(d.keys...)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheModeInvalidateEntity struct {
+	ORM  `orm:"localCache;redisCache;cacheMode=invalidate"`
+	ID   uint
+	Name string
+}
+
+type cacheModeDoubleDeleteEntity struct {
+	ORM  `orm:"localCache;redisCache;cacheMode=doubleDelete;cacheDelay=50ms"`
+	ID   uint
+	Name string
+}
+
+type cacheModeInvalidEntity struct {
+	ORM `orm:"localCache;cacheMode=unknown"`
+	ID  uint
+}
+
+func TestCacheModeInvalidate(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &cacheModeInvalidateEntity{})
+	entity := &cacheModeInvalidateEntity{Name: "a"}
+	engine.Flush(entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.Equal(t, CacheModeInvalidate, schema.GetCacheDefinition().CacheMode)
+	_, has := engine.GetLocalCache().Get(schema.getCacheKey(1))
+	assert.False(t, has)
+
+	assert.True(t, engine.LoadByID(1, entity))
+	_, has = engine.GetLocalCache().Get(schema.getCacheKey(1))
+	assert.True(t, has)
+	entity.Name = "b"
+	engine.Flush(entity)
+	_, has = engine.GetLocalCache().Get(schema.getCacheKey(1))
+	assert.False(t, has)
+	entity = &cacheModeInvalidateEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Name)
+}
+
+func TestCacheModeDoubleDelete(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &cacheModeDoubleDeleteEntity{})
+	entity := &cacheModeDoubleDeleteEntity{Name: "a"}
+	engine.Flush(entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	engine.LoadByID(1, entity)
+	entity.Name = "b"
+	engine.Flush(entity)
+	engine.GetLocalCache().Set(schema.getCacheKey(1), "stale")
+	engine.GetRedis().Set(schema.getCacheKey(1), "stale", 10)
+	time.Sleep(time.Millisecond * 200)
+	_, has := engine.GetLocalCache().Get(schema.getCacheKey(1))
+	assert.False(t, has)
+	_, has = engine.GetRedis().Get(schema.getCacheKey(1))
+	assert.False(t, has)
+
+	db := engine.GetMysql()
+	db.Begin()
+	entity.Name = "c"
+	engine.Flush(entity)
+	assert.Len(t, engine.afterCommitDelayedDeletes, 2)
+	db.Commit()
+	assert.Nil(t, engine.afterCommitDelayedDeletes)
+	engine.GetRedis().Set(schema.getCacheKey(1), "stale", 10)
+	time.Sleep(time.Millisecond * 200)
+	_, has = engine.GetRedis().Get(schema.getCacheKey(1))
+	assert.False(t, has)
+
+	db.Begin()
+	entity.Name = "d"
+	engine.Flush(entity)
+	db.Rollback()
+	assert.Nil(t, engine.afterCommitDelayedDeletes)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterLocalCache(1000)
+	registry.RegisterEntity(&cacheModeInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid cache mode 'unknown' in entity 'orm.cacheModeInvalidEntity'")
+}
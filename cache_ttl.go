@@ -0,0 +1,24 @@
+package orm
+
+func (tableSchema *tableSchema) setRedisCache(redisCache *RedisCache, pairs ...interface{}) {
+	if tableSchema.redisCacheTTL == 0 {
+		redisCache.MSet(pairs...)
+		return
+	}
+	pipeLine := redisCache.PipeLine()
+	for i := 0; i < len(pairs); i += 2 {
+		pipeLine.Set(pairs[i].(string), pairs[i+1], tableSchema.redisCacheTTL)
+	}
+	pipeLine.Exec()
+}
+
+func (tableSchema *tableSchema) setRedisCacheFields(redisCache *RedisCache, key string, fields ...interface{}) {
+	if tableSchema.redisCacheTTL == 0 {
+		redisCache.HSet(key, fields...)
+		return
+	}
+	pipeLine := redisCache.PipeLine()
+	pipeLine.HSet(key, fields...)
+	pipeLine.Expire(key, tableSchema.redisCacheTTL)
+	pipeLine.Exec()
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheTTLEntity struct {
+	ORM       `orm:"redisCache;redisCacheTTL=3600"`
+	ID        uint
+	Name      string
+	Age       uint
+	IndexAge  *CachedQuery `query:":Age = ?"`
+	IndexName *CachedQuery `queryOne:":Name = ?"`
+}
+
+type cacheTTLInvalidEntity struct {
+	ORM `orm:"redisCache;redisCacheTTL=-10"`
+	ID  uint
+}
+
+type cacheTTLNoCacheEntity struct {
+	ORM `orm:"redisCacheTTL=10"`
+	ID  uint
+}
+
+func TestCacheTTL(t *testing.T) {
+	var entity *cacheTTLEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.Equal(t, "default", schema.GetCacheDefinition().RedisCachePool)
+	assert.Equal(t, time.Hour, schema.GetCacheDefinition().RedisCacheTTL)
+	engine.FlushMany(&cacheTTLEntity{Name: "a", Age: 10}, &cacheTTLEntity{Name: "b", Age: 10})
+
+	redisCache := engine.GetRedis()
+	ttl := func(key string) time.Duration {
+		return redisCache.client.TTL(context.Background(), key).Val()
+	}
+	entity = &cacheTTLEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.InDelta(t, time.Hour.Seconds(), ttl(schema.getCacheKey(1)).Seconds(), 2)
+	redisCache.Expire(schema.getCacheKey(1), time.Minute)
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.InDelta(t, time.Minute.Seconds(), ttl(schema.getCacheKey(1)).Seconds(), 2)
+
+	var entities []*cacheTTLEntity
+	engine.LoadByIDs([]uint64{1, 2}, &entities)
+	assert.InDelta(t, time.Hour.Seconds(), ttl(schema.getCacheKey(2)).Seconds(), 2)
+
+	total := engine.CachedSearch(&entities, "IndexAge", nil, 10)
+	assert.Equal(t, 2, total)
+	assert.InDelta(t, time.Hour.Seconds(), ttl(getCacheKeySearch(schema, "IndexAge", 10)).Seconds(), 2)
+	assert.True(t, engine.CachedSearchOne(entity, "IndexName", "b"))
+	assert.InDelta(t, time.Hour.Seconds(), ttl(getCacheKeySearch(schema, "IndexName", "b")).Seconds(), 2)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&cacheTTLInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid redis cache TTL '-10' in entity 'orm.cacheTTLInvalidEntity'")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&cacheTTLNoCacheEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "redis cache TTL requires redisCache in entity 'orm.cacheTTLNoCacheEntity'")
+}
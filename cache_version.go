@@ -0,0 +1,176 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const cacheVersionKeyPrefix = "orm_cache_version:"
+const cacheVersionStream = "orm_cache_version"
+const cacheVersionStreamMaxLen = 10000
+
+var cacheVersionBlockTime = time.Second * 5
+var cacheVersionRetryTime = time.Second
+
+type cacheVersionListeners struct {
+	mutex   sync.Mutex
+	started map[string]bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (tableSchema *tableSchema) InvalidateAllCache(engine *Engine) {
+	if !tableSchema.hasCacheVersion {
+		panic(fmt.Errorf("cache version is not enabled in entity '%s'", tableSchema.t.String()))
+	}
+	r := tableSchema.getCacheVersionRedis(engine)
+	version := r.Incr(cacheVersionKeyPrefix + tableSchema.cachePrefix)
+	tableSchema.setCacheVersion(uint64(version))
+	r.xAdd(cacheVersionStream, []string{"prefix", tableSchema.cachePrefix, "version", strconv.FormatInt(version, 10)})
+	r.XTrim(cacheVersionStream, cacheVersionStreamMaxLen, true)
+}
+
+func (tableSchema *tableSchema) GetCacheVersion(engine *Engine) uint64 {
+	tableSchema.refreshCacheVersion(engine)
+	return atomic.LoadUint64(&tableSchema.cacheVersion)
+}
+
+func (tableSchema *tableSchema) getCacheVersionPool() string {
+	if tableSchema.hasRedisCache {
+		return tableSchema.redisCacheName
+	}
+	return "default"
+}
+
+func (tableSchema *tableSchema) getCacheVersionRedis(engine *Engine) *RedisCache {
+	return engine.GetRedis(tableSchema.getCacheVersionPool())
+}
+
+func (tableSchema *tableSchema) setCacheVersion(version uint64) {
+	for {
+		current := atomic.LoadUint64(&tableSchema.cacheVersion)
+		if version <= current || atomic.CompareAndSwapUint64(&tableSchema.cacheVersion, current, version) {
+			return
+		}
+	}
+}
+
+func (tableSchema *tableSchema) refreshCacheVersion(engine *Engine) {
+	if !tableSchema.hasCacheVersion || atomic.LoadInt32(&tableSchema.cacheVersionListening) == 1 {
+		return
+	}
+	engine.registry.startCacheVersionListener(tableSchema.getCacheVersionPool())
+	atomic.StoreInt32(&tableSchema.cacheVersionListening, 1)
+}
+
+func (r *validatedRegistry) startCacheVersionListener(pool string) {
+	r.cacheVersionListeners.mutex.Lock()
+	defer r.cacheVersionListeners.mutex.Unlock()
+	if r.cacheVersionListeners.started[pool] {
+		return
+	}
+	if r.cacheVersionListeners.started == nil {
+		r.cacheVersionListeners.started = make(map[string]bool)
+		r.cacheVersionListeners.ctx, r.cacheVersionListeners.cancel = context.WithCancel(context.Background())
+	}
+	engine := r.CreateEngine()
+	engine.context = r.cacheVersionListeners.ctx
+	lastID := r.syncCacheVersions(engine, pool)
+	r.cacheVersionListeners.started[pool] = true
+	go r.listenCacheVersions(engine, pool, lastID)
+}
+
+func (r *validatedRegistry) stopCacheVersionListeners() {
+	r.cacheVersionListeners.mutex.Lock()
+	defer r.cacheVersionListeners.mutex.Unlock()
+	if r.cacheVersionListeners.cancel != nil {
+		r.cacheVersionListeners.cancel()
+	}
+}
+
+func (r *validatedRegistry) syncCacheVersions(engine *Engine, pool string) string {
+	redisCache := engine.GetRedis(pool)
+	lastID := "0"
+	messages := redisCache.XRevRange(cacheVersionStream, "+", "-", 1)
+	if len(messages) > 0 {
+		lastID = messages[0].ID
+	}
+	for _, schema := range r.tableSchemas {
+		if !schema.hasCacheVersion || schema.getCacheVersionPool() != pool {
+			continue
+		}
+		value, has := redisCache.Get(cacheVersionKeyPrefix + schema.cachePrefix)
+		if has {
+			version, _ := strconv.ParseUint(value, 10, 64)
+			schema.setCacheVersion(version)
+		}
+	}
+	return lastID
+}
+
+func (r *validatedRegistry) listenCacheVersions(engine *Engine, pool, lastID string) {
+	ctx := engine.context
+	for {
+		var err error
+		lastID, err = r.readCacheVersions(engine, pool, lastID)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cacheVersionRetryTime):
+			}
+			lastID, _ = r.resyncCacheVersions(engine, pool, lastID)
+		}
+	}
+}
+
+func (r *validatedRegistry) readCacheVersions(engine *Engine, pool, lastID string) (id string, err error) {
+	id = lastID
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%v", rec)
+		}
+	}()
+	streams := engine.GetRedis(pool).XRead(&redis.XReadArgs{Streams: []string{cacheVersionStream, lastID}, Block: cacheVersionBlockTime})
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			id = message.ID
+			prefix, _ := message.Values["prefix"].(string)
+			value, _ := message.Values["version"].(string)
+			version, _ := strconv.ParseUint(value, 10, 64)
+			for _, schema := range r.tableSchemas {
+				if schema.hasCacheVersion && schema.cachePrefix == prefix && schema.getCacheVersionPool() == pool {
+					schema.setCacheVersion(version)
+				}
+			}
+		}
+	}
+	return id, nil
+}
+
+func (r *validatedRegistry) resyncCacheVersions(engine *Engine, pool, lastID string) (id string, err error) {
+	id = lastID
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%v", rec)
+		}
+	}()
+	return r.syncCacheVersions(engine, pool), nil
+}
+
+func (tableSchema *tableSchema) getCachePrefix() string {
+	version := atomic.LoadUint64(&tableSchema.cacheVersion)
+	if version == 0 {
+		return tableSchema.cachePrefix
+	}
+	return tableSchema.cachePrefix + "v" + strconv.FormatUint(version, 10)
+}
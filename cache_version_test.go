@@ -0,0 +1,64 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheVersionEntity struct {
+	ORM  `orm:"localCache;redisCache;cacheVersion"`
+	ID   uint
+	Name string
+}
+
+type cacheVersionDisabledEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestCacheVersion(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &cacheVersionEntity{}, &cacheVersionDisabledEntity{})
+	engine.Flush(&cacheVersionEntity{Name: "a"})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&cacheVersionEntity{})
+	assert.Equal(t, uint64(0), schema.GetCacheVersion(engine))
+
+	entity := &cacheVersionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	engine.GetMysql().Exec("UPDATE `cacheVersionEntity` SET `Name` = 'b' WHERE `ID` = 1")
+	entity = &cacheVersionEntity{}
+	engine.LoadByID(1, entity)
+	assert.Equal(t, "a", entity.Name)
+
+	schema.InvalidateAllCache(engine)
+	assert.Equal(t, uint64(1), schema.GetCacheVersion(engine))
+	entity = &cacheVersionEntity{}
+	engine.LoadByID(1, entity)
+	assert.Equal(t, "b", entity.Name)
+	entity.Name = "c"
+	engine.Flush(entity)
+	engine.GetLocalCache().Clear()
+	entity = &cacheVersionEntity{}
+	engine.LoadByID(1, entity)
+	assert.Equal(t, "c", entity.Name)
+
+	registry2, err := engine.GetRegistry().GetSourceRegistry().Validate()
+	assert.NoError(t, err)
+	defer registry2.(*validatedRegistry).stopCacheVersionListeners()
+	engine2 := registry2.CreateEngine()
+	schema2 := registry2.GetTableSchemaForEntity(&cacheVersionEntity{})
+	assert.Equal(t, uint64(1), schema2.GetCacheVersion(engine2))
+	schema.InvalidateAllCache(engine)
+	assert.Eventually(t, func() bool {
+		return schema2.GetCacheVersion(engine2) == 2
+	}, time.Second*2, time.Millisecond*10)
+	entity = &cacheVersionEntity{}
+	engine2.LoadByID(1, entity)
+	assert.Equal(t, "c", entity.Name)
+
+	assert.PanicsWithError(t, "cache version is not enabled in entity 'orm.cacheVersionDisabledEntity'", func() {
+		engine.GetRegistry().GetTableSchemaForEntity(&cacheVersionDisabledEntity{}).InvalidateAllCache(engine)
+	})
+}
@@ -6,11 +6,37 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/segmentio/fasthash/fnv1a"
 )
 
 const idsOnCachePage = 100
+const OrderByRandom = "RAND()"
+
+func cachedSearchWithOrder(engine *Engine, entities interface{}, indexName string, orderBy string, pager *Pager,
+	arguments []interface{}, lazy bool) (totalRows int) {
+	value := reflect.ValueOf(entities)
+	entityType, has, name := getEntityTypeForSlice(engine.registry, value.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	schema := getTableSchema(engine.registry, entityType)
+	definition, has := schema.cachedIndexes[indexName]
+	if !has {
+		panic(fmt.Errorf("index %s not found", indexName))
+	}
+	query, order := splitCachedQueryOrder(definition.Query)
+	if orderBy == "" || strings.EqualFold(orderBy, order) {
+		totalRows, _ = cachedSearch(engine, entities, indexName, pager, arguments, lazy, true, nil)
+		return totalRows
+	}
+	if query == "" {
+		query = "1"
+	}
+	where := NewWhere(query+" ORDER BY "+orderBy, arguments...)
+	return search(false, engine, where, pager, true, lazy, true, value.Elem())
+}
 
 func cachedSearch(engine *Engine, entities interface{}, indexName string, pager *Pager,
 	arguments []interface{}, lazy, checkIsSlice bool, references []string) (totalRows int, ids []uint64) {
@@ -20,16 +46,20 @@ func cachedSearch(engine *Engine, entities interface{}, indexName string, pager
 		panic(fmt.Errorf("entity '%s' is not registered", name))
 	}
 	schema := getTableSchema(engine.registry, entityType)
+	schema.refreshCacheVersion(engine)
 	definition, has := schema.cachedIndexes[indexName]
 	if !has {
 		panic(fmt.Errorf("index %s not found", indexName))
 	}
-	if pager == nil {
-		pager = NewPager(1, definition.Max)
-	}
+	pager = preparePager(pager, definition.Max)
+	defer func() {
+		pager.setTotalRows(totalRows)
+	}()
+	atomic.AddUint64(&definition.queries, 1)
 	start := (pager.GetCurrentPage() - 1) * pager.GetPageSize()
 	if start+pager.GetPageSize() > definition.Max {
-		panic(fmt.Errorf("max cache index page size (%d) exceeded %s", definition.Max, indexName))
+		atomic.AddUint64(&definition.fallbacks, 1)
+		return cachedSearchFallback(engine, schema, entities, definition, pager, arguments, lazy, references)
 	}
 	localCache, hasLocalCache := schema.GetLocalCache(engine)
 	if !hasLocalCache && engine.hasRequestCache {
@@ -42,6 +72,9 @@ func cachedSearch(engine *Engine, entities interface{}, indexName string, pager
 	}
 	where := NewWhere(definition.Query, arguments...)
 	cacheKey := getCacheKeySearch(schema, indexName, where.GetParameters()...)
+	if definition.sorted {
+		return cachedSearchSorted(engine, schema, entities, definition, cacheKey, where, pager, lazy, references)
+	}
 
 	minCachePage := float64((pager.GetCurrentPage() - 1) * pager.GetPageSize() / idsOnCachePage)
 	minCachePageCeil := minCachePage
@@ -109,7 +142,11 @@ func cachedSearch(engine *Engine, entities interface{}, indexName string, pager
 
 	if hasNil {
 		searchPager := NewPager(minPage, maxPage*idsOnCachePage)
-		results, total := searchIDsWithCount(false, engine, where, searchPager, entityType)
+		flightKey := cacheKey + ":" + strconv.Itoa(minPage) + ":" + strconv.Itoa(maxPage)
+		total, results := engine.searchFlight(schema, flightKey, func() (int, []uint64) {
+			results, total := searchIDsWithCount(false, engine, where, searchPager, entityType)
+			return total, results
+		})
 		totalRows = total
 		cacheFields := make([]interface{}, 0)
 		for key, ids := range fromCache {
@@ -143,7 +180,7 @@ func cachedSearch(engine *Engine, entities interface{}, indexName string, pager
 			}
 		}
 		if hasRedis {
-			redisCache.HSet(cacheKey, cacheFields...)
+			schema.setRedisCacheFields(redisCache, cacheKey, cacheFields...)
 		}
 	}
 	nilKeysLen := len(nilsKeys)
@@ -176,6 +213,7 @@ func cachedSearch(engine *Engine, entities interface{}, indexName string, pager
 	_, is := entities.(Entity)
 	if !is {
 		tryByIDs(engine, idsToReturn, value.Elem(), references, lazy)
+		filterByRowPolicy(engine, schema, value.Elem())
 	}
 	return totalRows, idsToReturn
 }
@@ -187,6 +225,7 @@ func cachedSearchOne(engine *Engine, entity Entity, indexName string, fillStruct
 	if schema == nil {
 		panic(fmt.Errorf("entity '%s' is not registered", entityType.String()))
 	}
+	schema.refreshCacheVersion(engine)
 	definition, has := schema.cachedIndexesOne[indexName]
 	if !has {
 		panic(fmt.Errorf("index %s not found", indexName))
@@ -210,7 +249,10 @@ func cachedSearchOne(engine *Engine, entity Entity, indexName string, fillStruct
 		fromCache = redisCache.HMget(cacheKey, "1")
 	}
 	if fromCache["1"] == nil {
-		results, _ := searchIDs(true, engine, Where, NewPager(1, 1), false, entityType)
+		_, results := engine.searchFlight(schema, cacheKey, func() (int, []uint64) {
+			results, _ := searchIDs(true, engine, Where, NewPager(1, 1), false, entityType)
+			return 0, results
+		})
 		l := len(results)
 		value := strconv.Itoa(l)
 		if l > 0 {
@@ -221,7 +263,7 @@ func cachedSearchOne(engine *Engine, entity Entity, indexName string, fillStruct
 			localCache.HMset(cacheKey, map[string]interface{}{"1": value})
 		}
 		if hasRedis {
-			redisCache.HSet(cacheKey, "1", value)
+			schema.setRedisCacheFields(redisCache, cacheKey, "1", value)
 		}
 	} else {
 		ids := strings.Split(fromCache["1"].(string), " ")
@@ -234,6 +276,9 @@ func cachedSearchOne(engine *Engine, entity Entity, indexName string, fillStruct
 		if fillStruct {
 			has, _ = loadByID(engine, id, entity, true, lazy, references...)
 		}
+		if has && !isAllowedByRowPolicy(engine, getTableSchema(engine.registry, entityType), id) {
+			has = false
+		}
 		if !has {
 			id = 0
 		}
@@ -242,6 +287,52 @@ func cachedSearchOne(engine *Engine, entity Entity, indexName string, fillStruct
 	return false, id
 }
 
+func (tableSchema *tableSchema) getCachedIndexForQuery(engine *Engine, query string, withOne bool) (indexName string, has bool) {
+	if !tableSchema.hasLocalCache && !tableSchema.hasRedisCache && !engine.hasRequestCache {
+		return "", false
+	}
+	query = trimCachedQueryOrder(query)
+	for name, definition := range tableSchema.cachedIndexes {
+		if trimCachedQueryOrder(definition.Query) == query {
+			return name, true
+		}
+	}
+	if withOne {
+		for name, definition := range tableSchema.cachedIndexesOne {
+			if trimCachedQueryOrder(definition.Query) == query {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func trimCachedQueryOrder(query string) string {
+	query, _ = splitCachedQueryOrder(query)
+	return query
+}
+
+func splitCachedQueryOrder(query string) (where, order string) {
+	pos := strings.Index(strings.ToLower(query), "order by ")
+	if pos > -1 {
+		order = strings.TrimSpace(query[pos+9:])
+		query = query[0:pos]
+	}
+	return strings.TrimSpace(query), order
+}
+
 func getCacheKeySearch(tableSchema *tableSchema, indexName string, parameters ...interface{}) string {
-	return tableSchema.cachePrefix + "_" + indexName + strconv.Itoa(int(fnv1a.HashString32(fmt.Sprintf("%v", parameters))))
+	return tableSchema.getCachePrefix() + "_" + indexName + strconv.Itoa(int(fnv1a.HashString32(fmt.Sprintf("%v", parameters))))
+}
+
+func cachedSearchFallback(engine *Engine, schema *tableSchema, entities interface{}, definition *cachedQueryDefinition, pager *Pager,
+	arguments []interface{}, lazy bool, references []string) (totalRows int, ids []uint64) {
+	ids, totalRows = searchIDsWithCount(false, engine, NewWhere(definition.Query, arguments...), pager, schema.t)
+	_, is := entities.(Entity)
+	if !is {
+		value := reflect.ValueOf(entities)
+		tryByIDs(engine, ids, value.Elem(), references, lazy)
+		filterByRowPolicy(engine, schema, value.Elem())
+	}
+	return totalRows, ids
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -316,9 +318,8 @@ func TestCachedSearchErrors(t *testing.T) {
 	})
 
 	pager := NewPager(51, 1000)
-	assert.PanicsWithError(t, "max cache index page size (50000) exceeded IndexAge", func() {
-		_ = engine.CachedSearch(&rows, "IndexAge", pager, 10)
-	})
+	assert.Equal(t, 0, engine.CachedSearch(&rows, "IndexAge", pager, 10))
+	assert.Len(t, rows, 0)
 
 	var rows2 []*cachedSearchRefEntity
 	assert.PanicsWithError(t, "cache search not allowed for entity without cache: 'orm.cachedSearchRefEntity'", func() {
@@ -360,3 +361,157 @@ func BenchmarkCachedSearch(b *testing.B) {
 		_ = engine.CachedSearch(&rows, "IndexAll", pager)
 	}
 }
+
+func TestCountAndExists(t *testing.T) {
+	var entity *cachedSearchEntity
+	var entityRef *cachedSearchRefEntity
+	engine := PrepareTables(t, &Registry{}, 5, entityRef, entity)
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 5; i++ {
+		flusher.Track(&cachedSearchEntity{Name: "Name " + strconv.Itoa(i), Age: uint16(10 + i%2)})
+	}
+	flusher.Flush()
+
+	assert.Equal(t, 5, engine.Count(NewWhere("1"), entity))
+	assert.Equal(t, 2, engine.Count(NewWhere("`Age` = ?", 10), entity))
+	assert.True(t, engine.Exists(NewWhere("`Name` = ?", "Name 2"), entity))
+	assert.False(t, engine.Exists(NewWhere("`Name` = ?", "Name 7"), entity))
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	schema.redisCacheName = "default"
+	schema.hasRedisCache = true
+	assert.Equal(t, 3, engine.Count(NewWhere("`Age` = ?", 11), entity))
+	assert.True(t, engine.Exists(NewWhere("`Name` = ?", "Name 3"), entity))
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	assert.Equal(t, 3, engine.Count(NewWhere("`Age` = ? ORDER BY `Age`", 11), entity))
+	assert.True(t, engine.Exists(NewWhere("`Name` = ?", "Name 3"), entity))
+	assert.Len(t, dbLogger.Entries, 0)
+	assert.Equal(t, 1, engine.Count(NewWhere("`Age` = ? AND `Name` = ?", 11, "Name 1"), entity))
+	assert.Len(t, dbLogger.Entries, 1)
+}
+
+func TestCachedSearchWithOrder(t *testing.T) {
+	var entity *cachedSearchEntity
+	var entityRef *cachedSearchRefEntity
+	engine := PrepareTables(t, &Registry{}, 5, entityRef, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	schema.redisCacheName = "default"
+	schema.hasRedisCache = true
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 5; i++ {
+		flusher.Track(&cachedSearchEntity{Name: "Name " + strconv.Itoa(i), Age: uint16(10)})
+	}
+	flusher.Flush()
+
+	var rows []*cachedSearchEntity
+	totalRows := engine.CachedSearchWithOrder(&rows, "IndexAge", "`Name` DESC", nil, 10)
+	assert.Equal(t, 5, totalRows)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, "Name 5", rows[0].Name)
+	assert.Equal(t, "Name 1", rows[4].Name)
+
+	totalRows = engine.CachedSearchWithOrder(&rows, "IndexAge", OrderByRandom, NewPager(1, 2), 10)
+	assert.Equal(t, 5, totalRows)
+	assert.Len(t, rows, 2)
+
+	totalRows = engine.CachedSearchWithOrderLazy(&rows, "IndexAge", "`Age`", nil, 10)
+	assert.Equal(t, 5, totalRows)
+	assert.Len(t, rows, 5)
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	totalRows = engine.CachedSearchWithOrderLazy(&rows, "IndexAge", "`Age`", nil, 10)
+	assert.Equal(t, 5, totalRows)
+	assert.Len(t, dbLogger.Entries, 0)
+}
+
+type cachedSearchSlowLogHandler struct {
+	queries int64
+}
+
+func (h *cachedSearchSlowLogHandler) HandleLog(_ *apexLog.Entry) error {
+	if atomic.AddInt64(&h.queries, 1) == 1 {
+		time.Sleep(time.Millisecond * 200)
+	}
+	return nil
+}
+
+type cachedSearchCoalescingEntity struct {
+	ORM      `orm:"localCache"`
+	ID       uint
+	Name     string
+	IndexAll *CachedQuery `query:""`
+}
+
+func TestCachedSearchCoalescing(t *testing.T) {
+	var entity *cachedSearchCoalescingEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.FlushMany(&cachedSearchCoalescingEntity{Name: "a"}, &cachedSearchCoalescingEntity{Name: "b"})
+	engine.GetLocalCache().Clear()
+
+	handler := &cachedSearchSlowLogHandler{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := engine.GetRegistry().CreateEngine()
+			e.AddQueryLogger(handler, apexLog.InfoLevel, QueryLoggerSourceDB)
+			total, ids := e.CachedSearchIDs(&cachedSearchCoalescingEntity{}, "IndexAll", nil)
+			assert.Equal(t, 2, total)
+			assert.Equal(t, []uint64{1, 2}, ids)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&handler.queries))
+}
+
+type cachedSearchMaxEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Age      uint
+	IndexAge *CachedQuery `query:":Age = ?" max:"10"`
+}
+
+type cachedSearchInvalidMaxEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	IndexAll *CachedQuery `query:"" max:"0"`
+}
+
+func TestCachedSearchFallback(t *testing.T) {
+	var entity *cachedSearchMaxEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	flusher := engine.NewFlusher()
+	for i := 0; i < 25; i++ {
+		flusher.Track(&cachedSearchMaxEntity{Age: 18})
+	}
+	flusher.Flush()
+
+	var rows []*cachedSearchMaxEntity
+	total := engine.CachedSearch(&rows, "IndexAge", NewPager(1, 10), 18)
+	assert.Equal(t, 25, total)
+	assert.Len(t, rows, 10)
+	assert.Equal(t, uint(1), rows[0].ID)
+
+	total = engine.CachedSearch(&rows, "IndexAge", NewPager(2, 10), 18)
+	assert.Equal(t, 25, total)
+	assert.Len(t, rows, 10)
+	assert.Equal(t, uint(11), rows[0].ID)
+
+	total, ids := engine.CachedSearchIDs(&cachedSearchMaxEntity{}, "IndexAge", NewPager(3, 10), 18)
+	assert.Equal(t, 25, total)
+	assert.Equal(t, []uint64{21, 22, 23, 24, 25}, ids)
+
+	stats := engine.GetRegistry().GetTableSchemaForEntity(entity).GetCachedIndexStats()["IndexAge"]
+	assert.Equal(t, uint64(3), stats.Queries)
+	assert.Equal(t, uint64(2), stats.Fallbacks)
+	assert.InDelta(t, 0.66, stats.FallbackRate(), 0.01)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterEntity(&cachedSearchInvalidMaxEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid max '0' in cached index 'IndexAll' in entity 'orm.cachedSearchInvalidMaxEntity'")
+}
@@ -0,0 +1,68 @@
+package orm
+
+func getDefaultCollation(version int, charset string) string {
+	switch charset {
+	case "utf8mb4":
+		if version == 8 {
+			return "utf8mb4_" + defaultCollate
+		}
+		return "utf8mb4_general_ci"
+	case "latin1":
+		return "latin1_swedish_ci"
+	case "binary":
+		return "binary"
+	}
+	return charset + "_general_ci"
+}
+
+func (tableSchema *tableSchema) getCharset(registry *validatedRegistry) string {
+	if tableSchema.charset != "" {
+		return tableSchema.charset
+	}
+	return registry.registry.defaultEncoding
+}
+
+func (tableSchema *tableSchema) getCollation(registry *validatedRegistry, version int) string {
+	if tableSchema.collation != "" {
+		return tableSchema.collation
+	}
+	if tableSchema.charset == "" && version == 8 {
+		return registry.registry.defaultEncoding + "_" + defaultCollate
+	}
+	return getDefaultCollation(version, tableSchema.getCharset(registry))
+}
+
+func (tableSchema *tableSchema) getTableCharsetSQL(registry *validatedRegistry, version int) string {
+	charset := tableSchema.getCharset(registry)
+	collation := tableSchema.getCollation(registry, version)
+	sql := " DEFAULT CHARSET=" + charset
+	if version == 8 || collation != getDefaultCollation(version, charset) {
+		sql += " COLLATE=" + collation
+	}
+	return sql
+}
+
+func (tableSchema *tableSchema) getColumnCharsetSQL(registry *validatedRegistry, version int, attributes map[string]string) string {
+	tableCollation := tableSchema.getCollation(registry, version)
+	charset := attributes["charset"]
+	collation := attributes["collation"]
+	if charset == "" {
+		charset = tableSchema.getCharset(registry)
+		if collation == "" {
+			collation = tableCollation
+		}
+	} else if collation == "" {
+		collation = getDefaultCollation(version, charset)
+	}
+	if version == 8 {
+		return " CHARACTER SET " + charset + " COLLATE " + collation
+	}
+	sql := ""
+	if collation != tableCollation {
+		sql += " CHARACTER SET " + charset
+	}
+	if collation != getDefaultCollation(version, charset) {
+		sql += " COLLATE " + collation
+	}
+	return sql
+}
@@ -0,0 +1,71 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type charsetEntity struct {
+	ORM
+	ID    uint
+	Token string `orm:"length=64;charset=ascii;collation=ascii_bin"`
+	Email string `orm:"charset=ascii"`
+	Name  string
+}
+
+type charsetTableEntity struct {
+	ORM  `orm:"charset=latin1"`
+	ID   uint
+	Name string
+	Code string `orm:"charset=utf8mb4;collation=utf8mb4_bin"`
+}
+
+func TestCharset5(t *testing.T) {
+	testCharset(t, 5)
+}
+
+func TestCharset8(t *testing.T) {
+	testCharset(t, 8)
+}
+
+func testCharset(t *testing.T, version int) {
+	engine := PrepareTables(t, &Registry{}, version, &charsetEntity{}, &charsetTableEntity{})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&charsetEntity{})
+	tableSchema := engine.GetRegistry().GetTableSchemaForEntity(&charsetTableEntity{})
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+	has, _ = tableSchema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	schema.DropTable(engine)
+	tableSchema.DropTable(engine)
+	_, alters := schema.GetSchemaChanges(engine)
+	assert.Len(t, alters, 1)
+	_, tableAlters := tableSchema.GetSchemaChanges(engine)
+	assert.Len(t, tableAlters, 1)
+	if version == 5 {
+		assert.Equal(t, "CREATE TABLE `test`.`charsetEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  `Token` varchar(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',\n  `Email` varchar(255) CHARACTER SET ascii NOT NULL DEFAULT '',\n  `Name` varchar(255) NOT NULL DEFAULT '',\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+		assert.Equal(t, "CREATE TABLE `test`.`charsetTableEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  `Name` varchar(255) NOT NULL DEFAULT '',\n  `Code` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '',\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1;", tableAlters[0].SQL)
+	} else {
+		assert.Equal(t, "CREATE TABLE `test`.`charsetEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n  `Token` varchar(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',\n  `Email` varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL DEFAULT '',\n  `Name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '',\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;", alters[0].SQL)
+		assert.Equal(t, "CREATE TABLE `test`.`charsetTableEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n  `Name` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT '',\n  `Code` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '',\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci;", tableAlters[0].SQL)
+	}
+	alters[0].Exec()
+	tableAlters[0].Exec()
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	engine.GetMysql().Exec("ALTER TABLE `charsetEntity` CHANGE COLUMN `Token` `Token` varchar(64) CHARACTER SET utf8mb4 NOT NULL DEFAULT ''")
+	has, alters = schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.Contains(t, alters[0].SQL, "CHANGE COLUMN `Token` `Token` varchar(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT ''")
+	alters[0].Exec()
+
+	engine.GetMysql().Exec("ALTER TABLE `charsetTableEntity` DEFAULT CHARSET=utf8mb4")
+	has, alters = tableSchema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.Contains(t, alters[0].SQL, "ENGINE=InnoDB DEFAULT CHARSET=latin1")
+}
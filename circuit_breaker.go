@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type circuitBreakerConfig struct {
+	failures int
+	coolDown time.Duration
+}
+
+type circuitBreaker struct {
+	mutex     sync.Mutex
+	config    circuitBreakerConfig
+	failures  int
+	openUntil time.Time
+}
+
+// SetEntityCacheCircuitBreaker protects only entity loads by ID, other redis operations are not guarded
+func (r *Registry) SetEntityCacheCircuitBreaker(failures int, coolDown time.Duration, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if _, has := r.redisPools[dbCode]; !has {
+		panic(fmt.Errorf("redis pool '%s' is not registered", dbCode))
+	}
+	if failures <= 0 {
+		panic(fmt.Errorf("circuit breaker failures must be greater than zero"))
+	}
+	if r.circuitBreakers == nil {
+		r.circuitBreakers = make(map[string]circuitBreakerConfig)
+	}
+	r.circuitBreakers[dbCode] = circuitBreakerConfig{failures: failures, coolDown: coolDown}
+}
+
+func (e *Engine) IsEntityCacheCircuitOpen(code ...string) bool {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	breaker := e.registry.circuitBreakers[dbCode]
+	return breaker != nil && !breaker.allow(e.GetClock().Now())
+}
+
+func (r *validatedRegistry) initCircuitBreakers() {
+	if len(r.registry.circuitBreakers) == 0 {
+		return
+	}
+	r.circuitBreakers = make(map[string]*circuitBreaker, len(r.registry.circuitBreakers))
+	for code, config := range r.registry.circuitBreakers {
+		r.circuitBreakers[code] = &circuitBreaker{config: config}
+	}
+}
+
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return !now.Before(b.openUntil)
+}
+
+func (b *circuitBreaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures = 0
+}
+
+func (b *circuitBreaker) failure(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures++
+	if b.failures >= b.config.failures {
+		b.failures = 0
+		b.openUntil = now.Add(b.config.coolDown)
+	}
+}
+
+func (e *Engine) protectCache(redisCache *RedisCache, run func()) (ok bool) {
+	breaker := e.registry.circuitBreakers[redisCache.config.GetCode()]
+	if breaker == nil {
+		run()
+		return true
+	}
+	if !breaker.allow(e.GetClock().Now()) {
+		return false
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			breaker.failure(e.GetClock().Now())
+			ok = false
+		}
+	}()
+	run()
+	breaker.success()
+	return true
+}
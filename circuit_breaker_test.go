@@ -0,0 +1,56 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type circuitBreakerEntity struct {
+	ORM  `orm:"redisCache=broken"`
+	ID   uint
+	Name string
+}
+
+func TestCacheCircuitBreaker(t *testing.T) {
+	var entity *circuitBreakerEntity
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterRedis("localhost:6399", 0, "broken")
+	registry.RegisterEntity(entity)
+	assert.PanicsWithError(t, "redis pool 'missing' is not registered", func() {
+		registry.SetEntityCacheCircuitBreaker(2, time.Minute, "missing")
+	})
+	registry.SetEntityCacheCircuitBreaker(2, time.Minute, "broken")
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	clock := NewMockClock(time.Now())
+	engine.SetClock(clock)
+	for _, alter := range engine.GetAlters() {
+		alter.Exec()
+	}
+	validatedRegistry.GetTableSchemaForEntity(entity).TruncateTable(engine)
+	engine.GetMysql().Exec("INSERT INTO `circuitBreakerEntity`(`ID`, `Name`) VALUES (1, 'a'), (2, 'b')")
+
+	assert.False(t, engine.IsEntityCacheCircuitOpen("broken"))
+	entity = &circuitBreakerEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.False(t, engine.IsEntityCacheCircuitOpen("broken"))
+	var rows []*circuitBreakerEntity
+	engine.LoadByIDs([]uint64{1, 2}, &rows)
+	assert.Len(t, rows, 2)
+	assert.True(t, engine.IsEntityCacheCircuitOpen("broken"))
+
+	entity = &circuitBreakerEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, "b", entity.Name)
+	assert.True(t, engine.Clone().IsEntityCacheCircuitOpen("broken"))
+
+	clock.Add(time.Minute)
+	assert.False(t, engine.IsEntityCacheCircuitOpen("broken"))
+	assert.False(t, engine.IsEntityCacheCircuitOpen())
+}
@@ -0,0 +1,28 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (e *Engine) ClaimRows(where *Where, limit int, entities interface{}, claim func(), references ...string) int {
+	val := reflect.ValueOf(entities).Elem()
+	entityType, has, name := getEntityTypeForSlice(e.registry, val.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	db := getTableSchema(e.registry, entityType).GetMysql(e)
+	if db.inTransaction {
+		panic(fmt.Errorf("claim rows can't be run inside transaction in mysql pool '%s'", db.GetPoolConfig().GetCode()))
+	}
+	db.Begin()
+	defer db.Rollback()
+	search(true, e, where.withLock(ForUpdateSkipLocked), NewPager(1, limit), false, false, true, val, references...)
+	total := val.Len()
+	if total == 0 {
+		return 0
+	}
+	claim()
+	db.Commit()
+	return total
+}
@@ -0,0 +1,52 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type claimRowsEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestClaimRows(t *testing.T) {
+	var entity *claimRowsEntity
+	engine := PrepareTables(t, &Registry{}, 8, entity)
+	engine.FlushMany(&claimRowsEntity{Name: "a"}, &claimRowsEntity{Name: "b"}, &claimRowsEntity{Name: "c"}, &claimRowsEntity{Name: "d"})
+
+	db := engine.GetMysql()
+	db.Begin()
+	assert.True(t, engine.LoadByIDForUpdate(1, &claimRowsEntity{}, ForUpdate))
+
+	worker := engine.registry.CreateEngine()
+	var rows []*claimRowsEntity
+	total := worker.ClaimRows(NewWhere("`Name` != ? ORDER BY `ID`", "claimed"), 2, &rows, func() {
+		for _, row := range rows {
+			row.Name = "claimed"
+		}
+		worker.FlushMany(rows[0], rows[1])
+	})
+	assert.Equal(t, 2, total)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, uint(2), rows[0].ID)
+	assert.Equal(t, uint(3), rows[1].ID)
+	db.Commit()
+
+	total = worker.ClaimRows(NewWhere("`Name` != ? ORDER BY `ID`", "claimed"), 5, &rows, func() {
+		assert.Len(t, rows, 2)
+	})
+	assert.Equal(t, 2, total)
+	total = worker.ClaimRows(NewWhere("`Name` = ?", "missing"), 5, &rows, func() {
+		assert.Fail(t, "no rows claimed")
+	})
+	assert.Equal(t, 0, total)
+
+	db.Begin()
+	defer db.Rollback()
+	assert.PanicsWithError(t, "claim rows can't be run inside transaction in mysql pool 'default'", func() {
+		engine.ClaimRows(NewWhere("1"), 1, &rows, func() {})
+	})
+}
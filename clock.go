@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (c systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type MockClock struct {
+	now  time.Time
+	lock sync.RWMutex
+}
+
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+func (c *MockClock) Now() time.Time {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.now
+}
+
+func (c *MockClock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = now
+}
+
+func (c *MockClock) Add(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(duration)
+}
@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)
+	clock := NewMockClock(now)
+	registry := &Registry{}
+	registry.RegisterLocalCache(100)
+	registry.SetClock(clock)
+	validatedRegistry, err := registry.Validate()
+	assert.Nil(t, err)
+	engine := validatedRegistry.CreateEngine()
+	assert.Equal(t, now, engine.GetClock().Now())
+
+	c := engine.GetLocalCache()
+	val := c.GetSet("test_clock", 10, func() interface{} {
+		return "hello"
+	})
+	assert.Equal(t, "hello", val)
+	clock.Add(time.Second * 10)
+	val = c.GetSet("test_clock", 10, func() interface{} {
+		return "hello2"
+	})
+	assert.Equal(t, "hello", val)
+	clock.Add(time.Second)
+	val = c.GetSet("test_clock", 10, func() interface{} {
+		return "hello3"
+	})
+	assert.Equal(t, "hello3", val)
+
+	other := NewMockClock(now.Add(time.Hour))
+	engine.SetClock(other)
+	assert.Equal(t, now.Add(time.Hour), engine.GetClock().Now())
+	other.Set(now)
+	assert.Equal(t, now, engine.GetClock().Now())
+	assert.Equal(t, now.Add(time.Second*11), validatedRegistry.CreateEngine().GetClock().Now())
+
+	registry = &Registry{}
+	validatedRegistry, err = registry.Validate()
+	assert.Nil(t, err)
+	engine = validatedRegistry.CreateEngine()
+	assert.WithinDuration(t, time.Now(), engine.GetClock().Now(), time.Second)
+}
@@ -0,0 +1,249 @@
+package orm
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GeneratedEntity interface {
+	Entity
+	OrmColumns() string
+	OrmFill(data []interface{})
+	OrmBind(oldData []interface{}, hasOld bool, bind Bind)
+}
+
+var generatedEntityType = reflect.TypeOf((*GeneratedEntity)(nil)).Elem()
+
+// GenerateEntityCode supports integer, string, bool, time and reference fields, nullable ones included
+func GenerateEntityCode(registry ValidatedRegistry, packageName string, entities ...Entity) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("// Code generated by orm. DO NOT EDIT.\n\n")
+	buffer.WriteString("package " + packageName + "\n\n")
+	buffer.WriteString("import \"github.com/latolukasz/orm\"\n")
+	for _, entity := range entities {
+		err := generateEntityCode(buffer, registry.GetTableSchemaForEntity(entity).(*tableSchema))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return format.Source(buffer.Bytes())
+}
+
+func generateEntityCode(buffer *bytes.Buffer, schema *tableSchema) error {
+	err := checkGeneratedEntityFields(schema)
+	if err != nil {
+		return err
+	}
+	fields := schema.fields
+	name := schema.t.Name()
+	fill := &strings.Builder{}
+	bind := &strings.Builder{}
+	for _, i := range fields.uintegers {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\te.%s = %s(data[%d].(uint64))\n", field.Name, field.Type.String(), index)
+		if i != 1 {
+			fmt.Fprintf(bind, "\tif v := uint64(e.%s); !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n", field.Name, index, field.Name)
+		}
+	}
+	for _, i := range fields.uintegersNullable {
+		generateNullableCode(fill, bind, schema, fields.fields[i], "uint64")
+	}
+	for _, i := range fields.integers {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\te.%s = %s(data[%d].(int64))\n", field.Name, field.Type.String(), index)
+		fmt.Fprintf(bind, "\tif v := int64(e.%s); !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n", field.Name, index, field.Name)
+	}
+	for _, i := range fields.integersNullable {
+		generateNullableCode(fill, bind, schema, fields.fields[i], "int64")
+	}
+	for _, i := range fields.strings {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\tif data[%d] == nil {\n\t\te.%s = \"\"\n\t} else {\n\t\te.%s = data[%d].(string)\n\t}\n", index, field.Name, field.Name, index)
+		empty := "nil"
+		if schema.tags[field.Name]["required"] == "true" {
+			empty = "\"\""
+		}
+		fmt.Fprintf(bind, "\tif v := e.%s; !hasOld || !(oldData[%d] == v || (oldData[%d] == nil && v == \"\")) {\n", field.Name, index, index)
+		fmt.Fprintf(bind, "\t\tif v != \"\" {\n\t\t\tbind[\"%s\"] = v\n\t\t} else {\n\t\t\tbind[\"%s\"] = %s\n\t\t}\n\t}\n", field.Name, field.Name, empty)
+	}
+	for _, i := range fields.booleans {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\te.%s = data[%d].(bool)\n", field.Name, index)
+		fmt.Fprintf(bind, "\tif v := e.%s; !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n", field.Name, index, field.Name)
+	}
+	for _, i := range fields.booleansNullable {
+		generateNullableCode(fill, bind, schema, fields.fields[i], "bool")
+	}
+	for _, i := range fields.timesNullable {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\tif data[%d] == nil {\n\t\te.%s = nil\n\t} else {\n", index, field.Name)
+		fmt.Fprintf(fill, "\t\tv := orm.GeneratedTime(e, \"%s\", data[%d].(string))\n\t\te.%s = &v\n\t}\n", field.Name, index, field.Name)
+		fmt.Fprintf(bind, "\tif e.%s == nil {\n\t\tif !hasOld || oldData[%d] != nil {\n\t\t\tbind[\"%s\"] = nil\n\t\t}\n", field.Name, index, field.Name)
+		fmt.Fprintf(bind, "\t} else if v := orm.GeneratedTimeBind(e, \"%s\", *e.%s, true); !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n",
+			field.Name, field.Name, index, field.Name)
+	}
+	for _, i := range fields.times {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		fmt.Fprintf(fill, "\tif v := data[%d].(string); !(e.%s.IsZero() && (v == \"0001-01-01\" || v == \"0001-01-01 00:00:00\")) {\n", index, field.Name)
+		fmt.Fprintf(fill, "\t\te.%s = orm.GeneratedTime(e, \"%s\", v)\n\t}\n", field.Name, field.Name)
+		fmt.Fprintf(bind, "\tif v := orm.GeneratedTimeBind(e, \"%s\", e.%s, false); !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n",
+			field.Name, field.Name, index, field.Name)
+	}
+	for _, i := range fields.refs {
+		field := fields.fields[i]
+		index := schema.columnMapping[field.Name]
+		id := field.Type.Elem().Field(1).Name
+		fmt.Fprintf(bind, "\tif e.%s != nil && e.%s.%s != 0 {\n", field.Name, field.Name, id)
+		fmt.Fprintf(bind, "\t\tif v := uint64(e.%s.%s); !hasOld || oldData[%d] != v {\n\t\t\tbind[\"%s\"] = v\n\t\t}\n", field.Name, id, index, field.Name)
+		fmt.Fprintf(bind, "\t} else if !hasOld || oldData[%d] != nil {\n\t\tbind[\"%s\"] = nil\n\t}\n", index, field.Name)
+	}
+	fmt.Fprintf(buffer, "\nfunc (e *%s) OrmColumns() string {\n\treturn %s\n}\n", name, strconv.Quote(strings.Join(schema.columnNames, ",")))
+	fmt.Fprintf(buffer, "\nfunc (e *%s) OrmFill(data []interface{}) {\n%s}\n", name, fill.String())
+	fmt.Fprintf(buffer, "\nfunc (e *%s) OrmBind(oldData []interface{}, hasOld bool, bind orm.Bind) {\n%s}\n", name, bind.String())
+	return nil
+}
+
+func generateNullableCode(fill, bind *strings.Builder, schema *tableSchema, field reflect.StructField, dataType string) {
+	index := schema.columnMapping[field.Name]
+	value := "*e." + field.Name
+	data := fmt.Sprintf("data[%d].(%s)", index, dataType)
+	if dataType != "bool" {
+		value = dataType + "(" + value + ")"
+		data = field.Type.Elem().String() + "(" + data + ")"
+	}
+	fmt.Fprintf(fill, "\tif data[%d] == nil {\n\t\te.%s = nil\n\t} else {\n", index, field.Name)
+	fmt.Fprintf(fill, "\t\tv := %s\n\t\te.%s = &v\n\t}\n", data, field.Name)
+	fmt.Fprintf(bind, "\tif e.%s == nil {\n\t\tif !hasOld || oldData[%d] != nil {\n\t\t\tbind[\"%s\"] = nil\n\t\t}\n", field.Name, index, field.Name)
+	fmt.Fprintf(bind, "\t} else if v := %s; !hasOld || oldData[%d] != v {\n\t\tbind[\"%s\"] = v\n\t}\n", value, index, field.Name)
+}
+
+func checkGeneratedEntityFields(schema *tableSchema) error {
+	fields := schema.fields
+	if len(fields.sliceStrings) > 0 || len(fields.bytes) > 0 || fields.fakeDelete > 0 || len(fields.floats) > 0 ||
+		len(fields.floatsNullable) > 0 || len(fields.jsons) > 0 || len(fields.spatials) > 0 || len(fields.decimals) > 0 ||
+		len(fields.customs) > 0 || len(fields.structs) > 0 || len(fields.refsMany) > 0 || len(fields.arrays) > 0 {
+		return fmt.Errorf("entity '%s' has fields not supported by code generator", schema.t.String())
+	}
+	for _, group := range [][]int{fields.uintegers, fields.uintegersNullable, fields.integers, fields.integersNullable,
+		fields.strings, fields.booleans, fields.booleansNullable} {
+		for _, i := range group {
+			t := fields.fields[i].Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.PkgPath() != "" {
+				return fmt.Errorf("field '%s' in entity '%s' has named type not supported by code generator", fields.fields[i].Name, schema.t.String())
+			}
+		}
+	}
+	for _, i := range fields.strings {
+		if _, has := schema.tags[fields.fields[i].Name]["enum"]; has {
+			return fmt.Errorf("enum field '%s' in entity '%s' is not supported by code generator", fields.fields[i].Name, schema.t.String())
+		}
+	}
+	return nil
+}
+
+func initGeneratedEntity(schema *tableSchema) error {
+	if !reflect.PtrTo(schema.t).Implements(generatedEntityType) {
+		return nil
+	}
+	generated := reflect.New(schema.t).Interface().(GeneratedEntity)
+	if generated.OrmColumns() != strings.Join(schema.columnNames, ",") {
+		return fmt.Errorf("generated code for entity '%s' is outdated", schema.t.String())
+	}
+	err := checkGeneratedEntityFields(schema)
+	if err != nil {
+		return err
+	}
+	schema.generated = true
+	return nil
+}
+
+func GeneratedTime(entity Entity, column string, value string) time.Time {
+	layout := "2006-01-02"
+	if len(value) == 19 {
+		layout += " 15:04:05"
+	}
+	return entity.getORM().tableSchema.parseTime(column, layout, value)
+}
+
+func GeneratedTimeBind(entity Entity, column string, value time.Time, nullable bool) string {
+	schema := entity.getORM().tableSchema
+	hasTime := schema.tags[column]["time"] == "true"
+	if !nullable && value.Year() == 1 {
+		if hasTime {
+			return "0001-01-01 00:00:00"
+		}
+		return "0001-01-01"
+	}
+	if hasTime {
+		return schema.convertTimeForBind(column, value).Format("2006-01-02 15:04:05")
+	}
+	return schema.convertTimeForBind(column, value).Format("2006-01-02")
+}
+
+func fillGeneratedReferences(registry *validatedRegistry, data []interface{}, orm *ORM) {
+	fields := orm.tableSchema.fields
+	for k, i := range fields.refs {
+		field := orm.elem.Field(i)
+		id := uint64(0)
+		if value := data[orm.tableSchema.columnMapping[fields.fields[i].Name]]; value != nil {
+			id = value.(uint64)
+		}
+		if id == 0 {
+			if !field.IsZero() {
+				field.Set(reflect.Zero(fields.refsTypes[k]))
+			}
+			continue
+		}
+		if orm.lazy && !field.IsZero() {
+			continue
+		}
+		n := reflect.New(fields.refsTypes[k].Elem())
+		refORM := initIfNeeded(registry, n.Interface().(Entity))
+		refORM.idElem.SetUint(id)
+		refORM.inDB = true
+		field.Set(n)
+	}
+}
+
+func (orm *ORM) fillGeneratedBind(bind Bind, updateBind map[string]string) {
+	orm.value.Interface().(GeneratedEntity).OrmBind(orm.dBData, orm.inDB, bind)
+	if updateBind == nil {
+		return
+	}
+	for column, value := range bind {
+		switch v := value.(type) {
+		case nil:
+			updateBind[column] = "NULL"
+		case uint64:
+			updateBind[column] = strconv.FormatUint(v, 10)
+		case int64:
+			updateBind[column] = strconv.FormatInt(v, 10)
+		case bool:
+			if v {
+				updateBind[column] = "1"
+			} else {
+				updateBind[column] = "0"
+			}
+		case string:
+			if v == "" {
+				updateBind[column] = "''"
+			} else {
+				updateBind[column] = orm.escapeSQLParam(v)
+			}
+		}
+	}
+}
@@ -0,0 +1,550 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type codegenSourceEntity struct {
+	ORM
+	ID      uint
+	Age     uint8
+	Balance int
+	Name    string `orm:"required"`
+	Active  bool
+}
+
+type codegenEntity struct {
+	ORM
+	ID      uint
+	Age     uint8
+	Balance int
+	Name    string
+	Active  bool
+}
+
+func (e *codegenEntity) OrmColumns() string {
+	return "ID,Age,Balance,Name,Active"
+}
+
+func (e *codegenEntity) OrmFill(data []interface{}) {
+	e.ID = uint(data[0].(uint64))
+	e.Age = uint8(data[1].(uint64))
+	e.Balance = int(data[2].(int64))
+	if data[3] == nil {
+		e.Name = ""
+	} else {
+		e.Name = data[3].(string)
+	}
+	e.Active = data[4].(bool)
+}
+
+func (e *codegenEntity) OrmBind(oldData []interface{}, hasOld bool, bind Bind) {
+	if v := uint64(e.Age); !hasOld || oldData[1] != v {
+		bind["Age"] = v
+	}
+	if v := int64(e.Balance); !hasOld || oldData[2] != v {
+		bind["Balance"] = v
+	}
+	if v := e.Name; !hasOld || !(oldData[3] == v || (oldData[3] == nil && v == "")) {
+		if v != "" {
+			bind["Name"] = v
+		} else {
+			bind["Name"] = nil
+		}
+	}
+	if v := e.Active; !hasOld || oldData[4] != v {
+		bind["Active"] = v
+	}
+}
+
+type codegenReflectEntity struct {
+	ORM
+	ID      uint
+	Age     uint8
+	Balance int
+	Name    string
+	Active  bool
+}
+
+type codegenEnumEntity struct {
+	ORM
+	ID    uint
+	Color string `orm:"enum=orm.codegenColor"`
+}
+
+type codegenUnsupportedGeneratedEntity struct {
+	ORM
+	ID    uint
+	Price float64
+}
+
+func (e *codegenUnsupportedGeneratedEntity) OrmColumns() string {
+	return "ID,Price"
+}
+
+func (e *codegenUnsupportedGeneratedEntity) OrmFill(data []interface{}) {
+}
+
+func (e *codegenUnsupportedGeneratedEntity) OrmBind(oldData []interface{}, hasOld bool, bind Bind) {
+}
+
+type codegenUnsupportedEntity struct {
+	ORM
+	ID    uint
+	Price float64
+}
+
+type codegenOutdatedEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func (e *codegenOutdatedEntity) OrmColumns() string {
+	return "ID"
+}
+
+func (e *codegenOutdatedEntity) OrmFill(data []interface{}) {
+}
+
+func (e *codegenOutdatedEntity) OrmBind(oldData []interface{}, hasOld bool, bind Bind) {
+}
+
+type codegenReferenceEntity struct {
+	ORM
+	ID uint
+}
+
+type codegenNullableSourceEntity struct {
+	ORM
+	ID        uint
+	Age       *uint16
+	Balance   *int
+	Active    *bool
+	Born      time.Time
+	Created   time.Time `orm:"time"`
+	Deleted   *time.Time
+	Reference *codegenReferenceEntity
+}
+
+type codegenNullableEntity struct {
+	ORM
+	ID        uint
+	Age       *uint16
+	Balance   *int
+	Active    *bool
+	Born      time.Time
+	Created   time.Time `orm:"time"`
+	Deleted   *time.Time
+	Reference *codegenReferenceEntity
+}
+
+func (e *codegenNullableEntity) OrmColumns() string {
+	return "ID,Age,Balance,Active,Deleted,Born,Created,Reference"
+}
+
+func (e *codegenNullableEntity) OrmFill(data []interface{}) {
+	e.ID = uint(data[0].(uint64))
+	if data[1] == nil {
+		e.Age = nil
+	} else {
+		v := uint16(data[1].(uint64))
+		e.Age = &v
+	}
+	if data[2] == nil {
+		e.Balance = nil
+	} else {
+		v := int(data[2].(int64))
+		e.Balance = &v
+	}
+	if data[3] == nil {
+		e.Active = nil
+	} else {
+		v := data[3].(bool)
+		e.Active = &v
+	}
+	if data[4] == nil {
+		e.Deleted = nil
+	} else {
+		v := GeneratedTime(e, "Deleted", data[4].(string))
+		e.Deleted = &v
+	}
+	if v := data[5].(string); !(e.Born.IsZero() && (v == "0001-01-01" || v == "0001-01-01 00:00:00")) {
+		e.Born = GeneratedTime(e, "Born", v)
+	}
+	if v := data[6].(string); !(e.Created.IsZero() && (v == "0001-01-01" || v == "0001-01-01 00:00:00")) {
+		e.Created = GeneratedTime(e, "Created", v)
+	}
+}
+
+func (e *codegenNullableEntity) OrmBind(oldData []interface{}, hasOld bool, bind Bind) {
+	if e.Age == nil {
+		if !hasOld || oldData[1] != nil {
+			bind["Age"] = nil
+		}
+	} else if v := uint64(*e.Age); !hasOld || oldData[1] != v {
+		bind["Age"] = v
+	}
+	if e.Balance == nil {
+		if !hasOld || oldData[2] != nil {
+			bind["Balance"] = nil
+		}
+	} else if v := int64(*e.Balance); !hasOld || oldData[2] != v {
+		bind["Balance"] = v
+	}
+	if e.Active == nil {
+		if !hasOld || oldData[3] != nil {
+			bind["Active"] = nil
+		}
+	} else if v := *e.Active; !hasOld || oldData[3] != v {
+		bind["Active"] = v
+	}
+	if e.Deleted == nil {
+		if !hasOld || oldData[4] != nil {
+			bind["Deleted"] = nil
+		}
+	} else if v := GeneratedTimeBind(e, "Deleted", *e.Deleted, true); !hasOld || oldData[4] != v {
+		bind["Deleted"] = v
+	}
+	if v := GeneratedTimeBind(e, "Born", e.Born, false); !hasOld || oldData[5] != v {
+		bind["Born"] = v
+	}
+	if v := GeneratedTimeBind(e, "Created", e.Created, false); !hasOld || oldData[6] != v {
+		bind["Created"] = v
+	}
+	if e.Reference != nil && e.Reference.ID != 0 {
+		if v := uint64(e.Reference.ID); !hasOld || oldData[7] != v {
+			bind["Reference"] = v
+		}
+	} else if !hasOld || oldData[7] != nil {
+		bind["Reference"] = nil
+	}
+}
+
+type codegenNullableReflectEntity struct {
+	ORM
+	ID        uint
+	Age       *uint16
+	Balance   *int
+	Active    *bool
+	Born      time.Time
+	Created   time.Time `orm:"time"`
+	Deleted   *time.Time
+	Reference *codegenReferenceEntity
+}
+
+func TestGenerateEntityCode(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &codegenSourceEntity{}, &codegenEntity{}, &codegenUnsupportedEntity{})
+	code, err := GenerateEntityCode(engine.GetRegistry(), "models", &codegenSourceEntity{})
+	assert.NoError(t, err)
+	expected := `// Code generated by orm. DO NOT EDIT.
+
+package models
+
+import "github.com/latolukasz/orm"
+
+func (e *codegenSourceEntity) OrmColumns() string {
+	return "ID,Age,Balance,Name,Active"
+}
+
+func (e *codegenSourceEntity) OrmFill(data []interface{}) {
+	e.ID = uint(data[0].(uint64))
+	e.Age = uint8(data[1].(uint64))
+	e.Balance = int(data[2].(int64))
+	if data[3] == nil {
+		e.Name = ""
+	} else {
+		e.Name = data[3].(string)
+	}
+	e.Active = data[4].(bool)
+}
+
+func (e *codegenSourceEntity) OrmBind(oldData []interface{}, hasOld bool, bind orm.Bind) {
+	if v := uint64(e.Age); !hasOld || oldData[1] != v {
+		bind["Age"] = v
+	}
+	if v := int64(e.Balance); !hasOld || oldData[2] != v {
+		bind["Balance"] = v
+	}
+	if v := e.Name; !hasOld || !(oldData[3] == v || (oldData[3] == nil && v == "")) {
+		if v != "" {
+			bind["Name"] = v
+		} else {
+			bind["Name"] = ""
+		}
+	}
+	if v := e.Active; !hasOld || oldData[4] != v {
+		bind["Active"] = v
+	}
+}
+`
+	assert.Equal(t, expected, string(code))
+	_, err = GenerateEntityCode(engine.GetRegistry(), "models", &codegenUnsupportedEntity{})
+	assert.EqualError(t, err, "entity 'orm.codegenUnsupportedEntity' has fields not supported by code generator")
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&codegenEntity{}).(*tableSchema)
+	assert.True(t, schema.generated)
+	entity := &codegenEntity{Age: 10, Balance: -5, Name: "Tom", Active: true}
+	engine.Flush(entity)
+	entity = &codegenEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint8(10), entity.Age)
+	assert.Equal(t, -5, entity.Balance)
+	assert.Equal(t, "Tom", entity.Name)
+	assert.True(t, entity.Active)
+	assert.False(t, entity.IsDirty())
+
+	entity.Name = "O'Neil"
+	entity.Active = false
+	bind, _ := entity.GetDirtyBind()
+	assert.Equal(t, Bind{"Name": "O'Neil", "Active": false}, bind)
+	engine.Flush(entity)
+	entity = &codegenEntity{}
+	engine.GetLocalCache().Clear()
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "O'Neil", entity.Name)
+	assert.False(t, entity.Active)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&codegenOutdatedEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "generated code for entity 'orm.codegenOutdatedEntity' is outdated")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&codegenUnsupportedGeneratedEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "entity 'orm.codegenUnsupportedGeneratedEntity' has fields not supported by code generator")
+
+	registry = &Registry{}
+	registry.RegisterEnum("orm.codegenColor", []string{"red", "blue"})
+	engine = PrepareTables(t, registry, 5, &codegenEnumEntity{})
+	_, err = GenerateEntityCode(engine.GetRegistry(), "models", &codegenEnumEntity{})
+	assert.EqualError(t, err, "enum field 'Color' in entity 'orm.codegenEnumEntity' is not supported by code generator")
+}
+
+func TestGenerateEntityCodeNullable(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &codegenReferenceEntity{}, &codegenNullableSourceEntity{},
+		&codegenNullableEntity{}, &codegenNullableReflectEntity{})
+	code, err := GenerateEntityCode(engine.GetRegistry(), "models", &codegenNullableSourceEntity{})
+	assert.NoError(t, err)
+	expected := `// Code generated by orm. DO NOT EDIT.
+
+package models
+
+import "github.com/latolukasz/orm"
+
+func (e *codegenNullableSourceEntity) OrmColumns() string {
+	return "ID,Age,Balance,Active,Deleted,Born,Created,Reference"
+}
+
+func (e *codegenNullableSourceEntity) OrmFill(data []interface{}) {
+	e.ID = uint(data[0].(uint64))
+	if data[1] == nil {
+		e.Age = nil
+	} else {
+		v := uint16(data[1].(uint64))
+		e.Age = &v
+	}
+	if data[2] == nil {
+		e.Balance = nil
+	} else {
+		v := int(data[2].(int64))
+		e.Balance = &v
+	}
+	if data[3] == nil {
+		e.Active = nil
+	} else {
+		v := data[3].(bool)
+		e.Active = &v
+	}
+	if data[4] == nil {
+		e.Deleted = nil
+	} else {
+		v := orm.GeneratedTime(e, "Deleted", data[4].(string))
+		e.Deleted = &v
+	}
+	if v := data[5].(string); !(e.Born.IsZero() && (v == "0001-01-01" || v == "0001-01-01 00:00:00")) {
+		e.Born = orm.GeneratedTime(e, "Born", v)
+	}
+	if v := data[6].(string); !(e.Created.IsZero() && (v == "0001-01-01" || v == "0001-01-01 00:00:00")) {
+		e.Created = orm.GeneratedTime(e, "Created", v)
+	}
+}
+
+func (e *codegenNullableSourceEntity) OrmBind(oldData []interface{}, hasOld bool, bind orm.Bind) {
+	if e.Age == nil {
+		if !hasOld || oldData[1] != nil {
+			bind["Age"] = nil
+		}
+	} else if v := uint64(*e.Age); !hasOld || oldData[1] != v {
+		bind["Age"] = v
+	}
+	if e.Balance == nil {
+		if !hasOld || oldData[2] != nil {
+			bind["Balance"] = nil
+		}
+	} else if v := int64(*e.Balance); !hasOld || oldData[2] != v {
+		bind["Balance"] = v
+	}
+	if e.Active == nil {
+		if !hasOld || oldData[3] != nil {
+			bind["Active"] = nil
+		}
+	} else if v := *e.Active; !hasOld || oldData[3] != v {
+		bind["Active"] = v
+	}
+	if e.Deleted == nil {
+		if !hasOld || oldData[4] != nil {
+			bind["Deleted"] = nil
+		}
+	} else if v := orm.GeneratedTimeBind(e, "Deleted", *e.Deleted, true); !hasOld || oldData[4] != v {
+		bind["Deleted"] = v
+	}
+	if v := orm.GeneratedTimeBind(e, "Born", e.Born, false); !hasOld || oldData[5] != v {
+		bind["Born"] = v
+	}
+	if v := orm.GeneratedTimeBind(e, "Created", e.Created, false); !hasOld || oldData[6] != v {
+		bind["Created"] = v
+	}
+	if e.Reference != nil && e.Reference.ID != 0 {
+		if v := uint64(e.Reference.ID); !hasOld || oldData[7] != v {
+			bind["Reference"] = v
+		}
+	} else if !hasOld || oldData[7] != nil {
+		bind["Reference"] = nil
+	}
+}
+`
+	assert.Equal(t, expected, string(code))
+
+	rows := [][]interface{}{
+		{uint64(1), uint64(7), int64(-3), true, "2021-03-04", "2021-03-04", "2021-03-04 10:11:12", uint64(1)},
+		{uint64(2), nil, nil, nil, nil, "0001-01-01", "0001-01-01 00:00:00", nil},
+	}
+	for _, row := range rows {
+		generated := &codegenNullableEntity{}
+		reflective := &codegenNullableReflectEntity{}
+		generatedData := make([]interface{}, len(row))
+		copy(generatedData, row)
+		reflectiveData := make([]interface{}, len(row))
+		copy(reflectiveData, row)
+		fillFromDBRow(row[0].(uint64), engine, generatedData, generated, false)
+		fillFromDBRow(row[0].(uint64), engine, reflectiveData, reflective, false)
+		assert.True(t, generated.getORM().tableSchema.generated)
+		assert.Equal(t, reflective.Age, generated.Age)
+		assert.Equal(t, reflective.Balance, generated.Balance)
+		assert.Equal(t, reflective.Active, generated.Active)
+		assert.Equal(t, reflective.Born, generated.Born)
+		assert.Equal(t, reflective.Created, generated.Created)
+		assert.Equal(t, reflective.Deleted, generated.Deleted)
+		assert.Equal(t, reflective.Reference == nil, generated.Reference == nil)
+		if reflective.Reference != nil {
+			assert.Equal(t, reflective.Reference.GetID(), generated.Reference.GetID())
+		}
+		generatedBind, _ := generated.GetDirtyBind()
+		reflectiveBind, _ := reflective.GetDirtyBind()
+		assert.Equal(t, reflectiveBind, generatedBind)
+
+		age, balance, active, deleted := uint16(200), -10, false, time.Date(2022, 1, 2, 0, 0, 0, 0, time.Local)
+		generated.Age, reflective.Age = &age, &age
+		generated.Balance, reflective.Balance = &balance, &balance
+		generated.Active, reflective.Active = &active, &active
+		generated.Deleted, reflective.Deleted = &deleted, &deleted
+		generated.Born, reflective.Born = time.Time{}, time.Time{}
+		generated.Created, reflective.Created = deleted, deleted
+		generated.Reference, reflective.Reference = nil, nil
+		generatedBind, generatedUpdate, _ := generated.getORM().getDirtyBind()
+		reflectiveBind, reflectiveUpdate, _ := reflective.getORM().getDirtyBind()
+		assert.Equal(t, reflectiveBind, generatedBind)
+		assert.Equal(t, reflectiveUpdate, generatedUpdate)
+		releaseUpdateBind(generatedUpdate)
+		releaseUpdateBind(reflectiveUpdate)
+	}
+
+	reference := &codegenReferenceEntity{}
+	engine.Flush(reference)
+	created := time.Date(2021, 3, 4, 10, 11, 12, 0, time.Local)
+	age := uint16(10)
+	entity := &codegenNullableEntity{Age: &age, Born: created, Created: created, Deleted: &created, Reference: reference}
+	engine.Flush(entity)
+	entity = &codegenNullableEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint16(10), *entity.Age)
+	assert.Nil(t, entity.Balance)
+	assert.Nil(t, entity.Active)
+	assert.Equal(t, "2021-03-04", entity.Born.Format("2006-01-02"))
+	assert.Equal(t, created.Unix(), entity.Created.Unix())
+	assert.Equal(t, "2021-03-04", entity.Deleted.Format("2006-01-02"))
+	assert.Equal(t, uint64(1), entity.Reference.GetID())
+	assert.False(t, entity.IsDirty())
+}
+
+func TestGeneratedEntityParity(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &codegenEntity{}, &codegenReflectEntity{})
+	rows := [][]interface{}{
+		{uint64(1), uint64(7), int64(-3), "Tom", true},
+		{uint64(2), uint64(0), int64(0), nil, false},
+	}
+	for _, row := range rows {
+		generated := &codegenEntity{}
+		reflective := &codegenReflectEntity{}
+		generatedData := make([]interface{}, len(row))
+		copy(generatedData, row)
+		reflectiveData := make([]interface{}, len(row))
+		copy(reflectiveData, row)
+		fillFromDBRow(row[0].(uint64), engine, generatedData, generated, false)
+		fillFromDBRow(row[0].(uint64), engine, reflectiveData, reflective, false)
+		assert.True(t, generated.getORM().tableSchema.generated)
+		assert.False(t, reflective.getORM().tableSchema.generated)
+		assert.Equal(t, []interface{}{reflective.ID, reflective.Age, reflective.Balance, reflective.Name, reflective.Active},
+			[]interface{}{generated.ID, generated.Age, generated.Balance, generated.Name, generated.Active})
+		generatedBind, generatedUpdate, _ := generated.getORM().getDirtyBind()
+		reflectiveBind, reflectiveUpdate, _ := reflective.getORM().getDirtyBind()
+		assert.Equal(t, reflectiveBind, generatedBind)
+		assert.Equal(t, reflectiveUpdate, generatedUpdate)
+		releaseUpdateBind(generatedUpdate)
+		releaseUpdateBind(reflectiveUpdate)
+
+		generated.Age, reflective.Age = 200, 200
+		generated.Balance, reflective.Balance = -10, -10
+		generated.Name, reflective.Name = "", ""
+		generated.Active, reflective.Active = !generated.Active, !reflective.Active
+		generatedBind, generatedUpdate, _ = generated.getORM().getDirtyBind()
+		reflectiveBind, reflectiveUpdate, _ = reflective.getORM().getDirtyBind()
+		assert.Equal(t, reflectiveBind, generatedBind)
+		assert.Equal(t, reflectiveUpdate, generatedUpdate)
+		releaseUpdateBind(generatedUpdate)
+		releaseUpdateBind(reflectiveUpdate)
+	}
+
+	generated := &codegenEntity{Age: 3, Name: "O'Neil"}
+	reflective := &codegenReflectEntity{Age: 3, Name: "O'Neil"}
+	initIfNeeded(engine.registry, generated)
+	initIfNeeded(engine.registry, reflective)
+	generatedBind, _ := generated.GetDirtyBind()
+	reflectiveBind, _ := reflective.GetDirtyBind()
+	assert.Equal(t, reflectiveBind, generatedBind)
+}
+
+func BenchmarkLoadByIDReflection(b *testing.B) {
+	benchmarkLoadByIDCodegen(b, &codegenSourceEntity{Name: "Tom"}, &codegenSourceEntity{})
+}
+
+func BenchmarkLoadByIDGenerated(b *testing.B) {
+	benchmarkLoadByIDCodegen(b, &codegenEntity{Name: "Tom"}, &codegenEntity{})
+}
+
+func benchmarkLoadByIDCodegen(b *testing.B, source, entity Entity) {
+	engine := PrepareTables(nil, &Registry{}, 5, source)
+	engine.Flush(source)
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		engine.LoadByID(1, entity)
+	}
+}
@@ -0,0 +1,105 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const counterKeyPrefix = "orm_counter:"
+
+const counterUpdateScript = `
+for i = 1, #KEYS do
+	if redis.call('exists', KEYS[i]) == 1 then
+		redis.call('incrby', KEYS[i], ARGV[i])
+	end
+end
+return 1
+`
+
+func initCounter(registry *Registry, tags map[string]string, redisCache string, entityType reflect.Type) (string, error) {
+	_, has := tags["counter"]
+	if !has {
+		return "", nil
+	}
+	if redisCache != "" {
+		return redisCache, nil
+	}
+	_, has = registry.redisPools["default"]
+	if !has {
+		return "", fmt.Errorf("counter in entity '%s' requires redis pool", entityType.String())
+	}
+	return "default", nil
+}
+
+func (tableSchema *tableSchema) GetApproxCount(engine *Engine) int {
+	tableSchema.checkCounter()
+	value, has := engine.GetRedis(tableSchema.counterPool).Get(tableSchema.getCounterKey())
+	if !has {
+		return tableSchema.ReconcileCounter(engine)
+	}
+	count, _ := strconv.Atoi(value)
+	return count
+}
+
+func (tableSchema *tableSchema) ReconcileCounter(engine *Engine) int {
+	tableSchema.checkCounter()
+	where := NewWhere("1")
+	if tableSchema.hasFakeDelete {
+		where = NewWhere("`FakeDelete` = 0")
+	}
+	var count int
+	/* #nosec */
+	tableSchema.GetMysql(engine).QueryRow(NewWhere("SELECT COUNT(1) FROM `"+tableSchema.tableName+"` WHERE "+where.String()), &count)
+	engine.GetRedis(tableSchema.counterPool).Set(tableSchema.getCounterKey(), count, 0)
+	return count
+}
+
+func (tableSchema *tableSchema) checkCounter() {
+	if tableSchema.counterPool == "" {
+		panic(fmt.Errorf("counter is not enabled in entity '%s'", tableSchema.t.String()))
+	}
+}
+
+func (tableSchema *tableSchema) getCounterKey() string {
+	return counterKeyPrefix + tableSchema.cachePrefix
+}
+
+func (f *flusher) updateCounter(schema *tableSchema, delta int64) {
+	if schema.counterPool == "" {
+		return
+	}
+	f.getRedisFlusher().incrBy(schema.counterPool, schema.getCounterKey(), delta)
+}
+
+type CounterReconciler struct {
+	engine   *Engine
+	interval time.Duration
+}
+
+func NewCounterReconciler(engine *Engine, interval time.Duration) *CounterReconciler {
+	return &CounterReconciler{engine: engine, interval: interval}
+}
+
+func (r *CounterReconciler) Reconcile() {
+	for _, schema := range r.engine.registry.tableSchemas {
+		if schema.counterPool != "" {
+			schema.ReconcileCounter(r.engine)
+		}
+	}
+}
+
+func (r *CounterReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.Reconcile()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type counterEntity struct {
+	ORM        `orm:"counter"`
+	ID         uint
+	Name       string
+	FakeDelete bool
+}
+
+type counterDisabledEntity struct {
+	ORM
+	ID uint
+}
+
+func TestApproxCounter(t *testing.T) {
+	var entity *counterEntity
+	var disabled *counterDisabledEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity, disabled)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	engine.FlushMany(&counterEntity{Name: "a"}, &counterEntity{Name: "b"})
+	assert.Equal(t, 2, schema.GetApproxCount(engine))
+
+	engine.FlushMany(&counterEntity{Name: "c"}, &counterEntity{Name: "d"}, &counterEntity{Name: "e"})
+	assert.Equal(t, 5, schema.GetApproxCount(engine))
+
+	entity = &counterEntity{}
+	engine.LoadByID(1, entity)
+	engine.Delete(entity)
+	assert.Equal(t, 4, schema.GetApproxCount(engine))
+	engine.LoadByID(2, entity)
+	engine.ForceDelete(entity)
+	assert.Equal(t, 3, schema.GetApproxCount(engine))
+
+	engine.GetMysql().Exec("DELETE FROM `counterEntity` WHERE `ID` = 3")
+	assert.Equal(t, 3, schema.GetApproxCount(engine))
+	reconciler := NewCounterReconciler(engine, time.Millisecond)
+	reconciler.Reconcile()
+	assert.Equal(t, 2, schema.GetApproxCount(engine))
+
+	engine.GetMysql().Exec("DELETE FROM `counterEntity` WHERE `ID` = 4")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	reconciler.Run(ctx)
+	assert.Equal(t, 1, schema.GetApproxCount(engine))
+
+	assert.PanicsWithError(t, "counter is not enabled in entity 'orm.counterDisabledEntity'", func() {
+		engine.GetRegistry().GetTableSchemaForEntity(disabled).GetApproxCount(engine)
+	})
+}
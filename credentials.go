@@ -0,0 +1,148 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-sql-driver/mysql"
+)
+
+type CredentialsProvider func() (user, password string)
+
+type credentials struct {
+	provider CredentialsProvider
+	mutex    sync.Mutex
+	loaded   bool
+	user     string
+	password string
+}
+
+func (c *credentials) get(refresh bool) (user, password string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if refresh || !c.loaded {
+		c.user, c.password = c.provider()
+		c.loaded = true
+	}
+	return c.user, c.password
+}
+
+func (c *credentials) rotate() {
+	c.mutex.Lock()
+	c.loaded = false
+	c.mutex.Unlock()
+}
+
+func (r *Registry) RegisterMySQLCredentialsProvider(provider CredentialsProvider, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	pool, has := r.mysqlPools[dbCode]
+	if !has {
+		panic(fmt.Errorf("mysql pool '%s' is not registered", dbCode))
+	}
+	pool.(*mySQLPoolConfig).credentials = &credentials{provider: provider}
+}
+
+func (r *Registry) RegisterRedisCredentialsProvider(provider CredentialsProvider, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	pool, has := r.redisPools[dbCode]
+	if !has {
+		panic(fmt.Errorf("redis pool '%s' is not registered", dbCode))
+	}
+	config := pool.(*redisCacheConfig)
+	config.credentials = &credentials{provider: provider}
+	options := config.client.Options()
+	onConnect := options.OnConnect
+	options.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+		err := authRedis(ctx, cn, config.credentials, false)
+		if err != nil {
+			err = authRedis(ctx, cn, config.credentials, true)
+			if err != nil {
+				return err
+			}
+		}
+		if onConnect != nil {
+			return onConnect(ctx, cn)
+		}
+		return nil
+	}
+}
+
+func (e *Engine) RotateCredentials() {
+	for _, pool := range e.registry.mySQLServers {
+		if c := pool.(*mySQLPoolConfig).credentials; c != nil {
+			c.rotate()
+		}
+	}
+	for _, pool := range e.registry.redisServers {
+		if c := pool.(*redisCacheConfig).credentials; c != nil {
+			c.rotate()
+		}
+	}
+}
+
+func authRedis(ctx context.Context, cn *redis.Conn, c *credentials, refresh bool) error {
+	user, password := c.get(refresh)
+	if user == "" && password == "" {
+		return nil
+	}
+	if user == "" {
+		return cn.Auth(ctx, password).Err()
+	}
+	return cn.AuthACL(ctx, user, password).Err()
+}
+
+type credentialsConnector struct {
+	driver      driver.Driver
+	dsn         string
+	credentials *credentials
+}
+
+func openWithCredentials(driverName, dsn string, c *credentials) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	_ = db.Close()
+	return sql.OpenDB(&credentialsConnector{driver: d, dsn: dsn, credentials: c}), nil
+}
+
+func (c *credentialsConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connect(ctx, false)
+	if err != nil {
+		return c.connect(ctx, true)
+	}
+	return conn, nil
+}
+
+func (c *credentialsConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c *credentialsConnector) connect(ctx context.Context, refresh bool) (driver.Conn, error) {
+	config, err := mysql.ParseDSN(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	config.User, config.Passwd = c.credentials.get(refresh)
+	dsn := config.FormatDSN()
+	driverContext, is := c.driver.(driver.DriverContext)
+	if !is {
+		return c.driver.Open(dsn)
+	}
+	connector, err := driverContext.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
@@ -0,0 +1,54 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialsProvider(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterMySQLPool("invalid:invalid@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 15)
+	mysqlCalls := 0
+	registry.RegisterMySQLCredentialsProvider(func() (string, string) {
+		mysqlCalls++
+		if mysqlCalls == 1 {
+			return "root", "invalid"
+		}
+		return "root", "root"
+	})
+	redisCalls := 0
+	registry.RegisterRedisCredentialsProvider(func() (string, string) {
+		redisCalls++
+		return "", ""
+	})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mysqlCalls)
+	engine := validatedRegistry.CreateEngine()
+	db := engine.GetMysql()
+	db.SetMaxIdleConns(0)
+	var one int
+	db.QueryRow(NewWhere("SELECT 1"), &one)
+	assert.Equal(t, 1, one)
+	assert.Equal(t, 2, mysqlCalls)
+
+	engine.RotateCredentials()
+	db.QueryRow(NewWhere("SELECT 1"), &one)
+	assert.Equal(t, 3, mysqlCalls)
+	db.QueryRow(NewWhere("SELECT 1"), &one)
+	assert.Equal(t, 3, mysqlCalls)
+
+	engine.GetRedis().Set("credentials", "ok", 10)
+	value, has := engine.GetRedis().Get("credentials")
+	assert.True(t, has)
+	assert.Equal(t, "ok", value)
+	assert.Equal(t, 1, redisCalls)
+
+	assert.PanicsWithError(t, "mysql pool 'another' is not registered", func() {
+		registry.RegisterMySQLCredentialsProvider(func() (string, string) {
+			return "", ""
+		}, "another")
+	})
+}
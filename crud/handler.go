@@ -0,0 +1,218 @@
+package crud
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/latolukasz/orm"
+)
+
+const defaultPageSize = 100
+const maxPageSize = 1000
+
+type EngineProvider func(r *http.Request) *orm.Engine
+
+type Handler struct {
+	prefix   string
+	engine   EngineProvider
+	entities map[string]reflect.Type
+}
+
+func Mount(mux *http.ServeMux, prefix string, registry orm.ValidatedRegistry, engine EngineProvider) *Handler {
+	prefix = "/" + strings.Trim(prefix, "/")
+	handler := &Handler{prefix: prefix, engine: engine, entities: make(map[string]reflect.Type)}
+	for _, t := range registry.GetEntities() {
+		schema := registry.GetTableSchema(t.String())
+		handler.entities[schema.GetTableName()] = t
+	}
+	mux.Handle(prefix+"/", handler)
+	return handler
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("%v", rec))
+		}
+	}()
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, h.prefix), "/"), "/")
+	t, has := h.entities[parts[0]]
+	if !has || len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	engine := h.engine(r)
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.list(w, r, engine, t)
+		case http.MethodPost:
+			h.save(w, r, engine, newEntity(t), http.StatusCreated)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	entity := newEntity(t)
+	if !engine.LoadByID(id, entity) {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, toMap(reflect.ValueOf(entity).Elem()))
+	case http.MethodPut, http.MethodPatch:
+		h.save(w, r, engine, entity, http.StatusOK)
+	case http.MethodDelete:
+		engine.Delete(entity)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, engine *orm.Engine, t reflect.Type) {
+	query := r.URL.Query()
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	} else if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	schema := engine.GetRegistry().GetTableSchema(t.String())
+	conditions := make([]*orm.Where, 0)
+	for _, column := range schema.GetColumns() {
+		value, has := query[column]
+		if has {
+			conditions = append(conditions, orm.W.Eq(column, value[0]))
+		}
+	}
+	where := orm.NewWhere("1")
+	if len(conditions) > 0 {
+		where = conditions[0].And(conditions[1:]...)
+	}
+	rows := reflect.New(reflect.SliceOf(reflect.PtrTo(t)))
+	total := engine.SearchWithCount(where, orm.NewPager(page, limit), rows.Interface())
+	results := make([]map[string]interface{}, rows.Elem().Len())
+	for i := range results {
+		results[i] = toMap(rows.Elem().Index(i).Elem())
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total": total, "page": page, "limit": limit, "rows": results})
+}
+
+func (h *Handler) save(w http.ResponseWriter, r *http.Request, engine *orm.Engine, entity orm.Entity, status int) {
+	fields := make(map[string]interface{})
+	decoder := jsoniter.ConfigFastest.NewDecoder(r.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&fields)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	for field, value := range fields {
+		if field == "ID" {
+			continue
+		}
+		err = entity.SetField(field, convertTime(entity, field, value))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	err = engine.FlushWithCheck(entity)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, status, toMap(reflect.ValueOf(entity).Elem()))
+}
+
+func convertTime(entity orm.Entity, field string, value interface{}) interface{} {
+	asString, isString := value.(string)
+	structField, has := reflect.TypeOf(entity).Elem().FieldByName(field)
+	if !isString || !has || (structField.Type.String() != "time.Time" && structField.Type.String() != "*time.Time") {
+		return value
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		parsed, err := time.ParseInLocation(layout, asString, time.Local)
+		if err == nil {
+			if structField.Type.Kind() == reflect.Ptr {
+				return &parsed
+			}
+			return parsed
+		}
+	}
+	return value
+}
+
+func newEntity(t reflect.Type) orm.Entity {
+	return reflect.New(t).Interface().(orm.Entity)
+}
+
+func toMap(value reflect.Value) map[string]interface{} {
+	entityType := reflect.TypeOf((*orm.Entity)(nil)).Elem()
+	result := make(map[string]interface{})
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || field.Anonymous || field.Type.String() == "*orm.CachedQuery" {
+			continue
+		}
+		if isIgnored(field) {
+			continue
+		}
+		fieldValue := value.Field(i)
+		switch {
+		case field.Type.Kind() == reflect.Ptr && field.Type.Implements(entityType):
+			if fieldValue.IsNil() {
+				result[field.Name] = nil
+			} else {
+				result[field.Name] = fieldValue.Interface().(orm.Entity).GetID()
+			}
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Implements(entityType):
+			ids := make([]uint64, fieldValue.Len())
+			for j := range ids {
+				ids[j] = fieldValue.Index(j).Interface().(orm.Entity).GetID()
+			}
+			result[field.Name] = ids
+		case field.Type.Kind() == reflect.Struct && field.Type.String() != "time.Time" && field.Type.PkgPath() != "github.com/latolukasz/orm":
+			result[field.Name] = toMap(fieldValue)
+		default:
+			result[field.Name] = fieldValue.Interface()
+		}
+	}
+	return result
+}
+
+func isIgnored(field reflect.StructField) bool {
+	for _, attribute := range strings.Split(field.Tag.Get("orm"), ";") {
+		if attribute == "ignore" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = jsoniter.ConfigFastest.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,91 @@
+package crud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+type crudEntity struct {
+	orm.ORM
+	ID     uint
+	Name   string `orm:"required;unique=Name"`
+	Age    uint8
+	Secret string `orm:"ignore"`
+	Parent *crudEntity
+}
+
+func TestHandler(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&crudEntity{})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	for _, alter := range engine.GetAlters() {
+		engine.GetMysql(alter.Pool).Exec(alter.SQL)
+	}
+	engine.GetMysql().Exec("DELETE FROM `crudEntity`")
+	engine.GetMysql().Exec("ALTER TABLE `crudEntity` AUTO_INCREMENT = 1")
+
+	mux := http.NewServeMux()
+	Mount(mux, "/admin", validatedRegistry, func(r *http.Request) *orm.Engine {
+		return validatedRegistry.CreateEngine()
+	})
+	call := func(method, path, body string) (int, map[string]interface{}) {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		result := make(map[string]interface{})
+		_ = jsoniter.ConfigFastest.Unmarshal(recorder.Body.Bytes(), &result)
+		return recorder.Code, result
+	}
+
+	code, result := call(http.MethodPost, "/admin/crudEntity", `{"Name": "Tom", "Age": 18}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, float64(1), result["ID"])
+	assert.Equal(t, "Tom", result["Name"])
+	assert.NotContains(t, result, "Secret")
+	code, result = call(http.MethodPost, "/admin/crudEntity", `{"Name": "John", "Age": 20, "Parent": 1}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, float64(1), result["Parent"])
+	code, result = call(http.MethodPost, "/admin/crudEntity", `{"Name": "Tom"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["error"], "Duplicate entry")
+	code, result = call(http.MethodPost, "/admin/crudEntity", `{"Invalid": "Tom"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "field Invalid not found", result["error"])
+
+	code, result = call(http.MethodGet, "/admin/crudEntity/2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "John", result["Name"])
+	code, _ = call(http.MethodGet, "/admin/crudEntity/3", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = call(http.MethodGet, "/admin/unknown", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, result = call(http.MethodGet, "/admin/crudEntity?limit=1&page=2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), result["total"])
+	assert.Len(t, result["rows"], 1)
+	code, result = call(http.MethodGet, "/admin/crudEntity?Age=18", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), result["total"])
+
+	code, result = call(http.MethodPatch, "/admin/crudEntity/1", `{"Age": 19}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(19), result["Age"])
+	assert.Equal(t, "Tom", result["Name"])
+
+	code, _ = call(http.MethodDelete, "/admin/crudEntity/2", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	code, _ = call(http.MethodGet, "/admin/crudEntity/2", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = call(http.MethodPut, "/admin/crudEntity", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
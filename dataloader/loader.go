@@ -0,0 +1,228 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/latolukasz/orm"
+)
+
+const DefaultWait = time.Millisecond
+const DefaultMaxBatch = 1000
+
+type contextKey struct{}
+
+type Loaders struct {
+	engine     *orm.Engine
+	engineLock sync.Mutex
+	wait       time.Duration
+	maxBatch   int
+	loaders    map[reflect.Type]*Loader
+	lock       sync.Mutex
+}
+
+type Loader struct {
+	loaders    *Loaders
+	entityType reflect.Type
+	cache      map[uint64]orm.Entity
+	batch      *loaderBatch
+	lock       sync.Mutex
+}
+
+type loaderBatch struct {
+	ids     []uint64
+	keys    map[uint64]bool
+	results map[uint64]orm.Entity
+	err     error
+	closing bool
+	done    chan struct{}
+}
+
+func NewLoaders(engine *orm.Engine, wait time.Duration, maxBatch int) *Loaders {
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatch
+	}
+	return &Loaders{engine: engine, wait: wait, maxBatch: maxBatch, loaders: make(map[reflect.Type]*Loader)}
+}
+
+func Middleware(engine func(r *http.Request) *orm.Engine, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loaders := NewLoaders(engine(r), DefaultWait, DefaultMaxBatch)
+		next.ServeHTTP(w, r.WithContext(WithLoaders(r.Context(), loaders)))
+	})
+}
+
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, contextKey{}, loaders)
+}
+
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(contextKey{}).(*Loaders)
+	return loaders
+}
+
+func (l *Loaders) Get(entity orm.Entity) *Loader {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	loader, has := l.loaders[t]
+	if !has {
+		loader = &Loader{loaders: l, entityType: t, cache: make(map[uint64]orm.Entity)}
+		l.loaders[t] = loader
+	}
+	return loader
+}
+
+func (l *Loaders) LoadReference(entity orm.Entity, field string) (orm.Entity, error) {
+	value := reflect.ValueOf(entity).Elem().FieldByName(field)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("field %s not found", field)
+	}
+	reference, isEntity := value.Interface().(orm.Entity)
+	if !isEntity {
+		return nil, fmt.Errorf("field %s is not reference", field)
+	}
+	if value.IsNil() || reference.GetID() == 0 {
+		return nil, nil
+	}
+	return l.Get(reference).Load(reference.GetID())
+}
+
+func (l *Loaders) LoadReferences(entity orm.Entity, field string) ([]orm.Entity, []error) {
+	value := reflect.ValueOf(entity).Elem().FieldByName(field)
+	if !value.IsValid() || value.Kind() != reflect.Slice {
+		return nil, []error{fmt.Errorf("field %s is not references slice", field)}
+	}
+	if value.Len() == 0 {
+		return []orm.Entity{}, nil
+	}
+	ids := make([]uint64, value.Len())
+	for i := range ids {
+		ids[i] = value.Index(i).Interface().(orm.Entity).GetID()
+	}
+	return l.Get(reflect.New(value.Type().Elem().Elem()).Interface().(orm.Entity)).LoadAll(ids)
+}
+
+func (l *Loader) Load(id uint64) (orm.Entity, error) {
+	return l.LoadThunk(id)()
+}
+
+func (l *Loader) LoadAll(ids []uint64) ([]orm.Entity, []error) {
+	thunks := make([]func() (orm.Entity, error), len(ids))
+	for i, id := range ids {
+		thunks[i] = l.LoadThunk(id)
+	}
+	results := make([]orm.Entity, len(ids))
+	var errors []error
+	for i, thunk := range thunks {
+		result, err := thunk()
+		results[i] = result
+		if err != nil {
+			if errors == nil {
+				errors = make([]error, len(ids))
+			}
+			errors[i] = err
+		}
+	}
+	return results, errors
+}
+
+func (l *Loader) LoadThunk(id uint64) func() (orm.Entity, error) {
+	l.lock.Lock()
+	entity, has := l.cache[id]
+	if has {
+		l.lock.Unlock()
+		return func() (orm.Entity, error) {
+			return entity, nil
+		}
+	}
+	if l.batch == nil {
+		l.batch = &loaderBatch{keys: make(map[uint64]bool), done: make(chan struct{})}
+	}
+	batch := l.batch
+	batch.add(l, id)
+	l.lock.Unlock()
+	return func() (orm.Entity, error) {
+		<-batch.done
+		if batch.err != nil {
+			return nil, batch.err
+		}
+		entity := batch.results[id]
+		l.lock.Lock()
+		l.cache[id] = entity
+		l.lock.Unlock()
+		return entity, nil
+	}
+}
+
+func (l *Loader) Prime(entity orm.Entity) {
+	l.lock.Lock()
+	l.cache[entity.GetID()] = entity
+	l.lock.Unlock()
+}
+
+func (l *Loader) Clear(id uint64) {
+	l.lock.Lock()
+	delete(l.cache, id)
+	l.lock.Unlock()
+}
+
+func (b *loaderBatch) add(l *Loader, id uint64) {
+	if b.keys[id] {
+		return
+	}
+	b.keys[id] = true
+	b.ids = append(b.ids, id)
+	if len(b.ids) == 1 {
+		go b.startTimer(l)
+	}
+	if len(b.ids) >= l.loaders.maxBatch && !b.closing {
+		b.closing = true
+		l.batch = nil
+		go b.end(l)
+	}
+}
+
+func (b *loaderBatch) startTimer(l *Loader) {
+	time.Sleep(l.loaders.wait)
+	l.lock.Lock()
+	if b.closing {
+		l.lock.Unlock()
+		return
+	}
+	b.closing = true
+	l.batch = nil
+	l.lock.Unlock()
+	b.end(l)
+}
+
+func (b *loaderBatch) end(l *Loader) {
+	defer close(b.done)
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, isErr := rec.(error)
+			if !isErr {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			b.err = asErr
+		}
+	}()
+	rows := reflect.New(reflect.SliceOf(reflect.PtrTo(l.entityType)))
+	l.loaders.engineLock.Lock()
+	defer l.loaders.engineLock.Unlock()
+	l.loaders.engine.LoadByIDs(b.ids, rows.Interface())
+	b.results = make(map[uint64]orm.Entity, len(b.ids))
+	for i, id := range b.ids {
+		row := rows.Elem().Index(i)
+		if !row.IsNil() {
+			b.results[id] = row.Interface().(orm.Entity)
+		}
+	}
+}
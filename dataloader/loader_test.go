@@ -0,0 +1,81 @@
+package dataloader
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+type loaderEntity struct {
+	orm.ORM
+	ID      uint
+	Name    string
+	Parent  *loaderEntity
+	Friends []*loaderEntity
+}
+
+func TestLoader(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&loaderEntity{})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	for _, alter := range engine.GetAlters() {
+		engine.GetMysql(alter.Pool).Exec(alter.SQL)
+	}
+	engine.GetMysql().Exec("SET FOREIGN_KEY_CHECKS = 0")
+	engine.GetMysql().Exec("TRUNCATE TABLE `loaderEntity`")
+	engine.GetMysql().Exec("SET FOREIGN_KEY_CHECKS = 1")
+	parent := &loaderEntity{Name: "parent"}
+	engine.Flush(parent)
+	child := &loaderEntity{Name: "child", Parent: parent, Friends: []*loaderEntity{parent}}
+	engine.Flush(child)
+	engine.Flush(&loaderEntity{Name: "other"})
+
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, orm.QueryLoggerSourceDB)
+	loaders := NewLoaders(engine, time.Millisecond*5, 100)
+	loader := loaders.Get(&loaderEntity{})
+	results := make([]orm.Entity, 4)
+	var wg sync.WaitGroup
+	for i, id := range []uint64{1, 2, 3, 4} {
+		wg.Add(1)
+		go func(i int, id uint64) {
+			defer wg.Done()
+			results[i], _ = loader.Load(id)
+		}(i, id)
+	}
+	wg.Wait()
+	assert.Len(t, dbLogger.Entries, 1)
+	assert.Equal(t, "parent", results[0].(*loaderEntity).Name)
+	assert.Equal(t, "other", results[2].(*loaderEntity).Name)
+	assert.Nil(t, results[3])
+
+	reference, err := loaders.LoadReference(results[1], "Parent")
+	assert.NoError(t, err)
+	assert.Same(t, results[0], reference)
+	reference, err = loaders.LoadReference(results[0], "Parent")
+	assert.NoError(t, err)
+	assert.Nil(t, reference)
+	references, errors := loaders.LoadReferences(results[1], "Friends")
+	assert.Nil(t, errors)
+	assert.Len(t, references, 1)
+	assert.Same(t, results[0], references[0])
+	assert.Len(t, dbLogger.Entries, 1)
+	_, err = loaders.LoadReference(results[1], "Name")
+	assert.EqualError(t, err, "field Name is not reference")
+
+	loader.Clear(1)
+	rows, errors := loader.LoadAll([]uint64{1, 2})
+	assert.Nil(t, errors)
+	assert.Len(t, dbLogger.Entries, 2)
+	assert.Equal(t, "parent", rows[0].(*loaderEntity).Name)
+	assert.Same(t, results[1], rows[1])
+}
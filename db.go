@@ -1,8 +1,11 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
 	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -24,12 +27,15 @@ type MySQLPoolConfig interface {
 
 type mySQLPoolConfig struct {
 	dataSourceName string
+	driverName     string
 	code           string
 	databaseName   string
 	client         *sql.DB
 	autoincrement  uint64
 	version        int
 	maxConnections int
+	waitCount      int64
+	credentials    *credentials
 }
 
 func (p *mySQLPoolConfig) GetCode() string {
@@ -107,16 +113,41 @@ type dbClientTX interface {
 	Rollback() error
 }
 
+type dbClientQueryContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type dbClientBeginContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
 type standardSQLClient struct {
-	db dbClient
-	tx dbClientTX
+	db  dbClient
+	tx  dbClientTX
+	ctx context.Context
+}
+
+func (db *standardSQLClient) client() dbClientQuery {
+	if db.tx != nil {
+		return db.tx
+	}
+	return db.db
 }
 
 func (db *standardSQLClient) Begin() error {
 	if db.tx != nil {
 		return errors.New("transaction already started")
 	}
-	tx, err := db.db.Begin()
+	var tx *sql.Tx
+	var err error
+	withContext, is := db.db.(dbClientBeginContext)
+	if is && db.ctx != nil {
+		tx, err = withContext.BeginTx(db.ctx, nil)
+	} else {
+		tx, err = db.db.Begin()
+	}
 	if err != nil {
 		return err
 	}
@@ -149,14 +180,15 @@ func (db *standardSQLClient) Rollback() (bool, error) {
 }
 
 func (db *standardSQLClient) Exec(query string, args ...interface{}) (sql.Result, error) {
-	if db.tx != nil {
-		res, err := db.tx.Exec(query, args...)
-		if err != nil {
-			return nil, err
-		}
-		return res, nil
+	client := db.client()
+	var res sql.Result
+	var err error
+	withContext, is := client.(dbClientQueryContext)
+	if is && db.ctx != nil {
+		res, err = withContext.ExecContext(db.ctx, query, args...)
+	} else {
+		res, err = client.Exec(query, args...)
 	}
-	res, err := db.db.Exec(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,21 +196,24 @@ func (db *standardSQLClient) Exec(query string, args ...interface{}) (sql.Result
 }
 
 func (db *standardSQLClient) QueryRow(query string, args ...interface{}) SQLRow {
-	if db.tx != nil {
-		return db.tx.QueryRow(query, args...)
+	client := db.client()
+	withContext, is := client.(dbClientQueryContext)
+	if is && db.ctx != nil {
+		return withContext.QueryRowContext(db.ctx, query, args...)
 	}
-	return db.db.QueryRow(query, args...)
+	return client.QueryRow(query, args...)
 }
 
 func (db *standardSQLClient) Query(query string, args ...interface{}) (SQLRows, error) {
-	if db.tx != nil {
-		rows, err := db.tx.Query(query, args...)
-		if err != nil {
-			return nil, err
-		}
-		return rows, nil
+	client := db.client()
+	var rows *sql.Rows
+	var err error
+	withContext, is := client.(dbClientQueryContext)
+	if is && db.ctx != nil {
+		rows, err = withContext.QueryContext(db.ctx, query, args...)
+	} else {
+		rows, err = client.Query(query, args...)
 	}
-	rows, err := db.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -201,10 +236,15 @@ type Rows interface {
 
 type rowsStruct struct {
 	sqlRows SQLRows
+	counter int64
 }
 
 func (r *rowsStruct) Next() bool {
-	return r.sqlRows.Next()
+	if r.sqlRows.Next() {
+		r.counter++
+		return true
+	}
+	return false
 }
 
 func (r *rowsStruct) Columns() []string {
@@ -233,11 +273,39 @@ func (db *DB) GetPoolConfig() MySQLPoolConfig {
 	return db.config
 }
 
+func (db *DB) Stats() sql.DBStats {
+	stats := db.config.getClient().Stats()
+	config := db.config.(*mySQLPoolConfig)
+	previous := atomic.SwapInt64(&config.waitCount, stats.WaitCount)
+	if stats.WaitCount > previous {
+		db.engine.Log().Warn("mysql pool "+config.code+" is waiting for connections", log2.Fields{"pool": config.code,
+			"wait_count": stats.WaitCount, "new_waits": stats.WaitCount - previous, "wait_duration": stats.WaitDuration.Microseconds(),
+			"max_open": stats.MaxOpenConnections, "in_use": stats.InUse, "idle": stats.Idle})
+	}
+	return stats
+}
+
+func (db *DB) SetMaxOpenConns(n int) {
+	db.config.getClient().SetMaxOpenConns(n)
+}
+
+func (db *DB) SetMaxIdleConns(n int) {
+	db.config.getClient().SetMaxIdleConns(n)
+}
+
+func (db *DB) SetConnMaxLifetime(d time.Duration) {
+	db.config.getClient().SetConnMaxLifetime(d)
+}
+
+func (db *DB) SetConnMaxIdleTime(d time.Duration) {
+	db.config.getClient().SetConnMaxIdleTime(d)
+}
+
 func (db *DB) Begin() {
 	start := time.Now()
 	err := db.client.Begin()
 	if db.engine.hasDBLogger {
-		db.fillLogFields("[ORM][MYSQL][BEGIN]", start, "transaction", "START TRANSACTION", nil, err)
+		db.fillLogFields("[ORM][MYSQL][BEGIN]", start, "transaction", "START TRANSACTION", nil, -1, err)
 	}
 	checkError(err)
 	db.inTransaction = true
@@ -247,7 +315,7 @@ func (db *DB) Commit() {
 	start := time.Now()
 	err := db.client.Commit()
 	if db.engine.hasDBLogger {
-		db.fillLogFields("[ORM][MYSQL][COMMIT]", start, "transaction", "COMMIT", nil, err)
+		db.fillLogFields("[ORM][MYSQL][COMMIT]", start, "transaction", "COMMIT", nil, -1, err)
 	}
 	checkError(err)
 	db.inTransaction = false
@@ -263,6 +331,10 @@ func (db *DB) Commit() {
 		db.engine.afterCommitRedisFlusher.Flush()
 		db.engine.afterCommitRedisFlusher = nil
 	}
+	if db.engine.afterCommitDelayedDeletes != nil {
+		scheduleDelayedDeletes(db.engine, db.engine.afterCommitDelayedDeletes)
+		db.engine.afterCommitDelayedDeletes = nil
+	}
 }
 
 func (db *DB) Rollback() {
@@ -270,20 +342,29 @@ func (db *DB) Rollback() {
 	has, err := db.client.Rollback()
 	if has {
 		if db.engine.hasDBLogger {
-			db.fillLogFields("[ORM][MYSQL][ROLLBACK]", start, "transaction", "ROLLBACK", nil, err)
+			db.fillLogFields("[ORM][MYSQL][ROLLBACK]", start, "transaction", "ROLLBACK", nil, -1, err)
 		}
 	}
 	checkError(err)
 	db.engine.afterCommitLocalCacheSets = nil
 	db.engine.afterCommitRedisFlusher = nil
+	db.engine.afterCommitDelayedDeletes = nil
 	db.inTransaction = false
 }
 
 func (db *DB) Exec(query string, args ...interface{}) ExecResult {
+	if db.engine.readOnly && !isReadOnlyQuery(query) {
+		panic(&ReadOnlyError{Message: "engine is in read only mode, query not allowed: " + query})
+	}
 	start := time.Now()
+	query = db.engine.queryComment + query
 	rows, err := db.client.Exec(query, args...)
 	if db.engine.hasDBLogger {
-		db.fillLogFields("[ORM][MYSQL][EXEC]", start, "exec", query, args, err)
+		affected := int64(-1)
+		if err == nil {
+			affected, _ = rows.RowsAffected()
+		}
+		db.fillLogFields("[ORM][MYSQL][EXEC]", start, "exec", query, args, affected, err)
 	}
 	if err != nil {
 		panic(db.convertToError(err))
@@ -293,36 +374,47 @@ func (db *DB) Exec(query string, args ...interface{}) ExecResult {
 
 func (db *DB) QueryRow(query *Where, toFill ...interface{}) (found bool) {
 	start := time.Now()
-	row := db.client.QueryRow(query.String(), query.GetParameters()...)
+	sqlQuery := db.engine.queryComment + query.String()
+	row := db.client.QueryRow(sqlQuery, query.GetParameters()...)
 	err := row.Scan(toFill...)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			if db.engine.hasDBLogger {
-				db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query.String(), query.GetParameters(), nil)
+				db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", sqlQuery, query.GetParameters(), 0, nil)
 			}
 			return false
 		}
 		if db.engine.hasDBLogger {
-			db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query.String(), query.GetParameters(), err)
+			db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", sqlQuery, query.GetParameters(), -1, err)
 		}
 		panic(err)
 	}
 	if db.engine.hasDBLogger {
-		db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query.String(), query.GetParameters(), nil)
+		db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", sqlQuery, query.GetParameters(), 1, nil)
 	}
 	return true
 }
 
 func (db *DB) Query(query string, args ...interface{}) (rows Rows, deferF func()) {
 	start := time.Now()
+	query = db.engine.queryComment + query
 	result, err := db.client.Query(query, args...)
-	if db.engine.hasDBLogger {
-		db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query, args, err)
+	if err != nil && db.engine.hasDBLogger {
+		db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query, args, -1, err)
 	}
 	checkError(err)
-	return &rowsStruct{result}, func() {
+	wrapped := &rowsStruct{sqlRows: result}
+	logged := false
+	return wrapped, func() {
+		var err error
+		if result != nil {
+			err = result.Err()
+		}
+		if !logged && db.engine.hasDBLogger {
+			logged = true
+			db.fillLogFields("[ORM][MYSQL][SELECT]", start, "select", query, args, wrapped.counter, err)
+		}
 		if result != nil {
-			err := result.Err()
 			checkError(err)
 			err = result.Close()
 			checkError(err)
@@ -330,7 +422,7 @@ func (db *DB) Query(query string, args ...interface{}) (rows Rows, deferF func()
 	}
 }
 
-func (db *DB) fillLogFields(message string, start time.Time, typeCode string, query string, args []interface{}, err error) {
+func (db *DB) fillLogFields(message string, start time.Time, typeCode string, query string, args []interface{}, rows int64, err error) {
 	now := time.Now()
 	stop := time.Since(start).Microseconds()
 	e := db.engine.queryLoggers[QueryLoggerSourceDB].log.WithFields(log2.Fields{
@@ -343,8 +435,14 @@ func (db *DB) fillLogFields(message string, start time.Time, typeCode string, qu
 		"started":      start.UnixNano(),
 		"finished":     now.UnixNano(),
 	})
+	if rows >= 0 {
+		e = e.WithField("rows", rows)
+	}
 	if args != nil {
 		e = e.WithField("args", args)
+		if db.engine.interpolateQueries {
+			e = e.WithField("interpolated", InterpolateSQL(query, args, db.engine.interpolateMaxLength))
+		}
 	}
 	if err != nil {
 		injectLogError(err, e).Error(message)
@@ -353,21 +451,54 @@ func (db *DB) fillLogFields(message string, start time.Time, typeCode string, qu
 	}
 }
 
+func (db *DB) getUniqueIndexColumns(index string) []string {
+	table := ""
+	if dot := strings.LastIndex(index, "."); dot > 0 {
+		table = index[0:dot]
+		index = index[dot+1:]
+	}
+	for _, schema := range db.engine.registry.tableSchemas {
+		if schema.mysqlPoolName != db.GetPoolConfig().GetCode() || (table != "" && schema.tableName != table) {
+			continue
+		}
+		if columns, has := schema.uniqueIndices[index]; has {
+			return columns
+		}
+	}
+	return nil
+}
+
+func isReadOnlyQuery(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexAny(query, " \t\r\n")
+	if end > 0 {
+		query = query[0:end]
+	}
+	switch strings.ToUpper(query) {
+	case "SELECT", "SHOW", "EXPLAIN", "DESCRIBE", "DESC":
+		return true
+	}
+	return false
+}
+
 func (db *DB) convertToError(err error) error {
 	sqlErr, yes := err.(*mysql.MySQLError)
 	if yes {
 		if sqlErr.Number == 1062 {
-			var abortLabelReg, _ = regexp.Compile(` for key '(.*?)'`)
+			var abortLabelReg, _ = regexp.Compile(`Duplicate entry '(.*?)' for key '(.*?)'`)
 			labels := abortLabelReg.FindStringSubmatch(sqlErr.Message)
 			if len(labels) > 0 {
-				return &DuplicatedKeyError{Message: sqlErr.Message, Index: labels[1]}
+				return &DuplicatedKeyError{Message: sqlErr.Message, Index: labels[2], Value: labels[1],
+					Columns: db.getUniqueIndexColumns(labels[2]), Err: err}
 			}
 		} else if sqlErr.Number == 1451 || sqlErr.Number == 1452 {
 			var abortLabelReg, _ = regexp.Compile(" CONSTRAINT `(.*?)`")
 			labels := abortLabelReg.FindStringSubmatch(sqlErr.Message)
 			if len(labels) > 0 {
-				return &ForeignKeyError{Message: "foreign key error in key `" + labels[1] + "`", Constraint: labels[1]}
+				return &ForeignKeyError{Message: "foreign key error in key `" + labels[1] + "`", Constraint: labels[1], Err: err}
 			}
+		} else if sqlErr.Number == 1205 {
+			return &LockTimeoutError{Message: sqlErr.Message, Err: err}
 		}
 	}
 	return err
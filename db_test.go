@@ -163,3 +163,33 @@ func TestDBErrors(t *testing.T) {
 		row.RowsAffected()
 	})
 }
+
+func TestDBReadOnly(t *testing.T) {
+	var entity *dbEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.FlushMany(&dbEntity{Name: "Tom"})
+
+	engine.SetReadOnly(true)
+	assert.True(t, engine.IsReadOnly())
+	db := engine.GetMysql()
+	var name string
+	found := db.QueryRow(NewWhere("SELECT `Name` FROM `dbEntity` WHERE `ID` = ?", 1), &name)
+	assert.True(t, found)
+	assert.Equal(t, "Tom", name)
+	assert.PanicsWithError(t, "engine is in read only mode, query not allowed: INSERT INTO `dbEntity` VALUES(?, ?)", func() {
+		db.Exec("INSERT INTO `dbEntity` VALUES(?, ?)", 2, "John")
+	})
+	assert.PanicsWithError(t, "engine is in read only mode", func() {
+		engine.Flush(&dbEntity{Name: "John"})
+	})
+	err := engine.FlushWithCheck(&dbEntity{Name: "John"})
+	assert.IsType(t, &ReadOnlyError{}, err)
+
+	loaded := &dbEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, "Tom", loaded.Name)
+
+	engine.SetReadOnly(false)
+	engine.Flush(&dbEntity{Name: "John"})
+	assert.True(t, engine.LoadByID(2, loaded))
+}
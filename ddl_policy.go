@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DDLDecision int
+
+const (
+	DDLAllow DDLDecision = iota
+	DDLRequireForce
+	DDLBlock
+)
+
+type DDLPolicy func(alter Alter) DDLDecision
+
+type DDLAuditEvent struct {
+	SQL    string
+	Pool   string
+	Table  string
+	Safe   bool
+	Forced bool
+	Online bool
+	Time   int64
+}
+
+func DefaultDDLPolicy(alter Alter) DDLDecision {
+	if !alter.Safe || strings.Contains(alter.SQL, "DROP COLUMN") || strings.Contains(alter.SQL, "CHANGED FROM") {
+		return DDLRequireForce
+	}
+	return DDLAllow
+}
+
+func (r *Registry) SetDDLPolicy(policy DDLPolicy) {
+	r.ddlPolicy = policy
+}
+
+func (r *Registry) SetDDLAuditStream(stream string) {
+	r.ddlAuditStream = stream
+}
+
+func (a Alter) ExecForce() {
+	a.exec(true)
+}
+
+func (a Alter) exec(force bool) {
+	registry := a.engine.registry.registry
+	if registry.ddlPolicy != nil {
+		switch registry.ddlPolicy(a) {
+		case DDLBlock:
+			panic(fmt.Errorf("alter blocked by DDL policy: %s", a.SQL))
+		case DDLRequireForce:
+			if !force {
+				panic(fmt.Errorf("alter requires force: %s", a.SQL))
+			}
+		}
+	}
+	online := a.Large && registry.alterExecutor != nil
+	if online {
+		registry.alterExecutor.ExecAlter(a.engine, a)
+	} else {
+		a.engine.GetMysql(a.Pool).Exec(a.SQL)
+	}
+	if registry.ddlAuditStream != "" {
+		a.engine.GetEventBroker().Publish(registry.ddlAuditStream, DDLAuditEvent{SQL: a.SQL, Pool: a.Pool, Table: a.Table,
+			Safe: a.Safe, Forced: force, Online: online, Time: a.engine.GetClock().Now().Unix()})
+	}
+}
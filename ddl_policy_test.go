@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ddlPolicyEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestDDLPolicy(t *testing.T) {
+	var entity *ddlPolicyEntity
+	registry := &Registry{}
+	registry.RegisterRedisStream("ddl-audit", "default", []string{"ddl-audit-group"})
+	engine := PrepareTables(t, registry, 5, entity)
+	registry.SetDDLPolicy(DefaultDDLPolicy)
+	registry.SetDDLAuditStream("ddl-audit")
+	engine.GetMysql().Exec("ALTER TABLE `ddlPolicyEntity` ADD COLUMN `Extra` int NOT NULL DEFAULT 0")
+	engine.Flush(&ddlPolicyEntity{Name: "a"})
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	_, alters := schema.GetSchemaChanges(engine)
+	assert.Len(t, alters, 1)
+	assert.False(t, alters[0].Safe)
+	assert.PanicsWithError(t, "alter requires force: "+alters[0].SQL, func() {
+		alters[0].Exec()
+	})
+	alters[0].ExecForce()
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	registry.SetDDLPolicy(func(alter Alter) DDLDecision {
+		return DDLBlock
+	})
+	engine.GetMysql().Exec("ALTER TABLE `ddlPolicyEntity` ADD COLUMN `Extra` int NOT NULL DEFAULT 0")
+	_, alters = schema.GetSchemaChanges(engine)
+	assert.PanicsWithError(t, "alter blocked by DDL policy: "+alters[0].SQL, func() {
+		alters[0].ExecForce()
+	})
+	registry.SetDDLPolicy(nil)
+	alters[0].Exec()
+
+	valid := false
+	consumer := engine.GetEventBroker().Consumer("ddl-audit-consumer", "ddl-audit-group")
+	consumer.DisableLoop()
+	consumer.(*eventsConsumer).blockTime = time.Millisecond * 10
+	consumer.Consume(context.Background(), 10, true, func(events []Event) {
+		valid = true
+		assert.Len(t, events, 2)
+		audit := &DDLAuditEvent{}
+		assert.NoError(t, events[0].Unserialize(audit))
+		assert.Equal(t, "ddlPolicyEntity", audit.Table)
+		assert.Equal(t, "default", audit.Pool)
+		assert.True(t, audit.Forced)
+		assert.False(t, audit.Safe)
+		assert.Contains(t, audit.SQL, "DROP COLUMN `Extra`")
+		assert.NoError(t, events[1].Unserialize(audit))
+		assert.False(t, audit.Forced)
+	})
+	assert.True(t, valid)
+}
@@ -0,0 +1,217 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+func NewDecimal(value string) (Decimal, error) {
+	original := value
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		negative = value[0] == '-'
+		value = value[1:]
+	}
+	integer := value
+	fraction := ""
+	pos := strings.Index(value, ".")
+	if pos >= 0 {
+		integer = value[0:pos]
+		fraction = value[pos+1:]
+	}
+	if integer == "" && fraction == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal value '%s'", original)
+	}
+	for _, c := range integer + fraction {
+		if c < '0' || c > '9' {
+			return Decimal{}, fmt.Errorf("invalid decimal value '%s'", original)
+		}
+	}
+	unscaled, _ := new(big.Int).SetString("0"+integer+fraction, 10)
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{value: unscaled, scale: int32(len(fraction))}, nil
+}
+
+func MustDecimal(value string) Decimal {
+	d, err := NewDecimal(value)
+	checkError(err)
+	return d
+}
+
+func NewDecimalFromInt(value int64, scale int32) Decimal {
+	if scale < 0 {
+		panic(fmt.Errorf("invalid decimal scale %d", scale))
+	}
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) String() string {
+	digits := d.unscaled().String()
+	negative := strings.HasPrefix(digits, "-")
+	if negative {
+		digits = digits[1:]
+	}
+	if d.scale > 0 {
+		if len(digits) <= int(d.scale) {
+			digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+		}
+		digits = digits[0:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := alignDecimals(d, other)
+	return Decimal{value: a.Add(a, b), scale: scale}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := alignDecimals(d, other)
+	return Decimal{value: a.Sub(a, b), scale: scale}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.unscaled(), other.unscaled()), scale: d.scale + other.scale}
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := alignDecimals(d, other)
+	return a.Cmp(b)
+}
+
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) Round(scale int32) Decimal {
+	if scale < 0 {
+		panic(fmt.Errorf("invalid decimal scale %d", scale))
+	}
+	if scale >= d.scale {
+		return Decimal{value: new(big.Int).Mul(d.unscaled(), decimalPow10(scale-d.scale)), scale: scale}
+	}
+	divisor := decimalPow10(d.scale - scale)
+	quotient, remainder := new(big.Int).QuoRem(d.unscaled(), divisor, new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(divisor) >= 0 {
+		if d.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Decimal{value: quotient, scale: scale}
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + d.String() + "\""), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	parsed, err := NewDecimal(strings.Trim(string(data), "\""))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) unscaled() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+func alignDecimals(a, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	return new(big.Int).Mul(a.unscaled(), decimalPow10(scale-a.scale)), new(big.Int).Mul(b.unscaled(), decimalPow10(scale-b.scale)), scale
+}
+
+func decimalPow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func getDecimalDefinition(attributes map[string]string) (precision int, scale int32) {
+	precision = 10
+	decimal, has := attributes["decimal"]
+	if has {
+		decimalArgs := strings.Split(decimal, ",")
+		precision, _ = strconv.Atoi(decimalArgs[0])
+		if len(decimalArgs) > 1 {
+			parsed, _ := strconv.Atoi(decimalArgs[1])
+			scale = int32(parsed)
+		}
+	}
+	return precision, scale
+}
+
+func (e *Engine) SumDecimal(where *Where, entity Entity, field string) Decimal {
+	return aggregateDecimal(e, "SUM", where, entity, field)
+}
+
+func (e *Engine) AvgDecimal(where *Where, entity Entity, field string) Decimal {
+	return aggregateDecimal(e, "AVG", where, entity, field)
+}
+
+func aggregateDecimal(engine *Engine, function string, where *Where, entity Entity, field string) Decimal {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	if !schema.decimals[field] {
+		panic(fmt.Errorf("field '%s' in entity '%s' is not decimal", field, schema.t.String()))
+	}
+	whereQuery := where.resolve(engine.registry, schema)
+	if schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	}
+	/* #nosec */
+	query := "SELECT " + function + "(`" + field + "`) FROM `" + schema.tableName + "` WHERE " + whereQuery
+	var result *string
+	schema.GetMysql(engine).QueryRow(NewWhere(query, where.GetParameters()...), &result)
+	if result == nil {
+		_, scale := getDecimalDefinition(schema.tags[field])
+		return NewDecimalFromInt(0, scale)
+	}
+	return MustDecimal(*result)
+}
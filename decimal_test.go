@@ -0,0 +1,88 @@
+package orm
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type decimalEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Name     string
+	Price    Decimal  `orm:"decimal=10,2"`
+	Discount *Decimal `orm:"decimal=5,3;unsigned"`
+}
+
+func TestDecimalValue(t *testing.T) {
+	a := MustDecimal("10.05")
+	b := MustDecimal("-0.7")
+	assert.Equal(t, "10.05", a.String())
+	assert.Equal(t, "-0.7", b.String())
+	assert.Equal(t, "9.35", a.Add(b).String())
+	assert.Equal(t, "10.75", a.Sub(b).String())
+	assert.Equal(t, "-7.035", a.Mul(b).String())
+	assert.Equal(t, "0.00", NewDecimalFromInt(0, 2).String())
+	assert.Equal(t, "-0.05", NewDecimalFromInt(-5, 2).String())
+	assert.Equal(t, "10.1", a.Round(1).String())
+	assert.Equal(t, "-1", b.Round(0).String())
+	assert.Equal(t, "10.0500", a.Round(4).String())
+	assert.True(t, MustDecimal("1.50").Equal(MustDecimal("1.5")))
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, 10.05, a.Float64())
+
+	_, err := NewDecimal("1.2.3")
+	assert.EqualError(t, err, "invalid decimal value '1.2.3'")
+	_, err = NewDecimal("")
+	assert.EqualError(t, err, "invalid decimal value ''")
+
+	encoded, _ := jsoniter.ConfigFastest.Marshal(a)
+	assert.Equal(t, "\"10.05\"", string(encoded))
+	decoded := Decimal{}
+	assert.NoError(t, jsoniter.ConfigFastest.Unmarshal(encoded, &decoded))
+	assert.True(t, a.Equal(decoded))
+}
+
+func TestDecimal(t *testing.T) {
+	var entity *decimalEntity
+	engine := PrepareTables(t, &Registry{}, 8, entity)
+
+	entity = &decimalEntity{Name: "a", Price: MustDecimal("19.99")}
+	engine.Flush(entity)
+	discount := MustDecimal("0.125")
+	engine.Flush(&decimalEntity{Name: "b", Price: MustDecimal("0.011"), Discount: &discount})
+
+	loaded := &decimalEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, "19.99", loaded.Price.String())
+	assert.Nil(t, loaded.Discount)
+	assert.False(t, loaded.IsDirty())
+	loaded.Price = MustDecimal("19.990")
+	assert.False(t, loaded.IsDirty())
+	loaded.Price = MustDecimal("20")
+	assert.True(t, loaded.IsDirty())
+	engine.Flush(loaded)
+
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	loaded = &decimalEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, "0.01", loaded.Price.String())
+	assert.Equal(t, "0.125", loaded.Discount.String())
+	assert.NoError(t, loaded.SetField("Discount", nil))
+	assert.NoError(t, loaded.SetField("Price", "5.5"))
+	engine.Flush(loaded)
+	loaded = &decimalEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Nil(t, loaded.Discount)
+	assert.Equal(t, "5.50", loaded.Price.String())
+
+	assert.Equal(t, "25.50", engine.SumDecimal(NewWhere("1"), entity, "Price").String())
+	assert.Equal(t, "0.00", engine.SumDecimal(W.Eq("Name", "missing"), entity, "Price").String())
+	assert.True(t, engine.AvgDecimal(NewWhere("1"), entity, "Price").Equal(MustDecimal("12.75")))
+	assert.PanicsWithError(t, "field 'Name' in entity 'orm.decimalEntity' is not decimal", func() {
+		engine.SumDecimal(NewWhere("1"), entity, "Name")
+	})
+}
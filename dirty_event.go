@@ -10,14 +10,17 @@ type DirtyEntityEvent interface {
 	Added() bool
 	Updated() bool
 	Deleted() bool
+	Tenant() string
 }
 
 func EventDirtyEntity(e Event) DirtyEntityEvent {
 	data := e.RawData()
 	id, _ := strconv.ParseUint(data["I"].(string), 10, 64)
 	action := data["A"].(string)
-	schema := e.(*event).consumer.redis.engine.registry.GetTableSchema(data["E"].(string))
-	return &dirtyEntityEvent{id: id, schema: schema, added: action == "i", updated: action == "u", deleted: action == "d"}
+	tenant, _ := data["T"].(string)
+	registry := e.(*event).consumer.redis.engine.registry.getTenantRegistry(tenant)
+	schema := registry.GetTableSchema(data["E"].(string))
+	return &dirtyEntityEvent{id: id, schema: schema, tenant: tenant, added: action == "i", updated: action == "u", deleted: action == "d"}
 }
 
 type dirtyEntityEvent struct {
@@ -26,6 +29,7 @@ type dirtyEntityEvent struct {
 	updated bool
 	deleted bool
 	schema  TableSchema
+	tenant  string
 }
 
 func (d *dirtyEntityEvent) ID() uint64 {
@@ -47,3 +51,7 @@ func (d *dirtyEntityEvent) Updated() bool {
 func (d *dirtyEntityEvent) Deleted() bool {
 	return d.deleted
 }
+
+func (d *dirtyEntityEvent) Tenant() string {
+	return d.tenant
+}
@@ -91,7 +91,7 @@ func (e *Elastic) Search(index string, query elastic.Query, pager *Pager, option
 			}
 		}
 	}
-	result, err := searchService.Do(context.Background())
+	result, err := searchService.Do(e.engine.context)
 	if e.engine.hasElasticLogger {
 		s, _ := query.Source()
 		queryType := strings.Split(reflect.TypeOf(query).Elem().String(), ".")
@@ -129,7 +129,7 @@ func (e *Elastic) Search(index string, query elastic.Query, pager *Pager, option
 }
 
 func (e *Elastic) DropIndex(index ElasticIndexDefinition) {
-	ctx := context.Background()
+	ctx := e.engine.context
 
 	existService := elastic.NewIndicesExistsService(e.client)
 	existService.Index([]string{index.GetName()})
@@ -142,7 +142,7 @@ func (e *Elastic) DropIndex(index ElasticIndexDefinition) {
 }
 
 func (e *Elastic) CreateIndex(index ElasticIndexDefinition) {
-	ctx := context.Background()
+	ctx := e.engine.context
 	e.DropIndex(index)
 	_, err := e.client.CreateIndex(index.GetName()).BodyJson(index.GetDefinition()).Do(ctx)
 	checkError(err)
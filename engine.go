@@ -35,6 +35,7 @@ type Engine struct {
 	logMetaData               map[string]interface{}
 	logMetaDataMutex          sync.RWMutex
 	hasRequestCache           bool
+	readOnly                  bool
 	queryLoggers              map[QueryLoggerSource]*logger
 	hasRedisLogger            bool
 	hasStreamsLogger          bool
@@ -47,8 +48,21 @@ type Engine struct {
 	logMutex                  sync.Mutex
 	logDebugOnce              sync.Once
 	afterCommitLocalCacheSets map[string][]interface{}
+	clock                     Clock
+	interpolateQueries        bool
+	interpolateMaxLength      int
+	queryTags                 map[string]string
+	queryComment              string
+	role                      string
+	tenant                    string
 	afterCommitRedisFlusher   *redisFlusher
+	afterCommitDelayedDeletes []*delayedCacheDelete
 	eventBroker               *eventBroker
+	loadByIDCalls             map[string]*loadByIDCall
+	loadByIDCallsMutex        sync.Mutex
+	identityMap               *identityMap
+	recoveryHandler           RecoveryHandler
+	maxSearchRows             int
 }
 
 func (e *Engine) Log() Log {
@@ -62,6 +76,14 @@ func (e *Engine) EnableRequestCache() {
 	e.hasRequestCache = true
 }
 
+func (e *Engine) SetReadOnly(readOnly bool) {
+	e.readOnly = readOnly
+}
+
+func (e *Engine) IsReadOnly() bool {
+	return e.readOnly
+}
+
 func (e *Engine) EnableLogger(level logApex.Level, handlers ...logApex.Handler) {
 	if len(handlers) == 0 {
 		handlers = []logApex.Handler{&jsonHandler{}}
@@ -147,9 +169,9 @@ func (e *Engine) GetMysql(code ...string) *DB {
 	if !has {
 		config, has := e.registry.mySQLServers[dbCode]
 		if !has {
-			panic(fmt.Errorf("unregistered mysql pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "mysql", Code: dbCode})
 		}
-		db = &DB{engine: e, config: config, client: &standardSQLClient{db: config.getClient()}}
+		db = &DB{engine: e, config: config, client: &standardSQLClient{db: config.getClient(), ctx: e.context}}
 		if e.dbs == nil {
 			e.dbs = map[string]*DB{dbCode: db}
 		} else {
@@ -179,7 +201,7 @@ func (e *Engine) GetLocalCache(code ...string) *LocalCache {
 				}
 				return cache
 			}
-			panic(fmt.Errorf("unregistered local cache pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "local cache", Code: dbCode})
 		}
 		cache = &LocalCache{engine: e, config: config.(*localCachePoolConfig), lru: lru.New(config.GetLimit())}
 		if e.localCache == nil {
@@ -202,13 +224,13 @@ func (e *Engine) GetRedis(code ...string) *RedisCache {
 	if !has {
 		config, has := e.registry.redisServers[dbCode]
 		if !has {
-			panic(fmt.Errorf("unregistered redis cache pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "redis cache", Code: dbCode})
 		}
 		client := config.getClient()
 		if client != nil {
 			client = client.WithContext(e.context)
 		}
-		cache = &RedisCache{engine: e, config: config, client: client, ctx: context.Background()}
+		cache = &RedisCache{engine: e, config: config, client: client, ctx: e.context}
 		if e.redis == nil {
 			e.redis = map[string]*RedisCache{dbCode: cache}
 		} else {
@@ -223,20 +245,23 @@ func (e *Engine) GetRedisSearch(code ...string) *RedisSearch {
 	if len(code) > 0 {
 		dbCode = code[0]
 	}
+	if e.registry.redisSearchDisabled[dbCode] {
+		panic(fmt.Errorf("redis search is not available in redis pool '%s'", dbCode))
+	}
 	e.redisSearchMutex.Lock()
 	defer e.redisSearchMutex.Unlock()
 	cache, has := e.redisSearch[dbCode]
 	if !has {
 		config, has := e.registry.redisServers[dbCode]
 		if !has {
-			panic(fmt.Errorf("unregistered redis cache pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "redis cache", Code: dbCode})
 		}
 		client := config.getClient()
 		if client != nil {
 			client = client.WithContext(e.context)
 		}
-		redisClient := &RedisCache{engine: e, config: config, client: client, ctx: context.Background()}
-		cache = &RedisSearch{engine: e, redis: redisClient, ctx: context.Background()}
+		redisClient := &RedisCache{engine: e, config: config, client: client, ctx: e.context}
+		cache = &RedisSearch{engine: e, redis: redisClient, ctx: e.context}
 		if e.redisSearch == nil {
 			e.redisSearch = map[string]*RedisSearch{dbCode: cache}
 		} else {
@@ -257,7 +282,7 @@ func (e *Engine) GetClickHouse(code ...string) *ClickHouse {
 	if !has {
 		val, has := e.registry.clickHouseClients[dbCode]
 		if !has {
-			panic(fmt.Errorf("unregistered clickhouse pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "clickhouse", Code: dbCode})
 		}
 		ch = &ClickHouse{engine: e, code: val.code, client: val.db}
 		if e.clickHouseDbs == nil {
@@ -280,7 +305,7 @@ func (e *Engine) GetElastic(code ...string) *Elastic {
 	if !has {
 		val, has := e.registry.elasticServers[dbCode]
 		if !has {
-			panic(fmt.Errorf("unregistered elastic pool '%s'", dbCode))
+			panic(&PoolNotRegisteredError{Type: "elastic", Code: dbCode})
 		}
 		elastic = &Elastic{engine: e, code: val.code, client: val.client}
 		if e.elastic == nil {
@@ -344,40 +369,104 @@ func (e *Engine) DeleteMany(entities ...Entity) {
 	e.FlushMany(entities...)
 }
 
+func (e *Engine) SetClock(clock Clock) {
+	e.clock = clock
+}
+
+func (e *Engine) GetClock() Clock {
+	if e.clock != nil {
+		return e.clock
+	}
+	if e.registry != nil && e.registry.registry != nil && e.registry.registry.clock != nil {
+		return e.registry.registry.clock
+	}
+	return systemClock{}
+}
+
 func (e *Engine) GetRegistry() ValidatedRegistry {
 	return e.registry
 }
 
 func (e *Engine) SearchWithCount(where *Where, pager *Pager, entities interface{}, references ...string) (totalRows int) {
-	return search(true, e, where, pager, true, false, true, reflect.ValueOf(entities).Elem(), references...)
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows = search(true, e, where, pager, true, false, true, reflect.ValueOf(entities).Elem(), references...)
+	})
+	return totalRows
 }
 
 func (e *Engine) SearchWithCountLAzy(where *Where, pager *Pager, entities interface{}, references ...string) (totalRows int) {
-	return search(true, e, where, pager, true, true, true, reflect.ValueOf(entities).Elem(), references...)
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows = search(true, e, where, pager, true, true, true, reflect.ValueOf(entities).Elem(), references...)
+	})
+	return totalRows
 }
 
 func (e *Engine) Search(where *Where, pager *Pager, entities interface{}, references ...string) {
-	search(true, e, where, pager, false, false, true, reflect.ValueOf(entities).Elem(), references...)
+	e.withPagerLimit(pager, func(pager *Pager) {
+		search(true, e, where, pager, false, false, true, reflect.ValueOf(entities).Elem(), references...)
+	})
 }
 
 func (e *Engine) SearchLazy(where *Where, pager *Pager, entities interface{}, references ...string) {
-	search(true, e, where, pager, false, true, true, reflect.ValueOf(entities).Elem(), references...)
+	e.withPagerLimit(pager, func(pager *Pager) {
+		search(true, e, where, pager, false, true, true, reflect.ValueOf(entities).Elem(), references...)
+	})
+}
+
+func (e *Engine) SearchRaw(where *Where, entities interface{}, references ...string) {
+	searchRaw(e, where, false, reflect.ValueOf(entities).Elem(), references...)
+}
+
+func (e *Engine) SearchRawLazy(where *Where, entities interface{}, references ...string) {
+	searchRaw(e, where, true, reflect.ValueOf(entities).Elem(), references...)
+}
+
+func (e *Engine) SearchForUpdate(where *Where, pager *Pager, entities interface{}, lock LockMode, references ...string) {
+	e.withPagerLimit(pager, func(pager *Pager) {
+		search(true, e, where.withLock(lock), pager, false, false, true, reflect.ValueOf(entities).Elem(), references...)
+	})
 }
 
 func (e *Engine) SearchIDsWithCount(where *Where, pager *Pager, entity Entity) (results []uint64, totalRows int) {
-	return searchIDsWithCount(true, e, where, pager, reflect.TypeOf(entity).Elem())
+	e.withPagerLimit(pager, func(pager *Pager) {
+		results, totalRows = searchIDsWithCount(true, e, where, pager, reflect.TypeOf(entity).Elem())
+	})
+	return results, totalRows
 }
 
-func (e *Engine) SearchIDs(where *Where, pager *Pager, entity Entity) []uint64 {
-	results, _ := searchIDs(true, e, where, pager, false, reflect.TypeOf(entity).Elem())
+func (e *Engine) SearchIDs(where *Where, pager *Pager, entity Entity) (results []uint64) {
+	e.withPagerLimit(pager, func(pager *Pager) {
+		results, _ = searchIDs(true, e, where, pager, false, reflect.TypeOf(entity).Elem())
+	})
 	return results
 }
 
+func (e *Engine) Count(where *Where, entity Entity) int {
+	return searchCount(e, where, entity)
+}
+
+func (e *Engine) Exists(where *Where, entity Entity) bool {
+	return searchExists(e, where, entity)
+}
+
+func (e *Engine) GetReferenced(parent Entity, children interface{}, field string, pager *Pager, references ...string) (totalRows int) {
+	return getReferenced(e, parent, children, field, pager, false, references)
+}
+
+func (e *Engine) GetReferencedLazy(parent Entity, children interface{}, field string, pager *Pager, references ...string) (totalRows int) {
+	return getReferenced(e, parent, children, field, pager, true, references)
+}
+
 func (e *Engine) SearchOne(where *Where, entity Entity, references ...string) (found bool) {
 	found, _, _ = searchOne(true, e, where, entity, false, references)
 	return found
 }
 
+func (e *Engine) SearchOneForUpdate(where *Where, entity Entity, lock LockMode, references ...string) (found bool) {
+	found, _, _ = searchOne(true, e, where.withLock(lock), entity, false, references)
+	return found
+}
+
 func (e *Engine) SearchOneLazy(where *Where, entity Entity, references ...string) (found bool) {
 	found, _, _ = searchOne(true, e, where, entity, true, references)
 	return found
@@ -404,17 +493,38 @@ func (e *Engine) CachedSearchOneWithReferencesLazy(entity Entity, indexName stri
 }
 
 func (e *Engine) CachedSearch(entities interface{}, indexName string, pager *Pager, arguments ...interface{}) (totalRows int) {
-	total, _ := cachedSearch(e, entities, indexName, pager, arguments, false, true, nil)
-	return total
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows, _ = cachedSearch(e, entities, indexName, pager, arguments, false, true, nil)
+	})
+	return totalRows
 }
 
 func (e *Engine) CachedSearchLazy(entities interface{}, indexName string, pager *Pager, arguments ...interface{}) (totalRows int) {
-	total, _ := cachedSearch(e, entities, indexName, pager, arguments, true, true, nil)
-	return total
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows, _ = cachedSearch(e, entities, indexName, pager, arguments, true, true, nil)
+	})
+	return totalRows
+}
+
+func (e *Engine) CachedSearchWithOrder(entities interface{}, indexName string, orderBy string, pager *Pager, arguments ...interface{}) (totalRows int) {
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows = cachedSearchWithOrder(e, entities, indexName, orderBy, pager, arguments, false)
+	})
+	return totalRows
+}
+
+func (e *Engine) CachedSearchWithOrderLazy(entities interface{}, indexName string, orderBy string, pager *Pager, arguments ...interface{}) (totalRows int) {
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows = cachedSearchWithOrder(e, entities, indexName, orderBy, pager, arguments, true)
+	})
+	return totalRows
 }
 
 func (e *Engine) CachedSearchIDs(entity Entity, indexName string, pager *Pager, arguments ...interface{}) (totalRows int, ids []uint64) {
-	return cachedSearch(e, entity, indexName, pager, arguments, false, false, nil)
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows, ids = cachedSearch(e, entity, indexName, pager, arguments, false, false, nil)
+	})
+	return totalRows, ids
 }
 
 func (e *Engine) CachedSearchCount(entity Entity, indexName string, arguments ...interface{}) int {
@@ -424,14 +534,18 @@ func (e *Engine) CachedSearchCount(entity Entity, indexName string, arguments ..
 
 func (e *Engine) CachedSearchWithReferences(entities interface{}, indexName string, pager *Pager,
 	arguments []interface{}, references []string) (totalRows int) {
-	total, _ := cachedSearch(e, entities, indexName, pager, arguments, false, true, references)
-	return total
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows, _ = cachedSearch(e, entities, indexName, pager, arguments, false, true, references)
+	})
+	return totalRows
 }
 
 func (e *Engine) CachedSearchWithReferencesLazy(entities interface{}, indexName string, pager *Pager,
 	arguments []interface{}, references []string) (totalRows int) {
-	total, _ := cachedSearch(e, entities, indexName, pager, arguments, true, true, references)
-	return total
+	e.withPagerLimit(pager, func(pager *Pager) {
+		totalRows, _ = cachedSearch(e, entities, indexName, pager, arguments, true, true, references)
+	})
+	return totalRows
 }
 
 func (e *Engine) ClearByIDs(entity Entity, ids ...uint64) {
@@ -439,15 +553,29 @@ func (e *Engine) ClearByIDs(entity Entity, ids ...uint64) {
 }
 
 func (e *Engine) LoadByID(id uint64, entity Entity, references ...string) (found bool) {
+	if !isAllowedByRowPolicy(e, initIfNeeded(e.registry, entity).tableSchema, id) {
+		return false
+	}
 	found, _ = loadByID(e, id, entity, true, false, references...)
+	if found && e.identityMap != nil {
+		e.identityMap.add(entity)
+	}
 	return found
 }
 
 func (e *Engine) LoadByIDLazy(id uint64, entity Entity, references ...string) (found bool) {
+	if !isAllowedByRowPolicy(e, initIfNeeded(e.registry, entity).tableSchema, id) {
+		return false
+	}
 	found, _ = loadByID(e, id, entity, true, true, references...)
 	return found
 }
 
+func (e *Engine) LoadByIDForUpdate(id uint64, entity Entity, lock LockMode, references ...string) (found bool) {
+	found, _, _ = searchRow(false, e, NewWhere("`ID` = ?", id).withLock(lock), entity, false, references)
+	return found
+}
+
 func (e *Engine) Load(entity Entity, references ...string) (found bool) {
 	return e.load(entity, false, references...)
 }
@@ -474,19 +602,25 @@ func (e *Engine) load(entity Entity, lazy bool, references ...string) bool {
 }
 
 func (e *Engine) LoadByIDs(ids []uint64, entities interface{}, references ...string) (missing bool) {
-	missing, _ = tryByIDs(e, ids, reflect.ValueOf(entities).Elem(), references, false)
-	return missing
+	value := reflect.ValueOf(entities).Elem()
+	missing, schema := tryByIDs(e, ids, value, references, false)
+	return filterByRowPolicy(e, schema, value) || missing
 }
 
 func (e *Engine) LoadByIDsLazy(ids []uint64, entities interface{}, references ...string) (missing bool) {
-	missing, _ = tryByIDs(e, ids, reflect.ValueOf(entities).Elem(), references, true)
-	return missing
+	value := reflect.ValueOf(entities).Elem()
+	missing, schema := tryByIDs(e, ids, value, references, true)
+	return filterByRowPolicy(e, schema, value) || missing
 }
 
 func (e *Engine) GetAlters() (alters []Alter) {
 	return getAlters(e)
 }
 
+func (e *Engine) GetSchemaSnapshot() string {
+	return getSchemaSnapshot(e)
+}
+
 func (e *Engine) GetRedisSearchIndexAlters() (alters []RedisSearchIndexAlter) {
 	return getRedisSearchAlters(e)
 }
@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"context"
+
+	logApex "github.com/apex/log"
+	"github.com/apex/log/handlers/multi"
+)
+
+type EngineOption func(engine *Engine)
+
+func WithLogMetaData(key string, value interface{}) EngineOption {
+	return func(engine *Engine) {
+		engine.SetLogMetaData(key, value)
+	}
+}
+
+func WithQueryLogger(handler logApex.Handler, level logApex.Level, source ...QueryLoggerSource) EngineOption {
+	return func(engine *Engine) {
+		engine.AddQueryLogger(handler, level, source...)
+	}
+}
+
+func WithRequestCache() EngineOption {
+	return func(engine *Engine) {
+		engine.EnableRequestCache()
+	}
+}
+
+func WithReadOnly(readOnly bool) EngineOption {
+	return func(engine *Engine) {
+		engine.SetReadOnly(readOnly)
+	}
+}
+
+func WithInterpolatedQueryLog(maxValueLength int) EngineOption {
+	return func(engine *Engine) {
+		engine.EnableInterpolatedQueryLog(maxValueLength)
+	}
+}
+
+func WithRole(role string) EngineOption {
+	return func(engine *Engine) {
+		engine.SetRole(role)
+	}
+}
+
+func WithClock(clock Clock) EngineOption {
+	return func(engine *Engine) {
+		engine.SetClock(clock)
+	}
+}
+
+func WithContext(ctx context.Context) EngineOption {
+	return func(engine *Engine) {
+		engine.context = ctx
+	}
+}
+
+func NewContext(ctx context.Context, engine *Engine) *Engine {
+	return engine.WithOptions(WithContext(ctx))
+}
+
+func (e *Engine) Ctx() context.Context {
+	return e.context
+}
+
+func (e *Engine) Clone() *Engine {
+	clone := &Engine{registry: e.registry, context: e.context, clock: e.clock}
+	clone.hasRequestCache = e.hasRequestCache
+	clone.readOnly = e.readOnly
+	clone.role = e.role
+	clone.tenant = e.tenant
+	clone.recoveryHandler = e.recoveryHandler
+	clone.maxSearchRows = e.maxSearchRows
+	if e.identityMap != nil {
+		clone.EnableIdentityMap()
+	}
+	clone.interpolateQueries = e.interpolateQueries
+	clone.interpolateMaxLength = e.interpolateMaxLength
+	if e.queryTags != nil {
+		clone.queryTags = make(map[string]string, len(e.queryTags))
+		for key, value := range e.queryTags {
+			clone.queryTags[key] = value
+		}
+		clone.queryComment = e.queryComment
+	}
+	e.logMetaDataMutex.RLock()
+	if e.logMetaData != nil {
+		clone.logMetaData = make(map[string]interface{}, len(e.logMetaData))
+		for key, value := range e.logMetaData {
+			clone.logMetaData[key] = value
+		}
+	}
+	e.logMetaDataMutex.RUnlock()
+
+	e.logMutex.Lock()
+	defer e.logMutex.Unlock()
+	if e.queryLoggers != nil {
+		clone.queryLoggers = make(map[QueryLoggerSource]*logger, len(e.queryLoggers))
+		for source, l := range e.queryLoggers {
+			handlers := make([]logApex.Handler, len(l.handler.Handlers))
+			copy(handlers, l.handler.Handlers)
+			multiHandler := multi.New(handlers...)
+			queryLogger := &logApex.Logger{Handler: multiHandler, Level: l.log.Logger.Level}
+			clone.queryLoggers[source] = &logger{log: queryLogger.WithField("from", "orm"), handler: multiHandler}
+		}
+	}
+	clone.hasRedisLogger = e.hasRedisLogger
+	clone.hasStreamsLogger = e.hasStreamsLogger
+	clone.hasDBLogger = e.hasDBLogger
+	clone.hasClickHouseLogger = e.hasClickHouseLogger
+	clone.hasElasticLogger = e.hasElasticLogger
+	clone.hasLocalCacheLogger = e.hasLocalCacheLogger
+	if e.log != nil {
+		l := clone.Log().(*log)
+		l.logger.handler.Handlers = append(l.logger.handler.Handlers, e.log.logger.handler.Handlers...)
+		fields := make(logApex.Fields, len(e.log.logger.log.Fields))
+		for key, value := range e.log.logger.log.Fields {
+			if key != "logger.thread_name" {
+				fields[key] = value
+			}
+		}
+		l.AddFields(fields)
+	}
+	return clone
+}
+
+func (e *Engine) WithOptions(options ...EngineOption) *Engine {
+	clone := e.Clone()
+	for _, option := range options {
+		option(clone)
+	}
+	return clone
+}
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineClone(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterLocalCache(100)
+	validatedRegistry, err := registry.Validate()
+	assert.Nil(t, err)
+	engine := validatedRegistry.CreateEngine()
+	engine.SetLogMetaData("source", "test")
+	engine.SetReadOnly(true)
+	parentLogger := memory.New()
+	engine.AddQueryLogger(parentLogger, apexLog.InfoLevel, QueryLoggerSourceLocalCache)
+	appLogger := memory.New()
+	engine.EnableLogger(apexLog.InfoLevel, appLogger)
+	engine.Log().AddFields(apexLog.Fields{"app": "orm"})
+
+	clone := engine.Clone()
+	assert.True(t, clone.IsReadOnly())
+	assert.Equal(t, map[string]interface{}{"source": "test"}, clone.logMetaData)
+	clone.SetLogMetaData("user", 7)
+	assert.Len(t, engine.logMetaData, 1)
+	assert.Len(t, clone.logMetaData, 2)
+
+	cloneLogger := memory.New()
+	clone.AddQueryLogger(cloneLogger, apexLog.InfoLevel, QueryLoggerSourceLocalCache)
+	clone.GetLocalCache().Set("a", "b")
+	assert.Len(t, parentLogger.Entries, 1)
+	assert.Len(t, cloneLogger.Entries, 1)
+	engine.GetLocalCache().Set("a", "b")
+	assert.Len(t, parentLogger.Entries, 2)
+	assert.Len(t, cloneLogger.Entries, 1)
+
+	clone.Log().AddFields(apexLog.Fields{"request": "abc"})
+	clone.Log().Info("clone", nil)
+	engine.Log().Info("parent", nil)
+	assert.Len(t, appLogger.Entries, 2)
+	assert.Equal(t, "orm", appLogger.Entries[0].Fields["app"])
+	assert.Equal(t, "abc", appLogger.Entries[0].Fields["request"])
+	assert.Nil(t, appLogger.Entries[1].Fields["request"])
+
+	child := engine.WithOptions(WithReadOnly(false), WithRequestCache(), WithLogMetaData("user", 10), WithClock(NewMockClock(validatedRegistry.CreateEngine().GetClock().Now())))
+	assert.False(t, child.IsReadOnly())
+	assert.True(t, child.hasRequestCache)
+	assert.Equal(t, 10, child.logMetaData["user"])
+	assert.True(t, engine.IsReadOnly())
+	assert.False(t, engine.hasRequestCache)
+	assert.IsType(t, &MockClock{}, child.GetClock())
+	assert.IsType(t, systemClock{}, engine.GetClock())
+}
+
+type engineContextKey struct{}
+
+func TestEngineContext(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterLocalCache(100)
+	validatedRegistry, err := registry.Validate()
+	assert.Nil(t, err)
+	engine := validatedRegistry.CreateEngine()
+	assert.Equal(t, context.Background(), engine.Ctx())
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), engineContextKey{}, "value"))
+	defer cancel()
+	child := NewContext(ctx, engine)
+	assert.Equal(t, ctx, child.Ctx())
+	assert.Equal(t, "value", child.Ctx().Value(engineContextKey{}))
+	assert.Equal(t, context.Background(), engine.Ctx())
+	cancel()
+	assert.Equal(t, context.Canceled, child.Ctx().Err())
+}
@@ -41,6 +41,50 @@ func (e *Engine) redisSearchBase(entities interface{}, query *RedisSearchQuery,
 	return total
 }
 
+func (e *Engine) RedisSearchWithHighlights(entities interface{}, query *RedisSearchQuery, pager *Pager,
+	references ...string) (totalRows uint64, highlights map[uint64]RedisSearchHighlights) {
+	elem := reflect.ValueOf(entities).Elem()
+	_, has, name := getEntityTypeForSlice(e.registry, elem.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	schema := e.GetRegistry().GetTableSchema(name).(*tableSchema)
+	checkRedisSearchQuery(schema, query)
+	fields := make([]string, 0)
+	for _, list := range [][]interface{}{query.highlight, query.summarize} {
+		if list == nil {
+			continue
+		}
+		if len(list) == 0 {
+			fields = fields[:0]
+			for _, field := range schema.redisSearchIndex.Fields {
+				if field.Type == redisSearchIndexFieldText {
+					fields = append(fields, field.Name)
+				}
+			}
+			break
+		}
+		for _, field := range list {
+			fields = append(fields, field.(string))
+		}
+	}
+	totalRows, rows := e.GetRedisSearch(schema.searchCacheName).Search(schema.redisSearchIndex.Name, query, pager)
+	ids := make([]uint64, len(rows))
+	highlights = make(map[uint64]RedisSearchHighlights, len(rows))
+	for i, row := range rows {
+		ids[i], _ = strconv.ParseUint(row.Key[6:], 10, 64)
+		fragments := make(RedisSearchHighlights)
+		for _, field := range fields {
+			if value, is := row.Value(field).(string); is {
+				fragments[field] = value
+			}
+		}
+		highlights[ids[i]] = fragments
+	}
+	tryByIDs(e, ids, elem, references, false)
+	return totalRows, highlights
+}
+
 func (e *Engine) RedisSearchOne(entity Entity, query *RedisSearchQuery, references ...string) (found bool) {
 	return e.redisSearchOne(entity, query, false, references...)
 }
@@ -60,12 +104,23 @@ func (e *Engine) redisSearchOne(entity Entity, query *RedisSearchQuery, lazy boo
 }
 
 func redisSearch(e *Engine, schema *tableSchema, query *RedisSearchQuery, pager *Pager, references []string) ([]uint64, uint64) {
+	checkRedisSearchQuery(schema, query)
+	search := e.GetRedisSearch(schema.searchCacheName)
+	totalRows, res := search.search(schema.redisSearchIndex.Name, query, pager, true)
+	ids := make([]uint64, len(res))
+	for i, v := range res {
+		ids[i], _ = strconv.ParseUint(v.(string)[6:], 10, 64)
+	}
+	return ids, totalRows
+}
+
+func checkRedisSearchQuery(schema *tableSchema, query *RedisSearchQuery) {
 	if schema.redisSearchIndex == nil {
 		panic(errors.Errorf("entity %s is not searchable", schema.t.String()))
 	}
 	for k := range query.filtersString {
 		_, has := schema.columnMapping[k]
-		if !has {
+		if !has && !schema.isRedisSearchComputedField(k) {
 			panic(fmt.Errorf("unknown field %s", k))
 		}
 		valid := false
@@ -85,7 +140,7 @@ func redisSearch(e *Engine, schema *tableSchema, query *RedisSearchQuery, pager
 	}
 	for k := range query.filtersNumeric {
 		_, has := schema.columnMapping[k]
-		if !has {
+		if !has && !schema.isRedisSearchComputedField(k) {
 			panic(fmt.Errorf("unknown field %s", k))
 		}
 		valid := false
@@ -105,7 +160,7 @@ func redisSearch(e *Engine, schema *tableSchema, query *RedisSearchQuery, pager
 	}
 	for k := range query.filtersTags {
 		_, has := schema.columnMapping[k]
-		if !has {
+		if !has && !schema.isRedisSearchComputedField(k) {
 			panic(fmt.Errorf("unknown field %s", k))
 		}
 		valid := false
@@ -123,11 +178,4 @@ func redisSearch(e *Engine, schema *tableSchema, query *RedisSearchQuery, pager
 			panic(fmt.Errorf("missing `searchable` tag for field %s", k))
 		}
 	}
-	search := e.GetRedisSearch(schema.searchCacheName)
-	totalRows, res := search.search(schema.redisSearchIndex.Name, query, pager, true)
-	ids := make([]uint64, len(res))
-	for i, v := range res {
-		ids[i], _ = strconv.ParseUint(v.(string)[6:], 10, 64)
-	}
-	return ids, totalRows
 }
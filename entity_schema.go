@@ -0,0 +1,177 @@
+package orm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type ColumnDefinition struct {
+	Name       string
+	SQLType    string
+	Definition string
+	GoType     reflect.Type
+	Nullable   bool
+	Tags       map[string]string
+}
+
+type IndexDefinition struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+type ReferenceDefinition struct {
+	Column     string
+	Entity     string
+	Many       bool
+	ManyToMany bool
+	ForeignKey bool
+	OnDelete   string
+}
+
+type CachedIndexDefinition struct {
+	Name   string
+	Query  string
+	Max    int
+	One    bool
+	Fields []string
+}
+
+type CachedIndexStats struct {
+	Queries   uint64
+	Fallbacks uint64
+}
+
+func (s CachedIndexStats) FallbackRate() float64 {
+	if s.Queries == 0 {
+		return 0
+	}
+	return float64(s.Fallbacks) / float64(s.Queries)
+}
+
+type CacheDefinition struct {
+	LocalCachePool  string
+	RedisCachePool  string
+	RedisSearchPool string
+	CachePrefix     string
+	CacheMode       string
+	RedisCacheTTL   time.Duration
+	CachedIndexes   []CachedIndexDefinition
+}
+
+func (tableSchema *tableSchema) GetColumnDefinitions(engine *Engine) []ColumnDefinition {
+	columns, err := checkStruct(tableSchema, engine, tableSchema.t, make(map[string]*index), make(map[string]*foreignIndex), "")
+	checkError(err)
+	goTypes := tableSchema.fields.getColumnTypes()
+	definitions := make([]ColumnDefinition, len(columns))
+	for i, column := range columns {
+		name := column[0]
+		definition := strings.TrimPrefix(column[1], "`"+name+"` ")
+		sqlType := definition
+		for _, suffix := range []string{" NOT NULL", " DEFAULT ", " /*!"} {
+			pos := strings.Index(sqlType, suffix)
+			if pos > 0 {
+				sqlType = sqlType[0:pos]
+			}
+		}
+		tags := make(map[string]string, len(tableSchema.tags[name]))
+		for key, value := range tableSchema.tags[name] {
+			tags[key] = value
+		}
+		definitions[i] = ColumnDefinition{Name: name, SQLType: sqlType, Definition: definition, GoType: goTypes[name],
+			Nullable: !strings.Contains(definition, " NOT NULL"), Tags: tags}
+	}
+	return definitions
+}
+
+func (tableSchema *tableSchema) GetIndexDefinitions(engine *Engine) []IndexDefinition {
+	indexes := make(map[string]*index)
+	_, err := checkStruct(tableSchema, engine, tableSchema.t, indexes, make(map[string]*foreignIndex), "")
+	checkError(err)
+	definitions := make([]IndexDefinition, 0, len(indexes))
+	for name, value := range indexes {
+		positions := make([]int, 0, len(value.Columns))
+		for position := range value.Columns {
+			positions = append(positions, position)
+		}
+		sort.Ints(positions)
+		columns := make([]string, len(positions))
+		for i, position := range positions {
+			columns[i] = value.Columns[position]
+		}
+		definitions = append(definitions, IndexDefinition{Name: name, Unique: value.Unique, Columns: columns})
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+func (tableSchema *tableSchema) GetReferenceDefinitions(engine *Engine) []ReferenceDefinition {
+	foreignKeys := make(map[string]*foreignIndex)
+	_, err := checkStruct(tableSchema, engine, tableSchema.t, make(map[string]*index), foreignKeys, "")
+	checkError(err)
+	keys := make(map[string]*foreignIndex, len(foreignKeys))
+	for _, foreignKey := range foreignKeys {
+		keys[foreignKey.Column] = foreignKey
+	}
+	definitions := make([]ReferenceDefinition, 0)
+	for _, column := range tableSchema.refOne {
+		definition := ReferenceDefinition{Column: column, Entity: tableSchema.tags[column]["ref"]}
+		foreignKey, has := keys[column]
+		if has {
+			definition.ForeignKey = true
+			definition.OnDelete = foreignKey.OnDelete
+		}
+		definitions = append(definitions, definition)
+	}
+	for _, column := range tableSchema.refMany {
+		definitions = append(definitions, ReferenceDefinition{Column: column, Entity: tableSchema.tags[column]["refs"], Many: true})
+	}
+	for column, m2m := range tableSchema.manyToMany {
+		definitions = append(definitions, ReferenceDefinition{Column: column, Entity: m2m.refType, Many: true, ManyToMany: true})
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Column < definitions[j].Column
+	})
+	return definitions
+}
+
+func (tableSchema *tableSchema) GetCacheDefinition() CacheDefinition {
+	definition := CacheDefinition{LocalCachePool: tableSchema.localCacheName, RedisCachePool: tableSchema.redisCacheName,
+		RedisSearchPool: tableSchema.searchCacheName, CachePrefix: tableSchema.cachePrefix,
+		CacheMode: tableSchema.cacheMode, RedisCacheTTL: tableSchema.redisCacheTTL, CachedIndexes: make([]CachedIndexDefinition, 0, len(tableSchema.cachedIndexesAll))}
+	for name, index := range tableSchema.cachedIndexesAll {
+		_, isOne := tableSchema.cachedIndexesOne[name]
+		definition.CachedIndexes = append(definition.CachedIndexes, CachedIndexDefinition{Name: name, Query: index.Query,
+			Max: index.Max, One: isOne, Fields: index.TrackedFields})
+	}
+	sort.Slice(definition.CachedIndexes, func(i, j int) bool {
+		return definition.CachedIndexes[i].Name < definition.CachedIndexes[j].Name
+	})
+	return definition
+}
+
+func (tableSchema *tableSchema) GetCachedIndexStats() map[string]CachedIndexStats {
+	stats := make(map[string]CachedIndexStats, len(tableSchema.cachedIndexes))
+	for name, index := range tableSchema.cachedIndexes {
+		stats[name] = CachedIndexStats{Queries: atomic.LoadUint64(&index.queries), Fallbacks: atomic.LoadUint64(&index.fallbacks)}
+	}
+	return stats
+}
+
+func (fields *tableFields) getColumnTypes() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, f := range fields.fields {
+		types[fields.prefix+f.Name] = f.Type
+	}
+	for _, subFields := range fields.structs {
+		for column, t := range subFields.getColumnTypes() {
+			types[column] = t
+		}
+	}
+	return types
+}
@@ -0,0 +1,73 @@
+package orm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type entitySchemaEntity struct {
+	ORM       `orm:"localCache;redisCache"`
+	ID        uint
+	Name      string `orm:"index=NameAge;required"`
+	Age       *uint8 `orm:"index=NameAge:2"`
+	Code      string `orm:"unique=Code"`
+	Owner     *entitySchemaRef
+	Parent    *entitySchemaRef `orm:"skip_FK"`
+	Friends   []*entitySchemaRef
+	IndexAge  *CachedQuery `query:":Age = ?"`
+	IndexCode *CachedQuery `queryOne:":Code = ?"`
+}
+
+type entitySchemaRef struct {
+	ORM
+	ID uint
+}
+
+func TestEntitySchema(t *testing.T) {
+	var entity *entitySchemaEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity, &entitySchemaRef{})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+
+	columns := schema.GetColumnDefinitions(engine)
+	assert.Len(t, columns, 7)
+	assert.Equal(t, "ID", columns[0].Name)
+	assert.Equal(t, "int(10) unsigned", columns[0].SQLType)
+	assert.False(t, columns[0].Nullable)
+	assert.Equal(t, "Name", columns[1].Name)
+	assert.Equal(t, "varchar(255)", columns[1].SQLType)
+	assert.Equal(t, "varchar(255) NOT NULL DEFAULT ''", columns[1].Definition)
+	assert.Equal(t, reflect.TypeOf(""), columns[1].GoType)
+	assert.Equal(t, map[string]string{"index": "NameAge", "required": "true"}, columns[1].Tags)
+	assert.Equal(t, "Age", columns[2].Name)
+	assert.Equal(t, "tinyint(3) unsigned", columns[2].SQLType)
+	assert.True(t, columns[2].Nullable)
+	assert.Equal(t, reflect.TypeOf((*uint8)(nil)), columns[2].GoType)
+	assert.Equal(t, "Friends", columns[6].Name)
+	assert.Equal(t, "json", columns[6].SQLType)
+
+	indexes := schema.GetIndexDefinitions(engine)
+	assert.Equal(t, []IndexDefinition{
+		{Name: "Code", Unique: true, Columns: []string{"Code"}},
+		{Name: "NameAge", Columns: []string{"Name", "Age"}},
+		{Name: "Owner", Columns: []string{"Owner"}},
+	}, indexes)
+
+	references := schema.GetReferenceDefinitions(engine)
+	assert.Equal(t, []ReferenceDefinition{
+		{Column: "Friends", Entity: "orm.entitySchemaRef", Many: true},
+		{Column: "Owner", Entity: "orm.entitySchemaRef", ForeignKey: true, OnDelete: "RESTRICT"},
+		{Column: "Parent", Entity: "orm.entitySchemaRef"},
+	}, references)
+
+	cache := schema.GetCacheDefinition()
+	assert.Equal(t, "default", cache.LocalCachePool)
+	assert.Equal(t, "default", cache.RedisCachePool)
+	assert.Equal(t, "", cache.RedisSearchPool)
+	assert.Equal(t, CacheModeWriteThrough, cache.CacheMode)
+	assert.Len(t, cache.CachedIndexes, 2)
+	assert.Equal(t, CachedIndexDefinition{Name: "IndexAge", Query: "`Age` = ?", Max: 50000, Fields: []string{"Age"}}, cache.CachedIndexes[0])
+	assert.Equal(t, "IndexCode", cache.CachedIndexes[1].Name)
+	assert.True(t, cache.CachedIndexes[1].One)
+}
@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var envLoaderKeys = []struct {
+	suffix string
+	key    string
+}{
+	{"_MYSQL_ENCODING", "mysqlEncoding"},
+	{"_ELASTIC_TRACE", "elastic_trace"},
+	{"_LOCAL_CACHE", "local_cache"},
+	{"_CLICKHOUSE", "clickhouse"},
+	{"_SENTINEL", "sentinel"},
+	{"_ELASTIC", "elastic"},
+	{"_STREAMS", "streams"},
+	{"_MYSQL", "mysql"},
+	{"_REDIS", "redis"},
+}
+
+func (r *Registry) InitByEnv(prefix string) {
+	r.InitByYaml(buildEnvConfig(prefix, os.Environ()))
+}
+
+func buildEnvConfig(prefix string, environ []string) map[string]interface{} {
+	config := make(map[string]interface{})
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_"
+	for _, row := range environ {
+		parts := strings.SplitN(row, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+		name := parts[0][len(prefix)-1:]
+		for _, definition := range envLoaderKeys {
+			if !strings.HasSuffix(name, definition.suffix) || len(name) == len(definition.suffix) {
+				continue
+			}
+			pool := strings.ToLower(name[1 : len(name)-len(definition.suffix)])
+			poolConfig, has := config[pool].(map[string]interface{})
+			if !has {
+				poolConfig = make(map[string]interface{})
+				config[pool] = poolConfig
+			}
+			poolConfig[definition.key] = parseEnvValue(definition.key, parts[0], strings.TrimSpace(parts[1]))
+			break
+		}
+	}
+	return config
+}
+
+func parseEnvValue(key, name, value string) interface{} {
+	switch key {
+	case "local_cache":
+		if value == "" {
+			return 1000
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			panic(fmt.Errorf("orm env %s: %v is not valid", name, value))
+		}
+		return size
+	case "redis":
+		if strings.Count(value, ":") == 1 {
+			return value + ":0"
+		}
+		return value
+	case "streams":
+		streams := make(map[string]interface{})
+		for _, stream := range strings.Split(value, ";") {
+			parts := strings.SplitN(stream, ":", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				panic(fmt.Errorf("orm env %s: %v is not valid", name, value))
+			}
+			streams[strings.TrimSpace(parts[0])] = splitEnvList(parts[1])
+		}
+		return streams
+	case "sentinel":
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			panic(fmt.Errorf("orm env %s: %v is not valid", name, value))
+		}
+		return map[string]interface{}{strings.TrimSpace(parts[0]): splitEnvList(parts[1])}
+	}
+	return value
+}
+
+func splitEnvList(value string) []interface{} {
+	elements := strings.Split(value, ",")
+	result := make([]interface{}, len(elements))
+	for i, element := range elements {
+		result[i] = strings.TrimSpace(element)
+	}
+	return result
+}
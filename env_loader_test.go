@@ -0,0 +1,56 @@
+package orm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvLoader(t *testing.T) {
+	config := buildEnvConfig("APP", []string{
+		"APP_DEFAULT_MYSQL=root:root@tcp(localhost:3308)/test",
+		"APP_DEFAULT_MYSQL_ENCODING=utf8",
+		"APP_DEFAULT_REDIS=localhost:6382",
+		"APP_DEFAULT_STREAMS=stream-1:test-group-1,test-group-2;stream-2:test-group-1",
+		"APP_DEFAULT_LOCAL_CACHE=",
+		"APP_DEFAULT_QUEUE_REDIS=localhost:6382:1",
+		"APP_ANOTHER_SENTINEL=master:1=:26379,192.156.23.11:26379",
+		"APP_ANOTHER_STREAMS=stream-3:test-group-1",
+		"APP_UNKNOWN=1",
+		"OTHER_DEFAULT_MYSQL=root:root@tcp(localhost:3309)/test",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"default": map[string]interface{}{
+			"mysql":         "root:root@tcp(localhost:3308)/test",
+			"mysqlEncoding": "utf8",
+			"redis":         "localhost:6382:0",
+			"streams": map[string]interface{}{
+				"stream-1": []interface{}{"test-group-1", "test-group-2"},
+				"stream-2": []interface{}{"test-group-1"},
+			},
+			"local_cache": 1000,
+		},
+		"default_queue": map[string]interface{}{"redis": "localhost:6382:1"},
+		"another": map[string]interface{}{
+			"sentinel": map[string]interface{}{"master:1": []interface{}{":26379", "192.156.23.11:26379"}},
+			"streams":  map[string]interface{}{"stream-3": []interface{}{"test-group-1"}},
+		},
+	}, config)
+
+	os.Setenv("ORM_TEST_DEFAULT_REDIS", "localhost:6382:0")
+	os.Setenv("ORM_TEST_DEFAULT_STREAMS", "stream-1:test-group-1")
+	defer os.Unsetenv("ORM_TEST_DEFAULT_REDIS")
+	defer os.Unsetenv("ORM_TEST_DEFAULT_STREAMS")
+	registry := NewRegistry()
+	registry.InitByEnv("ORM_TEST")
+	assert.NotNil(t, registry.redisPools["default"])
+	assert.True(t, registry.redisStreamGroups["default"]["stream-1"]["test-group-1"])
+
+	assert.PanicsWithError(t, "orm env APP_DEFAULT_LOCAL_CACHE: big is not valid", func() {
+		buildEnvConfig("APP", []string{"APP_DEFAULT_LOCAL_CACHE=big"})
+	})
+	assert.PanicsWithError(t, "orm env APP_DEFAULT_STREAMS: stream is not valid", func() {
+		buildEnvConfig("APP", []string{"APP_DEFAULT_STREAMS=stream"})
+	})
+}
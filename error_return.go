@@ -0,0 +1,94 @@
+package orm
+
+import "fmt"
+
+func catchError(run func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			asErr, is := r.(error)
+			if !is {
+				asErr = fmt.Errorf("%v", r)
+			}
+			err = asErr
+		}
+	}()
+	run()
+	return nil
+}
+
+func (e *Engine) LoadByIDE(id uint64, entity Entity, references ...string) (found bool, err error) {
+	err = catchError(func() {
+		found = e.LoadByID(id, entity, references...)
+	})
+	return found, err
+}
+
+func (e *Engine) LoadByIDsE(ids []uint64, entities interface{}, references ...string) (missing bool, err error) {
+	err = catchError(func() {
+		missing = e.LoadByIDs(ids, entities, references...)
+	})
+	return missing, err
+}
+
+func (e *Engine) SearchE(where *Where, pager *Pager, entities interface{}, references ...string) error {
+	return catchError(func() {
+		e.Search(where, pager, entities, references...)
+	})
+}
+
+func (e *Engine) SearchWithCountE(where *Where, pager *Pager, entities interface{}, references ...string) (totalRows int, err error) {
+	err = catchError(func() {
+		totalRows = e.SearchWithCount(where, pager, entities, references...)
+	})
+	return totalRows, err
+}
+
+func (e *Engine) SearchIDsE(where *Where, pager *Pager, entity Entity) (ids []uint64, err error) {
+	err = catchError(func() {
+		ids = e.SearchIDs(where, pager, entity)
+	})
+	return ids, err
+}
+
+func (e *Engine) SearchOneE(where *Where, entity Entity, references ...string) (found bool, err error) {
+	err = catchError(func() {
+		found = e.SearchOne(where, entity, references...)
+	})
+	return found, err
+}
+
+func (e *Engine) CountE(where *Where, entity Entity) (total int, err error) {
+	err = catchError(func() {
+		total = e.Count(where, entity)
+	})
+	return total, err
+}
+
+func (e *Engine) FlushE(entity Entity) error {
+	return e.FlushManyE(entity)
+}
+
+func (e *Engine) FlushManyE(entities ...Entity) error {
+	return e.NewFlusher().Track(entities...).FlushWithFullCheck()
+}
+
+func (db *DB) ExecE(query string, args ...interface{}) (result ExecResult, err error) {
+	err = catchError(func() {
+		result = db.Exec(query, args...)
+	})
+	return result, err
+}
+
+func (db *DB) QueryRowE(query *Where, toFill ...interface{}) (found bool, err error) {
+	err = catchError(func() {
+		found = db.QueryRow(query, toFill...)
+	})
+	return found, err
+}
+
+func (db *DB) QueryE(query string, args ...interface{}) (rows Rows, deferF func(), err error) {
+	err = catchError(func() {
+		rows, deferF = db.Query(query, args...)
+	})
+	return rows, deferF, err
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type errorReturnEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"unique=Name"`
+}
+
+func TestErrorReturnVariants(t *testing.T) {
+	var entity *errorReturnEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	assert.NoError(t, engine.FlushManyE(&errorReturnEntity{Name: "a"}, &errorReturnEntity{Name: "b"}))
+
+	err := engine.FlushE(&errorReturnEntity{Name: "a"})
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+
+	entity = &errorReturnEntity{}
+	found, err := engine.LoadByIDE(1, entity)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "a", entity.Name)
+
+	var rows []*errorReturnEntity
+	missing, err := engine.LoadByIDsE([]uint64{1, 2, 3}, &rows)
+	assert.NoError(t, err)
+	assert.True(t, missing)
+
+	total, err := engine.SearchWithCountE(NewWhere("1"), nil, &rows)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, rows, 2)
+
+	err = engine.SearchE(NewWhere("`Invalid` = 1"), nil, &rows)
+	assert.Error(t, err)
+	ids, err := engine.SearchIDsE(NewWhere("`Invalid` = 1"), nil, entity)
+	assert.Error(t, err)
+	assert.Nil(t, ids)
+	_, err = engine.SearchOneE(NewWhere("`Name` = ?", "b"), entity)
+	assert.NoError(t, err)
+	count, err := engine.CountE(NewWhere("1"), entity)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	err = engine.SearchE(NewWhere("1"), nil, rows)
+	assert.Error(t, err)
+
+	result, err := engine.GetMysql().ExecE("UPDATE `errorReturnEntity` SET `Name` = ? WHERE `ID` = 2", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), result.RowsAffected())
+	_, err = engine.GetMysql().ExecE("UPDATE `invalid_table` SET `Name` = 'c'")
+	assert.Error(t, err)
+	var name string
+	found, err = engine.GetMysql().QueryRowE(NewWhere("SELECT `Name` FROM `errorReturnEntity` WHERE `ID` = 2"), &name)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "c", name)
+	_, _, err = engine.GetMysql().QueryE("SELECT * FROM `invalid_table`")
+	assert.Error(t, err)
+	rowsResult, def, err := engine.GetMysql().QueryE("SELECT `ID` FROM `errorReturnEntity`")
+	assert.NoError(t, err)
+	assert.True(t, rowsResult.Next())
+	def()
+}
@@ -0,0 +1,124 @@
+package orm
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrDuplicateKey      = errors.New("duplicate key")
+	ErrForeignKey        = errors.New("foreign key")
+	ErrLockTimeout       = errors.New("lock timeout")
+	ErrPoolNotRegistered = errors.New("pool not registered")
+	ErrReadOnly          = errors.New("read only")
+	ErrRowLimit          = errors.New("row limit exceeded")
+)
+
+type DuplicatedKeyError struct {
+	Message string
+	Index   string
+	Columns []string
+	Value   string
+	ID      uint64
+	Err     error
+}
+
+func (err *DuplicatedKeyError) Error() string {
+	return err.Message
+}
+
+func (err *DuplicatedKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+func (err *DuplicatedKeyError) Unwrap() error {
+	return err.Err
+}
+
+type ForeignKeyError struct {
+	Message    string
+	Constraint string
+	Err        error
+}
+
+func (err *ForeignKeyError) Error() string {
+	return err.Message
+}
+
+func (err *ForeignKeyError) Is(target error) bool {
+	return target == ErrForeignKey
+}
+
+func (err *ForeignKeyError) Unwrap() error {
+	return err.Err
+}
+
+type ReadOnlyError struct {
+	Message string
+}
+
+func (err *ReadOnlyError) Error() string {
+	return err.Message
+}
+
+func (err *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
+type LockTimeoutError struct {
+	Message string
+	Err     error
+}
+
+func (err *LockTimeoutError) Error() string {
+	return err.Message
+}
+
+func (err *LockTimeoutError) Is(target error) bool {
+	return target == ErrLockTimeout
+}
+
+func (err *LockTimeoutError) Unwrap() error {
+	return err.Err
+}
+
+type NotFoundError struct {
+	Entity string
+	ID     uint64
+}
+
+func (err *NotFoundError) Error() string {
+	return "entity " + err.Entity + " [" + strconv.FormatUint(err.ID, 10) + "] not found"
+}
+
+func (err *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+type PoolNotRegisteredError struct {
+	Type string
+	Code string
+}
+
+func (err *PoolNotRegisteredError) Error() string {
+	return "unregistered " + err.Type + " pool '" + err.Code + "'"
+}
+
+func (err *PoolNotRegisteredError) Is(target error) bool {
+	return target == ErrPoolNotRegistered
+}
+
+type RowLimitError struct {
+	Entity string
+	Limit  int
+}
+
+func (err *RowLimitError) Error() string {
+	return "search for entity '" + err.Entity + "' exceeded limit of " + strconv.Itoa(err.Limit) +
+		" rows, use pager to load rows in smaller pages or raise limit with Where.WithMaxRows"
+}
+
+func (err *RowLimitError) Is(target error) bool {
+	return target == ErrRowLimit
+}
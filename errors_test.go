@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+type errorsEntity struct {
+	ORM     `orm:"unique=NameAge:Name,Age"`
+	ID      uint
+	Name    string
+	Age     uint
+	Counter uint
+	Ref     *errorsEntityRef
+}
+
+type errorsEntityRef struct {
+	ORM
+	ID uint
+}
+
+func TestTypedErrors(t *testing.T) {
+	var entity *errorsEntity
+	var ref *errorsEntityRef
+	engine := PrepareTables(t, &Registry{}, 5, entity, ref)
+	engine.Flush(&errorsEntity{Name: "a", Age: 1})
+
+	err := engine.FlushWithCheck(&errorsEntity{Name: "a", Age: 1})
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+	assert.False(t, errors.Is(err, ErrForeignKey))
+	var duplicated *DuplicatedKeyError
+	assert.True(t, errors.As(err, &duplicated))
+	assert.Equal(t, "NameAge", duplicated.Index)
+	assert.Equal(t, []string{"Name", "Age"}, duplicated.Columns)
+	assert.Equal(t, "a-1", duplicated.Value)
+	var mysqlErr *mysql.MySQLError
+	assert.True(t, errors.As(err, &mysqlErr))
+	assert.Equal(t, uint16(1062), mysqlErr.Number)
+
+	err = engine.FlushWithCheck(&errorsEntity{Name: "b", Ref: &errorsEntityRef{ID: 100}})
+	assert.True(t, errors.Is(err, ErrForeignKey))
+	var foreignKey *ForeignKeyError
+	assert.True(t, errors.As(err, &foreignKey))
+	assert.Equal(t, "test:errorsEntity:Ref", foreignKey.Constraint)
+
+	err = engine.NewFlusher().Track(&errorsEntity{Name: "c"}).FlushWithCheck()
+	assert.NoError(t, err)
+	engine.SetReadOnly(true)
+	err = engine.FlushWithCheck(&errorsEntity{Name: "d"})
+	assert.True(t, errors.Is(err, ErrReadOnly))
+	engine.SetReadOnly(false)
+
+	missing := &errorsEntity{}
+	assert.True(t, engine.LoadByID(1, missing))
+	engine.GetMysql().Exec("DELETE FROM `errorsEntity` WHERE `ID` = 1")
+	assert.PanicsWithError(t, "entity orm.errorsEntity [1] not found", func() {
+		defer func() {
+			r := recover()
+			assert.True(t, errors.Is(r.(error), ErrNotFound))
+			panic(r)
+		}()
+		engine.IncrementField(missing, "Counter", 1)
+	})
+
+	assert.PanicsWithError(t, "unregistered mysql pool 'invalid'", func() {
+		defer func() {
+			r := recover()
+			assert.True(t, errors.Is(r.(error), ErrPoolNotRegistered))
+			var poolErr *PoolNotRegisteredError
+			assert.True(t, errors.As(r.(error), &poolErr))
+			assert.Equal(t, "mysql", poolErr.Type)
+			assert.Equal(t, "invalid", poolErr.Code)
+			panic(r)
+		}()
+		engine.GetMysql("invalid")
+	})
+}
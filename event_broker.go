@@ -173,7 +173,7 @@ func (eb *eventBroker) Publish(stream string, event interface{}) (id string) {
 }
 
 func getRedisForStream(engine *Engine, stream string) *RedisCache {
-	pool, has := engine.registry.redisStreamPools[stream]
+	pool, has := engine.registry.getRedisStreamPool(stream)
 	if !has {
 		panic(fmt.Errorf("unregistered stream %s", stream))
 	}
@@ -189,6 +189,7 @@ type EventsConsumer interface {
 	SetLimit(limit int)
 	SetHeartBeat(duration time.Duration, beat func())
 	SetErrorHandler(handler ConsumerErrorHandler)
+	SetRecoveryHandler(handler RecoveryHandler)
 }
 
 type speedHandler struct {
@@ -218,30 +219,29 @@ func (s *speedHandler) Clear() {
 
 func (eb *eventBroker) Consumer(name, group string) EventsConsumer {
 	streams := make([]string, 0)
-	for _, row := range eb.engine.registry.redisStreamGroups {
+	redisPool := ""
+	for pool, row := range eb.engine.registry.GetRedisStreams() {
 		for stream, groups := range row {
-			_, has := groups[group]
-			if has {
+			for _, name := range groups {
+				if name != group {
+					continue
+				}
 				streams = append(streams, stream)
+				if redisPool == "" {
+					redisPool = pool
+				} else if redisPool != pool {
+					panic(fmt.Errorf("reading from different redis pool not allowed"))
+				}
 			}
 		}
 	}
 	if len(streams) == 0 {
 		panic(fmt.Errorf("unregistered streams for group %s", group))
 	}
-	redisPool := ""
-	for _, stream := range streams {
-		pool := eb.engine.registry.redisStreamPools[stream]
-		if redisPool == "" {
-			redisPool = pool
-		} else if redisPool != pool {
-			panic(fmt.Errorf("reading from different redis pool not allowed"))
-		}
-	}
 	speedPrefixKey := group + "_" + redisPool
 	speedLogger := &speedHandler{}
 	eb.engine.AddQueryLogger(speedLogger, logApex.InfoLevel, QueryLoggerSourceDB, QueryLoggerSourceRedis, QueryLoggerSourceStreams)
-	return &eventsConsumer{eventConsumerBase: eventConsumerBase{loop: true, limit: 1, blockTime: time.Second * 30},
+	return &eventsConsumer{eventConsumerBase: eventConsumerBase{loop: true, limit: 1, blockTime: time.Second * 30, clock: eb.engine.GetClock()},
 		redis: eb.engine.GetRedis(redisPool), name: name, streams: streams, group: group,
 		lockTTL: time.Second * 90, lockTick: time.Minute,
 		garbageTick: time.Second * 30, minIdle: pendingClaimCheckDuration,
@@ -257,6 +257,8 @@ type eventConsumerBase struct {
 	heartBeatDuration time.Duration
 	heartBeatTime     time.Time
 	blockTime         time.Duration
+	clock             Clock
+	recoveryHandler   RecoveryHandler
 }
 
 type eventsConsumer struct {
@@ -303,9 +305,9 @@ func (b *eventConsumerBase) SetErrorHandler(handler ConsumerErrorHandler) {
 }
 
 func (b *eventConsumerBase) HeartBeat(force bool) {
-	if b.heartBeat != nil && (force || time.Since(b.heartBeatTime) >= b.heartBeatDuration) {
+	if b.heartBeat != nil && (force || b.clock.Now().Sub(b.heartBeatTime) >= b.heartBeatDuration) {
 		b.heartBeat()
-		b.heartBeatTime = time.Now()
+		b.heartBeatTime = b.clock.Now()
 	}
 }
 
@@ -339,7 +341,7 @@ func (r *eventsConsumer) consume(ctx context.Context, count int, blocking bool,
 		lock = locked
 		r.nr = nr
 		r.nrString = strconv.Itoa(nr)
-		r.redis.HSet(runningKey, r.nrString, strconv.FormatInt(time.Now().Unix(), 10))
+		r.redis.HSet(runningKey, r.nrString, strconv.FormatInt(r.clock.Now().Unix(), 10))
 		break
 	}
 	ticker := time.NewTicker(r.lockTick)
@@ -396,7 +398,7 @@ func (r *eventsConsumer) consume(ctx context.Context, count int, blocking bool,
 	keys := []string{"pending", "0", ">"}
 	streams := make([]string, len(r.streams)*2)
 	if r.heartBeat != nil {
-		r.heartBeatTime = time.Now()
+		r.heartBeatTime = r.clock.Now()
 	}
 	pendingChecked := false
 	var pendingCheckedTime time.Time
@@ -516,10 +518,14 @@ func (r *eventsConsumer) consume(ctx context.Context, count int, blocking bool,
 				}
 				r.speedEvents += totalMessages
 				r.speedLogger.Clear()
-				start := time.Now()
+				start := r.clock.Now()
 				func() {
 					defer func() {
 						if rec := recover(); rec != nil {
+							if r.handleRecovery(rec, events) {
+								events = make([]Event, 0)
+								return
+							}
 							if r.errorHandler != nil {
 								finalEvents := make([]Event, 0)
 								for _, row := range events {
@@ -549,7 +555,7 @@ func (r *eventsConsumer) consume(ctx context.Context, count int, blocking bool,
 					}()
 					handler(events)
 				}()
-				r.speedTimeMicroseconds += time.Since(start).Microseconds()
+				r.speedTimeMicroseconds += r.clock.Now().Sub(start).Microseconds()
 				r.speedDBQueries += r.speedLogger.DBQueries
 				r.speedRedisQueries += r.speedLogger.RedisQueries
 				r.speedDBMicroseconds += r.speedLogger.DBMicroseconds
@@ -572,7 +578,7 @@ func (r *eventsConsumer) consume(ctx context.Context, count int, blocking bool,
 					r.redis.XAck(stream, r.group, ids...)
 				}
 				if r.speedEvents >= r.speedLimit {
-					today := time.Now().Format("01-02-06")
+					today := r.clock.Now().Format("01-02-06")
 					key := speedHSetKey + today
 					pipeline := r.redis.PipeLine()
 					pipeline.Expire(key, time.Hour*216)
@@ -625,11 +631,10 @@ func (r *eventsConsumer) garbageCollector(ctx context.Context, engine *Engine) {
 			return
 		}
 	}
-	def := engine.registry.redisStreamGroups[redisGarbage.config.GetCode()]
 	for _, stream := range r.streams {
 		info := redisGarbage.XInfoGroups(stream)
 		ids := make(map[string][]int64)
-		for name := range def[stream] {
+		for name := range engine.registry.getRedisStreamGroups(redisGarbage.config.GetCode(), stream) {
 			ids[name] = []int64{0, 0}
 		}
 		inPending := false
@@ -0,0 +1,100 @@
+package orm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type ExplainRow struct {
+	ID           int
+	SelectType   string
+	Table        string
+	Type         string
+	PossibleKeys []string
+	Key          string
+	KeyLen       string
+	Ref          string
+	Rows         uint64
+	Filtered     float64
+	Extra        string
+}
+
+func (e *Engine) ExplainSearch(where *Where, pager *Pager, entity Entity) []*ExplainRow {
+	pool, query, where := prepareExplainSearch(e, where, pager, entity)
+	results, def := pool.Query("EXPLAIN "+query, where.GetParameters()...)
+	defer def()
+	columns := results.Columns()
+	rows := make([]*ExplainRow, 0)
+	for results.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		results.Scan(pointers...)
+		row := &ExplainRow{}
+		for i, column := range columns {
+			value := values[i].String
+			switch strings.ToLower(column) {
+			case "id":
+				row.ID, _ = strconv.Atoi(value)
+			case "select_type":
+				row.SelectType = value
+			case "table":
+				row.Table = value
+			case "type":
+				row.Type = value
+			case "possible_keys":
+				if value != "" {
+					row.PossibleKeys = strings.Split(value, ",")
+				}
+			case "key":
+				row.Key = value
+			case "key_len":
+				row.KeyLen = value
+			case "ref":
+				row.Ref = value
+			case "rows":
+				row.Rows, _ = strconv.ParseUint(value, 10, 64)
+			case "filtered":
+				row.Filtered, _ = strconv.ParseFloat(value, 64)
+			case "extra":
+				row.Extra = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	def()
+	return rows
+}
+
+func (e *Engine) ExplainAnalyzeSearch(where *Where, pager *Pager, entity Entity) string {
+	pool, query, where := prepareExplainSearch(e, where, pager, entity)
+	results, def := pool.Query("EXPLAIN ANALYZE "+query, where.GetParameters()...)
+	defer def()
+	lines := make([]string, 0)
+	for results.Next() {
+		var line string
+		results.Scan(&line)
+		lines = append(lines, line)
+	}
+	def()
+	return strings.Join(lines, "\n")
+}
+
+func prepareExplainSearch(engine *Engine, where *Where, pager *Pager, entity Entity) (*DB, string, *Where) {
+	entityType := reflect.TypeOf(entity)
+	if entityType.Kind() == reflect.Ptr {
+		entityType = entityType.Elem()
+	}
+	schema := getTableSchema(engine.registry, entityType)
+	if schema == nil {
+		panic(fmt.Errorf("entity '%s' is not registered", entityType.String()))
+	}
+	pager = preparePager(pager, 50000)
+	query, where := buildSearchQuery(true, engine, schema, where, pager)
+	return schema.GetMysql(engine), query, where
+}
@@ -0,0 +1,36 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type explainEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"index=NameIndex"`
+	Age  int
+}
+
+func TestExplainSearch(t *testing.T) {
+	var entity *explainEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.FlushMany(&explainEntity{Name: "a", Age: 1}, &explainEntity{Name: "b", Age: 2})
+
+	rows := engine.ExplainSearch(NewWhere("`Name` = ?", "a"), NewPager(1, 10), entity)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, 1, rows[0].ID)
+	assert.Equal(t, "SIMPLE", rows[0].SelectType)
+	assert.Equal(t, "explainEntity", rows[0].Table)
+	assert.Equal(t, "ref", rows[0].Type)
+	assert.Equal(t, []string{"NameIndex"}, rows[0].PossibleKeys)
+	assert.Equal(t, "NameIndex", rows[0].Key)
+	assert.Equal(t, uint64(1), rows[0].Rows)
+
+	rows = engine.ExplainSearch(NewWhere("`Age` = ?", 1), nil, entity)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "ALL", rows[0].Type)
+	assert.Equal(t, "", rows[0].Key)
+	assert.Nil(t, rows[0].PossibleKeys)
+}
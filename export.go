@@ -0,0 +1,198 @@
+package orm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type ExportFormat int
+
+const (
+	ExportCSV ExportFormat = iota
+	ExportNDJSON
+)
+
+type ExportOptions struct {
+	Fields    []string
+	Mask      func(entity Entity, field string, value interface{}) interface{}
+	ChunkSize int
+	Comma     rune
+}
+
+func (e *Engine) Export(where *Where, entity Entity, writer io.Writer, format ExportFormat, options *ExportOptions) (rows int, err error) {
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	chunkSize := options.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = getExportFields(schema)
+	}
+	for _, field := range fields {
+		_, has := schema.t.FieldByName(field)
+		if !has {
+			panic(fmt.Errorf("field %s not found", field))
+		}
+	}
+	var csvWriter *csv.Writer
+	switch format {
+	case ExportCSV:
+		csvWriter = csv.NewWriter(writer)
+		if options.Comma != 0 {
+			csvWriter.Comma = options.Comma
+		}
+		err = csvWriter.Write(fields)
+		if err != nil {
+			return 0, err
+		}
+	case ExportNDJSON:
+	default:
+		panic(fmt.Errorf("unsupported export format %d", format))
+	}
+	lastID := uint64(0)
+	for {
+		chunk := &Where{query: "`ID` > ? AND (" + where.query + ") ORDER BY `ID`", references: where.references,
+			entities: where.entities, spatials: where.spatials}
+		chunk.parameters = append([]interface{}{lastID}, where.parameters...)
+		entities := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+		e.Search(chunk, NewPager(1, chunkSize), entities.Interface())
+		total := entities.Elem().Len()
+		for i := 0; i < total; i++ {
+			row := entities.Elem().Index(i).Interface().(Entity)
+			values := make([]interface{}, len(fields))
+			for j, field := range fields {
+				values[j] = getExportValue(schema, row, field)
+				if options.Mask != nil {
+					values[j] = options.Mask(row, field, values[j])
+				}
+			}
+			if csvWriter != nil {
+				err = writeExportCSV(csvWriter, values)
+			} else {
+				err = writeExportJSON(writer, fields, values)
+			}
+			if err != nil {
+				return rows, err
+			}
+			rows++
+			lastID = row.GetID()
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			err = csvWriter.Error()
+			if err != nil {
+				return rows, err
+			}
+		}
+		if total < chunkSize {
+			return rows, nil
+		}
+	}
+}
+
+func getExportFields(schema *tableSchema) []string {
+	fields := make([]string, 0)
+	for i := 0; i < schema.t.NumField(); i++ {
+		field := schema.t.Field(i)
+		if field.PkgPath != "" || field.Anonymous || field.Type.String() == "*orm.CachedQuery" {
+			continue
+		}
+		_, ignored := schema.tags[field.Name]["ignore"]
+		if ignored {
+			continue
+		}
+		fields = append(fields, field.Name)
+	}
+	return fields
+}
+
+func getExportValue(schema *tableSchema, entity Entity, field string) interface{} {
+	value := reflect.ValueOf(entity).Elem().FieldByName(field)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		reference, isEntity := value.Interface().(Entity)
+		if isEntity {
+			return reference.GetID()
+		}
+		value = value.Elem()
+	case reflect.Slice:
+		if value.Type().Elem().Implements(reflect.TypeOf((*Entity)(nil)).Elem()) {
+			ids := make([]uint64, value.Len())
+			for i := range ids {
+				ids[i] = value.Index(i).Interface().(Entity).GetID()
+			}
+			return ids
+		}
+	}
+	switch v := value.Interface().(type) {
+	case time.Time:
+		if schema.tags[field]["time"] == "true" {
+			return v.Format("2006-01-02 15:04:05")
+		}
+		return v.Format("2006-01-02")
+	case Decimal:
+		return v.String()
+	case Point:
+		return v.WKT()
+	case Polygon:
+		return v.WKT()
+	}
+	return value.Interface()
+}
+
+func writeExportCSV(writer *csv.Writer, values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		kind := reflect.TypeOf(value).Kind()
+		if (kind == reflect.Slice || kind == reflect.Map) && reflect.ValueOf(value).Len() == 0 {
+			continue
+		}
+		if kind == reflect.Slice || kind == reflect.Map || kind == reflect.Struct {
+			asJSON, err := jsoniter.ConfigFastest.Marshal(value)
+			if err != nil {
+				return err
+			}
+			record[i] = string(asJSON)
+			continue
+		}
+		record[i] = fmt.Sprintf("%v", value)
+	}
+	return writer.Write(record)
+}
+
+func writeExportJSON(writer io.Writer, fields []string, values []interface{}) error {
+	builder := strings.Builder{}
+	builder.WriteString("{")
+	for i, field := range fields {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		key, _ := jsoniter.ConfigFastest.Marshal(field)
+		builder.Write(key)
+		builder.WriteString(":")
+		value, err := jsoniter.ConfigFastest.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		builder.Write(value)
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
@@ -0,0 +1,60 @@
+package orm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type exportEntity struct {
+	ORM
+	ID        uint
+	Name      string
+	Email     string
+	Tags      []string
+	CreatedAt time.Time `orm:"time"`
+	Secret    string    `orm:"ignore"`
+	Parent    *exportEntity
+}
+
+func TestExport(t *testing.T) {
+	var entity *exportEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+
+	createdAt := time.Date(2021, 3, 1, 10, 11, 12, 0, time.UTC)
+	parent := &exportEntity{Name: "Tom", Email: "tom@example.com", CreatedAt: createdAt}
+	engine.Flush(parent)
+	engine.Flush(&exportEntity{Name: "John", Email: "john@example.com", Tags: []string{"a", "b"}, CreatedAt: createdAt, Parent: parent})
+	engine.Flush(&exportEntity{Name: "Adam", Email: "adam@example.com", CreatedAt: createdAt})
+
+	buffer := &bytes.Buffer{}
+	rows, err := engine.Export(NewWhere("1"), entity, buffer, ExportCSV, &ExportOptions{ChunkSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "ID,Name,Email,Tags,CreatedAt,Parent", lines[0])
+	assert.Equal(t, "1,Tom,tom@example.com,,2021-03-01 10:11:12,", lines[1])
+	assert.Equal(t, "2,John,john@example.com,\"[\"\"a\"\",\"\"b\"\"]\",2021-03-01 10:11:12,1", lines[2])
+
+	buffer.Reset()
+	rows, err = engine.Export(NewWhere("`Name` != ?", "Tom"), entity, buffer, ExportNDJSON, &ExportOptions{
+		Fields: []string{"ID", "Email"},
+		Mask: func(entity Entity, field string, value interface{}) interface{} {
+			if field == "Email" {
+				return "***" + value.(string)[strings.Index(value.(string), "@"):]
+			}
+			return value
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, "{\"ID\":2,\"Email\":\"***@example.com\"}\n{\"ID\":3,\"Email\":\"***@example.com\"}\n", buffer.String())
+
+	assert.PanicsWithError(t, "field Invalid not found", func() {
+		_, _ = engine.Export(NewWhere("1"), entity, buffer, ExportCSV, &ExportOptions{Fields: []string{"Invalid"}})
+	})
+}
@@ -0,0 +1,126 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+type Factory struct {
+	entityType reflect.Type
+	fields     []string
+	values     map[string]interface{}
+	sequence   int
+}
+
+func NewFactory(entity Entity) *Factory {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return &Factory{entityType: t, values: make(map[string]interface{})}
+}
+
+func (f *Factory) With(field string, value interface{}) *Factory {
+	_, has := f.values[field]
+	if !has {
+		f.fields = append(f.fields, field)
+	}
+	f.values[field] = value
+	return f
+}
+
+func (f *Factory) Make(engine *Engine) Entity {
+	schema := getTableSchema(engine.registry, f.entityType)
+	if schema == nil {
+		panic(fmt.Errorf("entity '%s' is not registered", f.entityType.String()))
+	}
+	f.sequence++
+	entity := reflect.New(f.entityType).Interface().(Entity)
+	orm := initIfNeeded(engine.registry, entity)
+	fillFactoryDefaults(engine, schema, schema.fields, orm.elem, f.sequence)
+	for _, field := range f.fields {
+		value := f.values[field]
+		sequence, isSequence := value.(func(i int) interface{})
+		if isSequence {
+			value = sequence(f.sequence)
+		}
+		err := entity.SetField(field, value)
+		checkError(err)
+	}
+	return entity
+}
+
+func (f *Factory) MakeMany(engine *Engine, total int) []Entity {
+	entities := make([]Entity, total)
+	for i := range entities {
+		entities[i] = f.Make(engine)
+	}
+	return entities
+}
+
+func (f *Factory) Create(engine *Engine) Entity {
+	entity := f.Make(engine)
+	engine.Flush(entity)
+	return entity
+}
+
+func (f *Factory) CreateMany(engine *Engine, total int) []Entity {
+	entities := f.MakeMany(engine, total)
+	if total > 0 {
+		engine.FlushMany(entities...)
+	}
+	return entities
+}
+
+func fillFactoryDefaults(engine *Engine, schema *tableSchema, fields *tableFields, value reflect.Value, sequence int) {
+	for _, i := range fields.uintegers {
+		field := value.Field(i)
+		if !field.OverflowUint(uint64(sequence)) {
+			field.SetUint(uint64(sequence))
+		}
+	}
+	for _, i := range fields.integers {
+		field := value.Field(i)
+		if !field.OverflowInt(int64(sequence)) {
+			field.SetInt(int64(sequence))
+		}
+	}
+	for _, i := range fields.floats {
+		value.Field(i).SetFloat(float64(sequence))
+	}
+	for _, i := range fields.strings {
+		name := fields.prefix + fields.fields[i].Name
+		tags := schema.tags[name]
+		enumCode, hasEnum := tags["enum"]
+		if hasEnum {
+			enum, has := engine.registry.enums[enumCode]
+			if has {
+				value.Field(i).SetString(enum.GetDefault())
+			}
+			continue
+		}
+		generated := name + " " + strconv.Itoa(sequence)
+		length, err := strconv.Atoi(tags["length"])
+		if err == nil && length < len(generated) {
+			generated = strconv.Itoa(sequence)
+			if length < len(generated) {
+				generated = generated[len(generated)-length:]
+			}
+		}
+		value.Field(i).SetString(generated)
+	}
+	for _, i := range fields.times {
+		now := engine.GetClock().Now().UTC()
+		if schema.tags[fields.prefix+fields.fields[i].Name]["time"] == "true" {
+			now = now.Truncate(time.Second)
+		} else {
+			now = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		value.Field(i).Set(reflect.ValueOf(now))
+	}
+	for i, subFields := range fields.structs {
+		fillFactoryDefaults(engine, schema, subFields, value.Field(i), sequence)
+	}
+}
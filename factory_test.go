@@ -0,0 +1,65 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type factoryEntity struct {
+	ORM
+	ID        uint
+	Name      string `orm:"required;unique=Name"`
+	Code      string `orm:"length=3"`
+	Color     string `orm:"enum=orm.factoryColor"`
+	Age       uint8
+	Score     int
+	Rate      float64
+	Active    bool
+	Nullable  *uint
+	CreatedAt time.Time `orm:"time"`
+	Parent    *factoryEntity
+}
+
+func TestFactory(t *testing.T) {
+	var entity *factoryEntity
+	registry := &Registry{}
+	registry.RegisterEnum("orm.factoryColor", []string{"red", "blue"})
+	engine := PrepareTables(t, registry, 5, entity)
+
+	factory := NewFactory(entity).With("Score", func(i int) interface{} {
+		return i * 10
+	})
+	created := factory.CreateMany(engine, 3)
+	assert.Len(t, created, 3)
+	first := created[0].(*factoryEntity)
+	assert.Equal(t, uint64(1), first.GetID())
+	assert.Equal(t, "Name 1", first.Name)
+	assert.Equal(t, "1", first.Code)
+	assert.Equal(t, "red", first.Color)
+	assert.Equal(t, uint8(1), first.Age)
+	assert.Equal(t, 10, first.Score)
+	assert.Equal(t, float64(1), first.Rate)
+	assert.Nil(t, first.Nullable)
+	assert.False(t, first.CreatedAt.IsZero())
+	assert.Equal(t, 30, created[2].(*factoryEntity).Score)
+
+	parent := NewFactory(entity).With("Name", "Parent").With("Parent", first).Create(engine).(*factoryEntity)
+	loaded := &factoryEntity{}
+	assert.True(t, engine.LoadByID(parent.GetID(), loaded))
+	assert.Equal(t, "Parent", loaded.Name)
+	assert.Equal(t, first.GetID(), loaded.Parent.GetID())
+
+	made := factory.Make(engine).(*factoryEntity)
+	assert.Equal(t, "Name 4", made.Name)
+	assert.Equal(t, uint64(0), made.GetID())
+	assert.Len(t, factory.MakeMany(engine, 2), 2)
+
+	assert.PanicsWithError(t, "field Invalid not found", func() {
+		NewFactory(entity).With("Invalid", 1).Make(engine)
+	})
+	assert.PanicsWithError(t, "entity 'orm.ormEntity' is not registered", func() {
+		NewFactory(&ormEntity{}).Make(engine)
+	})
+}
@@ -0,0 +1,149 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type FieldPermissions struct {
+	Read  []string
+	Write []string
+}
+
+type FieldPermissionError struct {
+	Entity string
+	Field  string
+	Role   string
+}
+
+func (err *FieldPermissionError) Error() string {
+	return fmt.Sprintf("field '%s' in entity '%s' is not writable for role '%s'", err.Field, err.Entity, err.Role)
+}
+
+type fieldPermissions struct {
+	read  map[string]bool
+	write map[string]bool
+}
+
+func (r *Registry) RegisterFieldPermissions(entity Entity, role string, permissions FieldPermissions) {
+	if r.fieldPermissions == nil {
+		r.fieldPermissions = make(map[string]map[string]FieldPermissions)
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if r.fieldPermissions[t.String()] == nil {
+		r.fieldPermissions[t.String()] = make(map[string]FieldPermissions)
+	}
+	r.fieldPermissions[t.String()][role] = permissions
+}
+
+func (e *Engine) SetRole(role string) {
+	e.role = role
+}
+
+func (e *Engine) GetRole() string {
+	return e.role
+}
+
+func initFieldPermissions(registry *Registry, schema *tableSchema) error {
+	definitions := registry.fieldPermissions[schema.t.String()]
+	if len(definitions) == 0 {
+		return nil
+	}
+	schema.columnFields = make(map[string]string)
+	for i, field := range schema.fields.fields {
+		subFields, isStruct := schema.fields.structs[i]
+		if isStruct {
+			collectStructColumns(subFields, field.Name, schema.columnFields)
+		} else if isScalarArray(field) {
+			for k := 0; k < field.Type.Len(); k++ {
+				schema.columnFields[getArrayColumnName(field.Name, k)] = field.Name
+			}
+		} else {
+			schema.columnFields[field.Name] = field.Name
+		}
+	}
+	topLevel := make(map[string]bool)
+	for _, field := range schema.columnFields {
+		topLevel[field] = true
+	}
+	schema.fieldPermissions = make(map[string]*fieldPermissions, len(definitions))
+	for role, definition := range definitions {
+		permissions := &fieldPermissions{read: map[string]bool{"ID": true}, write: map[string]bool{"ID": true}}
+		for _, list := range []struct {
+			fields []string
+			target map[string]bool
+		}{{definition.Read, permissions.read}, {definition.Write, permissions.write}} {
+			for _, field := range list.fields {
+				if !topLevel[field] {
+					return fmt.Errorf("field '%s' not found in entity '%s'", field, schema.t.String())
+				}
+				list.target[field] = true
+			}
+		}
+		schema.fieldPermissions[role] = permissions
+	}
+	return nil
+}
+
+func collectStructColumns(fields *tableFields, topLevel string, columns map[string]string) {
+	for i, field := range fields.fields {
+		subFields, isStruct := fields.structs[i]
+		if isStruct {
+			collectStructColumns(subFields, topLevel, columns)
+		} else if isScalarArray(field) {
+			for k := 0; k < field.Type.Len(); k++ {
+				columns[getArrayColumnName(fields.prefix+field.Name, k)] = topLevel
+			}
+		} else {
+			columns[fields.prefix+field.Name] = topLevel
+		}
+	}
+}
+
+func (tableSchema *tableSchema) getFieldPermissions(engine *Engine) *fieldPermissions {
+	if tableSchema.fieldPermissions == nil || engine.role == "" {
+		return nil
+	}
+	return tableSchema.fieldPermissions[engine.role]
+}
+
+func applyReadPermissions(engine *Engine, schema *tableSchema, elem reflect.Value) {
+	permissions := schema.getFieldPermissions(engine)
+	if permissions == nil {
+		return
+	}
+	for _, field := range schema.fields.fields {
+		if !permissions.read[field.Name] {
+			value := elem.FieldByName(field.Name)
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+}
+
+func checkWritePermissions(engine *Engine, orm *ORM, bind Bind, updateBind map[string]string) (isDirty bool) {
+	schema := orm.tableSchema
+	permissions := schema.getFieldPermissions(engine)
+	if permissions == nil {
+		return true
+	}
+	for column, value := range bind {
+		field := schema.columnFields[column]
+		if permissions.write[field] {
+			continue
+		}
+		isZero := value == nil || reflect.ValueOf(value).IsZero()
+		if isZero && !orm.inDB {
+			continue
+		}
+		if isZero && !permissions.read[field] {
+			delete(bind, column)
+			delete(updateBind, column)
+			continue
+		}
+		panic(&FieldPermissionError{Entity: schema.t.String(), Field: field, Role: engine.role})
+	}
+	return !orm.inDB || len(bind) > 0
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldPermissionEntity struct {
+	ORM
+	ID     uint
+	Name   string
+	Email  string
+	Salary uint
+}
+
+func TestFieldPermissions(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterFieldPermissions(&fieldPermissionEntity{}, "user", FieldPermissions{Read: []string{"Name", "Email"}, Write: []string{"Name"}})
+	engine := PrepareTables(t, registry, 5, &fieldPermissionEntity{})
+	engine.Flush(&fieldPermissionEntity{Name: "a", Email: "a@x.com", Salary: 100})
+
+	user := engine.WithOptions(WithRole("user"))
+	assert.Equal(t, "user", user.GetRole())
+	entity := &fieldPermissionEntity{}
+	assert.True(t, user.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, "a@x.com", entity.Email)
+	assert.Equal(t, uint(0), entity.Salary)
+
+	entity.Name = "b"
+	user.Flush(entity)
+	entity = &fieldPermissionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint(100), entity.Salary)
+
+	entity = &fieldPermissionEntity{}
+	user.LoadByID(1, entity)
+	entity.Email = "c@x.com"
+	assert.PanicsWithError(t, "field 'Email' in entity 'orm.fieldPermissionEntity' is not writable for role 'user'", func() {
+		user.Flush(entity)
+	})
+	entity.Email = "a@x.com"
+	entity.Salary = 5
+	err := &FieldPermissionError{Entity: "orm.fieldPermissionEntity", Field: "Salary", Role: "user"}
+	assert.PanicsWithError(t, err.Error(), func() {
+		user.Flush(entity)
+	})
+
+	user.Flush(&fieldPermissionEntity{Name: "n"})
+	assert.PanicsWithError(t, "field 'Salary' in entity 'orm.fieldPermissionEntity' is not writable for role 'user'", func() {
+		user.Flush(&fieldPermissionEntity{Name: "n2", Salary: 10})
+	})
+
+	admin := engine.WithOptions(WithRole("admin"))
+	entity = &fieldPermissionEntity{}
+	assert.True(t, admin.LoadByID(1, entity))
+	assert.Equal(t, uint(100), entity.Salary)
+	entity.Salary = 200
+	admin.Flush(entity)
+	assert.Equal(t, 2, engine.Count(NewWhere("1"), &fieldPermissionEntity{}))
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&fieldPermissionEntity{})
+	registry.RegisterFieldPermissions(&fieldPermissionEntity{}, "user", FieldPermissions{Read: []string{"Missing"}})
+	_, err2 := registry.Validate()
+	assert.EqualError(t, err2, "field 'Missing' not found in entity 'orm.fieldPermissionEntity'")
+}
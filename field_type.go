@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const customFieldTypeName = "orm.FieldTypeDefinition"
+
+type FieldTypeDefinition struct {
+	SQLType     string
+	BindSetter  func(value interface{}) (string, error)
+	FieldSetter func(value string) (interface{}, error)
+	Getter      func(value string) (interface{}, error)
+}
+
+func (r *Registry) RegisterFieldType(goType interface{}, definition FieldTypeDefinition) {
+	t := reflect.TypeOf(goType)
+	if t == nil {
+		panic(fmt.Errorf("field type is nil"))
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if definition.SQLType == "" || definition.BindSetter == nil || definition.FieldSetter == nil {
+		panic(fmt.Errorf("field type '%s' requires SQLType, BindSetter and FieldSetter", t.String()))
+	}
+	if r.fieldTypes == nil {
+		r.fieldTypes = make(map[reflect.Type]*FieldTypeDefinition)
+	}
+	r.fieldTypes[t] = &definition
+}
+
+func (r *Registry) getFieldType(t reflect.Type) (*FieldTypeDefinition, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	definition, has := r.fieldTypes[t]
+	if !has && t == ipFieldType {
+		return ipFieldDefinition, true
+	}
+	return definition, has
+}
+
+func isNullCustomField(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr:
+		return field.IsNil()
+	case reflect.Slice:
+		return field.IsNil()
+	}
+	return false
+}
+
+func (d *FieldTypeDefinition) toBind(field reflect.Value, name string, schema *tableSchema) string {
+	val, err := d.BindSetter(reflect.Indirect(field).Interface())
+	if err != nil {
+		panic(fmt.Errorf("invalid value of field '%s' in entity '%s': %w", name, schema.t.String(), err))
+	}
+	return val
+}
+
+func (d *FieldTypeDefinition) setField(field reflect.Value, value string) {
+	val, err := d.FieldSetter(value)
+	if err != nil {
+		panic(fmt.Errorf("invalid value '%s' for field type '%s': %w", value, field.Type().String(), err))
+	}
+	if field.Kind() == reflect.Ptr {
+		pointer := reflect.New(field.Type().Elem())
+		pointer.Elem().Set(reflect.ValueOf(val))
+		field.Set(pointer)
+		return
+	}
+	field.Set(reflect.ValueOf(val))
+}
+
+func (d *FieldTypeDefinition) get(name, value string) interface{} {
+	if d.Getter == nil {
+		return value
+	}
+	val, err := d.Getter(value)
+	if err != nil {
+		panic(fmt.Errorf("invalid value '%s' of field '%s': %w", value, name, err))
+	}
+	return val
+}
+
+func (fields *tableFields) getCustomFieldType(column string) *FieldTypeDefinition {
+	for k, i := range fields.customs {
+		if fields.prefix+fields.fields[i].Name == column {
+			return fields.customTypes[k]
+		}
+	}
+	for _, subFields := range fields.structs {
+		definition := subFields.getCustomFieldType(column)
+		if definition != nil {
+			return definition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package orm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldTypeMoney struct {
+	Amount   int64
+	Currency string
+}
+
+type fieldTypeCodes []int
+
+type fieldTypeEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Name     string
+	Price    fieldTypeMoney
+	Discount *fieldTypeMoney
+	Codes    fieldTypeCodes
+}
+
+func registerMoneyFieldType(registry *Registry) {
+	registry.RegisterFieldType(fieldTypeMoney{}, FieldTypeDefinition{
+		SQLType: "varchar(20)",
+		BindSetter: func(value interface{}) (string, error) {
+			money := value.(fieldTypeMoney)
+			if money.Currency == "" && money.Amount != 0 {
+				return "", fmt.Errorf("missing currency")
+			}
+			return money.Currency + ":" + strconv.FormatInt(money.Amount, 10), nil
+		},
+		FieldSetter: func(value string) (interface{}, error) {
+			parts := strings.Split(value, ":")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid money")
+			}
+			amount, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return fieldTypeMoney{Amount: amount, Currency: parts[0]}, nil
+		},
+		Getter: func(value string) (interface{}, error) {
+			parts := strings.Split(value, ":")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid money")
+			}
+			return parts[1] + " " + parts[0], nil
+		},
+	})
+	registry.RegisterFieldType(fieldTypeCodes{}, FieldTypeDefinition{
+		SQLType: "varchar(100)",
+		BindSetter: func(value interface{}) (string, error) {
+			codes := make([]string, len(value.(fieldTypeCodes)))
+			for i, code := range value.(fieldTypeCodes) {
+				codes[i] = strconv.Itoa(code)
+			}
+			return strings.Join(codes, ","), nil
+		},
+		FieldSetter: func(value string) (interface{}, error) {
+			codes := fieldTypeCodes{}
+			for _, code := range strings.Split(value, ",") {
+				if code == "" {
+					continue
+				}
+				asInt, err := strconv.Atoi(code)
+				if err != nil {
+					return nil, err
+				}
+				codes = append(codes, asInt)
+			}
+			return codes, nil
+		},
+	})
+}
+
+func TestRegisterFieldType(t *testing.T) {
+	registry := &Registry{}
+	registerMoneyFieldType(registry)
+	engine := PrepareTables(t, registry, 5, &fieldTypeEntity{})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&fieldTypeEntity{})
+	assert.Equal(t, []string{"ID", "Name", "Price", "Discount", "Codes"}, schema.GetColumns())
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+	var createTable string
+	engine.GetMysql().QueryRow(NewWhere("SHOW CREATE TABLE `fieldTypeEntity`"), &createTable, &createTable)
+	assert.Contains(t, createTable, "`Price` varchar(20) NOT NULL")
+	assert.Contains(t, createTable, "`Discount` varchar(20) DEFAULT NULL")
+
+	entity := &fieldTypeEntity{Name: "a", Price: fieldTypeMoney{Amount: 1250, Currency: "USD"}}
+	engine.Flush(entity)
+	var price string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Price` FROM `fieldTypeEntity` WHERE `ID` = 1"), &price)
+	assert.Equal(t, "USD:1250", price)
+
+	entity = &fieldTypeEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, fieldTypeMoney{Amount: 1250, Currency: "USD"}, entity.Price)
+	assert.Nil(t, entity.Discount)
+	assert.False(t, entity.IsDirty())
+
+	entity.Discount = &fieldTypeMoney{Amount: 100, Currency: "USD"}
+	engine.Flush(entity)
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	var rows []*fieldTypeEntity
+	engine.Search(NewWhere("`Price` = ?", "USD:1250"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, int64(100), rows[0].Discount.Amount)
+	entity = &fieldTypeEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "USD", entity.Discount.Currency)
+
+	entity.Price = fieldTypeMoney{Amount: 5}
+	assert.PanicsWithError(t, "invalid value of field 'Price' in entity 'orm.fieldTypeEntity': missing currency", func() {
+		engine.Flush(entity)
+	})
+
+	entity = &fieldTypeEntity{Name: "b", Price: fieldTypeMoney{Amount: 1, Currency: "EUR"}, Codes: fieldTypeCodes{}}
+	engine.Flush(entity)
+	var codes *string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Codes` FROM `fieldTypeEntity` WHERE `ID` = ?", entity.ID), &codes)
+	assert.NotNil(t, codes)
+	assert.Equal(t, "", *codes)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Codes` FROM `fieldTypeEntity` WHERE `ID` = 1"), &codes)
+	assert.Nil(t, codes)
+
+	entity = &fieldTypeEntity{}
+	assert.True(t, engine.LoadByIDLazy(1, entity))
+	assert.Equal(t, "1250 USD", entity.GetFieldLazy("Price"))
+	assert.Equal(t, "100 USD", entity.GetFieldLazy("Discount"))
+	assert.Nil(t, entity.GetFieldLazy("Codes"))
+	assert.Equal(t, "a", entity.GetFieldLazy("Name"))
+
+	assert.PanicsWithError(t, "field type 'orm.fieldTypeMoney' requires SQLType, BindSetter and FieldSetter", func() {
+		registry.RegisterFieldType(&fieldTypeMoney{}, FieldTypeDefinition{SQLType: "int"})
+	})
+}
@@ -0,0 +1,202 @@
+package orm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const fixtureReferencePrefix = "@"
+
+type Fixtures struct {
+	entities map[string]Entity
+	names    []string
+}
+
+type fixture struct {
+	name         string
+	entity       Entity
+	fields       yaml.MapSlice
+	dependencies []string
+}
+
+func (f *Fixtures) Get(name string) Entity {
+	return f.entities[name]
+}
+
+func (f *Fixtures) Has(name string) bool {
+	_, has := f.entities[name]
+	return has
+}
+
+func (f *Fixtures) GetNames() []string {
+	return f.names
+}
+
+func (e *Engine) LoadFixturesFile(path string) (*Fixtures, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return e.LoadFixtures(data)
+}
+
+func (e *Engine) LoadFixtures(data []byte) (*Fixtures, error) {
+	definitions := yaml.MapSlice{}
+	err := yaml.Unmarshal(data, &definitions)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := &Fixtures{entities: make(map[string]Entity)}
+	rows := make([]*fixture, 0)
+	for _, definition := range definitions {
+		entityName := fmt.Sprintf("%v", definition.Key)
+		t := getFixtureEntityType(e.registry, entityName)
+		if t == nil {
+			return nil, fmt.Errorf("entity '%s' is not registered", entityName)
+		}
+		handles, ok := definition.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("invalid fixtures definition for entity '%s'", entityName)
+		}
+		for _, handle := range handles {
+			name := fmt.Sprintf("%v", handle.Key)
+			if fixtures.Has(name) {
+				return nil, fmt.Errorf("fixture '%s' already defined", name)
+			}
+			entity := reflect.New(t).Interface().(Entity)
+			initIfNeeded(e.registry, entity)
+			fixtures.entities[name] = entity
+			fixtures.names = append(fixtures.names, name)
+			fields, ok := handle.Value.(yaml.MapSlice)
+			if !ok && handle.Value != nil {
+				return nil, fmt.Errorf("invalid fixture '%s'", name)
+			}
+			row := &fixture{name: name, entity: entity, fields: fields}
+			for _, field := range fields {
+				row.dependencies = append(row.dependencies, getFixtureDependencies(field.Value)...)
+			}
+			rows = append(rows, row)
+		}
+	}
+	for _, row := range rows {
+		for _, field := range row.fields {
+			err = setFixtureField(fixtures, row, fmt.Sprintf("%v", field.Key), field.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	flushed := make(map[string]bool, len(rows))
+	for len(flushed) < len(rows) {
+		flusher := e.NewFlusher()
+		level := make([]string, 0)
+		for _, row := range rows {
+			if flushed[row.name] || !isFixtureReady(row, flushed) {
+				continue
+			}
+			flusher.Track(row.entity)
+			level = append(level, row.name)
+		}
+		if len(level) == 0 {
+			for _, row := range rows {
+				if !flushed[row.name] {
+					return nil, fmt.Errorf("circular fixture reference in '%s'", row.name)
+				}
+			}
+		}
+		err = flusher.FlushWithCheck()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range level {
+			flushed[name] = true
+		}
+	}
+	return fixtures, nil
+}
+
+func getFixtureEntityType(registry *validatedRegistry, name string) reflect.Type {
+	t, has := registry.entities[name]
+	if has {
+		return t
+	}
+	for _, t := range registry.entities {
+		if getTableSchema(registry, t).tableName == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func getFixtureDependencies(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, fixtureReferencePrefix) {
+			return []string{v[1:]}
+		}
+	case []interface{}:
+		dependencies := make([]string, 0)
+		for _, item := range v {
+			dependencies = append(dependencies, getFixtureDependencies(item)...)
+		}
+		return dependencies
+	}
+	return nil
+}
+
+func isFixtureReady(row *fixture, flushed map[string]bool) bool {
+	for _, dependency := range row.dependencies {
+		if !flushed[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+func setFixtureField(fixtures *Fixtures, row *fixture, field string, value interface{}) error {
+	f := reflect.ValueOf(row.entity).Elem().FieldByName(field)
+	if !f.IsValid() {
+		return fmt.Errorf("field %s not found in fixture '%s'", field, row.name)
+	}
+	entityType := reflect.TypeOf((*Entity)(nil)).Elem()
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, fixtureReferencePrefix) {
+			reference, has := fixtures.entities[v[1:]]
+			if !has {
+				return fmt.Errorf("fixture '%s' not found", v[1:])
+			}
+			value = reference
+		} else if v != "" {
+			value = convertImportTime(f.Type(), v)
+		}
+	case []interface{}:
+		if f.Kind() == reflect.Slice && f.Type().Elem().Implements(entityType) {
+			slice := reflect.MakeSlice(f.Type(), 0, len(v))
+			for _, item := range v {
+				name := strings.TrimPrefix(fmt.Sprintf("%v", item), fixtureReferencePrefix)
+				reference, has := fixtures.entities[name]
+				if !has {
+					return fmt.Errorf("fixture '%s' not found", name)
+				}
+				slice = reflect.Append(slice, reflect.ValueOf(reference))
+			}
+			f.Set(slice)
+			return nil
+		}
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = fmt.Sprintf("%v", item)
+		}
+		value = values
+	}
+	err := row.entity.SetField(field, value)
+	if err != nil {
+		return fmt.Errorf("fixture '%s': %s", row.name, err.Error())
+	}
+	return nil
+}
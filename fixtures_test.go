@@ -0,0 +1,86 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixtureCompanyEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type fixtureUserEntity struct {
+	ORM
+	ID        uint
+	Name      string
+	Age       uint8
+	Tags      []string
+	CreatedAt time.Time
+	Company   *fixtureCompanyEntity
+	Manager   *fixtureUserEntity
+	Friends   []*fixtureUserEntity
+}
+
+const testFixtures = `
+fixtureUserEntity:
+  tom:
+    Name: Tom
+    Age: 30
+    Tags: [a, b]
+    CreatedAt: "2021-03-01"
+    Company: "@acme"
+    Manager: "@john"
+    Friends: ["@john", "@adam"]
+  john:
+    Name: John
+    Company: "@acme"
+  adam:
+    Name: Adam
+    Manager: "@john"
+orm.fixtureCompanyEntity:
+  acme:
+    Name: Acme
+`
+
+func TestFixtures(t *testing.T) {
+	var company *fixtureCompanyEntity
+	var user *fixtureUserEntity
+	engine, fixtures := PrepareTablesWithFixtures(t, &Registry{}, 5, testFixtures, company, user)
+	assert.Equal(t, []string{"tom", "john", "adam", "acme"}, fixtures.GetNames())
+	assert.True(t, fixtures.Has("tom"))
+	assert.False(t, fixtures.Has("unknown"))
+	assert.Nil(t, fixtures.Get("unknown"))
+
+	tom := fixtures.Get("tom").(*fixtureUserEntity)
+	john := fixtures.Get("john").(*fixtureUserEntity)
+	assert.Equal(t, uint64(1), fixtures.Get("acme").GetID())
+	assert.Equal(t, uint64(1), john.GetID())
+	assert.Equal(t, uint64(2), fixtures.Get("adam").GetID())
+	assert.Equal(t, uint64(3), tom.GetID())
+
+	loaded := &fixtureUserEntity{}
+	assert.True(t, engine.LoadByID(tom.GetID(), loaded, "Manager", "Company", "Friends"))
+	assert.Equal(t, "Tom", loaded.Name)
+	assert.Equal(t, uint8(30), loaded.Age)
+	assert.Equal(t, []string{"a", "b"}, loaded.Tags)
+	assert.Equal(t, "2021-03-01", loaded.CreatedAt.Format("2006-01-02"))
+	assert.Equal(t, "Acme", loaded.Company.Name)
+	assert.Equal(t, "John", loaded.Manager.Name)
+	assert.Len(t, loaded.Friends, 2)
+	assert.Equal(t, "Adam", loaded.Friends[1].Name)
+
+	_, err := engine.LoadFixtures([]byte("fixtureUserEntity:\n  a:\n    Manager: \"@b\"\n  b:\n    Manager: \"@a\"\n"))
+	assert.EqualError(t, err, "circular fixture reference in 'a'")
+	_, err = engine.LoadFixtures([]byte("fixtureUserEntity:\n  a:\n    Manager: \"@missing\"\n"))
+	assert.EqualError(t, err, "fixture 'missing' not found")
+	_, err = engine.LoadFixtures([]byte("fixtureUserEntity:\n  a:\n    Invalid: 1\n"))
+	assert.EqualError(t, err, "field Invalid not found in fixture 'a'")
+	_, err = engine.LoadFixtures([]byte("unknownEntity:\n  a:\n    Name: a\n"))
+	assert.EqualError(t, err, "entity 'unknownEntity' is not registered")
+	_, err = engine.LoadFixtures([]byte("fixtureUserEntity:\n  a:\n    Age: invalid\n"))
+	assert.EqualError(t, err, "fixture 'a': Age value invalid not valid")
+}
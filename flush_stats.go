@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"context"
+	"runtime/pprof"
+	"time"
+)
+
+type flushPhase int
+
+const (
+	flushPhaseBinds flushPhase = iota
+	flushPhaseSQL
+	flushPhaseCache
+	flushPhaseStreams
+)
+
+type FlushStats struct {
+	Entities int
+	Queries  int
+	Binds    time.Duration
+	SQL      time.Duration
+	Cache    time.Duration
+	Streams  time.Duration
+	Total    time.Duration
+}
+
+func (f *flusher) FlushWithStats() *FlushStats {
+	stats := &FlushStats{Entities: f.trackedEntitiesCounter}
+	f.stats = stats
+	defer func() {
+		f.stats = nil
+	}()
+	start := time.Now()
+	pprof.Do(context.Background(), pprof.Labels("orm", "flush"), func(context.Context) {
+		f.flushTrackedEntities(false, false)
+	})
+	stats.Total = time.Since(start)
+	return stats
+}
+
+func (f *flusher) hasStats() bool {
+	return f != nil && f.stats != nil
+}
+
+func (f *flusher) phaseStart() time.Time {
+	if !f.hasStats() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (f *flusher) phaseEnd(phase flushPhase, start time.Time) {
+	if !f.hasStats() {
+		return
+	}
+	duration := time.Since(start)
+	switch phase {
+	case flushPhaseBinds:
+		f.stats.Binds += duration
+	case flushPhaseSQL:
+		f.stats.SQL += duration
+		f.stats.Queries++
+	case flushPhaseCache:
+		f.stats.Cache += duration
+	case flushPhaseStreams:
+		f.stats.Streams += duration
+	}
+}
+
+func (f *flusher) sqlPhaseEnd(start time.Time, queries int) {
+	if f.stats == nil {
+		return
+	}
+	f.stats.SQL += time.Since(start)
+	f.stats.Queries += queries
+}
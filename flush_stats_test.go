@@ -0,0 +1,61 @@
+package orm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushStatsEntity struct {
+	ORM  `orm:"redisCache;dirty=stats_dirty"`
+	ID   uint
+	Name string
+}
+
+func TestFlushWithStats(t *testing.T) {
+	var entity *flushStatsEntity
+	registry := &Registry{}
+	registry.RegisterRedisStream("stats_dirty", "default", []string{"test-group"})
+	engine := PrepareTables(t, registry, 5, entity)
+
+	flusher := engine.NewFlusher()
+	stats := flusher.FlushWithStats()
+	assert.Equal(t, 0, stats.Entities)
+	assert.Equal(t, 0, stats.Queries)
+
+	flusher.Track(&flushStatsEntity{Name: "a"}, &flushStatsEntity{Name: "b"})
+	stats = flusher.FlushWithStats()
+	assert.Equal(t, 2, stats.Entities)
+	assert.Equal(t, 1, stats.Queries)
+	assert.Greater(t, stats.SQL.Nanoseconds(), int64(0))
+	assert.Greater(t, stats.Streams.Nanoseconds(), int64(0))
+	assert.GreaterOrEqual(t, stats.Total, stats.Binds+stats.SQL+stats.Cache+stats.Streams)
+
+	entity = &flushStatsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	entity.Name = "c"
+	toDelete := &flushStatsEntity{}
+	assert.True(t, engine.LoadByID(2, toDelete))
+	flusher.Track(entity)
+	flusher.Delete(toDelete)
+	stats = flusher.FlushWithStats()
+	assert.Equal(t, 2, stats.Entities)
+	assert.Equal(t, 2, stats.Queries)
+	assert.Greater(t, stats.Cache.Nanoseconds(), int64(0))
+	assert.Greater(t, stats.Streams.Nanoseconds(), int64(0))
+
+	flusher.Track(&flushStatsEntity{Name: "d"}, &flushStatsEntity{Name: "e"})
+	flusher.Flush()
+	var entities []*flushStatsEntity
+	engine.LoadByIDs([]uint64{1, 3, 4}, &entities)
+	for i, e := range entities {
+		e.Name = "updated" + strconv.Itoa(i)
+		flusher.Track(e)
+	}
+	stats = flusher.FlushWithStats()
+	assert.Equal(t, 3, stats.Entities)
+	assert.Equal(t, 3, stats.Queries)
+	assert.Greater(t, stats.Cache.Nanoseconds(), int64(0))
+	assert.Greater(t, stats.Streams.Nanoseconds(), int64(0))
+}
@@ -614,7 +614,49 @@ func BenchmarkFlusherUpdateNoCache(b *testing.B) {
 	benchmarkFlusher(b, false, false)
 }
 
-func benchmarkFlusher(b *testing.B, useLocaCache, useRedisCache bool) {
+func BenchmarkFlusherUpdateLocalCache(b *testing.B) {
+	benchmarkFlusher(b, true, false)
+}
+
+func BenchmarkFlusherUpdateRedisCache(b *testing.B) {
+	benchmarkFlusher(b, false, true)
+}
+
+func BenchmarkFlusherInsertNoCache(b *testing.B) {
+	engine := prepareFlusherBenchmark(b, false, false)
+	flusher := engine.NewFlusher()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		flusher.Track(&flushEntityBenchmark{Name: "Tom", Age: n})
+		flusher.Flush()
+	}
+}
+
+func BenchmarkFlusherUpdateLazy(b *testing.B) {
+	engine := prepareFlusherBenchmark(b, false, false)
+	entity := &flushEntityBenchmark{Name: "Tom"}
+	engine.Flush(entity)
+	flusher := engine.NewFlusher()
+	flusher.Track(entity)
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		entity.Age = n + 1
+		flusher.FlushLazy()
+	}
+}
+
+func BenchmarkBuildUpdateSQL(b *testing.B) {
+	updateBind := map[string]string{"Name": "'Tom'", "Age": "12"}
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		buildUpdateSQL("flushEntityBenchmark", updateBind, 1)
+	}
+}
+
+func prepareFlusherBenchmark(b *testing.B, useLocaCache, useRedisCache bool) *Engine {
 	var entity *flushEntityBenchmark
 	registry := &Registry{}
 	registry.RegisterRedisStream("entity_changed", "default", []string{"test-group-1"})
@@ -630,8 +672,13 @@ func benchmarkFlusher(b *testing.B, useLocaCache, useRedisCache bool) {
 		schema.hasRedisCache = false
 		schema.redisCacheName = ""
 	}
+	return engine
+}
+
+func benchmarkFlusher(b *testing.B, useLocaCache, useRedisCache bool) {
+	engine := prepareFlusherBenchmark(b, useLocaCache, useRedisCache)
 
-	entity = &flushEntityBenchmark{Name: "Tom"}
+	entity := &flushEntityBenchmark{Name: "Tom"}
 	engine.Flush(entity)
 	engine.LoadByID(1, entity)
 	flusher := engine.NewFlusher()
@@ -643,3 +690,16 @@ func benchmarkFlusher(b *testing.B, useLocaCache, useRedisCache bool) {
 		flusher.Flush()
 	}
 }
+
+func TestBuildFlushSQL(t *testing.T) {
+	assert.Equal(t, "UPDATE `a` SET `Name`='Tom' WHERE `ID` = 7", buildUpdateSQL("`a`", map[string]string{"Name": "'Tom'"}, 7))
+	registry := &Registry{}
+	registry.RegisterEnum("orm.TestEnum", []string{"a", "b", "c"})
+	engine := PrepareTables(t, registry, 5, &flushEntityBenchmark{})
+	schema := engine.registry.GetTableSchemaForEntity(&flushEntityBenchmark{}).(*tableSchema)
+	assert.Equal(t, "INSERT INTO flushEntityBenchmark(`Name`,`Age`) VALUES (?,?),(?,?)", buildInsertSQL(schema, []string{"Name", "Age"}, 2))
+	updateBind := updateBindPool.Get().(map[string]string)
+	updateBind["Name"] = "'Tom'"
+	releaseUpdateBind(updateBind)
+	assert.Len(t, updateBind, 0)
+}
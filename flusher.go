@@ -1,40 +1,23 @@
 package orm
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 )
 
 type Bind map[string]interface{}
 
-type DuplicatedKeyError struct {
-	Message string
-	Index   string
-}
-
-func (err *DuplicatedKeyError) Error() string {
-	return err.Message
-}
-
-type ForeignKeyError struct {
-	Message    string
-	Constraint string
-}
-
-func (err *ForeignKeyError) Error() string {
-	return err.Message
-}
-
 type Flusher interface {
 	Track(entity ...Entity) Flusher
 	Flush()
 	FlushWithCheck() error
 	FlushInTransactionWithCheck() error
 	FlushWithFullCheck() error
+	FlushWithStats() *FlushStats
 	FlushLazy()
 	FlushInTransaction()
 	Clear()
@@ -54,6 +37,8 @@ type flusher struct {
 	lazyMap                map[string]interface{}
 	localCacheDeletes      map[string][]string
 	localCacheSets         map[string][]interface{}
+	delayedDeletes         []*delayedCacheDelete
+	stats                  *FlushStats
 }
 
 func (f *flusher) Track(entity ...Entity) Flusher {
@@ -91,7 +76,7 @@ func (f *flusher) ForceDelete(entity ...Entity) Flusher {
 }
 
 func (f *flusher) Flush() {
-	f.flushTrackedEntities(false, false)
+	f.flushWithRecovery(false, false)
 }
 
 func (f *flusher) FlushWithCheck() error {
@@ -118,11 +103,11 @@ func (f *flusher) FlushWithFullCheck() error {
 }
 
 func (f *flusher) FlushLazy() {
-	f.flushTrackedEntities(true, false)
+	f.flushWithRecovery(true, false)
 }
 
 func (f *flusher) FlushInTransaction() {
-	f.flushTrackedEntities(false, true)
+	f.flushWithRecovery(false, true)
 }
 
 func (f *flusher) Clear() {
@@ -146,6 +131,9 @@ func (f *flusher) flushTrackedEntities(lazy bool, transaction bool) {
 	if f.trackedEntitiesCounter == 0 {
 		return
 	}
+	if f.engine.readOnly {
+		panic(&ReadOnlyError{Message: "engine is in read only mode"})
+	}
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 	var dbPools map[string]*DB
@@ -164,6 +152,18 @@ func (f *flusher) flushTrackedEntities(lazy bool, transaction bool) {
 			db.Rollback()
 		}
 	}()
+	if lazy {
+		chunkSize := f.engine.registry.getLazyFlushOptions().ChunkSize
+		for start := 0; start < len(f.trackedEntities); start += chunkSize {
+			end := start + chunkSize
+			if end > len(f.trackedEntities) {
+				end = len(f.trackedEntities)
+			}
+			f.flush(true, true, false, f.trackedEntities[start:end]...)
+			f.clear()
+		}
+		return
+	}
 	f.flush(true, lazy, transaction, f.trackedEntities...)
 	if transaction {
 		for _, db := range dbPools {
@@ -180,14 +180,9 @@ func (f *flusher) flushWithCheck(transaction bool) error {
 			if r := recover(); r != nil {
 				f.Clear()
 				asErr := r.(error)
-				assErr1, is := asErr.(*ForeignKeyError)
-				if is {
-					err = assErr1
-					return
-				}
-				assErr2, is := asErr.(*DuplicatedKeyError)
-				if is {
-					err = assErr2
+				if errors.Is(asErr, ErrForeignKey) || errors.Is(asErr, ErrDuplicateKey) || errors.Is(asErr, ErrReadOnly) ||
+					errors.Is(asErr, ErrLockTimeout) || errors.Is(asErr, ErrNotFound) {
+					err = asErr
 					return
 				}
 				panic(asErr)
@@ -212,6 +207,7 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			panic(fmt.Errorf("lazy entity and can't be flushed: %v [%d]", entity.getORM().elem.Type().String(), entity.GetID()))
 		}
 		schema := entity.getORM().tableSchema
+		schema.refreshCacheVersion(f.engine)
 		if !transaction && schema.GetMysql(f.engine).inTransaction {
 			transaction = true
 		}
@@ -250,10 +246,21 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 
 		orm := entity.getORM()
 		dbData := orm.dBData
+		phaseStart := f.phaseStart()
 		bind, updateBind, isDirty := orm.getDirtyBind()
+		f.phaseEnd(flushPhaseBinds, phaseStart)
 		if !isDirty {
+			releaseUpdateBind(updateBind)
+			continue
+		}
+		if !orm.delete && !checkWritePermissions(f.engine, orm, bind, updateBind) {
+			releaseUpdateBind(updateBind)
 			continue
 		}
+		if !orm.delete {
+			checkBindSize(f.engine, orm.tableSchema, bind)
+			checkBindValidators(orm.tableSchema, bind)
+		}
 		bindLength := len(bind)
 
 		t := orm.tableSchema.t
@@ -262,6 +269,7 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			orm.delete = true
 		}
 		if orm.delete {
+			releaseUpdateBind(updateBind)
 			if f.deleteBinds == nil {
 				f.deleteBinds = make(map[reflect.Type]map[uint64]Entity)
 			}
@@ -270,6 +278,10 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			}
 			f.deleteBinds[t][currentID] = entity
 		} else if !orm.inDB {
+			if currentID == 0 && schema.idGenerator != nil {
+				currentID = schema.idGenerator.NextID(f.engine, schema)
+				orm.idElem.SetUint(currentID)
+			}
 			onUpdate := entity.getORM().onDuplicateKeyUpdate
 			if onUpdate != nil {
 				if lazy {
@@ -285,10 +297,17 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 				i := 0
 				for key, val := range bind {
 					columns[i] = "`" + key + "`"
-					values[i] = "?"
+					values[i] = schema.getBindPlaceholder(key)
 					bindRow[i] = val
 					i++
 				}
+				for key, old := range schema.renamedColumns {
+					if val, has := bind[key]; has {
+						columns = append(columns, "`"+old+"`")
+						values = append(values, schema.getBindPlaceholder(key))
+						bindRow = append(bindRow, val)
+					}
+				}
 				/* #nosec */
 				sql := "INSERT INTO " + schema.tableName + "(" + strings.Join(columns, ",") + ") VALUES (" + strings.Join(values, ",") + ")"
 				sql += " ON DUPLICATE KEY UPDATE "
@@ -297,15 +316,21 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 					if !first {
 						sql += ", "
 					}
-					sql += "`" + k + "` = ?"
+					sql += "`" + k + "` = " + schema.getBindPlaceholder(k)
 					bindRow = append(bindRow, v)
+					if old, has := schema.renamedColumns[k]; has {
+						sql += ", `" + old + "` = " + schema.getBindPlaceholder(k)
+						bindRow = append(bindRow, v)
+					}
 					first = false
 				}
 				if len(onUpdate) == 0 {
 					sql += "`Id` = `Id`"
 				}
 				db := schema.GetMysql(f.engine)
+				phaseStart = f.phaseStart()
 				result := db.Exec(sql, bindRow...)
+				f.phaseEnd(flushPhaseSQL, phaseStart)
 				affected := result.RowsAffected()
 				if affected > 0 {
 					lastID := result.LastInsertId()
@@ -343,6 +368,9 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 				}
 				continue
 			}
+			if schema.uniqueCheck {
+				checkUniqueIndices(f.engine, schema, bind)
+			}
 			if currentID > 0 {
 				bind["ID"] = currentID
 				bindLength++
@@ -357,14 +385,14 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 					fields[i] = key
 					i++
 				}
-				insertKeys[t] = fields
+				insertKeys[t] = schema.addRenamedInsertColumns(fields)
 			}
 			_, has := insertBinds[t]
 			if !has {
 				insertBinds[t] = make([]map[string]interface{}, 0)
 			}
 			for _, key := range insertKeys[t] {
-				insertArguments[t] = append(insertArguments[t], bind[key])
+				insertArguments[t] = append(insertArguments[t], schema.getInsertBindValue(bind, key))
 			}
 			insertReflectValues[t] = append(insertReflectValues[t], entity)
 			insertBinds[t] = append(insertBinds[t], bind)
@@ -372,17 +400,11 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			if !entity.IsLoaded() {
 				panic(fmt.Errorf("entity is not loaded and can't be updated: %v [%d]", entity.getORM().elem.Type().String(), currentID))
 			}
+			checkRowPolicy(f.engine, schema, currentID)
+			schema.addRenamedUpdateColumns(updateBind)
 			/* #nosec */
-			sql := "UPDATE " + schema.GetTableName() + " SET "
-			first := true
-			for key, value := range updateBind {
-				if !first {
-					sql += ","
-				}
-				first = false
-				sql += "`" + key + "`=" + value
-			}
-			sql += " WHERE `ID` = " + strconv.FormatUint(currentID, 10)
+			sql := buildUpdateSQL(schema.GetTableName(), updateBind, currentID)
+			releaseUpdateBind(updateBind)
 			db := schema.GetMysql(f.engine)
 			if lazy {
 				var logEvents []*LogQueueValue
@@ -400,7 +422,9 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 					f.updateSQLs = make(map[string][]string)
 				}
 				f.updateSQLs[schema.mysqlPoolName] = append(f.updateSQLs[schema.mysqlPoolName], sql)
+				phaseStart = f.phaseStart()
 				f.updateCacheAfterUpdate(dbData, entity, bind, schema, currentID, false)
+				f.phaseEnd(flushPhaseCache, phaseStart)
 			}
 		}
 	}
@@ -431,44 +455,14 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 	for typeOf, values := range insertKeys {
 		schema := getTableSchema(f.engine.registry, typeOf)
 		/* #nosec */
-		sql := "INSERT INTO " + schema.tableName
-		l := len(values)
-		if l > 0 {
-			sql += "("
-		}
-		first := true
-		for _, val := range values {
-			if !first {
-				sql += ","
-			}
-			first = false
-			sql += "`" + val + "`"
-		}
-		if l > 0 {
-			sql += ")"
-		}
-		sql += " VALUES "
-		bindPart := "("
-		if l > 0 {
-			bindPart += "?"
-		}
-		for i := 1; i < l; i++ {
-			bindPart += ",?"
-		}
-		bindPart += ")"
-		l = len(insertBinds[typeOf])
-		for i := 0; i < l; i++ {
-			if i > 0 {
-				sql += ","
-			}
-			sql += bindPart
-		}
+		sql := buildInsertSQL(schema, values, len(insertBinds[typeOf]))
 		db := schema.GetMysql(f.engine)
 		if lazy {
 			var logEvents []*LogQueueValue
 			var dirtyEvents []*dirtyQueueValue
 			for key, entity := range insertReflectValues[typeOf] {
 				logEvent, dirtyEvent := f.updateCacheForInserted(entity, lazy, 0, insertBinds[typeOf][key])
+				f.addLazySearchSuggestions(schema, entity.GetID(), key, entity.getORM().dBData)
 				if logEvent != nil {
 					logEvents = append(logEvents, logEvent)
 				}
@@ -478,7 +472,10 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			}
 			f.fillLazyQuery(db.GetPoolConfig().GetCode(), sql, insertArguments[typeOf], logEvents, dirtyEvents)
 		} else {
+			phaseStart := f.phaseStart()
 			res := db.Exec(sql, insertArguments[typeOf]...)
+			f.phaseEnd(flushPhaseSQL, phaseStart)
+			phaseStart = f.phaseStart()
 			id := res.LastInsertId()
 			for key, entity := range insertReflectValues[typeOf] {
 				bind := insertBinds[typeOf][key]
@@ -493,14 +490,17 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 				}
 				f.updateCacheForInserted(entity, lazy, insertedID, bind)
 			}
+			f.phaseEnd(flushPhaseCache, phaseStart)
 		}
 	}
 	if root {
 		for pool, queries := range f.updateSQLs {
 			db := f.engine.GetMysql(pool)
 			l := len(queries)
+			phaseStart := f.phaseStart()
 			if l == 1 {
 				db.Exec(queries[0])
+				f.phaseEnd(flushPhaseSQL, phaseStart)
 				continue
 			}
 			forcedTransaction := l >= 3 && !db.inTransaction
@@ -515,6 +515,7 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 					db.Commit()
 				}
 			}()
+			f.sqlPhaseEnd(phaseStart, l)
 		}
 		for typeOf, deleteBinds := range f.deleteBinds {
 			schema := getTableSchema(f.engine.registry, typeOf)
@@ -538,74 +539,30 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 					}
 				}
 			}
+			if schema.rowPolicy != nil {
+				policyIDs := make([]uint64, 0, len(deleteBinds))
+				for id := range deleteBinds {
+					policyIDs = append(policyIDs, id)
+				}
+				checkRowPolicy(f.engine, schema, policyIDs...)
+			}
 			/* #nosec */
 			sql := "DELETE FROM `" + schema.tableName + "` WHERE " + NewWhere("`ID` IN ?", ids).String()
 			db := schema.GetMysql(f.engine)
 			if lazy {
 				f.fillLazyQuery(db.GetPoolConfig().GetCode(), sql, ids, logEvents, dirtyEvents)
 			} else {
-				usage := schema.GetUsage(f.engine.registry)
-				if len(usage) > 0 {
-					for refT, refColumns := range usage {
-						for _, refColumn := range refColumns {
-							refSchema := getTableSchema(f.engine.registry, refT)
-							_, isCascade := refSchema.tags[refColumn]["cascade"]
-							if isCascade {
-								subValue := reflect.New(reflect.SliceOf(reflect.PtrTo(refT)))
-								subElem := subValue.Elem()
-								sub := subValue.Interface()
-								pager := NewPager(1, 1000)
-								where := NewWhere("`"+refColumn+"` IN ?", ids)
-								for {
-									f.engine.Search(where, pager, sub)
-									total := subElem.Len()
-									if total == 0 {
-										break
-									}
-									toDeleteAll := make([]Entity, total)
-									for i := 0; i < total; i++ {
-										toDeleteValue := subElem.Index(i).Interface().(Entity)
-										toDeleteValue.markToDelete()
-										toDeleteAll[i] = toDeleteValue
-									}
-									f.flush(true, transaction, lazy, toDeleteAll...)
-								}
-							}
-						}
-					}
-				}
+				f.deleteCascade(schema, ids, transaction, lazy)
+				phaseStart := f.phaseStart()
 				_ = db.Exec(sql, ids...)
+				f.phaseEnd(flushPhaseSQL, phaseStart)
 			}
 
-			localCache, hasLocalCache := schema.GetLocalCache(f.engine)
-			redisCache, hasRedis := schema.GetRedisCache(f.engine)
-			if !hasLocalCache && f.engine.hasRequestCache {
-				hasLocalCache = true
-				localCache = f.engine.GetLocalCache(requestCacheKey)
-			}
-			for id, entity := range deleteBinds {
-				dbData := entity.getORM().dBData
-				bind := f.convertDBDataToMap(schema, dbData)
-				if !lazy {
-					f.addDirtyQueues(bind, schema, id, "d", lazy)
-					f.addToLogQueue(schema, id, bind, nil, entity.getORM().logMeta, lazy)
-				}
-				if hasLocalCache {
-					f.addLocalCacheSet(localCache.config.GetCode(), schema.getCacheKey(id), cacheNilValue)
-					keys := f.getCacheQueriesKeys(schema, bind, dbData, true)
-					f.addLocalCacheDeletes(localCache.config.GetCode(), keys...)
-				}
-				if hasRedis {
-					f.getRedisFlusher().Del(redisCache.config.GetCode(), schema.getCacheKey(id))
-					keys := f.getCacheQueriesKeys(schema, bind, dbData, true)
-					f.getRedisFlusher().Del(redisCache.config.GetCode(), keys...)
-				}
-				if schema.hasSearchCache {
-					key := schema.redisSearchPrefix + strconv.FormatUint(id, 10)
-					f.getRedisFlusher().Del(schema.searchCacheName, key)
-				}
-			}
+			phaseStart := f.phaseStart()
+			f.updateCacheAfterDelete(schema, deleteBinds, lazy)
+			f.phaseEnd(flushPhaseCache, phaseStart)
 		}
+		phaseStart := f.phaseStart()
 		if f.localCacheDeletes != nil {
 			if lazy {
 				lazyMap := f.getLazyMap()
@@ -627,6 +584,7 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 				f.engine.afterCommitLocalCacheSets[cacheCode] = append(f.engine.afterCommitLocalCacheSets[cacheCode], keys...)
 			}
 		}
+		f.phaseEnd(flushPhaseCache, phaseStart)
 	}
 	if lazy {
 		lazyMap := f.getLazyMap()
@@ -643,12 +601,17 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 	} else if transaction {
 		f.engine.afterCommitRedisFlusher = f.getRedisFlusher()
 	}
+	phaseStart := f.phaseStart()
 	if len(f.lazyMap) > 0 {
-		f.getRedisFlusher().Publish(lazyChannelName, f.lazyMap)
+		f.publishLazyMap()
 		f.lazyMap = nil
 	}
+	f.phaseEnd(flushPhaseStreams, phaseStart)
 	if f.redisFlusher != nil && !transaction && root {
-		f.redisFlusher.Flush()
+		f.redisFlusher.flush(f)
+	}
+	if !lazy && root {
+		f.scheduleDelayedDeletes(transaction)
 	}
 }
 
@@ -661,7 +624,7 @@ func (f *flusher) updateCacheForInserted(entity Entity, lazy bool, id uint64, bi
 	}
 	if hasLocalCache {
 		if !lazy {
-			f.addLocalCacheSet(localCache.config.GetCode(), schema.getCacheKey(id), buildLocalCacheValue(entity.getORM().dBData))
+			f.setLocalCacheEntity(schema, localCache, schema.getCacheKey(id), entity.getORM().dBData)
 		} else {
 			f.addLocalCacheDeletes(localCache.config.GetCode(), schema.getCacheKey(id))
 		}
@@ -670,11 +633,14 @@ func (f *flusher) updateCacheForInserted(entity Entity, lazy bool, id uint64, bi
 	}
 	redisCache, hasRedis := schema.GetRedisCache(f.engine)
 	if hasRedis {
-		f.getRedisFlusher().Del(redisCache.config.GetCode(), schema.getCacheKey(id))
+		f.deleteRedisCacheEntity(schema, redisCache, schema.getCacheKey(id))
 		keys := f.getCacheQueriesKeys(schema, bind, entity.getORM().dBData, true)
 		f.getRedisFlusher().Del(redisCache.config.GetCode(), keys...)
+		f.updateSortedIndexes(schema, redisCache, id, nil, entity.getORM().dBData, lazy)
 	}
-	f.fillRedisSearchFromBind(schema, bind, id)
+	f.updateCounter(schema, 1)
+	f.fillRedisSearchFromBind(schema, entity, bind, id)
+	f.updateSearchSuggestions(schema, id, nil, entity.getORM().dBData)
 	return f.addToLogQueue(schema, id, nil, bind, entity.getORM().logMeta, lazy), f.addDirtyQueues(bind, schema, id, "i", lazy)
 }
 
@@ -695,11 +661,81 @@ func (f *flusher) getLazyMap() map[string]interface{} {
 	return f.lazyMap
 }
 
+func (f *flusher) deleteCascade(schema *tableSchema, ids []interface{}, transaction, lazy bool) {
+	usage := schema.GetUsage(f.engine.registry)
+	if len(usage) > 0 {
+		for refT, refColumns := range usage {
+			for _, refColumn := range refColumns {
+				refSchema := getTableSchema(f.engine.registry, refT)
+				_, isCascade := refSchema.tags[refColumn]["cascade"]
+				if isCascade {
+					subValue := reflect.New(reflect.SliceOf(reflect.PtrTo(refT)))
+					subElem := subValue.Elem()
+					sub := subValue.Interface()
+					pager := NewPager(1, 1000)
+					where := NewWhere("`"+refColumn+"` IN ?", ids)
+					for {
+						f.engine.Search(where, pager, sub)
+						total := subElem.Len()
+						if total == 0 {
+							break
+						}
+						toDeleteAll := make([]Entity, total)
+						for i := 0; i < total; i++ {
+							toDeleteValue := subElem.Index(i).Interface().(Entity)
+							toDeleteValue.markToDelete()
+							toDeleteAll[i] = toDeleteValue
+						}
+						f.flush(true, transaction, lazy, toDeleteAll...)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (f *flusher) updateCacheAfterDelete(schema *tableSchema, deleteBinds map[uint64]Entity, lazy bool) {
+	localCache, hasLocalCache := schema.GetLocalCache(f.engine)
+	redisCache, hasRedis := schema.GetRedisCache(f.engine)
+	if !hasLocalCache && f.engine.hasRequestCache {
+		hasLocalCache = true
+		localCache = f.engine.GetLocalCache(requestCacheKey)
+	}
+	for id, entity := range deleteBinds {
+		if f.engine.identityMap != nil {
+			f.engine.identityMap.remove(schema.t, id)
+		}
+		dbData := entity.getORM().dBData
+		bind := f.convertDBDataToMap(schema, dbData)
+		f.updateCounter(schema, -1)
+		if !lazy {
+			f.addDirtyQueues(bind, schema, id, "d", lazy)
+			f.addToLogQueue(schema, id, bind, nil, entity.getORM().logMeta, lazy)
+		}
+		if hasLocalCache {
+			f.addLocalCacheSet(localCache.config.GetCode(), schema.getCacheKey(id), cacheNilValue)
+			keys := f.getCacheQueriesKeys(schema, bind, dbData, true)
+			f.addLocalCacheDeletes(localCache.config.GetCode(), keys...)
+		}
+		if hasRedis {
+			f.getRedisFlusher().Del(redisCache.config.GetCode(), schema.getCacheKey(id))
+			keys := f.getCacheQueriesKeys(schema, bind, dbData, true)
+			f.getRedisFlusher().Del(redisCache.config.GetCode(), keys...)
+			f.updateSortedIndexes(schema, redisCache, id, dbData, nil, lazy)
+		}
+		if schema.hasSearchCache {
+			key := schema.redisSearchPrefix + strconv.FormatUint(id, 10)
+			f.getRedisFlusher().Del(schema.searchCacheName, key)
+			f.updateSearchSuggestions(schema, id, dbData, nil)
+		}
+	}
+}
+
 func (f *flusher) updateCacheAfterUpdate(dbData []interface{}, entity Entity, bind Bind, schema *tableSchema, currentID uint64, lazy bool) (*LogQueueValue, *dirtyQueueValue) {
 	var old []interface{}
 	localCache, hasLocalCache := schema.GetLocalCache(f.engine)
 	redisCache, hasRedis := schema.GetRedisCache(f.engine)
-	if hasLocalCache || hasRedis || schema.hasLog {
+	if hasLocalCache || hasRedis || schema.hasLog || len(schema.searchSuggest) > 0 {
 		old = make([]interface{}, len(dbData))
 		copy(old, dbData)
 	}
@@ -709,8 +745,7 @@ func (f *flusher) updateCacheAfterUpdate(dbData []interface{}, entity Entity, bi
 		localCache = f.engine.GetLocalCache(requestCacheKey)
 	}
 	if hasLocalCache {
-		cacheKey := schema.getCacheKey(currentID)
-		f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, buildLocalCacheValue(entity.getORM().dBData))
+		f.setLocalCacheEntity(schema, localCache, schema.getCacheKey(currentID), entity.getORM().dBData)
 		keys := f.getCacheQueriesKeys(schema, bind, dbData, false)
 		f.addLocalCacheDeletes(localCache.config.GetCode(), keys...)
 		keys = f.getCacheQueriesKeys(schema, bind, old, false)
@@ -718,13 +753,22 @@ func (f *flusher) updateCacheAfterUpdate(dbData []interface{}, entity Entity, bi
 	}
 	if hasRedis {
 		redisFlusher := f.getRedisFlusher()
-		redisFlusher.Del(redisCache.config.GetCode(), schema.getCacheKey(currentID))
+		f.deleteRedisCacheEntity(schema, redisCache, schema.getCacheKey(currentID))
 		keys := f.getCacheQueriesKeys(schema, bind, dbData, false)
 		redisFlusher.Del(redisCache.config.GetCode(), keys...)
 		keys = f.getCacheQueriesKeys(schema, bind, old, false)
 		redisFlusher.Del(redisCache.config.GetCode(), keys...)
+		f.updateSortedIndexes(schema, redisCache, currentID, old, entity.getORM().dBData, lazy)
 	}
-	f.fillRedisSearchFromBind(schema, bind, entity.GetID())
+	if fakeDelete, has := bind["FakeDelete"]; has && schema.hasFakeDelete {
+		if isFakeDeleted(fakeDelete) {
+			f.updateCounter(schema, -1)
+		} else {
+			f.updateCounter(schema, 1)
+		}
+	}
+	f.fillRedisSearchFromBind(schema, entity, bind, entity.GetID())
+	f.updateSearchSuggestions(schema, currentID, old, entity.getORM().dBData)
 	dirtyValue := f.addDirtyQueues(bind, schema, currentID, "u", lazy)
 	if schema.hasLog {
 		return f.addToLogQueue(schema, currentID, f.convertDBDataToMap(schema, old), bind, entity.getORM().logMeta, lazy), dirtyValue
@@ -746,6 +790,9 @@ func (f *flusher) addDirtyQueues(bind map[string]interface{}, schema *tableSchem
 			}
 			if key == nil {
 				key = EventAsMap{"E": schema.t.String(), "I": id, "A": action}
+				if f.engine.tenant != "" {
+					key["T"] = f.engine.tenant
+				}
 			}
 			if !lazy {
 				f.getRedisFlusher().PublishMap(stream, key)
@@ -779,7 +826,7 @@ func (f *flusher) addToLogQueue(tableSchema *tableSchema, id uint64, before Bind
 	}
 	val := &LogQueueValue{TableName: tableSchema.logTableName, ID: id,
 		PoolName: tableSchema.logPoolName, Before: before,
-		Changes: changes, Updated: time.Now(), Meta: entityMeta}
+		Changes: changes, Updated: f.engine.GetClock().Now(), Meta: entityMeta}
 	if val.Meta == nil {
 		val.Meta = f.engine.logMetaData
 	} else {
@@ -793,13 +840,23 @@ func (f *flusher) addToLogQueue(tableSchema *tableSchema, id uint64, before Bind
 	return val
 }
 
-func (f *flusher) fillRedisSearchFromBind(schema *tableSchema, bind map[string]interface{}, id uint64) {
+func (f *flusher) fillRedisSearchFromBind(schema *tableSchema, entity Entity, bind map[string]interface{}, id uint64) {
 	if schema.hasSearchCache {
 		if schema.hasFakeDelete {
 			val, has := bind["FakeDelete"]
 			if has && val.(uint64) > 0 {
 				f.getRedisFlusher().Del(schema.searchCacheName, schema.redisSearchPrefix+strconv.FormatUint(id, 10))
+				if schema.redisSearchPredicate != nil {
+					return
+				}
+			}
+		}
+		if schema.redisSearchPredicate != nil {
+			if !schema.redisSearchPredicate(entity) {
+				f.getRedisFlusher().Del(schema.searchCacheName, schema.redisSearchPrefix+strconv.FormatUint(id, 10))
+				return
 			}
+			bind = f.convertDBDataToMap(schema, entity.getORM().dBData)
 		}
 		values := make([]interface{}, 0)
 		idMap, has := schema.mapBindToRedisSearch["ID"]
@@ -814,6 +871,18 @@ func (f *flusher) fillRedisSearchFromBind(schema *tableSchema, bind map[string]i
 				hasChangedField = true
 			}
 		}
+		for _, computed := range schema.redisSearchComputed {
+			if computed.isChanged(bind) {
+				values = append(values, computed.field.Name, computed.value(entity))
+				hasChangedField = true
+			}
+		}
+		for _, reference := range schema.redisSearchReferences {
+			if reference.isChanged(bind) {
+				values = append(values, reference.field.Name, reference.value(reference.getReferenced(f.engine, entity)))
+				hasChangedField = true
+			}
+		}
 		if hasChangedField {
 			f.getRedisFlusher().HSet(schema.searchCacheName, schema.redisSearchPrefix+strconv.FormatUint(id, 10), values...)
 		}
@@ -843,6 +912,9 @@ func (f *flusher) getCacheQueriesKeys(schema *tableSchema, bind map[string]inter
 	keys = make([]string, 0)
 
 	for indexName, definition := range schema.cachedIndexesAll {
+		if definition.sorted {
+			continue
+		}
 		if !addedDeleted && schema.hasFakeDelete {
 			_, addedDeleted = bind["FakeDelete"]
 		}
@@ -910,3 +982,86 @@ func (f *flusher) clear() {
 	f.localCacheDeletes = nil
 	f.localCacheSets = nil
 }
+
+var updateBindPool = sync.Pool{
+	New: func() interface{} {
+		return make(map[string]string)
+	},
+}
+
+func releaseUpdateBind(updateBind map[string]string) {
+	if updateBind == nil {
+		return
+	}
+	for key := range updateBind {
+		delete(updateBind, key)
+	}
+	updateBindPool.Put(updateBind)
+}
+
+func buildUpdateSQL(tableName string, updateBind map[string]string, id uint64) string {
+	size := len(tableName) + 30
+	for key, value := range updateBind {
+		size += len(key) + len(value) + 4
+	}
+	builder := strings.Builder{}
+	builder.Grow(size)
+	builder.WriteString("UPDATE ")
+	builder.WriteString(tableName)
+	builder.WriteString(" SET ")
+	first := true
+	for key, value := range updateBind {
+		if !first {
+			builder.WriteString(",")
+		}
+		first = false
+		builder.WriteString("`")
+		builder.WriteString(key)
+		builder.WriteString("`=")
+		builder.WriteString(value)
+	}
+	builder.WriteString(" WHERE `ID` = ")
+	builder.WriteString(strconv.FormatUint(id, 10))
+	return builder.String()
+}
+
+func buildInsertSQL(schema *tableSchema, columns []string, rows int) string {
+	bindPart := strings.Builder{}
+	bindPart.WriteString("(")
+	for i, column := range columns {
+		if i > 0 {
+			bindPart.WriteString(",")
+		}
+		bindPart.WriteString(schema.getBindPlaceholder(column))
+	}
+	bindPart.WriteString(")")
+	size := len(schema.tableName) + 20 + (bindPart.Len()+1)*rows
+	for _, column := range columns {
+		size += len(column) + 3
+	}
+	builder := strings.Builder{}
+	builder.Grow(size)
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(schema.tableName)
+	if len(columns) > 0 {
+		builder.WriteString("(")
+		for i, column := range columns {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString("`")
+			builder.WriteString(column)
+			builder.WriteString("`")
+		}
+		builder.WriteString(")")
+	}
+	builder.WriteString(" VALUES ")
+	part := bindPart.String()
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(part)
+	}
+	return builder.String()
+}
@@ -0,0 +1,56 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fullTextEntity struct {
+	ORM
+	ID    uint
+	Title string `orm:"fulltext=TextIndex"`
+	Body  string `orm:"fulltext=TextIndex:2;length=max"`
+	Tag   string `orm:"fulltext"`
+}
+
+type fullTextInvalidEntity struct {
+	ORM
+	ID  uint
+	Age int `orm:"fulltext"`
+}
+
+func TestFullTextIndex(t *testing.T) {
+	var entity *fullTextEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+	assert.Len(t, alters, 0)
+	var tableName, createTable string
+	engine.GetMysql().QueryRow(NewWhere("SHOW CREATE TABLE `fullTextEntity`"), &tableName, &createTable)
+	assert.Contains(t, createTable, "FULLTEXT KEY `TextIndex` (`Title`,`Body`)")
+	assert.Contains(t, createTable, "FULLTEXT KEY `Tag` (`Tag`)")
+
+	engine.FlushMany(&fullTextEntity{Title: "golang orm", Body: "mysql database driver", Tag: "backend"},
+		&fullTextEntity{Title: "redis cache", Body: "fast memory storage", Tag: "backend"},
+		&fullTextEntity{Title: "frontend", Body: "javascript framework", Tag: "frontend"})
+
+	where := W.Match("Title, Body", "+database -redis", BooleanMode)
+	assert.Equal(t, "MATCH(`Title`,`Body`) AGAINST (? IN BOOLEAN MODE)", where.String())
+	assert.Equal(t, []interface{}{"+database -redis"}, where.GetParameters())
+	var rows []*fullTextEntity
+	engine.Search(where, nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "golang orm", rows[0].Title)
+
+	engine.Search(W.Match("Tag", "backend"), nil, &rows)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "MATCH(`Tag`) AGAINST (? IN NATURAL LANGUAGE MODE)", W.Match("Tag", "backend").String())
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&fullTextInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid entity struct 'orm.fullTextInvalidEntity': fulltext index not supported for field 'Age'")
+}
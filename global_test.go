@@ -3,24 +3,89 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"os"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+type PrepareTablesOptions struct {
+	MySQLDSN       string
+	RedisAddress   string
+	RedisDBs       []int
+	DatabasePrefix string
+	UniquePrefix   bool
+	KeepData       bool
+	Cleanup        bool
+}
+
 func PrepareTables(t *testing.T, registry *Registry, version int, entities ...Entity) *Engine {
+	return PrepareTablesWithOptions(t, registry, version, nil, entities...)
+}
+
+func PrepareTablesWithOptions(t *testing.T, registry *Registry, version int, options *PrepareTablesOptions, entities ...Entity) *Engine {
+	if options == nil {
+		options = &PrepareTablesOptions{}
+	}
+	dsn := options.MySQLDSN
+	if dsn == "" {
+		dsn = os.Getenv("ORM_TEST_MYSQL" + strconv.Itoa(version))
+	}
+	if dsn == "" && version == 5 {
+		dsn = "root:root@tcp(localhost:3311)/"
+	} else if dsn == "" {
+		dsn = "root:root@tcp(localhost:3312)/"
+	}
+	redisAddress := options.RedisAddress
+	if redisAddress == "" {
+		redisAddress = os.Getenv("ORM_TEST_REDIS")
+	}
+	if redisAddress == "" {
+		redisAddress = "localhost:6382"
+	}
+	redisDBs := options.RedisDBs
+	if redisDBs == nil {
+		redisDBs = []int{15, 14, 0}
+	}
+	prefix := options.DatabasePrefix
+	if options.UniquePrefix && t != nil {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(t.Name()))
+		sum := hash.Sum32()
+		prefix += "t" + strconv.FormatUint(uint64(sum), 36) + "_"
+		if options.RedisDBs == nil {
+			// per-run databases 1-12, shared 15, 14 and 0 are never flushed
+			first := 1 + int(sum%4)*3
+			redisDBs = []int{first, first + 1, first + 2}
+		}
+	}
+	if len(redisDBs) != 3 {
+		panic(fmt.Errorf("RedisDBs must contain 3 redis databases, got %d", len(redisDBs)))
+	}
+	databases := []string{prefix + "test", prefix + "test_log"}
+	if prefix != "" {
+		db, err := sql.Open("mysql", dsn)
+		checkError(err)
+		for _, database := range databases {
+			_, err = db.Exec("CREATE DATABASE IF NOT EXISTS `" + database + "`")
+			checkError(err)
+		}
+		_ = db.Close()
+	}
 	if version == 5 {
-		registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test?limit_connections=10")
-		registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test_log", "log")
+		registry.RegisterMySQLPool(dsn + databases[0] + "?limit_connections=10")
 	} else {
-		registry.RegisterMySQLPool("root:root@tcp(localhost:3312)/test")
-		registry.RegisterMySQLPool("root:root@tcp(localhost:3312)/test_log", "log")
+		registry.RegisterMySQLPool(dsn + databases[0])
 	}
-	registry.RegisterRedis("localhost:6382", 15)
-	registry.RegisterRedis("localhost:6382", 14, "default_queue")
-	registry.RegisterRedis("localhost:6382", 0, "search")
+	registry.RegisterMySQLPool(dsn+databases[1], "log")
+	registry.RegisterRedis(redisAddress, redisDBs[0])
+	registry.RegisterRedis(redisAddress, redisDBs[1], "default_queue")
+	registry.RegisterRedis(redisAddress, redisDBs[2], "search")
 	registry.RegisterLocalCache(1000)
 
 	registry.RegisterEntity(entities...)
@@ -33,12 +98,24 @@ func PrepareTables(t *testing.T, registry *Registry, version int, entities ...En
 	if t != nil {
 		assert.Equal(t, engine.GetRegistry(), validatedRegistry)
 	}
-	redisCache := engine.GetRedis()
-	redisCache.FlushDB()
-	redisCache = engine.GetRedis("default_queue")
-	redisCache.FlushDB()
-	redisSearch := engine.GetRedis("search")
-	redisSearch.FlushDB()
+	if t != nil && options.Cleanup {
+		t.Cleanup(func() {
+			if prefix != "" {
+				for _, database := range databases {
+					engine.GetMysql().Exec("DROP DATABASE IF EXISTS `" + database + "`")
+				}
+			}
+			_ = validatedRegistry.Close()
+		})
+	}
+	if !options.KeepData {
+		redisCache := engine.GetRedis()
+		redisCache.FlushDB()
+		redisCache = engine.GetRedis("default_queue")
+		redisCache.FlushDB()
+		redisSearch := engine.GetRedis("search")
+		redisSearch.FlushDB()
+	}
 
 	alters := engine.GetAlters()
 	for _, alter := range alters {
@@ -58,7 +135,9 @@ func PrepareTables(t *testing.T, registry *Registry, version int, entities ...En
 			eType = eType.Elem()
 		}
 		tableSchema := validatedRegistry.GetTableSchema(eType.String())
-		tableSchema.TruncateTable(engine)
+		if !options.KeepData {
+			tableSchema.TruncateTable(engine)
+		}
 		tableSchema.UpdateSchema(engine)
 		localCache, has := tableSchema.GetLocalCache(engine)
 		if has {
@@ -79,6 +158,13 @@ func PrepareTables(t *testing.T, registry *Registry, version int, entities ...En
 	return engine
 }
 
+func PrepareTablesWithFixtures(t *testing.T, registry *Registry, version int, fixtures string, entities ...Entity) (*Engine, *Fixtures) {
+	engine := PrepareTables(t, registry, version, entities...)
+	loaded, err := engine.LoadFixtures([]byte(fixtures))
+	assert.NoError(t, err)
+	return engine, loaded
+}
+
 type mockDBClient struct {
 	db           dbClient
 	tx           dbClientTX
@@ -4,6 +4,7 @@ go 1.16
 
 require (
 	github.com/ClickHouse/clickhouse-go v1.4.5
+	github.com/alicebob/miniredis/v2 v2.14.3
 	github.com/apex/log v1.9.0
 	github.com/bsm/redislock v0.7.1
 	github.com/go-redis/redis/v8 v8.11.0
@@ -0,0 +1,167 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const redisIDGeneratorKeyPrefix = "orm_id:"
+const sequenceTableName = "_orm_sequences"
+const snowflakeEpoch = int64(1577836800000)
+
+const redisIDGeneratorScript = `
+if redis.call('exists', KEYS[1]) == 0 then
+	redis.call('set', KEYS[1], ARGV[1])
+end
+return redis.call('incrby', KEYS[1], ARGV[2])
+`
+
+type IDGenerator interface {
+	NextID(engine *Engine, schema TableSchema) uint64
+}
+
+type IDBlockAllocator interface {
+	AllocateIDs(engine *Engine, schema TableSchema, count uint64) (first, last uint64)
+}
+
+func (e *Engine) ReserveIDs(entity Entity, count uint64) (first, last uint64) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	allocator, is := schema.idGenerator.(IDBlockAllocator)
+	if !is {
+		panic(fmt.Errorf("entity '%s' has no ID generator supporting block allocation", schema.t.String()))
+	}
+	if count == 0 {
+		panic(fmt.Errorf("invalid number of IDs to reserve"))
+	}
+	return allocator.AllocateIDs(e, schema, count)
+}
+
+func (r *Registry) RegisterIDGenerator(entity Entity, generator IDGenerator) {
+	if r.idGenerators == nil {
+		r.idGenerators = make(map[string]IDGenerator)
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r.idGenerators[t.String()] = generator
+}
+
+type snowflakeIDGenerator struct {
+	mutex    sync.Mutex
+	node     uint64
+	lastTime int64
+	sequence uint64
+}
+
+func NewSnowflakeIDGenerator(node uint16) IDGenerator {
+	if node > 1023 {
+		panic(fmt.Errorf("snowflake node %d exceeds 1023", node))
+	}
+	return &snowflakeIDGenerator{node: uint64(node)}
+}
+
+func (g *snowflakeIDGenerator) NextID(engine *Engine, _ TableSchema) uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := engine.GetClock().Now().UnixNano()/1000000 - snowflakeEpoch
+	if now < g.lastTime {
+		now = g.lastTime
+	}
+	if now == g.lastTime {
+		g.sequence = (g.sequence + 1) & 4095
+		if g.sequence == 0 {
+			now++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastTime = now
+	return uint64(now)<<22 | g.node<<12 | g.sequence
+}
+
+type redisIDGenerator struct {
+	mutex     sync.Mutex
+	pool      string
+	blockSize uint64
+	next      map[string]uint64
+	last      map[string]uint64
+}
+
+func NewRedisIDGenerator(pool string, blockSize uint64) IDGenerator {
+	if blockSize == 0 {
+		blockSize = 1
+	}
+	return &redisIDGenerator{pool: pool, blockSize: blockSize, next: make(map[string]uint64), last: make(map[string]uint64)}
+}
+
+func (g *redisIDGenerator) NextID(engine *Engine, schema TableSchema) uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	key := redisIDGeneratorKeyPrefix + schema.(*tableSchema).mysqlPoolName + ":" + schema.GetTableName()
+	next := g.next[key]
+	if next == 0 || next > g.last[key] {
+		last := g.allocate(engine, schema.(*tableSchema), key, g.blockSize)
+		next = last - g.blockSize + 1
+		g.last[key] = last
+	}
+	g.next[key] = next + 1
+	return next
+}
+
+func (g *redisIDGenerator) AllocateIDs(engine *Engine, schema TableSchema, count uint64) (first, last uint64) {
+	key := redisIDGeneratorKeyPrefix + schema.(*tableSchema).mysqlPoolName + ":" + schema.GetTableName()
+	last = g.allocate(engine, schema.(*tableSchema), key, count)
+	return last - count + 1, last
+}
+
+func (g *redisIDGenerator) allocate(engine *Engine, schema *tableSchema, key string, size uint64) uint64 {
+	redisCache := engine.GetRedis(g.pool)
+	result := redisCache.Eval(redisIDGeneratorScript, []string{key}, getMaxID(engine, schema), size)
+	return uint64(result.(int64))
+}
+
+type sequenceIDGenerator struct {
+	mutex       sync.Mutex
+	initialised map[string]bool
+}
+
+func NewSequenceIDGenerator() IDGenerator {
+	return &sequenceIDGenerator{initialised: make(map[string]bool)}
+}
+
+func (g *sequenceIDGenerator) NextID(engine *Engine, schema TableSchema) uint64 {
+	return g.allocate(engine, schema.(*tableSchema), 1)
+}
+
+func (g *sequenceIDGenerator) AllocateIDs(engine *Engine, schema TableSchema, count uint64) (first, last uint64) {
+	last = g.allocate(engine, schema.(*tableSchema), count)
+	return last - count + 1, last
+}
+
+func (g *sequenceIDGenerator) allocate(engine *Engine, schema *tableSchema, size uint64) uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	config := schema.GetMysql(engine).config
+	db := &DB{engine: engine, config: config, client: &standardSQLClient{db: config.getClient(), ctx: engine.context}}
+	if !g.initialised[schema.mysqlPoolName] {
+		db.Exec("CREATE TABLE IF NOT EXISTS `" + sequenceTableName + "` (`Name` varchar(191) NOT NULL, `Value` bigint unsigned NOT NULL, PRIMARY KEY (`Name`))")
+		g.initialised[schema.mysqlPoolName] = true
+	}
+	if !g.initialised[schema.mysqlPoolName+":"+schema.tableName] {
+		/* #nosec */
+		db.Exec("INSERT IGNORE INTO `"+sequenceTableName+"`(`Name`,`Value`) SELECT ?, COALESCE(MAX(`ID`), 0) FROM `"+schema.tableName+"`", schema.tableName)
+		g.initialised[schema.mysqlPoolName+":"+schema.tableName] = true
+	}
+	/* #nosec */
+	result := db.Exec("UPDATE `"+sequenceTableName+"` SET `Value` = LAST_INSERT_ID(`Value` + ?) WHERE `Name` = ?", size, schema.tableName)
+	return result.LastInsertId()
+}
+
+func getMaxID(engine *Engine, schema *tableSchema) uint64 {
+	var maxID uint64
+	/* #nosec */
+	schema.GetMysql(engine).QueryRow(NewWhere("SELECT COALESCE(MAX(`ID`), 0) FROM `"+schema.tableName+"`"), &maxID)
+	return maxID
+}
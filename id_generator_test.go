@@ -0,0 +1,110 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type idGeneratorSnowflakeEntity struct {
+	ORM
+	ID   uint64
+	Name string
+}
+
+type idGeneratorSnowflakeInvalidEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type idGeneratorRedisEntity struct {
+	ORM
+	ID   uint64
+	Name string
+}
+
+type idGeneratorSequenceEntity struct {
+	ORM
+	ID   uint64
+	Name string
+}
+
+func TestIDGenerators(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterIDGenerator(&idGeneratorSnowflakeEntity{}, NewSnowflakeIDGenerator(3))
+	registry.RegisterIDGenerator(&idGeneratorRedisEntity{}, NewRedisIDGenerator("default", 10))
+	registry.RegisterIDGenerator(&idGeneratorSequenceEntity{}, NewSequenceIDGenerator())
+	engine := PrepareTables(t, registry, 5, &idGeneratorSnowflakeEntity{}, &idGeneratorRedisEntity{}, &idGeneratorSequenceEntity{})
+	engine.GetMysql().Exec("DROP TABLE IF EXISTS `" + sequenceTableName + "`")
+	engine.SetClock(NewMockClock(time.Unix(1600000000, 0)))
+
+	snowflake1 := &idGeneratorSnowflakeEntity{Name: "a"}
+	snowflake2 := &idGeneratorSnowflakeEntity{Name: "b"}
+	engine.FlushMany(snowflake1, snowflake2)
+	expected := uint64(1600000000000-snowflakeEpoch)<<22 | 3<<12
+	assert.Equal(t, expected, snowflake1.ID)
+	assert.Equal(t, expected+1, snowflake2.ID)
+	assert.True(t, engine.LoadByID(expected+1, &idGeneratorSnowflakeEntity{}))
+
+	engine.GetMysql().Exec("INSERT INTO `idGeneratorRedisEntity`(`ID`, `Name`) VALUES (7, 'manual')")
+	redis1 := &idGeneratorRedisEntity{Name: "a"}
+	redis2 := &idGeneratorRedisEntity{Name: "b"}
+	engine.FlushMany(redis1, redis2)
+	assert.Equal(t, uint64(8), redis1.ID)
+	assert.Equal(t, uint64(9), redis2.ID)
+	value, _ := engine.GetRedis().Get(redisIDGeneratorKeyPrefix + "default:idGeneratorRedisEntity")
+	assert.Equal(t, "17", value)
+
+	engine.GetMysql().Exec("INSERT INTO `idGeneratorSequenceEntity`(`ID`, `Name`) VALUES (3, 'manual')")
+	sequence1 := &idGeneratorSequenceEntity{Name: "a"}
+	engine.Flush(sequence1)
+	sequence2 := &idGeneratorSequenceEntity{Name: "b"}
+	engine.Flush(sequence2)
+	assert.Equal(t, uint64(4), sequence1.ID)
+	assert.Equal(t, uint64(5), sequence2.ID)
+	has, _ := engine.GetRegistry().GetTableSchemaForEntity(&idGeneratorSequenceEntity{}).GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	engine.GetMysql().Begin()
+	sequence4 := &idGeneratorSequenceEntity{Name: "d"}
+	engine.Flush(sequence4)
+	engine.GetMysql().Rollback()
+	assert.Equal(t, uint64(6), sequence4.ID)
+	assert.False(t, engine.LoadByID(6, &idGeneratorSequenceEntity{}))
+
+	first, last := engine.ReserveIDs(&idGeneratorSequenceEntity{}, 100)
+	assert.Equal(t, uint64(7), first)
+	assert.Equal(t, uint64(106), last)
+	sequence3 := &idGeneratorSequenceEntity{Name: "c"}
+	engine.Flush(sequence3)
+	assert.Equal(t, uint64(107), sequence3.ID)
+	bulk := make([]Entity, 0, 100)
+	for id := first; id <= last; id++ {
+		bulk = append(bulk, &idGeneratorSequenceEntity{ID: id, Name: "bulk"})
+	}
+	engine.FlushMany(bulk...)
+	assert.Equal(t, 104, engine.Count(NewWhere("1"), &idGeneratorSequenceEntity{}))
+
+	first, last = engine.ReserveIDs(&idGeneratorRedisEntity{}, 5)
+	assert.Equal(t, uint64(18), first)
+	assert.Equal(t, uint64(22), last)
+	redis3 := &idGeneratorRedisEntity{Name: "c"}
+	engine.Flush(redis3)
+	assert.Equal(t, uint64(10), redis3.ID)
+
+	assert.PanicsWithError(t, "entity 'orm.idGeneratorSnowflakeEntity' has no ID generator supporting block allocation", func() {
+		engine.ReserveIDs(&idGeneratorSnowflakeEntity{}, 10)
+	})
+	assert.PanicsWithError(t, "snowflake node 1024 exceeds 1023", func() {
+		NewSnowflakeIDGenerator(1024)
+	})
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&idGeneratorSnowflakeInvalidEntity{})
+	registry.RegisterIDGenerator(&idGeneratorSnowflakeInvalidEntity{}, NewSnowflakeIDGenerator(1))
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "snowflake ID generator in entity 'orm.idGeneratorSnowflakeInvalidEntity' requires bigint unsigned ID")
+}
@@ -0,0 +1,102 @@
+package orm
+
+import (
+	"reflect"
+	"sync"
+)
+
+type identityMap struct {
+	mutex    sync.Mutex
+	entities map[reflect.Type]map[uint64]Entity
+}
+
+func WithIdentityMap() EngineOption {
+	return func(engine *Engine) {
+		engine.EnableIdentityMap()
+	}
+}
+
+func (e *Engine) EnableIdentityMap() {
+	if e.identityMap == nil {
+		e.identityMap = &identityMap{entities: make(map[reflect.Type]map[uint64]Entity)}
+	}
+}
+
+func (e *Engine) DisableIdentityMap() {
+	e.identityMap = nil
+}
+
+func (e *Engine) ClearIdentityMap() {
+	if e.identityMap != nil {
+		e.identityMap.mutex.Lock()
+		e.identityMap.entities = make(map[reflect.Type]map[uint64]Entity)
+		e.identityMap.mutex.Unlock()
+	}
+}
+
+func (e *Engine) GetByID(id uint64, entity Entity, references ...string) Entity {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	if e.identityMap != nil {
+		existing := e.identityMap.get(schema.t, id)
+		if existing != nil {
+			return existing
+		}
+	}
+	if !e.LoadByID(id, entity, references...) {
+		return nil
+	}
+	if e.identityMap != nil {
+		return e.identityMap.get(schema.t, id)
+	}
+	return entity
+}
+
+func (m *identityMap) get(t reflect.Type, id uint64) Entity {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.entities[t][id]
+}
+
+func (m *identityMap) add(entity Entity) Entity {
+	id := entity.GetID()
+	if id == 0 {
+		return entity
+	}
+	t := entity.getORM().tableSchema.t
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	entities, has := m.entities[t]
+	if !has {
+		entities = make(map[uint64]Entity)
+		m.entities[t] = entities
+	}
+	existing, has := entities[id]
+	if has {
+		return existing
+	}
+	entities[id] = entity
+	return entity
+}
+
+func (m *identityMap) remove(t reflect.Type, id uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.entities[t], id)
+}
+
+func applyIdentityMap(engine *Engine, entities reflect.Value) {
+	if engine.identityMap == nil {
+		return
+	}
+	for i := 0; i < entities.Len(); i++ {
+		row := entities.Index(i)
+		if row.IsNil() {
+			continue
+		}
+		entity := row.Interface().(Entity)
+		existing := engine.identityMap.add(entity)
+		if existing != entity {
+			row.Set(existing.getORM().value)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type identityMapEntity struct {
+	ORM  `orm:"localCache"`
+	ID   uint
+	Name string
+}
+
+func TestIdentityMap(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &identityMapEntity{})
+	engine.FlushMany(&identityMapEntity{Name: "a"}, &identityMapEntity{Name: "b"})
+
+	unit := engine.WithOptions(WithIdentityMap())
+	first := unit.GetByID(1, &identityMapEntity{})
+	second := unit.GetByID(1, &identityMapEntity{})
+	assert.Same(t, first, second)
+	assert.Nil(t, unit.GetByID(100, &identityMapEntity{}))
+
+	var rows []*identityMapEntity
+	unit.LoadByIDs([]uint64{1, 2}, &rows)
+	assert.Same(t, first, rows[0])
+	var found []*identityMapEntity
+	unit.Search(NewWhere("1 ORDER BY `ID`"), nil, &found)
+	assert.Same(t, first, found[0])
+	assert.Same(t, rows[1], found[1])
+
+	first.(*identityMapEntity).Name = "changed"
+	assert.Equal(t, "changed", found[0].Name)
+
+	unit.Delete(found[1])
+	assert.Nil(t, unit.GetByID(2, &identityMapEntity{}))
+
+	unit.ClearIdentityMap()
+	assert.NotSame(t, first, unit.GetByID(1, &identityMapEntity{}))
+
+	unit.DisableIdentityMap()
+	entity := &identityMapEntity{}
+	assert.Same(t, entity, unit.GetByID(1, entity))
+	engine.LoadByIDs([]uint64{1}, &rows)
+	engine.LoadByIDs([]uint64{1}, &found)
+	assert.NotSame(t, rows[0], found[0])
+}
@@ -0,0 +1,244 @@
+package orm
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var importJSON = jsoniter.Config{UseNumber: true}.Froze()
+
+type ImportOptions struct {
+	Columns     []string
+	Mapping     map[string]string
+	Comma       rune
+	BatchSize   int
+	Lazy        bool
+	StopOnError bool
+}
+
+type ImportRowError struct {
+	Row   int
+	Field string
+	Err   error
+}
+
+func (err *ImportRowError) Error() string {
+	if err.Field != "" {
+		return fmt.Sprintf("row %d, field %s: %s", err.Row, err.Field, err.Err.Error())
+	}
+	return fmt.Sprintf("row %d: %s", err.Row, err.Err.Error())
+}
+
+type ImportReport struct {
+	Rows     int
+	Imported int
+	Errors   []*ImportRowError
+}
+
+type importRow struct {
+	number int
+	entity Entity
+}
+
+type importBatch struct {
+	engine  *Engine
+	options *ImportOptions
+	report  *ImportReport
+	rows    []*importRow
+}
+
+func (e *Engine) ImportCSV(entity Entity, reader io.Reader, options *ImportOptions) *ImportReport {
+	options = prepareImportOptions(options)
+	csvReader := csv.NewReader(reader)
+	if options.Comma != 0 {
+		csvReader.Comma = options.Comma
+	}
+	csvReader.FieldsPerRecord = -1
+	columns := options.Columns
+	number := 0
+	if len(columns) == 0 {
+		header, err := csvReader.Read()
+		if err == io.EOF {
+			return &ImportReport{}
+		}
+		checkError(err)
+		columns = header
+		number++
+	}
+	return importRows(e, entity, options, func() (map[string]interface{}, int, error) {
+		record, err := csvReader.Read()
+		number++
+		if err != nil {
+			return nil, number, err
+		}
+		values := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			if i < len(columns) {
+				values[columns[i]] = value
+			}
+		}
+		return values, number, nil
+	})
+}
+
+func (e *Engine) ImportNDJSON(entity Entity, reader io.Reader, options *ImportOptions) *ImportReport {
+	options = prepareImportOptions(options)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	number := 0
+	return importRows(e, entity, options, func() (map[string]interface{}, int, error) {
+		for scanner.Scan() {
+			number++
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			values := make(map[string]interface{})
+			err := importJSON.Unmarshal(line, &values)
+			return values, number, err
+		}
+		err := scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return nil, number, err
+	})
+}
+
+func prepareImportOptions(options *ImportOptions) *ImportOptions {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	return options
+}
+
+func importRows(engine *Engine, entity Entity, options *ImportOptions, next func() (map[string]interface{}, int, error)) *ImportReport {
+	t := initIfNeeded(engine.registry, entity).tableSchema.t
+	report := &ImportReport{Errors: make([]*ImportRowError, 0)}
+	batch := &importBatch{engine: engine, options: options, report: report}
+	for {
+		values, number, err := next()
+		if err == io.EOF {
+			break
+		}
+		report.Rows++
+		if err != nil {
+			report.Errors = append(report.Errors, &ImportRowError{Row: number, Err: err})
+			if options.StopOnError {
+				return report
+			}
+			continue
+		}
+		row := reflect.New(t).Interface().(Entity)
+		initIfNeeded(engine.registry, row)
+		rowErr := setImportValues(row, values, options, number)
+		if rowErr != nil {
+			report.Errors = append(report.Errors, rowErr)
+			if options.StopOnError {
+				return report
+			}
+			continue
+		}
+		batch.rows = append(batch.rows, &importRow{number: number, entity: row})
+		if len(batch.rows) >= options.BatchSize {
+			if !batch.flush() && options.StopOnError {
+				return report
+			}
+		}
+	}
+	batch.flush()
+	return report
+}
+
+func setImportValues(entity Entity, values map[string]interface{}, options *ImportOptions, number int) *ImportRowError {
+	elem := entity.getORM().elem
+	for column, value := range values {
+		field := column
+		if options.Mapping != nil {
+			mapped, has := options.Mapping[column]
+			if !has {
+				continue
+			}
+			field = mapped
+		}
+		if field == "" || field == "ID" {
+			continue
+		}
+		asString, isString := value.(string)
+		if isString && asString == "" {
+			value = nil
+		} else if isString {
+			fieldValue := elem.FieldByName(field)
+			if fieldValue.IsValid() {
+				value = convertImportTime(fieldValue.Type(), asString)
+			}
+		}
+		err := entity.SetField(field, value)
+		if err != nil {
+			return &ImportRowError{Row: number, Field: field, Err: err}
+		}
+	}
+	return nil
+}
+
+func convertImportTime(t reflect.Type, value string) interface{} {
+	typeName := t.String()
+	if typeName != "time.Time" && typeName != "*time.Time" {
+		return value
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		parsed, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			if typeName == "*time.Time" {
+				return &parsed
+			}
+			return parsed
+		}
+	}
+	return value
+}
+
+func (b *importBatch) flush() bool {
+	if len(b.rows) == 0 {
+		return true
+	}
+	rows := b.rows
+	b.rows = nil
+	entities := make([]Entity, len(rows))
+	for i, row := range rows {
+		entities[i] = row.entity
+	}
+	if b.options.Lazy {
+		b.engine.FlushLazyMany(entities...)
+		b.report.Imported += len(rows)
+		return true
+	}
+	err := b.engine.NewFlusher().Track(entities...).FlushWithFullCheck()
+	if err == nil {
+		b.report.Imported += len(rows)
+		return true
+	}
+	success := true
+	for _, row := range rows {
+		err = b.engine.NewFlusher().Track(row.entity).FlushWithFullCheck()
+		if err != nil {
+			b.report.Errors = append(b.report.Errors, &ImportRowError{Row: row.number, Err: err})
+			success = false
+			if b.options.StopOnError {
+				return false
+			}
+			continue
+		}
+		b.report.Imported++
+	}
+	return success
+}
@@ -0,0 +1,86 @@
+package orm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type importEntity struct {
+	ORM
+	ID        uint
+	Name      string `orm:"required;unique=Name"`
+	Age       uint8
+	Active    bool
+	CreatedAt *time.Time `orm:"time"`
+}
+
+func TestImportCSV(t *testing.T) {
+	var entity *importEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+
+	data := "Name,Age,Active,CreatedAt,Unknown\n" +
+		"Tom,18,true,2021-03-01,x\n" +
+		"John,invalid,false,,x\n" +
+		"Adam,20,false,2021-03-02 10:11:12,x\n" +
+		"Tom,21,true,,x\n"
+	report := engine.ImportCSV(entity, strings.NewReader(data), &ImportOptions{
+		Mapping:   map[string]string{"Name": "Name", "Age": "Age", "Active": "Active", "CreatedAt": "CreatedAt"},
+		BatchSize: 2,
+	})
+	assert.Equal(t, 4, report.Rows)
+	assert.Equal(t, 2, report.Imported)
+	assert.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	assert.Equal(t, "Age", report.Errors[0].Field)
+	assert.Equal(t, "row 3, field Age: Age value invalid not valid", report.Errors[0].Error())
+	assert.Equal(t, 5, report.Errors[1].Row)
+	assert.Contains(t, report.Errors[1].Error(), "Duplicate entry")
+
+	var rows []*importEntity
+	engine.Search(NewWhere("1 ORDER BY ID"), nil, &rows)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Tom", rows[0].Name)
+	assert.Equal(t, uint8(18), rows[0].Age)
+	assert.True(t, rows[0].Active)
+	assert.Equal(t, "2021-03-01", rows[0].CreatedAt.Format("2006-01-02"))
+	assert.Equal(t, "Adam", rows[1].Name)
+	assert.Equal(t, "2021-03-02 10:11:12", rows[1].CreatedAt.Format("2006-01-02 15:04:05"))
+
+	report = engine.ImportCSV(entity, strings.NewReader("Bob;30\nTom;1\nAnna;31\n"), &ImportOptions{
+		Columns:     []string{"Name", "Age"},
+		Comma:       ';',
+		StopOnError: true,
+	})
+	assert.Equal(t, 3, report.Rows)
+	assert.Len(t, report.Errors, 1)
+	assert.Equal(t, 2, report.Errors[0].Row)
+	assert.Equal(t, 1, report.Imported)
+
+	report = engine.ImportCSV(entity, strings.NewReader(""), nil)
+	assert.Equal(t, 0, report.Rows)
+}
+
+func TestImportNDJSON(t *testing.T) {
+	var entity *importEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+
+	data := "{\"Name\": \"Tom\", \"Age\": 18, \"CreatedAt\": \"2021-03-01T10:00:00Z\"}\n" +
+		"\n" +
+		"{invalid\n" +
+		"{\"Name\": \"John\", \"Age\": -5}\n" +
+		"{\"Name\": \"Adam\", \"Active\": true}\n"
+	report := engine.ImportNDJSON(entity, strings.NewReader(data), nil)
+	assert.Equal(t, 4, report.Rows)
+	assert.Equal(t, 2, report.Imported)
+	assert.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	assert.Equal(t, 4, report.Errors[1].Row)
+
+	loaded := &importEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, "Adam", loaded.Name)
+	assert.True(t, loaded.Active)
+}
@@ -0,0 +1,119 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (e *Engine) IncrementField(entity Entity, field string, delta int64) {
+	incrementField(e, entity, field, delta, false)
+}
+
+func (e *Engine) IncrementFieldLazy(entity Entity, field string, delta int64) {
+	incrementField(e, entity, field, delta, true)
+}
+
+func incrementField(engine *Engine, entity Entity, field string, delta int64, lazy bool) {
+	orm := initIfNeeded(engine.registry, entity)
+	schema := orm.tableSchema
+	id := entity.GetID()
+	if !orm.inDB || !entity.IsLoaded() {
+		panic(fmt.Errorf("entity is not loaded and can't be updated: %v [%d]", schema.t.String(), id))
+	}
+	_, has := schema.columnMapping[field]
+	fieldValue := orm.elem.FieldByName(field)
+	if !has || !fieldValue.IsValid() {
+		panic(fmt.Errorf("unknown column '%s' in entity '%s'", field, schema.t.String()))
+	}
+	isUnsigned := false
+	switch fieldValue.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		isUnsigned = true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		panic(fmt.Errorf("column '%s' in entity '%s' is not an integer", field, schema.t.String()))
+	}
+	if delta == 0 {
+		return
+	}
+	if engine.readOnly {
+		panic(&ReadOnlyError{Message: "engine is in read only mode"})
+	}
+	checkRowPolicy(engine, schema, id)
+	db := schema.GetMysql(engine)
+	f := &flusher{engine: engine}
+	bind := Bind{}
+	if lazy {
+		var logEvents []*LogQueueValue
+		var dirtyEvents []*dirtyQueueValue
+		logEvent, dirtyEvent := incrementFieldLazy(f, entity, schema, field, delta, isUnsigned)
+		if logEvent != nil {
+			logEvents = append(logEvents, logEvent)
+		}
+		if dirtyEvent != nil {
+			dirtyEvents = append(dirtyEvents, dirtyEvent)
+		}
+		/* #nosec */
+		sql := "UPDATE `" + schema.tableName + "` SET `" + field + "` = `" + field + "` + ?" + schema.getRenamedCopyAssignment(field) + " WHERE `ID` = ?"
+		f.fillLazyQuery(db.GetPoolConfig().GetCode(), sql, []interface{}{delta, id}, logEvents, dirtyEvents)
+		f.flush(true, true, false)
+		return
+	}
+	/* #nosec */
+	sql := "UPDATE `" + schema.tableName + "` SET `" + field + "` = LAST_INSERT_ID(`" + field + "` + ?)" + schema.getRenamedCopyAssignment(field) + " WHERE `ID` = ?"
+	result := db.Exec(sql, delta, id)
+	if result.RowsAffected() == 0 {
+		panic(&NotFoundError{Entity: schema.t.String(), ID: id})
+	}
+	value := result.LastInsertId()
+	if isUnsigned {
+		bind[field] = value
+		fieldValue.SetUint(value)
+	} else {
+		bind[field] = int64(value)
+		fieldValue.SetInt(int64(value))
+	}
+	f.updateCacheAfterUpdate(orm.dBData, entity, bind, schema, id, false)
+	f.flush(true, false, db.inTransaction)
+}
+
+func incrementFieldLazy(f *flusher, entity Entity, schema *tableSchema, field string, delta int64, isUnsigned bool) (*LogQueueValue, *dirtyQueueValue) {
+	orm := entity.getORM()
+	id := orm.GetID()
+	fieldValue := orm.elem.FieldByName(field)
+	bind := Bind{}
+	if isUnsigned {
+		bind[field] = uint64(int64(fieldValue.Uint()) + delta)
+		fieldValue.SetUint(bind[field].(uint64))
+	} else {
+		bind[field] = fieldValue.Int() + delta
+		fieldValue.SetInt(bind[field].(int64))
+	}
+	before := make([]interface{}, len(orm.dBData))
+	copy(before, orm.dBData)
+	f.injectBind(entity, bind)
+	after := orm.dBData
+	localCache, hasLocalCache := schema.GetLocalCache(f.engine)
+	if !hasLocalCache && f.engine.hasRequestCache {
+		hasLocalCache = true
+		localCache = f.engine.GetLocalCache(requestCacheKey)
+	}
+	if hasLocalCache {
+		f.addLocalCacheDeletes(localCache.config.GetCode(), schema.getCacheKey(id))
+		f.addLocalCacheDeletes(localCache.config.GetCode(), f.getCacheQueriesKeys(schema, bind, before, false)...)
+		f.addLocalCacheDeletes(localCache.config.GetCode(), f.getCacheQueriesKeys(schema, bind, after, false)...)
+	}
+	redisCache, hasRedis := schema.GetRedisCache(f.engine)
+	if hasRedis {
+		f.getRedisFlusher().Del(redisCache.config.GetCode(), schema.getCacheKey(id))
+		f.getRedisFlusher().Del(redisCache.config.GetCode(), f.getCacheQueriesKeys(schema, bind, before, false)...)
+		f.getRedisFlusher().Del(redisCache.config.GetCode(), f.getCacheQueriesKeys(schema, bind, after, false)...)
+		f.updateSortedIndexes(schema, redisCache, id, before, after, true)
+	}
+	f.fillRedisSearchFromBind(schema, entity, bind, id)
+	dirtyEvent := f.addDirtyQueues(bind, schema, id, "u", true)
+	if schema.hasLog {
+		return f.addToLogQueue(schema, id, f.convertDBDataToMap(schema, before), bind, orm.logMeta, true), dirtyEvent
+	}
+	return nil, dirtyEvent
+}
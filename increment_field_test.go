@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type incrementFieldEntity struct {
+	ORM     `orm:"localCache;redisCache"`
+	ID      uint
+	Name    string
+	Counter uint32
+	Balance int
+}
+
+func TestIncrementField(t *testing.T) {
+	var entity *incrementFieldEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	entity = &incrementFieldEntity{Name: "a", Counter: 10}
+	engine.Flush(entity)
+
+	other := &incrementFieldEntity{}
+	assert.True(t, engine.LoadByID(1, other))
+	engine.IncrementField(other, "Counter", 5)
+	assert.Equal(t, uint32(15), other.Counter)
+	engine.IncrementField(entity, "Counter", 2)
+	assert.Equal(t, uint32(17), entity.Counter)
+	engine.IncrementField(entity, "Balance", -3)
+	assert.Equal(t, -3, entity.Balance)
+	assert.False(t, entity.IsDirty())
+
+	loaded := &incrementFieldEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, uint32(17), loaded.Counter)
+	assert.Equal(t, -3, loaded.Balance)
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	loaded = &incrementFieldEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, uint32(17), loaded.Counter)
+
+	assert.PanicsWithError(t, "unknown column 'Invalid' in entity 'orm.incrementFieldEntity'", func() {
+		engine.IncrementField(entity, "Invalid", 1)
+	})
+	assert.PanicsWithError(t, "column 'Name' in entity 'orm.incrementFieldEntity' is not an integer", func() {
+		engine.IncrementField(entity, "Name", 1)
+	})
+	assert.PanicsWithError(t, "entity is not loaded and can't be updated: orm.incrementFieldEntity [0]", func() {
+		engine.IncrementField(&incrementFieldEntity{}, "Counter", 1)
+	})
+	engine.GetMysql().Exec("DELETE FROM `incrementFieldEntity` WHERE `ID` = ?", 1)
+	assert.PanicsWithError(t, "entity orm.incrementFieldEntity [1] not found", func() {
+		engine.IncrementField(entity, "Counter", 1)
+	})
+}
+
+func TestIncrementFieldLazy(t *testing.T) {
+	var entity *incrementFieldEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.GetRedis().FlushDB()
+	entity = &incrementFieldEntity{Name: "a", Counter: 10}
+	engine.Flush(entity)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableLoop()
+	receiver.blockTime = time.Millisecond
+
+	engine.GetMysql().Exec("UPDATE `incrementFieldEntity` SET `Counter` = 20 WHERE `ID` = 1")
+	engine.IncrementFieldLazy(entity, "Counter", 3)
+	assert.Equal(t, uint32(13), entity.Counter)
+	loaded := &incrementFieldEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, uint32(10), loaded.Counter)
+
+	receiver.Digest(context.Background())
+	loaded = &incrementFieldEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, uint32(23), loaded.Counter)
+
+	engine.SetReadOnly(true)
+	assert.PanicsWithError(t, "engine is in read only mode", func() {
+		engine.IncrementFieldLazy(entity, "Counter", 1)
+	})
+}
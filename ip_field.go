@@ -0,0 +1,55 @@
+package orm
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+)
+
+var ipFieldType = reflect.TypeOf(net.IP{})
+
+var ipFieldDefinition = &FieldTypeDefinition{
+	SQLType: "varbinary(16)",
+	BindSetter: func(value interface{}) (string, error) {
+		ip := value.(net.IP).To16()
+		if ip == nil {
+			return "", fmt.Errorf("invalid ip address")
+		}
+		return string(ip), nil
+	},
+	FieldSetter: func(value string) (interface{}, error) {
+		if len(value) != net.IPv6len {
+			return nil, fmt.Errorf("invalid ip address length %d", len(value))
+		}
+		return net.IP(value), nil
+	},
+}
+
+func GetNetworkRange(network *net.IPNet) (start, end net.IP) {
+	ip := network.IP.To16()
+	mask := network.Mask
+	if len(mask) == net.IPv4len {
+		mask = append(net.CIDRMask(96, 128)[0:12], mask...)
+	}
+	start = make(net.IP, net.IPv6len)
+	end = make(net.IP, net.IPv6len)
+	for i := 0; i < net.IPv6len; i++ {
+		start[i] = ip[i] & mask[i]
+		end[i] = ip[i] | ^mask[i]
+	}
+	return start, end
+}
+
+func (w WhereBuilder) InNetwork(field string, cidr string) *Where {
+	_, network, err := net.ParseCIDR(cidr)
+	checkError(err)
+	start, end := GetNetworkRange(network)
+	return NewWhere("`"+field+"` BETWEEN ? AND ?", string(start), string(end))
+}
+
+func (w WhereBuilder) IPEq(field string, ip net.IP) *Where {
+	if ip == nil {
+		return w.IsNull(field)
+	}
+	return NewWhere("`"+field+"` = ?", string(ip.To16()))
+}
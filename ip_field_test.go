@@ -0,0 +1,62 @@
+package orm
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ipFieldEntity struct {
+	ORM     `orm:"localCache;redisCache"`
+	ID      uint
+	Address net.IP
+}
+
+func TestIPField(t *testing.T) {
+	var entity *ipFieldEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+	var createTable string
+	engine.GetMysql().QueryRow(NewWhere("SHOW CREATE TABLE `ipFieldEntity`"), &createTable, &createTable)
+	assert.Contains(t, createTable, "`Address` varbinary(16) DEFAULT NULL")
+
+	engine.FlushMany(&ipFieldEntity{Address: net.ParseIP("10.0.0.1")}, &ipFieldEntity{Address: net.ParseIP("10.0.1.255")},
+		&ipFieldEntity{Address: net.ParseIP("192.168.0.1")}, &ipFieldEntity{Address: net.ParseIP("2001:db8::1")}, &ipFieldEntity{})
+
+	entity = &ipFieldEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "10.0.0.1", entity.Address.String())
+	assert.False(t, entity.IsDirty())
+	entity = &ipFieldEntity{}
+	assert.True(t, engine.LoadByID(5, entity))
+	assert.Nil(t, entity.Address)
+
+	var rows []*ipFieldEntity
+	engine.Search(W.InNetwork("Address", "10.0.0.0/23"), nil, &rows)
+	assert.Len(t, rows, 2)
+	engine.Search(W.InNetwork("Address", "2001:db8::/32"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(4), rows[0].ID)
+	engine.Search(W.IPEq("Address", net.ParseIP("192.168.0.1")), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(3), rows[0].ID)
+	engine.Search(W.IPEq("Address", nil), nil, &rows)
+	assert.Len(t, rows, 1)
+	engine.Search(NewWhere("1 ORDER BY `Address`"), nil, &rows)
+	assert.Equal(t, uint(2), rows[2].ID)
+
+	entity = rows[2]
+	entity.Address = net.ParseIP("10.0.2.1")
+	engine.Flush(entity)
+	engine.GetLocalCache().Clear()
+	entity = &ipFieldEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, "10.0.2.1", entity.Address.String())
+
+	start, end := GetNetworkRange(&net.IPNet{IP: net.ParseIP("172.16.5.4"), Mask: net.CIDRMask(16, 32)})
+	assert.Equal(t, "172.16.0.0", start.String())
+	assert.Equal(t, "172.16.255.255", end.String())
+}
@@ -0,0 +1,139 @@
+package orm
+
+import (
+	"strings"
+)
+
+type lazyInsertEvent struct {
+	event   Event
+	data    map[string]interface{}
+	blobKey string
+	pool    string
+	tenant  string
+	prefix  string
+	values  string
+	params  []interface{}
+	rows    int
+}
+
+func (r *BackgroundConsumer) SetLazyInsertBatchSize(rows int) {
+	r.lazyInsertBatch = rows
+}
+
+func (r *BackgroundConsumer) handleEventsInBatches(events []Event) {
+	batch := make([]*lazyInsertEvent, 0)
+	rows := 0
+	for _, event := range events {
+		if event.Stream() != lazyChannelName {
+			r.handleLazyInsertBatch(batch)
+			batch = batch[:0]
+			rows = 0
+			switch event.Stream() {
+			case logChannelName:
+				r.handleLogEvent(event)
+			case redisSearchIndexerChannelName:
+				r.handleRedisIndexerEvent(event)
+			case redisSearchReferenceChannelName:
+				r.handleRedisSearchReferenceEvent(event)
+			}
+			continue
+		}
+		var data map[string]interface{}
+		err := event.Unserialize(&data)
+		if err != nil {
+			event.Ack()
+			continue
+		}
+		data, blobKey, valid := r.loadLazyBlob(data)
+		if !valid {
+			event.Ack()
+			continue
+		}
+		insert := getLazyInsertEvent(event, data, blobKey)
+		if len(batch) > 0 && (insert == nil || batch[0].pool != insert.pool || batch[0].tenant != insert.tenant ||
+			batch[0].prefix != insert.prefix || rows+insert.rows > r.lazyInsertBatch) {
+			r.handleLazyInsertBatch(batch)
+			batch = batch[:0]
+			rows = 0
+		}
+		if insert == nil {
+			ids := r.handleQueries(r.engine.withEventTenant(data["t"]), data)
+			r.finishLazy(event, data, blobKey, ids)
+			continue
+		}
+		batch = append(batch, insert)
+		rows += insert.rows
+	}
+	r.handleLazyInsertBatch(batch)
+}
+
+func getLazyInsertEvent(event Event, data map[string]interface{}, blobKey string) *lazyInsertEvent {
+	queries, _ := data["q"].([]interface{})
+	if len(queries) != 1 {
+		return nil
+	}
+	query := queries[0].([]interface{})
+	sql := query[1].(string)
+	params, _ := query[2].([]interface{})
+	if !strings.HasPrefix(sql, "INSERT INTO ") || len(params) == 0 || strings.Contains(sql, " ON DUPLICATE KEY ") {
+		return nil
+	}
+	pos := strings.Index(sql, ") VALUES (")
+	if pos == -1 {
+		return nil
+	}
+	prefix := sql[0 : pos+1]
+	columns := strings.Count(prefix, "`") / 2
+	if columns == 0 || len(params)%columns != 0 {
+		return nil
+	}
+	tenant, _ := data["t"].(string)
+	return &lazyInsertEvent{event: event, data: data, blobKey: blobKey, pool: query[0].(string), tenant: tenant, prefix: prefix,
+		values: sql[pos+9:], params: params, rows: len(params) / columns}
+}
+
+func (r *BackgroundConsumer) handleLazyInsertBatch(batch []*lazyInsertEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	if len(batch) == 1 {
+		r.handleLazyInsertEvents(batch)
+		return
+	}
+	engine := r.engine.withEventTenant(batch[0].tenant)
+	db := engine.GetMysql(batch[0].pool)
+	values := make([]string, len(batch))
+	params := make([]interface{}, 0)
+	for i, insert := range batch {
+		values[i] = insert.values
+		params = append(params, insert.params...)
+	}
+	var res ExecResult
+	executed := func() (ok bool) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				ok = false
+			}
+		}()
+		res = db.Exec(batch[0].prefix+" VALUES "+strings.Join(values, ","), params...)
+		return true
+	}()
+	if !executed {
+		r.handleLazyInsertEvents(batch)
+		return
+	}
+	id := res.LastInsertId()
+	for _, insert := range batch {
+		r.assignInsertID(db, insert.data, id)
+		r.handleQueryEvents(engine, insert.data)
+		r.finishLazy(insert.event, insert.data, insert.blobKey, []uint64{id})
+		id += uint64(insert.rows) * db.GetPoolConfig().getAutoincrement()
+	}
+}
+
+func (r *BackgroundConsumer) handleLazyInsertEvents(batch []*lazyInsertEvent) {
+	for _, insert := range batch {
+		ids := r.handleQueries(r.engine.withEventTenant(insert.tenant), insert.data)
+		r.finishLazy(insert.event, insert.data, insert.blobKey, ids)
+	}
+}
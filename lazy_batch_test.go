@@ -0,0 +1,66 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type lazyBatchEntity struct {
+	ORM  `orm:"redisCache;asyncRedisLazyFlush=default"`
+	ID   uint
+	Name string
+	Age  uint
+}
+
+func TestLazyInsertBatch(t *testing.T) {
+	var entity *lazyBatchEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.GetRedis().FlushDB()
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableLoop()
+	receiver.blockTime = time.Millisecond
+	receiver.SetLazyInsertBatchSize(2)
+
+	engine.FlushLazy(&lazyBatchEntity{Name: "a", Age: 1})
+	engine.FlushLazy(&lazyBatchEntity{Name: "b", Age: 2})
+	engine.FlushLazy(&lazyBatchEntity{Name: "c", Age: 3})
+	engine.GetMysql().Exec("INSERT INTO `lazyBatchEntity`(`ID`, `Name`, `Age`) VALUES (10, 'x', 10)")
+	toUpdate := &lazyBatchEntity{}
+	assert.True(t, engine.LoadByID(10, toUpdate))
+	toUpdate.Name = "y"
+	engine.FlushLazy(toUpdate)
+	engine.FlushLazy(&lazyBatchEntity{Name: "d", Age: 4})
+
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	receiver.Digest(context.Background())
+	inserts := 0
+	for _, entry := range dbLogger.Entries {
+		if strings.HasPrefix(entry.Fields["Query"].(string), "INSERT INTO") {
+			inserts++
+		}
+	}
+	assert.Equal(t, 3, inserts)
+
+	var rows []*lazyBatchEntity
+	engine.Search(NewWhere("1 ORDER BY `ID`"), nil, &rows)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, uint(2), rows[1].ID)
+	assert.Equal(t, "b", rows[1].Name)
+	assert.Equal(t, "c", rows[2].Name)
+	assert.Equal(t, "y", rows[3].Name)
+	assert.Equal(t, uint(11), rows[4].ID)
+	assert.Equal(t, "d", rows[4].Name)
+
+	entity = &lazyBatchEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, uint(2), entity.Age)
+}
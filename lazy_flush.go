@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	apexLog "github.com/apex/log"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const lazyBlobKeyPrefix = "orm_lazy_blob:"
+
+type LazyFlushOptions struct {
+	ChunkSize    int
+	MaxEventSize int
+	BlobTTL      time.Duration
+}
+
+var defaultLazyFlushOptions = LazyFlushOptions{ChunkSize: 1000, MaxEventSize: 512 * 1024, BlobTTL: time.Hour * 24 * 7}
+
+func (r *Registry) SetLazyFlushOptions(options LazyFlushOptions) {
+	r.lazyFlushOptions = &options
+}
+
+func (r *validatedRegistry) getLazyFlushOptions() LazyFlushOptions {
+	options := defaultLazyFlushOptions
+	if r.registry == nil || r.registry.lazyFlushOptions == nil {
+		return options
+	}
+	custom := r.registry.lazyFlushOptions
+	if custom.ChunkSize > 0 {
+		options.ChunkSize = custom.ChunkSize
+	}
+	if custom.MaxEventSize > 0 {
+		options.MaxEventSize = custom.MaxEventSize
+	}
+	if custom.BlobTTL > 0 {
+		options.BlobTTL = custom.BlobTTL
+	}
+	return options
+}
+
+func (f *flusher) publishLazyMap() {
+	if f.engine.tenant != "" {
+		f.lazyMap["t"] = f.engine.tenant
+	}
+	asJSON, err := jsoniter.ConfigFastest.MarshalToString(f.lazyMap)
+	checkError(err)
+	options := f.engine.registry.getLazyFlushOptions()
+	if len(asJSON) > options.MaxEventSize {
+		random := make([]byte, 16)
+		_, err = rand.Read(random)
+		checkError(err)
+		key := lazyBlobKeyPrefix + hex.EncodeToString(random)
+		getRedisForStream(f.engine, lazyChannelName).Set(key, asJSON, int(options.BlobTTL.Seconds()))
+		asJSON, err = jsoniter.ConfigFastest.MarshalToString(map[string]string{"b": key})
+		checkError(err)
+	}
+	f.getRedisFlusher().PublishMap(lazyChannelName, EventAsMap{"_s": asJSON})
+}
+
+func (r *BackgroundConsumer) loadLazyBlob(data map[string]interface{}) (map[string]interface{}, string, bool) {
+	key, isBlob := data["b"].(string)
+	if !isBlob {
+		return data, "", true
+	}
+	payload, has := getRedisForStream(r.engine, lazyChannelName).Get(key)
+	if !has {
+		r.engine.Log().ErrorMessage("lazy flush payload "+key+" not found", apexLog.Fields{"key": key})
+		return nil, key, false
+	}
+	var blob map[string]interface{}
+	err := jsoniter.ConfigFastest.UnmarshalFromString(payload, &blob)
+	if err != nil {
+		r.engine.Log().Error(err, apexLog.Fields{"key": key})
+		return nil, key, false
+	}
+	return blob, key, true
+}
@@ -0,0 +1,40 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lazyFlushChunkEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestLazyFlushChunks(t *testing.T) {
+	registry := &Registry{}
+	registry.SetLazyFlushOptions(LazyFlushOptions{ChunkSize: 2, MaxEventSize: 100, BlobTTL: time.Minute})
+	engine := PrepareTables(t, registry, 5, &lazyFlushChunkEntity{})
+	engine.GetRedis().FlushDB()
+	options := engine.registry.getLazyFlushOptions()
+	assert.Equal(t, 2, options.ChunkSize)
+	assert.Equal(t, 100, options.MaxEventSize)
+	assert.Equal(t, time.Minute, options.BlobTTL)
+
+	entities := make([]Entity, 5)
+	for i := range entities {
+		entities[i] = &lazyFlushChunkEntity{Name: "name that makes payload bigger than max event size"}
+	}
+	engine.FlushLazyMany(entities...)
+	assert.Equal(t, int64(3), engine.GetRedis().XLen(lazyChannelName))
+	assert.Equal(t, 0, engine.Count(NewWhere("1"), &lazyFlushChunkEntity{}))
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableLoop()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	assert.Equal(t, 5, engine.Count(NewWhere("1"), &lazyFlushChunkEntity{}))
+}
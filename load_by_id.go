@@ -3,13 +3,68 @@ package orm
 import (
 	"fmt"
 	"reflect"
+	"sync"
 
 	jsoniter "github.com/json-iterator/go"
 )
 
 const cacheNilValue = ""
 
+type loadByIDCall struct {
+	wg    sync.WaitGroup
+	done  bool
+	found bool
+	data  []interface{}
+}
+
 func loadByID(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool, references ...string) (found bool, schema *tableSchema) {
+	if !useCache {
+		return loadByIDFromSource(engine, id, entity, false, lazy, references...)
+	}
+	orm := initIfNeeded(engine.registry, entity)
+	schema = orm.tableSchema
+	schema.refreshCacheVersion(engine)
+	callKey := schema.getCacheKey(id)
+	engine.loadByIDCallsMutex.Lock()
+	if engine.loadByIDCalls == nil {
+		engine.loadByIDCalls = make(map[string]*loadByIDCall)
+	}
+	call, has := engine.loadByIDCalls[callKey]
+	if has {
+		engine.loadByIDCallsMutex.Unlock()
+		call.wg.Wait()
+		if !call.done {
+			return loadByIDFromSource(engine, id, entity, true, lazy, references...)
+		}
+		if !call.found {
+			return false, schema
+		}
+		fillFromDBRow(id, engine, buildLocalCacheValue(call.data), entity, lazy)
+		if len(references) > 0 {
+			warmUpReferences(engine, schema, orm.value, references, false, lazy)
+		}
+		return true, schema
+	}
+	call = &loadByIDCall{}
+	call.wg.Add(1)
+	engine.loadByIDCalls[callKey] = call
+	engine.loadByIDCallsMutex.Unlock()
+	defer func() {
+		engine.loadByIDCallsMutex.Lock()
+		delete(engine.loadByIDCalls, callKey)
+		engine.loadByIDCallsMutex.Unlock()
+		call.wg.Done()
+	}()
+	found, schema = loadByIDFromSource(engine, id, entity, true, lazy, references...)
+	call.found = found
+	if found {
+		call.data = buildLocalCacheValue(orm.dBData)
+	}
+	call.done = true
+	return found, schema
+}
+
+func loadByIDFromSource(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool, references ...string) (found bool, schema *tableSchema) {
 	orm := initIfNeeded(engine.registry, entity)
 	schema = orm.tableSchema
 	localCache, hasLocalCache := schema.GetLocalCache(engine)
@@ -38,19 +93,21 @@ func loadByID(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool
 		}
 		if hasRedis {
 			cacheKey = schema.getCacheKey(id)
-			row, has := redisCache.Get(cacheKey)
-			if has {
+			var row string
+			var has bool
+			if !engine.protectCache(redisCache, func() { row, has = redisCache.Get(cacheKey) }) {
+				redisCache = nil
+			} else if has {
 				if row == cacheNilValue {
 					return false, schema
 				}
-				decoded := make([]interface{}, len(schema.columnNames))
-				_ = jsoniter.ConfigFastest.UnmarshalFromString(row, &decoded)
-				convertDataFromJSON(schema.fields, 0, decoded)
-				fillFromDBRow(id, engine, decoded, entity, lazy)
-				if len(references) > 0 {
-					warmUpReferences(engine, schema, orm.value, references, false, lazy)
+				if _, valid := fillFromRedisValue(engine, schema, row, lazy, entity); valid {
+					if len(references) > 0 {
+						warmUpReferences(engine, schema, orm.value, references, false, lazy)
+					}
+					return true, schema
 				}
-				return true, schema
+				schema.evictInvalidCache(redisCache, cacheKey, row)
 			}
 		}
 	}
@@ -61,7 +118,7 @@ func loadByID(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool
 			localCache.Set(cacheKey, cacheNilValue)
 		}
 		if redisCache != nil {
-			redisCache.Set(cacheKey, cacheNilValue, 60)
+			engine.protectCache(redisCache, func() { redisCache.Set(cacheKey, cacheNilValue, 60) })
 		}
 		return false, schema
 	}
@@ -70,7 +127,9 @@ func loadByID(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool
 			localCache.Set(cacheKey, buildLocalCacheValue(data))
 		}
 		if redisCache != nil {
-			redisCache.Set(cacheKey, buildRedisValue(data), 0)
+			engine.protectCache(redisCache, func() {
+				redisCache.Set(cacheKey, buildRedisValue(schema, data), int(schema.redisCacheTTL.Seconds()))
+			})
 		}
 	}
 
@@ -82,9 +141,9 @@ func loadByID(engine *Engine, id uint64, entity Entity, useCache bool, lazy bool
 	return true, schema
 }
 
-func buildRedisValue(data []interface{}) string {
+func buildRedisValue(schema *tableSchema, data []interface{}) string {
 	encoded, _ := jsoniter.ConfigFastest.Marshal(buildLocalCacheValue(data))
-	return string(encoded)
+	return schema.cacheFormat + string(encoded)
 }
 
 func buildLocalCacheValue(data []interface{}) []interface{} {
@@ -108,6 +167,8 @@ func initIfNeeded(registry *validatedRegistry, entity Entity) *ORM {
 		orm.value = value
 		orm.elem = elem
 		orm.idElem = elem.Field(1)
+	} else if orm.tableSchema.tenant != registry.tenant {
+		orm.tableSchema = getTableSchema(registry, orm.elem.Type())
 	}
 	return orm
 }
@@ -141,7 +202,8 @@ func convertDataFromJSON(fields *tableFields, start int, encoded []interface{})
 		start++
 	}
 	start += len(fields.booleans) + len(fields.booleansNullable) + len(fields.floats) + len(fields.floatsNullable) +
-		len(fields.timesNullable) + len(fields.times) + len(fields.jsons)
+		len(fields.timesNullable) + len(fields.times) + len(fields.jsons) + len(fields.spatials) + len(fields.decimals) +
+		len(fields.customs)
 	for i := 0; i < len(fields.refs); i++ {
 		v := encoded[start]
 		if v != nil {
@@ -150,6 +212,7 @@ func convertDataFromJSON(fields *tableFields, start int, encoded []interface{})
 		start++
 	}
 	start += len(fields.refsMany)
+	start = convertArraysFromJSON(fields, start, encoded)
 	for _, subFields := range fields.structs {
 		start = convertDataFromJSON(subFields, start, encoded)
 	}
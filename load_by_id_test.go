@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"sync"
 	"testing"
 
 	apexLog "github.com/apex/log"
@@ -273,3 +274,38 @@ func benchmarkLoadByIDLocalCache(b *testing.B, lazy, local, redis bool) {
 		}
 	}
 }
+
+func TestLoadByIDInFlight(t *testing.T) {
+	var entity *loadByIDNoCacheEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.FlushMany(&loadByIDNoCacheEntity{Name: "a"}, &loadByIDNoCacheEntity{Name: "b"})
+
+	schema := engine.registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	call := &loadByIDCall{done: true, found: true, data: []interface{}{uint64(1), "in flight"}}
+	call.wg.Add(1)
+	engine.loadByIDCalls = map[string]*loadByIDCall{schema.getCacheKey(1): call}
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	go call.wg.Done()
+	entity = &loadByIDNoCacheEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "in flight", entity.Name)
+	assert.Len(t, dbLogger.Entries, 0)
+
+	engine.loadByIDCalls = nil
+	var wg sync.WaitGroup
+	results := make([]*loadByIDNoCacheEntity, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = &loadByIDNoCacheEntity{}
+			engine.LoadByID(2, results[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results {
+		assert.Equal(t, "b", result.Name)
+	}
+	assert.Len(t, engine.loadByIDCalls, 0)
+}
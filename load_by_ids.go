@@ -22,6 +22,7 @@ func tryByIDs(engine *Engine, ids []uint64, entities reflect.Value, references [
 	}
 
 	schema = getTableSchema(engine.registry, t)
+	schema.refreshCacheVersion(engine)
 	hasLocalCache := schema.hasLocalCache
 	hasRedis := schema.hasRedisCache
 	hasValid := false
@@ -88,21 +89,30 @@ func tryByIDs(engine *Engine, ids []uint64, entities reflect.Value, references [
 	}
 	if hasRedis && len(ids) > 0 {
 		redisCache, _ = schema.GetRedisCache(engine)
-		inCache := redisCache.MGetFast(cacheKeys...)
+		var inCache []interface{}
+		if !engine.protectCache(redisCache, func() { inCache = redisCache.MGetFast(cacheKeys...) }) {
+			inCache = make([]interface{}, len(cacheKeys))
+			redisCache = nil
+		}
 		j := 0
 		for i, val := range inCache {
+			var decoded []interface{}
+			var e Entity
+			if val != nil && val != cacheNilValue {
+				e = schema.newEntity()
+				var valid bool
+				if decoded, valid = fillFromRedisValue(engine, schema, val.(string), lazy, e); !valid {
+					schema.evictInvalidCache(redisCache, cacheKeys[i], val.(string))
+					val = nil
+				}
+			}
 			if val != nil {
 				if val != cacheNilValue {
 					k := i
 					if hasLocalCache {
 						k = cacheMap[k]
 					}
-					var decoded []interface{}
-					_ = jsoniter.ConfigFastest.UnmarshalFromString(val.(string), &decoded)
-					convertDataFromJSON(schema.fields, 0, decoded)
-					e := schema.newEntity()
 					newSlice.Index(k).Set(e.getORM().value)
-					fillFromDBRow(ids[k], engine, decoded, e, lazy)
 					hasValid = true
 					if hasLocalCache {
 						localCacheToSet = append(localCacheToSet, cacheKeys[i], buildLocalCacheValue(decoded))
@@ -167,7 +177,7 @@ func tryByIDs(engine *Engine, ids []uint64, entities reflect.Value, references [
 					localCacheToSet = append(localCacheToSet, cacheKey, buildLocalCacheValue(pointers))
 				}
 				if hasRedis {
-					redisCacheToSet = append(redisCacheToSet, cacheKey, buildRedisValue(pointers))
+					redisCacheToSet = append(redisCacheToSet, cacheKey, buildRedisValue(schema, pointers))
 				}
 			}
 			hasValid = true
@@ -203,13 +213,14 @@ func tryByIDs(engine *Engine, ids []uint64, entities reflect.Value, references [
 			localCache.MSet(localCacheToSet...)
 		}
 		if len(redisCacheToSet) > 0 && redisCache != nil {
-			redisCache.MSet(redisCacheToSet...)
+			engine.protectCache(redisCache, func() { schema.setRedisCache(redisCache, redisCacheToSet...) })
 		}
 		if len(ids) != found {
 			missing = true
 		}
 		def()
 	}
+	applyIdentityMap(engine, newSlice)
 	entities.Set(newSlice)
 	if len(references) > 0 && hasValid {
 		warmUpReferences(engine, schema, entities, references, true, lazy)
@@ -383,14 +394,13 @@ func warmUpReferences(engine *Engine, schema *tableSchema, rows reflect.Value, r
 			keys[i] = k
 			i++
 		}
-		for key, fromCache := range engine.GetRedis(k).MGet(keys...) {
+		redisCache := engine.GetRedis(k)
+		for key, fromCache := range redisCache.MGet(keys...) {
 			if fromCache != nil && fromCache != cacheNilValue {
 				schema := v[key][0].(Entity).getORM().tableSchema
-				decoded := make([]interface{}, len(schema.columnNames))
-				_ = jsoniter.ConfigFastest.UnmarshalFromString(fromCache.(string), &decoded)
-				convertDataFromJSON(schema.fields, 0, decoded)
-				for _, r := range v[key] {
-					fillFromDBRow(decoded[0].(uint64), engine, decoded, r, lazy)
+				if _, valid := fillFromRedisValue(engine, schema, fromCache.(string), lazy, v[key]...); !valid {
+					schema.evictInvalidCache(redisCache, key, fromCache.(string))
+					continue
 				}
 				fillRef(key, nil, redisMap, dbMap)
 			}
@@ -428,16 +438,20 @@ func warmUpReferences(engine *Engine, schema *tableSchema, rows reflect.Value, r
 		if len(v) == 0 {
 			continue
 		}
-		values := make([]interface{}, 0)
+		values := make(map[*tableSchema][]interface{})
 		for cacheKey, refs := range v {
 			e := refs[0].(Entity)
+			schema := e.getORM().tableSchema
 			if e.IsLoaded() {
-				values = append(values, cacheKey, buildRedisValue(e.getORM().dBData))
+				values[schema] = append(values[schema], cacheKey, buildRedisValue(schema, e.getORM().dBData))
 			} else {
-				values = append(values, cacheKey, cacheNilValue)
+				values[schema] = append(values[schema], cacheKey, cacheNilValue)
 			}
 		}
-		engine.GetRedis(pool).MSet(values...)
+		redisCache := engine.GetRedis(pool)
+		for schema, pairs := range values {
+			schema.setRedisCache(redisCache, pairs...)
+		}
 	}
 	for pool, v := range localMap {
 		if len(v) == 0 {
@@ -0,0 +1,49 @@
+package orm
+
+import "reflect"
+
+type LoadByIDsResult struct {
+	Found   []uint64
+	Missing []uint64
+	missing map[uint64]bool
+}
+
+func (r *LoadByIDsResult) IsMissing(id uint64) bool {
+	return r.missing[id]
+}
+
+func (r *LoadByIDsResult) HasMissing() bool {
+	return len(r.Missing) > 0
+}
+
+func (e *Engine) LoadByIDsWithResult(ids []uint64, entities interface{}, references ...string) *LoadByIDsResult {
+	requested := make([]uint64, len(ids))
+	copy(requested, ids)
+	e.LoadByIDs(ids, entities, references...)
+	return buildLoadByIDsResult(requested, reflect.ValueOf(entities).Elem())
+}
+
+func (e *Engine) LoadByIDsLazyWithResult(ids []uint64, entities interface{}, references ...string) *LoadByIDsResult {
+	requested := make([]uint64, len(ids))
+	copy(requested, ids)
+	e.LoadByIDsLazy(ids, entities, references...)
+	return buildLoadByIDsResult(requested, reflect.ValueOf(entities).Elem())
+}
+
+func buildLoadByIDsResult(ids []uint64, entities reflect.Value) *LoadByIDsResult {
+	result := &LoadByIDsResult{Found: make([]uint64, 0, len(ids)), Missing: make([]uint64, 0), missing: make(map[uint64]bool)}
+	visited := make(map[uint64]bool, len(ids))
+	for i, id := range ids {
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if entities.Index(i).IsNil() {
+			result.Missing = append(result.Missing, id)
+			result.missing[id] = true
+		} else {
+			result.Found = append(result.Found, id)
+		}
+	}
+	return result
+}
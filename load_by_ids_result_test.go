@@ -0,0 +1,53 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loadByIDsResultEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestLoadByIDsWithResult(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &loadByIDsResultEntity{})
+	engine.FlushMany(&loadByIDsResultEntity{Name: "a"}, &loadByIDsResultEntity{Name: "b"}, &loadByIDsResultEntity{Name: "c"})
+
+	var rows []*loadByIDsResultEntity
+	result := engine.LoadByIDsWithResult([]uint64{3, 10, 1, 10, 12}, &rows)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, []uint64{3, 1}, result.Found)
+	assert.Equal(t, []uint64{10, 12}, result.Missing)
+	assert.True(t, result.HasMissing())
+	assert.True(t, result.IsMissing(10))
+	assert.False(t, result.IsMissing(1))
+	assert.Nil(t, rows[1])
+	assert.Equal(t, "c", rows[0].Name)
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&loadByIDsResultEntity{}).(*tableSchema)
+	value, has := engine.GetRedis().Get(schema.getCacheKey(10))
+	assert.True(t, has)
+	assert.Equal(t, cacheNilValue, value)
+
+	result = engine.LoadByIDsLazyWithResult([]uint64{1, 2}, &rows)
+	assert.Equal(t, []uint64{1, 2}, result.Found)
+	assert.Len(t, result.Missing, 0)
+	assert.False(t, result.HasMissing())
+
+	engine.GetRedis().Del(schema.getCacheKey(1), schema.getCacheKey(2), schema.getCacheKey(3))
+	engine.LoadByID(2, &loadByIDsResultEntity{})
+	ids := []uint64{1, 2, 3}
+	result = engine.LoadByIDsWithResult(ids, &rows)
+	assert.Equal(t, []uint64{1, 2, 3}, result.Found)
+	assert.Len(t, result.Missing, 0)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "b", rows[1].Name)
+	assert.Equal(t, "c", rows[2].Name)
+
+	result = engine.LoadByIDsWithResult([]uint64{}, &rows)
+	assert.Len(t, result.Found, 0)
+	assert.Len(t, result.Missing, 0)
+}
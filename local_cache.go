@@ -2,7 +2,6 @@ package orm
 
 import (
 	"sync"
-	"time"
 
 	log2 "github.com/apex/log"
 
@@ -49,12 +48,12 @@ func (c *LocalCache) GetSet(key string, ttlSeconds int, provider GetSetProvider)
 	val, has := c.Get(key)
 	if has {
 		ttlVal := val.(ttlValue)
-		if time.Now().Unix()-ttlVal.time <= int64(ttlSeconds) {
+		if c.engine.GetClock().Now().Unix()-ttlVal.time <= int64(ttlSeconds) {
 			return ttlVal.value
 		}
 	}
 	userVal := provider()
-	val = ttlValue{value: userVal, time: time.Now().Unix()}
+	val = ttlValue{value: userVal, time: c.engine.GetClock().Now().Unix()}
 	c.Set(key, val)
 	return userVal
 }
@@ -0,0 +1,208 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func (e *Engine) AddManyToMany(entity Entity, field string, ids ...uint64) {
+	schema, m2m := getManyToMany(e, entity, field)
+	if len(ids) == 0 {
+		return
+	}
+	id := entity.GetID()
+	values := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)*2)
+	for i, targetID := range ids {
+		values[i] = "(?,?)"
+		args = append(args, id, targetID)
+	}
+	/* #nosec */
+	sql := "INSERT IGNORE INTO `" + m2m.table + "`(`SourceID`,`TargetID`) VALUES " + strings.Join(values, ",")
+	schema.GetMysql(e).Exec(sql, args...)
+	clearManyToManyCache(e, schema, field, id)
+}
+
+func (e *Engine) RemoveManyToMany(entity Entity, field string, ids ...uint64) {
+	schema, m2m := getManyToMany(e, entity, field)
+	if len(ids) == 0 {
+		return
+	}
+	id := entity.GetID()
+	where := NewWhere("`SourceID` = ? AND `TargetID` IN ?", id, ids)
+	/* #nosec */
+	schema.GetMysql(e).Exec("DELETE FROM `"+m2m.table+"` WHERE "+where.String(), where.GetParameters()...)
+	clearManyToManyCache(e, schema, field, id)
+}
+
+func (e *Engine) GetManyToManyIDs(entity Entity, field string) []uint64 {
+	schema, m2m := getManyToMany(e, entity, field)
+	id := entity.GetID()
+	return getManyToManyIDs(e, schema, m2m, field, id)[id]
+}
+
+func (e *Engine) LoadManyToMany(field string, entities ...Entity) {
+	if len(entities) == 0 {
+		return
+	}
+	schema, m2m := getManyToMany(e, entities[0], field)
+	ids := make([]uint64, len(entities))
+	for i, entity := range entities {
+		entitySchema, _ := getManyToMany(e, entity, field)
+		if entitySchema != schema {
+			panic(fmt.Errorf("entity '%s' is not '%s'", entitySchema.t.String(), schema.t.String()))
+		}
+		ids[i] = entity.GetID()
+	}
+	relations := getManyToManyIDs(e, schema, m2m, field, ids...)
+	targetIDs := make([]uint64, 0)
+	unique := make(map[uint64]bool)
+	for _, related := range relations {
+		for _, targetID := range related {
+			if !unique[targetID] {
+				unique[targetID] = true
+				targetIDs = append(targetIDs, targetID)
+			}
+		}
+	}
+	fieldDefinition, _ := schema.t.FieldByName(field)
+	fieldType := fieldDefinition.Type
+	loaded := make(map[uint64]reflect.Value, len(targetIDs))
+	if len(targetIDs) > 0 {
+		rows := reflect.New(fieldType)
+		e.LoadByIDs(targetIDs, rows.Interface())
+		rowsElem := rows.Elem()
+		for i := 0; i < rowsElem.Len(); i++ {
+			row := rowsElem.Index(i)
+			if !row.IsNil() {
+				loaded[row.Interface().(Entity).GetID()] = row
+			}
+		}
+	}
+	for _, entity := range entities {
+		related := relations[entity.GetID()]
+		value := reflect.MakeSlice(fieldType, 0, len(related))
+		for _, targetID := range related {
+			row, has := loaded[targetID]
+			if has {
+				value = reflect.Append(value, row)
+			}
+		}
+		entity.getORM().elem.FieldByName(field).Set(value)
+	}
+}
+
+func getManyToMany(engine *Engine, entity Entity, field string) (*tableSchema, *manyToManyDefinition) {
+	orm := initIfNeeded(engine.registry, entity)
+	schema := orm.tableSchema
+	m2m, has := schema.manyToMany[field]
+	if !has {
+		panic(fmt.Errorf("unknown m2m field '%s' in entity '%s'", field, schema.t.String()))
+	}
+	if entity.GetID() == 0 {
+		panic(fmt.Errorf("entity '%s' is not saved", schema.t.String()))
+	}
+	return schema, m2m
+}
+
+func getManyToManyIDs(engine *Engine, schema *tableSchema, m2m *manyToManyDefinition, field string, ids ...uint64) map[uint64][]uint64 {
+	results := make(map[uint64][]uint64, len(ids))
+	missing := ids
+	localCache, hasLocalCache := schema.GetLocalCache(engine)
+	redisCache, hasRedis := schema.GetRedisCache(engine)
+	if hasLocalCache {
+		keys := make([]string, len(missing))
+		for i, id := range missing {
+			keys[i] = getManyToManyCacheKey(schema, field, id)
+		}
+		stillMissing := make([]uint64, 0)
+		for i, value := range localCache.MGetFast(keys...) {
+			if value == nil {
+				stillMissing = append(stillMissing, missing[i])
+				continue
+			}
+			results[missing[i]] = value.([]uint64)
+		}
+		missing = stillMissing
+	}
+	if hasRedis && len(missing) > 0 {
+		keys := make([]string, len(missing))
+		for i, id := range missing {
+			keys[i] = getManyToManyCacheKey(schema, field, id)
+		}
+		stillMissing := make([]uint64, 0)
+		for i, value := range redisCache.MGetFast(keys...) {
+			if value == nil {
+				stillMissing = append(stillMissing, missing[i])
+				continue
+			}
+			related := make([]uint64, 0)
+			if value.(string) != "" {
+				for _, targetID := range strings.Split(value.(string), ",") {
+					parsed, _ := strconv.ParseUint(targetID, 10, 64)
+					related = append(related, parsed)
+				}
+			}
+			results[missing[i]] = related
+			if hasLocalCache {
+				localCache.Set(keys[i], related)
+			}
+		}
+		missing = stillMissing
+	}
+	if len(missing) == 0 {
+		return results
+	}
+	for _, id := range missing {
+		results[id] = make([]uint64, 0)
+	}
+	where := NewWhere("`SourceID` IN ?", missing)
+	/* #nosec */
+	query := "SELECT `SourceID`,`TargetID` FROM `" + m2m.table + "` WHERE " + where.String() + " ORDER BY `SourceID`,`TargetID`"
+	rows, def := schema.GetMysql(engine).Query(query, where.GetParameters()...)
+	defer def()
+	for rows.Next() {
+		var sourceID, targetID uint64
+		rows.Scan(&sourceID, &targetID)
+		results[sourceID] = append(results[sourceID], targetID)
+	}
+	def()
+	if hasLocalCache || hasRedis {
+		localPairs := make([]interface{}, 0, len(missing)*2)
+		redisPairs := make([]interface{}, 0, len(missing)*2)
+		for _, id := range missing {
+			key := getManyToManyCacheKey(schema, field, id)
+			localPairs = append(localPairs, key, results[id])
+			related := make([]string, len(results[id]))
+			for i, targetID := range results[id] {
+				related[i] = strconv.FormatUint(targetID, 10)
+			}
+			redisPairs = append(redisPairs, key, strings.Join(related, ","))
+		}
+		if hasLocalCache {
+			localCache.MSet(localPairs...)
+		}
+		if hasRedis {
+			schema.setRedisCache(redisCache, redisPairs...)
+		}
+	}
+	return results
+}
+
+func clearManyToManyCache(engine *Engine, schema *tableSchema, field string, id uint64) {
+	key := getManyToManyCacheKey(schema, field, id)
+	localCache, hasLocalCache := schema.GetLocalCache(engine)
+	if hasLocalCache {
+		localCache.Remove(key)
+	}
+	redisCache, hasRedis := schema.GetRedisCache(engine)
+	if hasRedis {
+		redisCache.Del(key)
+	}
+}
+
+func getManyToManyCacheKey(schema *tableSchema, field string, id uint64) string {
+	return schema.getCachePrefix() + ":m2m:" + field + ":" + strconv.FormatUint(id, 10)
+}
@@ -0,0 +1,71 @@
+package orm
+
+import (
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type manyToManyEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+	Tags []*manyToManyTagEntity `orm:"m2m=manyToManyEntityTags"`
+}
+
+type manyToManyTagEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestManyToMany(t *testing.T) {
+	var entity *manyToManyEntity
+	var tag *manyToManyTagEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity, tag)
+	assert.Len(t, engine.GetAlters(), 0)
+	assert.Equal(t, []string{"ID", "Name"}, engine.GetRegistry().GetTableSchemaForEntity(entity).GetColumns())
+
+	engine.FlushMany(&manyToManyTagEntity{Name: "a"}, &manyToManyTagEntity{Name: "b"}, &manyToManyTagEntity{Name: "c"})
+	entity = &manyToManyEntity{Name: "first"}
+	other := &manyToManyEntity{Name: "second"}
+	engine.FlushMany(entity, other)
+
+	assert.Len(t, engine.GetManyToManyIDs(entity, "Tags"), 0)
+	engine.AddManyToMany(entity, "Tags", 3, 1)
+	engine.AddManyToMany(entity, "Tags", 1)
+	engine.AddManyToMany(other, "Tags", 2)
+	assert.Equal(t, []uint64{1, 3}, engine.GetManyToManyIDs(entity, "Tags"))
+
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	assert.Equal(t, []uint64{1, 3}, engine.GetManyToManyIDs(entity, "Tags"))
+	assert.Len(t, dbLogger.Entries, 0)
+	engine.GetLocalCache().Clear()
+	assert.Equal(t, []uint64{1, 3}, engine.GetManyToManyIDs(entity, "Tags"))
+	assert.Len(t, dbLogger.Entries, 0)
+
+	loaded := &manyToManyEntity{}
+	loadedOther := &manyToManyEntity{}
+	engine.LoadByID(1, loaded)
+	engine.LoadByID(2, loadedOther)
+	engine.LoadManyToMany("Tags", loaded, loadedOther)
+	assert.Len(t, loaded.Tags, 2)
+	assert.Equal(t, "a", loaded.Tags[0].Name)
+	assert.Equal(t, "c", loaded.Tags[1].Name)
+	assert.Len(t, loadedOther.Tags, 1)
+	assert.Equal(t, "b", loadedOther.Tags[0].Name)
+	assert.False(t, loaded.IsDirty())
+
+	engine.RemoveManyToMany(entity, "Tags", 1)
+	assert.Equal(t, []uint64{3}, engine.GetManyToManyIDs(entity, "Tags"))
+
+	assert.PanicsWithError(t, "unknown m2m field 'Name' in entity 'orm.manyToManyEntity'", func() {
+		engine.GetManyToManyIDs(entity, "Name")
+	})
+	assert.PanicsWithError(t, "entity 'orm.manyToManyEntity' is not saved", func() {
+		engine.AddManyToMany(&manyToManyEntity{}, "Tags", 1)
+	})
+}
@@ -0,0 +1,116 @@
+package orm
+
+import (
+	"regexp"
+	"sync"
+
+	apexLog "github.com/apex/log"
+)
+
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Helper()
+}
+
+type CapturedQuery struct {
+	Target string
+	Pool   string
+	Query  string
+	Args   []interface{}
+	Error  bool
+}
+
+type MockLogHandler struct {
+	mutex sync.Mutex
+	Logs  []*apexLog.Entry
+}
+
+func NewMockLogHandler() *MockLogHandler {
+	return &MockLogHandler{}
+}
+
+func (h *MockLogHandler) HandleLog(e *apexLog.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.Logs = append(h.Logs, e)
+	return nil
+}
+
+func (h *MockLogHandler) Clear() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.Logs = nil
+}
+
+func (h *MockLogHandler) Queries() []*CapturedQuery {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	queries := make([]*CapturedQuery, len(h.Logs))
+	for i, entry := range h.Logs {
+		query := &CapturedQuery{Error: entry.Level == apexLog.ErrorLevel}
+		query.Target, _ = entry.Fields["target"].(string)
+		query.Pool, _ = entry.Fields["pool"].(string)
+		query.Args, _ = entry.Fields["args"].([]interface{})
+		if sql, has := entry.Fields["Query"].(string); has {
+			query.Query = sql
+		} else {
+			query.Query = entry.Message
+		}
+		queries[i] = query
+	}
+	return queries
+}
+
+func (h *MockLogHandler) DBQueries() []*CapturedQuery {
+	queries := make([]*CapturedQuery, 0)
+	for _, query := range h.Queries() {
+		if query.Target == "mysql" {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+func (h *MockLogHandler) LastQuery() *CapturedQuery {
+	queries := h.Queries()
+	if len(queries) == 0 {
+		return nil
+	}
+	return queries[len(queries)-1]
+}
+
+func (h *MockLogHandler) LastQueryMatches(expression string) bool {
+	last := h.LastQuery()
+	if last == nil {
+		return false
+	}
+	return regexp.MustCompile(expression).MatchString(last.Query)
+}
+
+func (h *MockLogHandler) AssertQueryCount(t TestingT, expected int) bool {
+	t.Helper()
+	queries := h.Queries()
+	if len(queries) != expected {
+		t.Errorf("expected %d queries, got %d: %v", expected, len(queries), capturedQueriesToStrings(queries))
+		return false
+	}
+	return true
+}
+
+func (h *MockLogHandler) AssertNoDBQueries(t TestingT) bool {
+	t.Helper()
+	queries := h.DBQueries()
+	if len(queries) > 0 {
+		t.Errorf("expected no DB queries, got %d: %v", len(queries), capturedQueriesToStrings(queries))
+		return false
+	}
+	return true
+}
+
+func capturedQueriesToStrings(queries []*CapturedQuery) []string {
+	result := make([]string, len(queries))
+	for i, query := range queries {
+		result[i] = query.Query
+	}
+	return result
+}
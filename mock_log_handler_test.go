@@ -0,0 +1,65 @@
+package orm
+
+import (
+	"fmt"
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLogHandlerEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+type mockTestingT struct {
+	errors []string
+}
+
+func (t *mockTestingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *mockTestingT) Helper() {
+}
+
+func TestMockLogHandler(t *testing.T) {
+	var entity *mockLogHandlerEntity
+	registry := &Registry{}
+	engine := PrepareTables(t, registry, 5, entity)
+	engine.Flush(&mockLogHandlerEntity{Name: "a"})
+	entity = &mockLogHandlerEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+
+	handler := NewMockLogHandler()
+	engine.AddQueryLogger(handler, apexLog.InfoLevel, QueryLoggerSourceDB, QueryLoggerSourceRedis)
+	handler.AssertQueryCount(t, 0)
+	assert.Nil(t, handler.LastQuery())
+	assert.False(t, handler.LastQueryMatches("SELECT"))
+
+	entity = &mockLogHandlerEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	handler.AssertNoDBQueries(t)
+	handler.AssertQueryCount(t, 1)
+	assert.Equal(t, "redis", handler.LastQuery().Target)
+
+	handler.Clear()
+	engine.GetMysql().Exec("UPDATE `mockLogHandlerEntity` SET `Name` = ? WHERE `ID` = ?", "b", 1)
+	handler.AssertQueryCount(t, 1)
+	assert.True(t, handler.LastQueryMatches("^UPDATE `mockLogHandlerEntity`"))
+	last := handler.LastQuery()
+	assert.Equal(t, "mysql", last.Target)
+	assert.Equal(t, "default", last.Pool)
+	assert.Equal(t, []interface{}{"b", 1}, last.Args)
+	assert.Len(t, handler.DBQueries(), 1)
+
+	mockT := &mockTestingT{}
+	assert.False(t, handler.AssertNoDBQueries(mockT))
+	assert.False(t, handler.AssertQueryCount(mockT, 2))
+	assert.Equal(t, []string{
+		"expected no DB queries, got 1: [UPDATE `mockLogHandlerEntity` SET `Name` = ? WHERE `ID` = ?]",
+		"expected 2 queries, got 1: [UPDATE `mockLogHandlerEntity` SET `Name` = ? WHERE `ID` = ?]",
+	}, mockT.errors)
+}
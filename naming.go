@@ -0,0 +1,41 @@
+package orm
+
+import (
+	"strings"
+	"unicode"
+)
+
+type NamingStrategy func(entityName string) string
+
+func SnakeCaseNaming(entityName string) string {
+	runes := []rune(entityName)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func SnakeCasePluralNaming(entityName string) string {
+	name := SnakeCaseNaming(entityName)
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[0:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+func (r *Registry) SetNamingStrategy(strategy NamingStrategy) {
+	r.namingStrategy = strategy
+}
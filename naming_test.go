@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namingCustomerAccount struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type namingCategory struct {
+	ORM  `orm:"table=custom_categories;log"`
+	ID   uint
+	Name string
+}
+
+func TestNamingStrategies(t *testing.T) {
+	assert.Equal(t, "customer_account", SnakeCaseNaming("CustomerAccount"))
+	assert.Equal(t, "http_request2_log", SnakeCaseNaming("HTTPRequest2Log"))
+	assert.Equal(t, "customer_accounts", SnakeCasePluralNaming("CustomerAccount"))
+	assert.Equal(t, "categories", SnakeCasePluralNaming("Category"))
+	assert.Equal(t, "keys", SnakeCasePluralNaming("Key"))
+	assert.Equal(t, "boxes", SnakeCasePluralNaming("Box"))
+	assert.Equal(t, "addresses", SnakeCasePluralNaming("Address"))
+}
+
+func TestRegistryNamingStrategy(t *testing.T) {
+	var account *namingCustomerAccount
+	var category *namingCategory
+	registry := &Registry{}
+	registry.SetNamingStrategy(SnakeCasePluralNaming)
+	engine := PrepareTables(t, registry, 5, account, category)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(account)
+	assert.Equal(t, "naming_customer_accounts", schema.GetTableName())
+	assert.Equal(t, "custom_categories", engine.GetRegistry().GetTableSchemaForEntity(category).GetTableName())
+	assert.Equal(t, "_log_default_custom_categories", engine.GetRegistry().GetTableSchemaForEntity(category).(*tableSchema).logTableName)
+
+	engine.Flush(&namingCustomerAccount{Name: "Tom"})
+	account = &namingCustomerAccount{}
+	assert.True(t, engine.LoadByID(1, account))
+	assert.Equal(t, "Tom", account.Name)
+	var name string
+	assert.True(t, engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `naming_customer_accounts` WHERE `ID` = 1"), &name))
+	assert.Equal(t, "Tom", name)
+}
@@ -0,0 +1,93 @@
+package orm
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type OnlineAlterTool string
+
+const (
+	GhOst                OnlineAlterTool = "gh-ost"
+	PtOnlineSchemaChange OnlineAlterTool = "pt-online-schema-change"
+)
+
+const onlineAlterTablePrefix = "ALTER TABLE "
+
+type AlterExecutor interface {
+	ExecAlter(engine *Engine, alter Alter)
+}
+
+func (r *Registry) SetOnlineAlterThreshold(rows uint64) {
+	r.onlineAlterRows = rows
+}
+
+func (r *Registry) SetAlterExecutor(executor AlterExecutor) {
+	r.alterExecutor = executor
+}
+
+func (a Alter) OnlineCommand(tool OnlineAlterTool) string {
+	clause := a.alterClause()
+	if clause == "" {
+		return ""
+	}
+	pool := a.engine.GetMysql(a.Pool).GetPoolConfig()
+	host, port, user := "localhost", "3306", ""
+	config, err := mysql.ParseDSN(pool.GetDataSourceURI())
+	if err == nil {
+		user = config.User
+		if h, p, err := net.SplitHostPort(config.Addr); err == nil {
+			host, port = h, p
+		}
+	}
+	switch tool {
+	case GhOst:
+		return fmt.Sprintf("gh-ost --host=%s --port=%s --user=%s --database=%s --table=%s --alter=%s --execute",
+			host, port, user, pool.GetDatabase(), a.Table, strconv.Quote(clause))
+	case PtOnlineSchemaChange:
+		return fmt.Sprintf("pt-online-schema-change --alter %s h=%s,P=%s,u=%s,D=%s,t=%s --execute",
+			strconv.Quote(clause), host, port, user, pool.GetDatabase(), a.Table)
+	}
+	panic(fmt.Errorf("unsupported online alter tool '%s'", tool))
+}
+
+func (a Alter) alterClause() string {
+	if !strings.HasPrefix(a.SQL, onlineAlterTablePrefix) {
+		return ""
+	}
+	sql := strings.TrimSpace(a.SQL)
+	newLine := strings.Index(sql, "\n")
+	if newLine == -1 || strings.Count(sql, ";") > 1 {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(sql[newLine+1:], ";"), "\n")
+	for i, line := range lines {
+		if pos := strings.Index(line, "/*"); pos > 0 {
+			line = line[0:pos]
+		}
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimRight(strings.Join(lines, " "), ",;")
+}
+
+func markLargeAlters(engine *Engine, tableSchema *tableSchema, alters []Alter) {
+	threshold := engine.registry.registry.onlineAlterRows
+	for i := range alters {
+		alters[i].Table = tableSchema.tableName
+	}
+	if threshold == 0 {
+		return
+	}
+	var rows uint64
+	pool := engine.GetMysql(tableSchema.mysqlPoolName)
+	pool.QueryRow(NewWhere("SELECT IFNULL(`TABLE_ROWS`, 0) FROM `information_schema`.`TABLES` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` = ?",
+		pool.GetPoolConfig().GetDatabase(), tableSchema.tableName), &rows)
+	for i := range alters {
+		alters[i].Rows = rows
+		alters[i].Large = rows >= threshold && strings.HasPrefix(alters[i].SQL, onlineAlterTablePrefix)
+	}
+}
@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type onlineAlterEntity struct {
+	ORM
+	ID   uint
+	Name string
+	Age  int
+}
+
+type onlineAlterExecutorMock struct {
+	alters []Alter
+}
+
+func (e *onlineAlterExecutorMock) ExecAlter(_ *Engine, alter Alter) {
+	e.alters = append(e.alters, alter)
+}
+
+func TestOnlineAlter(t *testing.T) {
+	var entity *onlineAlterEntity
+	registry := &Registry{}
+	registry.SetOnlineAlterThreshold(2)
+	executor := &onlineAlterExecutorMock{}
+	registry.SetAlterExecutor(executor)
+	engine := PrepareTables(t, registry, 5, entity)
+	engine.FlushMany(&onlineAlterEntity{Name: "a"}, &onlineAlterEntity{Name: "b"}, &onlineAlterEntity{Name: "c"})
+	engine.GetMysql().Exec("ALTER TABLE `onlineAlterEntity` DROP COLUMN `Age`")
+	engine.GetMysql().Exec("ANALYZE TABLE `onlineAlterEntity`")
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.True(t, alters[0].Large)
+	assert.Equal(t, "onlineAlterEntity", alters[0].Table)
+	assert.GreaterOrEqual(t, alters[0].Rows, uint64(2))
+	assert.Equal(t, "gh-ost --host=localhost --port=3311 --user=root --database=test --table=onlineAlterEntity "+
+		"--alter=\"ADD COLUMN `Age` int(11) NOT NULL DEFAULT '0' AFTER `Name`\" --execute", alters[0].OnlineCommand(GhOst))
+	assert.Equal(t, "pt-online-schema-change --alter \"ADD COLUMN `Age` int(11) NOT NULL DEFAULT '0' AFTER `Name`\" "+
+		"h=localhost,P=3311,u=root,D=test,t=onlineAlterEntity --execute", alters[0].OnlineCommand(PtOnlineSchemaChange))
+	alters[0].Exec()
+	assert.Len(t, executor.alters, 1)
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+
+	registry.SetOnlineAlterThreshold(100)
+	has, alters = schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.False(t, alters[0].Large)
+	alters[0].Exec()
+	assert.Len(t, executor.alters, 1)
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+}
@@ -28,6 +28,9 @@ type Entity interface {
 	SetEntityLogMeta(key string, value interface{})
 	SetField(field string, value interface{}) error
 	GetFieldLazy(field string) interface{}
+	HasOption(field string, option string) bool
+	AddOption(field string, option string) error
+	RemoveOption(field string, option string) error
 }
 
 type ORM struct {
@@ -65,7 +68,13 @@ func (orm *ORM) GetFieldLazy(field string) interface{} {
 	if !has {
 		panic(fmt.Errorf("unknown field %s", field))
 	}
-	return orm.dBData[i]
+	value := orm.dBData[i]
+	if value != nil {
+		if definition := orm.tableSchema.fields.getCustomFieldType(field); definition != nil {
+			return definition.get(field, value.(string))
+		}
+	}
+	return value
 }
 
 func (orm *ORM) initDBData() {
@@ -93,6 +102,7 @@ func (orm *ORM) IsLazy() bool {
 func (orm *ORM) Fill(engine *Engine) {
 	if orm.lazy && orm.loaded {
 		fillStruct(engine.registry, 0, orm.dBData, orm.tableSchema.fields, orm, orm.elem)
+		computeVirtualFields(orm.tableSchema, orm.value.Interface().(Entity), orm.elem)
 		orm.lazy = false
 	}
 }
@@ -117,7 +127,8 @@ func (orm *ORM) IsDirty() bool {
 }
 
 func (orm *ORM) GetDirtyBind() (bind Bind, has bool) {
-	bind, _, has = orm.getDirtyBind()
+	bind, updateBind, has := orm.getDirtyBind()
+	releaseUpdateBind(updateBind)
 	return bind, has
 }
 
@@ -137,9 +148,13 @@ func (orm *ORM) getDirtyBind() (bind Bind, updateBind map[string]string, has boo
 	orm.initDBData()
 	bind = make(Bind)
 	if orm.inDB && !orm.delete {
-		updateBind = make(map[string]string)
+		updateBind = updateBindPool.Get().(map[string]string)
+	}
+	if orm.tableSchema.generated {
+		orm.fillGeneratedBind(bind, updateBind)
+	} else {
+		orm.fillBind(id, bind, updateBind, orm.tableSchema, orm.tableSchema.fields, orm.elem, orm.dBData, "")
 	}
-	orm.fillBind(id, bind, updateBind, orm.tableSchema, orm.tableSchema.fields, orm.elem, orm.dBData, "")
 	has = id == 0 || len(bind) > 0
 	return bind, updateBind, has
 }
@@ -163,6 +178,9 @@ func (orm *ORM) SetField(field string, value interface{}) error {
 		return fmt.Errorf("field %s is not public", field)
 	}
 	typeName := f.Type().String()
+	if isStringSlice(f.Type()) {
+		typeName = "[]string"
+	}
 	switch typeName {
 	case "uint",
 		"uint8",
@@ -261,11 +279,19 @@ func (orm *ORM) SetField(field string, value interface{}) error {
 			f.SetString(fmt.Sprintf("%v", value))
 		}
 	case "[]string":
-		_, ok := value.([]string)
-		if !ok {
-			return fmt.Errorf("%s value %v not valid", field, value)
+		if value == nil {
+			f.Set(reflect.Zero(f.Type()))
+		} else {
+			val := reflect.ValueOf(value)
+			if !isStringSlice(val.Type()) {
+				return fmt.Errorf("%s value %v not valid", field, value)
+			}
+			slice := reflect.MakeSlice(f.Type(), val.Len(), val.Len())
+			for i := 0; i < val.Len(); i++ {
+				slice.Index(i).SetString(val.Index(i).String())
+			}
+			f.Set(slice)
 		}
-		f.Set(reflect.ValueOf(value))
 	case "[]uint8":
 		_, ok := value.([]uint8)
 		if !ok {
@@ -334,9 +360,50 @@ func (orm *ORM) SetField(field string, value interface{}) error {
 			return fmt.Errorf("%s value %v is not valid", field, value)
 		}
 		f.Set(reflect.ValueOf(value))
+	case "orm.Decimal", "*orm.Decimal":
+		if value == nil {
+			if typeName == "orm.Decimal" {
+				return fmt.Errorf("%s value %v is not valid", field, value)
+			}
+			f.Set(reflect.Zero(f.Type()))
+		} else {
+			var val Decimal
+			switch v := value.(type) {
+			case Decimal:
+				val = v
+			case *Decimal:
+				val = *v
+			default:
+				parsed, err := NewDecimal(fmt.Sprintf("%v", value))
+				if err != nil {
+					return fmt.Errorf("%s value %v is not valid", field, value)
+				}
+				val = parsed
+			}
+			if typeName == "orm.Decimal" {
+				f.Set(reflect.ValueOf(val))
+			} else {
+				f.Set(reflect.ValueOf(&val))
+			}
+		}
+	case "*orm.Point":
+		if value == nil {
+			f.Set(reflect.Zero(f.Type()))
+		} else {
+			point, ok := value.(Point)
+			if ok {
+				f.Set(reflect.ValueOf(&point))
+			} else {
+				pointer, ok := value.(*Point)
+				if !ok {
+					return fmt.Errorf("%s value %v is not valid", field, value)
+				}
+				f.Set(reflect.ValueOf(pointer))
+			}
+		}
 	default:
 		k := f.Type().Kind().String()
-		if k == "struct" || k == "slice" {
+		if k == "struct" || k == "slice" || k == "array" {
 			f.Set(reflect.ValueOf(value))
 		} else if k == "ptr" {
 			modelType := reflect.TypeOf((*Entity)(nil)).Elem()
@@ -371,6 +438,97 @@ func (orm *ORM) SetField(field string, value interface{}) error {
 	return nil
 }
 
+func (orm *ORM) HasOption(field string, option string) bool {
+	f, err := orm.getSetField(field)
+	if err != nil {
+		return false
+	}
+	for i := 0; i < f.Len(); i++ {
+		if f.Index(i).String() == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (orm *ORM) AddOption(field string, option string) error {
+	f, err := orm.getSetField(field)
+	if err != nil {
+		return err
+	}
+	enum, isSet := orm.tableSchema.sets[field]
+	if isSet && !enum.Has(option) {
+		return fmt.Errorf("%s value %v not valid", field, option)
+	}
+	if orm.HasOption(field, option) {
+		return nil
+	}
+	values := make([]string, f.Len()+1)
+	for i := 0; i < f.Len(); i++ {
+		values[i] = f.Index(i).String()
+	}
+	values[f.Len()] = option
+	if isSet {
+		values = sortSetOptions(enum, values)
+	}
+	return orm.SetField(field, values)
+}
+
+func (orm *ORM) RemoveOption(field string, option string) error {
+	f, err := orm.getSetField(field)
+	if err != nil {
+		return err
+	}
+	values := make([]string, 0, f.Len())
+	for i := 0; i < f.Len(); i++ {
+		if f.Index(i).String() != option {
+			values = append(values, f.Index(i).String())
+		}
+	}
+	if len(values) == f.Len() {
+		return nil
+	}
+	if len(values) == 0 {
+		return orm.SetField(field, nil)
+	}
+	return orm.SetField(field, values)
+}
+
+func (orm *ORM) getSetField(field string) (reflect.Value, error) {
+	if !orm.elem.IsValid() {
+		return reflect.Value{}, errors.New("entity is not loaded")
+	}
+	f := orm.elem.FieldByName(field)
+	if !f.IsValid() {
+		return reflect.Value{}, fmt.Errorf("field %s not found", field)
+	}
+	if !isStringSlice(f.Type()) {
+		return reflect.Value{}, fmt.Errorf("field %s is not a set", field)
+	}
+	return f, nil
+}
+
+func sortSetOptions(enum Enum, values []string) []string {
+	sorted := make([]string, 0, len(values))
+	known := make(map[string]bool, len(values))
+	for _, option := range enum.GetFields() {
+		for _, value := range values {
+			if value == option {
+				sorted = append(sorted, option)
+				known[option] = true
+				break
+			}
+		}
+	}
+	for _, value := range values {
+		if !known[value] {
+			sorted = append(sorted, value)
+			known[value] = true
+		}
+	}
+	return sorted
+}
+
 func (orm *ORM) prepareFieldBind(prefix string, schema *tableSchema, fields *tableFields, value reflect.Value,
 	oldData []interface{}, index int) (reflect.Value, string, interface{}) {
 	name := prefix + fields.fields[index].Name
@@ -634,7 +792,7 @@ func (orm *ORM) fillBind(id uint64, bind Bind, updateBind map[string]string, tab
 	}
 	for _, i := range fields.times {
 		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
-		value := field.Interface().(time.Time)
+		value := tableSchema.convertTimeForBind(name, field.Interface().(time.Time))
 		layout := "2006-01-02"
 		var valueAsString string
 		if tableSchema.tags[name]["time"] == "true" {
@@ -671,7 +829,7 @@ func (orm *ORM) fillBind(id uint64, bind Bind, updateBind map[string]string, tab
 			}
 		}
 		if value != nil {
-			valueAsString = value.Format(layout)
+			valueAsString = tableSchema.convertTimeForBind(name, *value).Format(layout)
 		}
 		if hasOld && (old == valueAsString || (valueAsString == "" && (old == nil || old == "nil"))) {
 			continue
@@ -690,11 +848,15 @@ func (orm *ORM) fillBind(id uint64, bind Bind, updateBind map[string]string, tab
 	}
 	for _, i := range fields.sliceStrings {
 		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
-		value := field.Interface().([]string)
-		var valueAsString string
-		if value != nil {
-			valueAsString = strings.Join(value, ",")
+		values := make([]string, field.Len())
+		for j := range values {
+			values[j] = field.Index(j).String()
+		}
+		enum, isSet := tableSchema.sets[name]
+		if isSet {
+			values = sortSetOptions(enum, values)
 		}
+		valueAsString := strings.Join(values, ",")
 		if hasOld && (old == valueAsString || (valueAsString == "" && old == nil)) {
 			continue
 		}
@@ -719,9 +881,10 @@ func (orm *ORM) fillBind(id uint64, bind Bind, updateBind map[string]string, tab
 			}
 		}
 	}
+	orm.fillArraysBind(bind, updateBind, tableSchema, fields, value, oldData, prefix)
 	for i, subFields := range fields.structs {
 		field, _, _ := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
-		orm.fillBind(0, bind, updateBind, tableSchema, subFields, reflect.ValueOf(field.Interface()), oldData, fields.fields[i].Name)
+		orm.fillBind(0, bind, updateBind, tableSchema, subFields, reflect.ValueOf(field.Interface()), oldData, subFields.prefix)
 	}
 	for _, i := range fields.refs {
 		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
@@ -819,6 +982,83 @@ func (orm *ORM) fillBind(id uint64, bind Bind, updateBind map[string]string, tab
 			}
 		}
 	}
+	for _, i := range fields.spatials {
+		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
+		var valString string
+		switch field.Type().String() {
+		case "orm.Polygon":
+			valString = field.Interface().(Polygon).WKT()
+		case "*orm.Point":
+			if !field.IsNil() {
+				valString = field.Interface().(*Point).WKT()
+			}
+		default:
+			valString = field.Interface().(Point).WKT()
+		}
+		if hasOld {
+			if old == nil && valString == "" {
+				continue
+			}
+			if old != nil && normalizeWKT(old.(string)) == valString {
+				continue
+			}
+		}
+		if valString == "" {
+			bind[name] = nil
+			if hasUpdate {
+				updateBind[name] = "NULL"
+			}
+		} else {
+			bind[name] = valString
+			if hasUpdate {
+				updateBind[name] = strings.Replace(tableSchema.getBindPlaceholder(name), "?", orm.escapeSQLParam(valString), 1)
+			}
+		}
+	}
+	for _, i := range fields.decimals {
+		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			if hasOld && old == nil {
+				continue
+			}
+			bind[name] = nil
+			if hasUpdate {
+				updateBind[name] = "NULL"
+			}
+			continue
+		}
+		val := reflect.Indirect(field).Interface().(Decimal)
+		_, scale := getDecimalDefinition(tableSchema.tags[name])
+		valString := val.Round(scale).String()
+		if hasOld && old == valString {
+			continue
+		}
+		bind[name] = valString
+		if hasUpdate {
+			updateBind[name] = "'" + valString + "'"
+		}
+	}
+	for k, i := range fields.customs {
+		field, name, old := orm.prepareFieldBind(prefix, tableSchema, fields, value, oldData, i)
+		if isNullCustomField(field) {
+			if hasOld && old == nil {
+				continue
+			}
+			bind[name] = nil
+			if hasUpdate {
+				updateBind[name] = "NULL"
+			}
+			continue
+		}
+		valString := fields.customTypes[k].toBind(field, name, tableSchema)
+		if hasOld && old == valString {
+			continue
+		}
+		bind[name] = valString
+		if hasUpdate {
+			updateBind[name] = orm.escapeSQLParam(valString)
+		}
+	}
 }
 
 func (orm *ORM) escapeSQLParam(val string) string {
@@ -242,3 +242,105 @@ func TestORM(t *testing.T) {
 	assert.NotNil(t, entity.Ref)
 	assert.Equal(t, uint(1), entity.Ref.ID)
 }
+
+type ormSetOption string
+
+type ormSetEntity struct {
+	ORM
+	ID      uint
+	Options []ormSetOption `orm:"set=orm.TestEnum"`
+}
+
+func TestORMSetOptions(t *testing.T) {
+	var entity *ormSetEntity
+	registry := &Registry{}
+	registry.RegisterEnumStruct("orm.TestEnum", TestEnum)
+	engine := PrepareTables(t, registry, 5, entity)
+
+	entity = &ormSetEntity{}
+	engine.Flush(entity)
+	assert.False(t, entity.HasOption("Options", "a"))
+	assert.NoError(t, entity.AddOption("Options", "c"))
+	assert.NoError(t, entity.AddOption("Options", "a"))
+	assert.NoError(t, entity.AddOption("Options", "a"))
+	assert.Equal(t, []ormSetOption{"a", "c"}, entity.Options)
+	assert.EqualError(t, entity.AddOption("Options", "d"), "Options value d not valid")
+	assert.EqualError(t, entity.AddOption("ID", "a"), "field ID is not a set")
+	bind, has := entity.GetDirtyBind()
+	assert.True(t, has)
+	assert.Equal(t, Bind{"Options": "a,c"}, bind)
+	engine.Flush(entity)
+
+	entity = &ormSetEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, []ormSetOption{"a", "c"}, entity.Options)
+	assert.True(t, entity.HasOption("Options", "c"))
+	entity.Options = []ormSetOption{"c", "a"}
+	assert.False(t, entity.IsDirty())
+
+	engine.Flush(&ormSetEntity{Options: []ormSetOption{"b"}})
+	var rows []*ormSetEntity
+	engine.Search(W.InSet("Options", "a"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(1), rows[0].ID)
+	engine.Search(W.NotInSet("Options", "a"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(2), rows[0].ID)
+
+	assert.NoError(t, entity.RemoveOption("Options", "a"))
+	assert.NoError(t, entity.RemoveOption("Options", "c"))
+	assert.Nil(t, entity.Options)
+	engine.Flush(entity)
+	engine.Search(W.NotInSet("Options", "a"), nil, &rows)
+	assert.Len(t, rows, 2)
+}
+
+type ormTimeZoneEntity struct {
+	ORM
+	ID        uint
+	CreatedAt time.Time  `orm:"time"`
+	UpdatedAt *time.Time `orm:"time;timezone=America/New_York"`
+	Day       time.Time
+}
+
+func TestORMTimeZone(t *testing.T) {
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+	registry := &Registry{}
+	registry.SetTimeZone(warsaw)
+	var entity *ormTimeZoneEntity
+	engine := PrepareTables(t, registry, 5, entity)
+
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	createdAt := time.Date(2021, 6, 10, 9, 30, 0, 0, tokyo)
+	updatedAt := time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC)
+	entity = &ormTimeZoneEntity{CreatedAt: createdAt, UpdatedAt: &updatedAt, Day: time.Date(2021, 6, 10, 23, 30, 0, 0, time.UTC)}
+	engine.Flush(entity)
+
+	var createdAtRaw, updatedAtRaw, dayRaw string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `CreatedAt`, `UpdatedAt`, `Day` FROM `ormTimeZoneEntity` WHERE `ID` = 1"),
+		&createdAtRaw, &updatedAtRaw, &dayRaw)
+	assert.Equal(t, "2021-06-10 00:30:00", createdAtRaw)
+	assert.Equal(t, "2021-06-10 12:00:00", updatedAtRaw)
+	assert.Equal(t, "2021-06-11", dayRaw)
+
+	entity = &ormTimeZoneEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.True(t, createdAt.Equal(entity.CreatedAt))
+	assert.Equal(t, "Europe/Warsaw", entity.CreatedAt.Location().String())
+	assert.True(t, updatedAt.Equal(*entity.UpdatedAt))
+	assert.Equal(t, "America/New_York", entity.UpdatedAt.Location().String())
+	assert.True(t, time.Date(2021, 6, 11, 0, 0, 0, 0, warsaw).Equal(entity.Day))
+	assert.False(t, entity.IsDirty())
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&ormInvalidTimeZoneEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid timezone 'Mars/Olympus' for field 'CreatedAt' in entity 'orm.ormInvalidTimeZoneEntity'")
+}
+
+type ormInvalidTimeZoneEntity struct {
+	ORM
+	ID        uint
+	CreatedAt time.Time `orm:"time;timezone=Mars/Olympus"`
+}
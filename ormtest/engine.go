@@ -0,0 +1,57 @@
+package ormtest
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/latolukasz/orm"
+)
+
+var databaseCounter uint64
+
+func NewRegistry() *orm.Registry {
+	registry := &orm.Registry{}
+	RegisterMySQLPool(registry)
+	RegisterRedis(registry, NewRedisServer(), 0)
+	registry.RegisterLocalCache(1000)
+	return registry
+}
+
+func NewEngine(entities ...orm.Entity) *orm.Engine {
+	return CreateEngine(NewRegistry(), entities...)
+}
+
+func RegisterMySQLPool(registry *orm.Registry, code ...string) {
+	id := atomic.AddUint64(&databaseCounter, 1)
+	registry.RegisterMySQLPoolWithDriver(DriverName, fmt.Sprintf("ormtest-%d/test", id), code...)
+}
+
+func RegisterRedis(registry *orm.Registry, server *RedisServer, db int, code ...string) {
+	registry.RegisterRedisWithOptions(&redis.Options{Addr: "ormtest", DB: db, Dialer: server.Dial}, code...)
+}
+
+func CreateEngine(registry *orm.Registry, entities ...orm.Entity) *orm.Engine {
+	registry.RegisterEntity(entities...)
+	validatedRegistry, err := registry.Validate()
+	if err != nil {
+		panic(err)
+	}
+	engine := validatedRegistry.CreateEngine()
+	for _, t := range validatedRegistry.GetEntities() {
+		schema := validatedRegistry.GetTableSchema(t.String())
+		dataSourceName := schema.GetMysql(engine).GetPoolConfig().GetDataSourceURI()
+		if !strings.HasPrefix(dataSourceName, "ormtest-") {
+			continue
+		}
+		db := getDatabase(dataSourceName)
+		for _, index := range schema.GetIndexDefinitions(engine) {
+			if index.Unique {
+				db.addUniqueIndex(schema.GetTableName(), index.Name, index.Columns)
+			}
+		}
+	}
+	return engine
+}
@@ -0,0 +1,245 @@
+package ormtest
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+type ormtestEntity struct {
+	orm.ORM `orm:"redisCache"`
+	ID      uint
+	Name    string `orm:"required;unique=Name"`
+	Age     uint8
+	Parent  *ormtestEntity
+}
+
+type ormtestLocalEntity struct {
+	orm.ORM `orm:"localCache"`
+	ID      uint
+	Name    string
+}
+
+func TestEngine(t *testing.T) {
+	engine := NewEngine(&ormtestEntity{}, &ormtestLocalEntity{})
+
+	tom := &ormtestEntity{Name: "Tom", Age: 18}
+	engine.Flush(tom)
+	assert.Equal(t, uint64(1), tom.GetID())
+	john := &ormtestEntity{Name: "John", Age: 20, Parent: tom}
+	adam := &ormtestEntity{Name: "Adam", Age: 30}
+	engine.FlushMany(john, adam)
+	assert.Equal(t, uint64(2), john.GetID())
+	assert.Equal(t, uint64(3), adam.GetID())
+
+	loaded := &ormtestEntity{}
+	assert.True(t, engine.LoadByID(2, loaded, "Parent"))
+	assert.Equal(t, "John", loaded.Name)
+	assert.Equal(t, "Tom", loaded.Parent.Name)
+	assert.False(t, engine.LoadByID(10, loaded))
+
+	var rows []*ormtestEntity
+	total := engine.SearchWithCount(orm.NewWhere("`Age` >= ? ORDER BY `Age` DESC", 20), orm.NewPager(1, 1), &rows)
+	assert.Equal(t, 2, total)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Adam", rows[0].Name)
+	engine.Search(orm.NewWhere("`Name` IN ? AND `Parent` IS NULL", []string{"tom", "John"}), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Tom", rows[0].Name)
+	assert.Equal(t, []uint64{2}, engine.SearchIDs(orm.NewWhere("`Name` LIKE ?", "%oh%"), nil, &ormtestEntity{}))
+
+	loaded.Age = 21
+	engine.Flush(loaded)
+	loaded = &ormtestEntity{}
+	engine.GetLocalCache().Clear()
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, uint8(21), loaded.Age)
+
+	err := engine.FlushWithCheck(&ormtestEntity{Name: "Tom"})
+	assert.IsType(t, &orm.DuplicatedKeyError{}, err)
+	assert.Equal(t, "Name", err.(*orm.DuplicatedKeyError).Index)
+
+	engine.Delete(adam)
+	assert.False(t, engine.LoadByID(3, &ormtestEntity{}))
+	assert.Equal(t, 2, engine.Count(orm.NewWhere("1"), &ormtestEntity{}))
+
+	db := engine.GetMysql()
+	db.Begin()
+	engine.Flush(&ormtestLocalEntity{Name: "rollback"})
+	db.Rollback()
+	assert.Equal(t, 0, engine.Count(orm.NewWhere("1"), &ormtestLocalEntity{}))
+
+	redisCache := engine.GetRedis()
+	redisCache.Set("key", "value", 10)
+	value, has := redisCache.Get("key")
+	assert.True(t, has)
+	assert.Equal(t, "value", value)
+	redisCache.HSet("hash", "a", "1", "b", "2")
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, redisCache.HGetAll("hash"))
+	redisCache.RPush("list", "a", "b", "c")
+	assert.Equal(t, []string{"b", "c"}, redisCache.LRange("list", 1, -1))
+	assert.Equal(t, int64(1), redisCache.Incr("counter"))
+	assert.Equal(t, int64(2), redisCache.Incr("counter"))
+	redisCache.Del("key")
+	_, has = redisCache.Get("key")
+	assert.False(t, has)
+}
+
+func TestSQL(t *testing.T) {
+	db := getDatabase("ormtest-sql/test")
+	db.addUniqueIndex("users", "Email", []string{"Email"})
+	result, _, err := db.execute("INSERT INTO `users`(`Name`,`Email`,`Age`) VALUES (?,?,?),('Ann','ann@x.com',NULL)", []driver.Value{"Tom", "tom@x.com", int64(20)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.lastInsertID)
+	assert.Equal(t, int64(2), result.rowsAffected)
+
+	_, rows, err := db.execute("SELECT `Name`, IFNULL(`Age`, 0) FROM `test`.`users` WHERE `Age` IS NULL OR `Age` BETWEEN 10 AND 30 ORDER BY `Name`", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]driver.Value{{"Ann", int64(0)}, {"Tom", int64(20)}}, rows.values)
+
+	_, _, err = db.execute("INSERT INTO `users`(`Name`,`Email`) VALUES ('Bob','TOM@x.com')", nil)
+	assert.EqualError(t, err, "Error 1062: Duplicate entry 'TOM@x.com' for key 'Email'")
+	result, _, err = db.execute("INSERT INTO `users`(`Name`,`Email`) VALUES ('Bob','tom@x.com') ON DUPLICATE KEY UPDATE `Name` = VALUES(`Name`)", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.rowsAffected)
+	assert.Equal(t, int64(1), result.lastInsertID)
+
+	result, _, err = db.execute("UPDATE `users` SET `Age` = LAST_INSERT_ID(`Age` + ?) WHERE `ID` = ?;UPDATE users SET `Name`='Anna' WHERE `ID` = 2", []driver.Value{int64(5), int64(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.rowsAffected)
+	assert.Equal(t, int64(25), result.lastInsertID)
+
+	_, rows, err = db.execute("SELECT count(1), MAX(`Age`) FROM `users` WHERE `ID` IN (SELECT `ID` FROM `users` WHERE `Name` != 'x')", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]driver.Value{{int64(2), int64(25)}}, rows.values)
+
+	result, _, err = db.execute("DELETE FROM `users` WHERE `Name` = 'anna'", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.rowsAffected)
+	_, rows, _ = db.execute("SELECT `ID` FROM `users` LIMIT 0,10", nil)
+	assert.Len(t, rows.values, 1)
+
+	_, _, err = db.execute("SELECT * FROM `users` WHERE", nil)
+	assert.Error(t, err)
+}
+
+type snapshotTB struct {
+	testing.TB
+	errors []string
+}
+
+func (t *snapshotTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSchemaSnapshot(t *testing.T) {
+	engine := NewEngine(&ormtestEntity{}, &ormtestLocalEntity{})
+	path := filepath.Join(t.TempDir(), "schema", "snapshot.sql")
+	assert.True(t, AssertSchemaSnapshot(t, engine, path))
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, engine.GetSchemaSnapshot(), string(content))
+	assert.True(t, strings.HasPrefix(string(content), "-- pool: default\n\nCREATE TABLE `ormtestEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n"))
+	assert.Contains(t, string(content), "CREATE TABLE `ormtestLocalEntity` (")
+	assert.Contains(t, string(content), "ALTER TABLE `ormtestEntity`\n  ADD CONSTRAINT")
+	assert.True(t, AssertSchemaSnapshot(t, engine, path))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(strings.Replace(string(content), "`Age`", "`Years`", 1)), 0644))
+	mock := &snapshotTB{TB: t}
+	assert.False(t, AssertSchemaSnapshot(mock, engine, path))
+	assert.Len(t, mock.errors, 1)
+	assert.Contains(t, mock.errors[0], "- "+"  `Years`")
+	assert.Contains(t, mock.errors[0], "+ "+"  `Age`")
+}
+
+func TestContextCancel(t *testing.T) {
+	engine := NewEngine(&ormtestLocalEntity{})
+	engine.Flush(&ormtestLocalEntity{Name: "Tom"})
+	ctx, cancel := context.WithCancel(context.Background())
+	child := orm.NewContext(ctx, engine)
+	assert.Equal(t, 1, child.Count(orm.NewWhere("1"), &ormtestLocalEntity{}))
+	cancel()
+	assert.PanicsWithError(t, "context canceled", func() {
+		child.Count(orm.NewWhere("1"), &ormtestLocalEntity{})
+	})
+	assert.Equal(t, 1, engine.Count(orm.NewWhere("1"), &ormtestLocalEntity{}))
+}
+
+func TestPoolStats(t *testing.T) {
+	engine := NewEngine(&ormtestLocalEntity{})
+	db := engine.GetMysql()
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(time.Minute)
+	db.SetConnMaxIdleTime(time.Minute)
+	assert.Equal(t, 1, db.Stats().MaxOpenConnections)
+	logger := memory.New()
+	engine.EnableLogger(apexLog.WarnLevel, logger)
+
+	db.Begin()
+	done := make(chan bool)
+	go func() {
+		engine.Clone().GetMysql().Exec("DELETE FROM `ormtestLocalEntity`")
+		done <- true
+	}()
+	for i := 0; i < 100 && db.Stats().WaitCount == 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	db.Commit()
+	<-done
+	assert.Equal(t, int64(1), db.Stats().WaitCount)
+	assert.Len(t, logger.Entries, 1)
+	assert.Equal(t, "mysql pool default is waiting for connections", logger.Entries[0].Message)
+	assert.Equal(t, int64(1), logger.Entries[0].Fields["new_waits"])
+}
+
+func TestInterpolatedQueryLog(t *testing.T) {
+	engine := NewEngine(&ormtestLocalEntity{})
+	logger := memory.New()
+	engine.AddQueryLogger(logger, apexLog.InfoLevel, orm.QueryLoggerSourceDB)
+	engine.GetMysql().Exec("DELETE FROM `ormtestLocalEntity` WHERE `Name` = ?", "John")
+	assert.Len(t, logger.Entries, 1)
+	assert.Nil(t, logger.Entries[0].Fields["interpolated"])
+
+	engine.EnableInterpolatedQueryLog(3)
+	engine.GetMysql().Exec("DELETE FROM `ormtestLocalEntity` WHERE `Name` = ?", "John")
+	assert.Len(t, logger.Entries, 2)
+	assert.Equal(t, "DELETE FROM `ormtestLocalEntity` WHERE `Name` = 'Joh...'", logger.Entries[1].Fields["interpolated"])
+}
+
+func TestQueryTag(t *testing.T) {
+	engine := NewEngine(&ormtestLocalEntity{})
+	logger := memory.New()
+	engine.AddQueryLogger(logger, apexLog.InfoLevel, orm.QueryLoggerSourceDB)
+	engine.SetQueryTag("checkout-service:place order")
+	engine.SetQueryTraceID("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	engine.Flush(&ormtestLocalEntity{Name: "Tom"})
+	var rows []*ormtestLocalEntity
+	engine.Search(orm.NewWhere("`Name` = ?", "Tom"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, 1, engine.Count(orm.NewWhere("1"), &ormtestLocalEntity{}))
+	comment := "/*tag='checkout-service%3Aplace%20order',traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'*/ "
+	assert.Len(t, logger.Entries, 3)
+	for _, entry := range logger.Entries {
+		assert.True(t, strings.HasPrefix(entry.Fields["Query"].(string), comment))
+	}
+	assert.Equal(t, map[string]string{"tag": "checkout-service:place order",
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, engine.GetQueryTags())
+
+	child := engine.Clone()
+	child.SetQueryTag("")
+	child.GetMysql().Exec("DELETE FROM `ormtestLocalEntity`")
+	assert.Equal(t, "/*traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'*/ DELETE FROM `ormtestLocalEntity`", logger.Entries[3].Fields["Query"])
+	assert.Len(t, engine.GetQueryTags(), 2)
+}
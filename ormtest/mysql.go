@@ -0,0 +1,803 @@
+package ormtest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const DriverName = "ormtest"
+
+var databases = make(map[string]*database)
+var databasesLock sync.Mutex
+
+func init() {
+	sql.Register(DriverName, &mysqlDriver{})
+}
+
+type mysqlDriver struct{}
+
+type database struct {
+	name         string
+	lock         sync.Mutex
+	tables       map[string]*table
+	uniques      map[string][]uniqueIndex
+	lastInsertID int64
+}
+
+type table struct {
+	rows          []map[string]driver.Value
+	autoIncrement int64
+}
+
+type uniqueIndex struct {
+	name    string
+	columns []string
+}
+
+type selectItem struct {
+	expression expression
+	name       string
+	star       bool
+}
+
+type orderItem struct {
+	expression expression
+	desc       bool
+}
+
+type assignment struct {
+	column     string
+	expression expression
+}
+
+type selectStatement struct {
+	items  []selectItem
+	table  string
+	where  expression
+	order  []orderItem
+	limit  int64
+	offset int64
+}
+
+type insertStatement struct {
+	table       string
+	ignore      bool
+	columns     []string
+	rows        [][]expression
+	onDuplicate []assignment
+}
+
+type updateStatement struct {
+	table string
+	set   []assignment
+	where expression
+	limit int64
+}
+
+type deleteStatement struct {
+	table string
+	where expression
+	limit int64
+}
+
+type showVariablesStatement struct {
+	name string
+}
+
+type truncateStatement struct {
+	table string
+}
+
+type dropStatement struct {
+	table string
+}
+
+type noopStatement struct{}
+
+type mysqlConn struct {
+	db       *database
+	snapshot map[string]*table
+}
+
+type mysqlStmt struct {
+	conn  *mysqlConn
+	query string
+}
+
+type mysqlTx struct {
+	conn *mysqlConn
+}
+
+type mysqlResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+type mysqlRows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func getDatabase(dataSourceName string) *database {
+	name := strings.Split(dataSourceName, "?")[0]
+	databasesLock.Lock()
+	defer databasesLock.Unlock()
+	db, has := databases[name]
+	if !has {
+		parts := strings.Split(name, "/")
+		db = &database{name: parts[len(parts)-1], tables: make(map[string]*table), uniques: make(map[string][]uniqueIndex)}
+		databases[name] = db
+	}
+	return db
+}
+
+func (d *mysqlDriver) Open(name string) (driver.Conn, error) {
+	return &mysqlConn{db: getDatabase(name)}, nil
+}
+
+func (c *mysqlConn) Prepare(query string) (driver.Stmt, error) {
+	return &mysqlStmt{conn: c, query: query}, nil
+}
+
+func (c *mysqlConn) Close() error {
+	return nil
+}
+
+func (c *mysqlConn) Begin() (driver.Tx, error) {
+	if c.snapshot != nil {
+		return nil, errors.New("transaction already started")
+	}
+	c.db.lock.Lock()
+	c.snapshot = c.db.cloneTables()
+	c.db.lock.Unlock()
+	return &mysqlTx{conn: c}, nil
+}
+
+func (t *mysqlTx) Commit() error {
+	t.conn.snapshot = nil
+	return nil
+}
+
+func (t *mysqlTx) Rollback() error {
+	if t.conn.snapshot != nil {
+		t.conn.db.lock.Lock()
+		t.conn.db.tables = t.conn.snapshot
+		t.conn.db.lock.Unlock()
+		t.conn.snapshot = nil
+	}
+	return nil
+}
+
+func (s *mysqlStmt) Close() error {
+	return nil
+}
+
+func (s *mysqlStmt) NumInput() int {
+	return -1
+}
+
+func (s *mysqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, _, err := s.conn.db.execute(s.query, args)
+	return result, err
+}
+
+func (s *mysqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	_, rows, err := s.conn.db.execute(s.query, args)
+	return rows, err
+}
+
+func (r *mysqlResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r *mysqlResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func (r *mysqlRows) Columns() []string {
+	return r.columns
+}
+
+func (r *mysqlRows) Close() error {
+	return nil
+}
+
+func (r *mysqlRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	for i, value := range r.values[r.pos] {
+		asString, isString := value.(string)
+		if isString {
+			value = []byte(asString)
+		}
+		dest[i] = value
+	}
+	r.pos++
+	return nil
+}
+
+func (db *database) cloneTables() map[string]*table {
+	tables := make(map[string]*table, len(db.tables))
+	for name, source := range db.tables {
+		cloned := &table{autoIncrement: source.autoIncrement, rows: make([]map[string]driver.Value, len(source.rows))}
+		for i, row := range source.rows {
+			cloned.rows[i] = cloneRow(row)
+		}
+		tables[name] = cloned
+	}
+	return tables
+}
+
+func cloneRow(row map[string]driver.Value) map[string]driver.Value {
+	cloned := make(map[string]driver.Value, len(row))
+	for column, value := range row {
+		cloned[column] = value
+	}
+	return cloned
+}
+
+func (db *database) addUniqueIndex(tableName, name string, columns []string) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.uniques[tableName] = append(db.uniques[tableName], uniqueIndex{name: name, columns: columns})
+}
+
+func (db *database) getTable(name string) *table {
+	t, has := db.tables[name]
+	if !has {
+		t = &table{rows: make([]map[string]driver.Value, 0)}
+		db.tables[name] = t
+	}
+	return t
+}
+
+func (db *database) execute(query string, args []driver.Value) (result *mysqlResult, rows *mysqlRows, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, isErr := rec.(error)
+			if !isErr {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			err = asErr
+		}
+	}()
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	p := &parser{args: args}
+	result = &mysqlResult{}
+	rows = &mysqlRows{}
+	for _, statementTokens := range splitStatements(tokens) {
+		p.tokens = statementTokens
+		p.pos = 0
+		statement := p.parseStatement()
+		if p.peek().kind != tokenEOF {
+			return nil, nil, fmt.Errorf("unexpected '%s' in query %s", p.peek().text, query)
+		}
+		switch s := statement.(type) {
+		case *selectStatement:
+			rows = db.selectRows(s, &evalContext{db: db})
+		case *showVariablesStatement:
+			rows = db.showVariables(s)
+		case *insertStatement:
+			statementResult, err := db.insert(s)
+			if err != nil {
+				return nil, nil, err
+			}
+			result.lastInsertID = statementResult.lastInsertID
+			result.rowsAffected += statementResult.rowsAffected
+		case *updateStatement:
+			lastInsertID := db.lastInsertID
+			db.lastInsertID = 0
+			affected, err := db.update(s)
+			if err != nil {
+				db.lastInsertID = lastInsertID
+				return nil, nil, err
+			}
+			result.rowsAffected += affected
+			if db.lastInsertID != 0 {
+				result.lastInsertID = db.lastInsertID
+			} else {
+				db.lastInsertID = lastInsertID
+			}
+		case *deleteStatement:
+			result.rowsAffected += db.delete(s)
+		case *truncateStatement:
+			db.tables[s.table] = &table{rows: make([]map[string]driver.Value, 0)}
+		case *dropStatement:
+			delete(db.tables, s.table)
+		}
+	}
+	return result, rows, nil
+}
+
+func (p *parser) parseStatement() interface{} {
+	switch {
+	case p.isKeyword("SELECT"):
+		return p.parseSelect()
+	case p.acceptKeyword("SHOW", "VARIABLES", "LIKE"):
+		return &showVariablesStatement{name: toString(p.parsePrimary().eval(&evalContext{}))}
+	case p.isKeyword("INSERT"):
+		return p.parseInsert()
+	case p.isKeyword("UPDATE"):
+		return p.parseUpdate()
+	case p.isKeyword("DELETE"):
+		return p.parseDelete()
+	case p.acceptKeyword("TRUNCATE"):
+		p.acceptKeyword("TABLE")
+		return &truncateStatement{table: p.parseIdentifier()}
+	case p.acceptKeyword("DROP", "TABLE"):
+		p.acceptKeyword("IF", "EXISTS")
+		return &dropStatement{table: p.parseIdentifier()}
+	}
+	p.pos = len(p.tokens)
+	return &noopStatement{}
+}
+
+func (p *parser) parseSelect() *selectStatement {
+	p.expectKeyword("SELECT")
+	p.acceptKeyword("SQL_CALC_FOUND_ROWS")
+	p.acceptKeyword("DISTINCT")
+	s := &selectStatement{limit: -1}
+	for {
+		if p.acceptOperator("*") {
+			s.items = append(s.items, selectItem{star: true})
+		} else {
+			start := p.pos
+			item := selectItem{expression: p.parseExpression()}
+			column, isColumn := item.expression.(*columnExpression)
+			if isColumn {
+				item.name = column.name
+			} else {
+				parts := make([]string, 0)
+				for _, t := range p.tokens[start:p.pos] {
+					parts = append(parts, t.text)
+				}
+				item.name = strings.Join(parts, "")
+			}
+			if p.acceptKeyword("AS") {
+				item.name = p.parseIdentifier()
+			}
+			s.items = append(s.items, item)
+		}
+		if !p.acceptOperator(",") {
+			break
+		}
+	}
+	if p.acceptKeyword("FROM") {
+		s.table = p.parseIdentifier()
+		if p.acceptKeyword("AS") {
+			p.parseIdentifier()
+		}
+	}
+	if p.acceptKeyword("WHERE") {
+		s.where = p.parseExpression()
+	}
+	if p.acceptKeyword("ORDER", "BY") {
+		for {
+			item := orderItem{expression: p.parseExpression()}
+			if p.acceptKeyword("DESC") {
+				item.desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			s.order = append(s.order, item)
+			if !p.acceptOperator(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		first := toInt(p.parsePrimary().eval(&evalContext{}))
+		if p.acceptOperator(",") {
+			s.offset = first
+			s.limit = toInt(p.parsePrimary().eval(&evalContext{}))
+		} else {
+			s.limit = first
+			if p.acceptKeyword("OFFSET") {
+				s.offset = toInt(p.parsePrimary().eval(&evalContext{}))
+			}
+		}
+	}
+	for p.acceptKeyword("FOR") || p.acceptKeyword("LOCK") {
+		for p.peek().kind == tokenIdent && !p.isKeyword("FOR") {
+			p.pos++
+		}
+	}
+	return s
+}
+
+func (p *parser) parseInsert() *insertStatement {
+	p.expectKeyword("INSERT")
+	s := &insertStatement{}
+	s.ignore = p.acceptKeyword("IGNORE")
+	p.expectKeyword("INTO")
+	s.table = p.parseIdentifier()
+	if p.acceptOperator("(") {
+		for !p.acceptOperator(")") {
+			s.columns = append(s.columns, p.parseIdentifier())
+			p.acceptOperator(",")
+		}
+	}
+	if !p.acceptKeyword("VALUES") {
+		p.expectKeyword("VALUE")
+	}
+	for {
+		p.expectOperator("(")
+		row := make([]expression, 0, len(s.columns))
+		for !p.acceptOperator(")") {
+			row = append(row, p.parseExpression())
+			p.acceptOperator(",")
+		}
+		s.rows = append(s.rows, row)
+		if !p.acceptOperator(",") {
+			break
+		}
+	}
+	if p.acceptKeyword("ON", "DUPLICATE", "KEY", "UPDATE") {
+		s.onDuplicate = p.parseAssignments()
+	}
+	return s
+}
+
+func (p *parser) parseAssignments() []assignment {
+	assignments := make([]assignment, 0)
+	for {
+		column := p.parseIdentifier()
+		p.expectOperator("=")
+		assignments = append(assignments, assignment{column: column, expression: p.parseExpression()})
+		if !p.acceptOperator(",") {
+			return assignments
+		}
+	}
+}
+
+func (p *parser) parseUpdate() *updateStatement {
+	p.expectKeyword("UPDATE")
+	s := &updateStatement{table: p.parseIdentifier(), limit: -1}
+	p.expectKeyword("SET")
+	s.set = p.parseAssignments()
+	if p.acceptKeyword("WHERE") {
+		s.where = p.parseExpression()
+	}
+	if p.acceptKeyword("LIMIT") {
+		s.limit = toInt(p.parsePrimary().eval(&evalContext{}))
+	}
+	return s
+}
+
+func (p *parser) parseDelete() *deleteStatement {
+	p.expectKeyword("DELETE")
+	p.expectKeyword("FROM")
+	s := &deleteStatement{table: p.parseIdentifier(), limit: -1}
+	if p.acceptKeyword("WHERE") {
+		s.where = p.parseExpression()
+	}
+	if p.acceptKeyword("LIMIT") {
+		s.limit = toInt(p.parsePrimary().eval(&evalContext{}))
+	}
+	return s
+}
+
+func (db *database) showVariables(s *showVariablesStatement) *mysqlRows {
+	rows := &mysqlRows{columns: []string{"Variable_name", "Value"}}
+	values := map[string]driver.Value{"auto_increment_increment": int64(1), "max_connections": int64(1000), "wait_timeout": int64(180)}
+	value, has := values[s.name]
+	if has {
+		rows.values = append(rows.values, []driver.Value{s.name, value})
+	}
+	return rows
+}
+
+func (db *database) matchRows(tableName string, where expression, outer *evalContext) []map[string]driver.Value {
+	t, has := db.tables[tableName]
+	if !has {
+		return nil
+	}
+	matched := make([]map[string]driver.Value, 0)
+	for _, row := range t.rows {
+		if where != nil {
+			value := where.eval(&evalContext{db: db, row: row, inserted: outer.inserted})
+			if value == nil || !isTrue(value) {
+				continue
+			}
+		}
+		matched = append(matched, row)
+	}
+	return matched
+}
+
+func (db *database) selectRows(s *selectStatement, outer *evalContext) *mysqlRows {
+	var matched []map[string]driver.Value
+	if s.table == "" {
+		matched = []map[string]driver.Value{{}}
+		if s.where != nil && !isTrue(s.where.eval(&evalContext{db: db, row: map[string]driver.Value{}})) {
+			matched = nil
+		}
+	} else {
+		matched = db.matchRows(s.table, s.where, outer)
+	}
+	if len(s.order) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, order := range s.order {
+				left := order.expression.eval(&evalContext{db: db, row: matched[i]})
+				right := order.expression.eval(&evalContext{db: db, row: matched[j]})
+				result := 0
+				switch {
+				case left == nil && right != nil:
+					result = -1
+				case left != nil && right == nil:
+					result = 1
+				case left != nil && right != nil:
+					result = compareValues(left, right)
+				}
+				if result != 0 {
+					if order.desc {
+						return result > 0
+					}
+					return result < 0
+				}
+			}
+			return false
+		})
+	}
+	rows := &mysqlRows{}
+	aggregate := false
+	for _, item := range s.items {
+		if !item.star && isAggregate(item.expression) {
+			aggregate = true
+		}
+	}
+	if aggregate {
+		values := make([]driver.Value, len(s.items))
+		for i, item := range s.items {
+			rows.columns = append(rows.columns, item.name)
+			if isAggregate(item.expression) {
+				values[i] = evalAggregate(item.expression, db, matched)
+			} else if len(matched) > 0 {
+				values[i] = item.expression.eval(&evalContext{db: db, row: matched[0]})
+			}
+		}
+		rows.values = append(rows.values, values)
+		return rows
+	}
+	if s.offset > 0 {
+		if s.offset >= int64(len(matched)) {
+			matched = nil
+		} else {
+			matched = matched[s.offset:]
+		}
+	}
+	if s.limit >= 0 && int64(len(matched)) > s.limit {
+		matched = matched[0:s.limit]
+	}
+	columns := make([]string, 0)
+	for _, item := range s.items {
+		if item.star {
+			columns = append(columns, db.getColumns(s.table)...)
+		} else {
+			columns = append(columns, item.name)
+		}
+	}
+	rows.columns = columns
+	for _, row := range matched {
+		values := make([]driver.Value, 0, len(columns))
+		ctx := &evalContext{db: db, row: row}
+		for _, item := range s.items {
+			if item.star {
+				for _, column := range db.getColumns(s.table) {
+					values = append(values, row[column])
+				}
+				continue
+			}
+			values = append(values, item.expression.eval(ctx))
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows
+}
+
+func (db *database) getColumns(tableName string) []string {
+	columns := make(map[string]bool)
+	t, has := db.tables[tableName]
+	if has {
+		for _, row := range t.rows {
+			for column := range row {
+				columns[column] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(columns))
+	for column := range columns {
+		if column != "ID" {
+			names = append(names, column)
+		}
+	}
+	sort.Strings(names)
+	if columns["ID"] {
+		names = append([]string{"ID"}, names...)
+	}
+	return names
+}
+
+func (db *database) findDuplicate(tableName string, row map[string]driver.Value, skip map[string]driver.Value) (map[string]driver.Value, string) {
+	t := db.getTable(tableName)
+	indexes := append([]uniqueIndex{{name: "PRIMARY", columns: []string{"ID"}}}, db.uniques[tableName]...)
+	for _, index := range indexes {
+		values := make([]driver.Value, len(index.columns))
+		hasNull := false
+		for i, column := range index.columns {
+			values[i] = lookupColumn(row, column)
+			if values[i] == nil {
+				hasNull = true
+			}
+		}
+		if hasNull {
+			continue
+		}
+	ROWS:
+		for _, existing := range t.rows {
+			if sameRow(existing, skip) {
+				continue
+			}
+			for i, column := range index.columns {
+				value := lookupColumn(existing, column)
+				if value == nil || compareValues(value, values[i]) != 0 {
+					continue ROWS
+				}
+			}
+			keys := make([]string, len(values))
+			for i, value := range values {
+				keys[i] = toString(value)
+			}
+			return existing, fmt.Sprintf("Duplicate entry '%s' for key '%s'", strings.Join(keys, "-"), index.name)
+		}
+	}
+	return nil, ""
+}
+
+func sameRow(a, b map[string]driver.Value) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+func (db *database) insert(s *insertStatement) (*mysqlResult, error) {
+	t := db.getTable(s.table)
+	result := &mysqlResult{}
+	for _, values := range s.rows {
+		if len(values) != len(s.columns) {
+			return nil, &mysql.MySQLError{Number: 1136, Message: "Column count doesn't match value count"}
+		}
+		row := make(map[string]driver.Value, len(values))
+		for i, value := range values {
+			row[s.columns[i]] = value.eval(&evalContext{db: db})
+		}
+		id := toInt(lookupColumn(row, "ID"))
+		if id == 0 {
+			delete(row, "ID")
+		}
+		existing, message := db.findDuplicate(s.table, row, nil)
+		if existing != nil {
+			if s.onDuplicate != nil {
+				changed := false
+				updated := cloneRow(existing)
+				for _, assignment := range s.onDuplicate {
+					value := assignment.expression.eval(&evalContext{db: db, row: existing, inserted: row})
+					if lookupColumn(existing, assignment.column) != value {
+						changed = true
+					}
+					updated[assignment.column] = value
+				}
+				duplicate, message := db.findDuplicate(s.table, updated, existing)
+				if duplicate != nil {
+					return nil, &mysql.MySQLError{Number: 1062, Message: message}
+				}
+				for column, value := range updated {
+					existing[column] = value
+				}
+				if changed {
+					result.rowsAffected += 2
+				}
+				result.lastInsertID = toInt(existing["ID"])
+				continue
+			}
+			if s.ignore {
+				continue
+			}
+			return nil, &mysql.MySQLError{Number: 1062, Message: message}
+		}
+		if id == 0 {
+			t.autoIncrement++
+			id = t.autoIncrement
+		} else if id > t.autoIncrement {
+			t.autoIncrement = id
+		}
+		row["ID"] = id
+		t.rows = append(t.rows, row)
+		sort.SliceStable(t.rows, func(i, j int) bool {
+			return toInt(t.rows[i]["ID"]) < toInt(t.rows[j]["ID"])
+		})
+		if result.lastInsertID == 0 {
+			result.lastInsertID = id
+		}
+		result.rowsAffected++
+	}
+	if result.lastInsertID > 0 {
+		db.lastInsertID = result.lastInsertID
+	}
+	return result, nil
+}
+
+func (db *database) update(s *updateStatement) (int64, error) {
+	matched := db.matchRows(s.table, s.where, &evalContext{db: db})
+	affected := int64(0)
+	for _, row := range matched {
+		if s.limit >= 0 && affected >= s.limit {
+			break
+		}
+		updated := cloneRow(row)
+		for _, assignment := range s.set {
+			updated[assignment.column] = assignment.expression.eval(&evalContext{db: db, row: updated})
+		}
+		duplicate, message := db.findDuplicate(s.table, updated, row)
+		if duplicate != nil {
+			return affected, &mysql.MySQLError{Number: 1062, Message: message}
+		}
+		changed := false
+		for column, value := range updated {
+			if row[column] != value {
+				changed = true
+			}
+			row[column] = value
+		}
+		if changed {
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+func (db *database) delete(s *deleteStatement) int64 {
+	t, has := db.tables[s.table]
+	if !has {
+		return 0
+	}
+	matched := db.matchRows(s.table, s.where, &evalContext{db: db})
+	if s.limit >= 0 && int64(len(matched)) > s.limit {
+		matched = matched[0:s.limit]
+	}
+	if len(matched) == 0 {
+		return 0
+	}
+	rest := make([]map[string]driver.Value, 0, len(t.rows)-len(matched))
+	for _, row := range t.rows {
+		removed := false
+		for _, deleted := range matched {
+			if sameRow(row, deleted) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			rest = append(rest, row)
+		}
+	}
+	t.rows = rest
+	return int64(len(matched))
+}
@@ -0,0 +1,900 @@
+package ormtest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RedisServer struct {
+	lock      sync.Mutex
+	databases map[int]map[string]*redisValue
+}
+
+type redisValue struct {
+	kind    string
+	str     string
+	hash    map[string]string
+	list    []string
+	set     map[string]bool
+	zset    map[string]float64
+	stream  []redisStreamEntry
+	lastID  int64
+	expires time.Time
+}
+
+type redisStreamEntry struct {
+	id     string
+	fields []string
+}
+
+type redisConn struct {
+	server *RedisServer
+	db     int
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+type redisError string
+
+type redisStatus string
+
+const wrongType = redisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+func NewRedisServer() *RedisServer {
+	return &RedisServer{databases: make(map[int]map[string]*redisValue)}
+}
+
+func (s *RedisServer) Dial(_ context.Context, _, _ string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go s.serve(server)
+	return client, nil
+}
+
+func (s *RedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	c := &redisConn{server: s, reader: bufio.NewReader(conn), writer: bufio.NewWriter(conn)}
+	for {
+		args, err := c.readCommand()
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		s.lock.Lock()
+		response := c.execute(strings.ToUpper(args[0]), args[1:])
+		s.lock.Unlock()
+		writeRESP(c.writer, response)
+		if c.reader.Buffered() == 0 {
+			if c.writer.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *redisConn) readCommand() ([]string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+	total, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, total)
+	for i := range args {
+		header, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimRight(header, "\r\n")[1:])
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		_, err = io.ReadFull(c.reader, data)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = string(data[0:size])
+	}
+	return args, nil
+}
+
+func writeRESP(w *bufio.Writer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		_, _ = w.WriteString("$-1\r\n")
+	case redisError:
+		_, _ = w.WriteString("-" + string(v) + "\r\n")
+	case redisStatus:
+		_, _ = w.WriteString("+" + string(v) + "\r\n")
+	case error:
+		_, _ = w.WriteString("-ERR " + v.Error() + "\r\n")
+	case bool:
+		if v {
+			_, _ = w.WriteString("+OK\r\n")
+		} else {
+			_, _ = w.WriteString("$-1\r\n")
+		}
+	case int:
+		_, _ = w.WriteString(":" + strconv.Itoa(v) + "\r\n")
+	case int64:
+		_, _ = w.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+	case string:
+		_, _ = w.WriteString("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n")
+	case *string:
+		if v == nil {
+			_, _ = w.WriteString("$-1\r\n")
+		} else {
+			writeRESP(w, *v)
+		}
+	case []string:
+		_, _ = w.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, item := range v {
+			writeRESP(w, item)
+		}
+	case []interface{}:
+		if v == nil {
+			_, _ = w.WriteString("*-1\r\n")
+			return
+		}
+		_, _ = w.WriteString("*" + strconv.Itoa(len(v)) + "\r\n")
+		for _, item := range v {
+			writeRESP(w, item)
+		}
+	}
+}
+
+func (c *redisConn) keys() map[string]*redisValue {
+	keys, has := c.server.databases[c.db]
+	if !has {
+		keys = make(map[string]*redisValue)
+		c.server.databases[c.db] = keys
+	}
+	return keys
+}
+
+func (c *redisConn) get(key string, kind string) (*redisValue, redisError) {
+	value, has := c.keys()[key]
+	if !has {
+		return nil, ""
+	}
+	if !value.expires.IsZero() && time.Now().After(value.expires) {
+		delete(c.keys(), key)
+		return nil, ""
+	}
+	if kind != "" && value.kind != kind {
+		return nil, wrongType
+	}
+	return value, ""
+}
+
+func (c *redisConn) getOrCreate(key string, kind string) (*redisValue, redisError) {
+	value, err := c.get(key, kind)
+	if err != "" || value != nil {
+		return value, err
+	}
+	value = &redisValue{kind: kind}
+	switch kind {
+	case "hash":
+		value.hash = make(map[string]string)
+	case "set":
+		value.set = make(map[string]bool)
+	case "zset":
+		value.zset = make(map[string]float64)
+	}
+	c.keys()[key] = value
+	return value, ""
+}
+
+func (c *redisConn) cleanup(key string, value *redisValue) {
+	if len(value.hash) == 0 && len(value.list) == 0 && len(value.set) == 0 && len(value.zset) == 0 && value.kind != "string" && value.kind != "stream" {
+		delete(c.keys(), key)
+	}
+}
+
+func (c *redisConn) execute(command string, args []string) interface{} {
+	minArgs := map[string]int{"GET": 1, "SET": 2, "DEL": 1, "EXISTS": 1, "EXPIRE": 2, "PEXPIRE": 2, "TTL": 1, "TYPE": 1,
+		"INCR": 1, "INCRBY": 2, "DECR": 1, "DECRBY": 2, "MGET": 1, "MSET": 2, "SELECT": 1, "HSET": 3, "HMSET": 3, "HGET": 2,
+		"HMGET": 2, "HGETALL": 1, "HDEL": 2, "HLEN": 1, "HINCRBY": 3, "LPUSH": 2, "RPUSH": 2, "LPOP": 1, "RPOP": 1,
+		"LLEN": 1, "LRANGE": 3, "LREM": 3, "LSET": 3, "LTRIM": 3, "SADD": 2, "SREM": 2, "SCARD": 1, "SMEMBERS": 1,
+		"SISMEMBER": 2, "SPOP": 1, "ZADD": 3, "ZREM": 2, "ZCARD": 1, "ZSCORE": 2, "ZCOUNT": 3, "ZRANGE": 3, "ZREVRANGE": 3,
+		"XADD": 4, "XLEN": 1, "XDEL": 2, "XRANGE": 3, "XREVRANGE": 3, "XTRIM": 3}
+	required, has := minArgs[command]
+	if has && len(args) < required {
+		return fmt.Errorf("wrong number of arguments for '%s' command", strings.ToLower(command))
+	}
+	switch command {
+	case "PING":
+		if len(args) > 0 {
+			return args[0]
+		}
+		return redisStatus("PONG")
+	case "ECHO":
+		return args[0]
+	case "SELECT":
+		db, err := strconv.Atoi(args[0])
+		if err != nil {
+			return errors.New("invalid DB index")
+		}
+		c.db = db
+		return true
+	case "CLIENT", "READONLY":
+		return true
+	case "FLUSHDB":
+		c.server.databases[c.db] = make(map[string]*redisValue)
+		return true
+	case "FLUSHALL":
+		c.server.databases = make(map[int]map[string]*redisValue)
+		return true
+	case "DBSIZE":
+		return len(c.keys())
+	case "GET":
+		value, err := c.get(args[0], "string")
+		if err != "" {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+		return value.str
+	case "SET":
+		return c.set(args)
+	case "DEL", "UNLINK":
+		deleted := 0
+		for _, key := range args {
+			value, _ := c.get(key, "")
+			if value != nil {
+				delete(c.keys(), key)
+				deleted++
+			}
+		}
+		return deleted
+	case "EXISTS":
+		total := 0
+		for _, key := range args {
+			value, _ := c.get(key, "")
+			if value != nil {
+				total++
+			}
+		}
+		return total
+	case "EXPIRE", "PEXPIRE":
+		value, _ := c.get(args[0], "")
+		if value == nil {
+			return 0
+		}
+		ttl, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("value is not an integer or out of range")
+		}
+		unit := time.Second
+		if command == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		value.expires = time.Now().Add(time.Duration(ttl) * unit)
+		return 1
+	case "TTL":
+		value, _ := c.get(args[0], "")
+		if value == nil {
+			return -2
+		}
+		if value.expires.IsZero() {
+			return -1
+		}
+		return int64(time.Until(value.expires).Seconds() + 0.5)
+	case "TYPE":
+		value, _ := c.get(args[0], "")
+		if value == nil {
+			return redisStatus("none")
+		}
+		return redisStatus(value.kind)
+	case "INCR", "INCRBY", "DECR", "DECRBY":
+		by := int64(1)
+		if command == "INCRBY" || command == "DECRBY" {
+			parsed, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return errors.New("value is not an integer or out of range")
+			}
+			by = parsed
+		}
+		if command == "DECR" || command == "DECRBY" {
+			by = -by
+		}
+		value, err := c.getOrCreate(args[0], "string")
+		if err != "" {
+			return err
+		}
+		current := int64(0)
+		if value.str != "" {
+			parsed, err := strconv.ParseInt(value.str, 10, 64)
+			if err != nil {
+				return errors.New("value is not an integer or out of range")
+			}
+			current = parsed
+		}
+		current += by
+		value.str = strconv.FormatInt(current, 10)
+		return current
+	case "MGET":
+		results := make([]interface{}, len(args))
+		for i, key := range args {
+			value, _ := c.get(key, "string")
+			if value != nil {
+				results[i] = value.str
+			}
+		}
+		return results
+	case "MSET":
+		for i := 0; i+1 < len(args); i += 2 {
+			c.keys()[args[i]] = &redisValue{kind: "string", str: args[i+1]}
+		}
+		return true
+	}
+	return c.executeCollection(command, args)
+}
+
+func (c *redisConn) set(args []string) interface{} {
+	value := &redisValue{kind: "string", str: args[1]}
+	nx, xx := false, false
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errors.New("syntax error")
+			}
+			ttl, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return errors.New("value is not an integer or out of range")
+			}
+			unit := time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				unit = time.Millisecond
+			}
+			value.expires = time.Now().Add(time.Duration(ttl) * unit)
+			i++
+		}
+	}
+	existing, _ := c.get(args[0], "")
+	if (nx && existing != nil) || (xx && existing == nil) {
+		return nil
+	}
+	c.keys()[args[0]] = value
+	return true
+}
+
+func (c *redisConn) executeCollection(command string, args []string) interface{} {
+	switch command {
+	case "HSET", "HMSET":
+		value, err := c.getOrCreate(args[0], "hash")
+		if err != "" {
+			return err
+		}
+		added := 0
+		for i := 1; i+1 < len(args); i += 2 {
+			_, has := value.hash[args[i]]
+			if !has {
+				added++
+			}
+			value.hash[args[i]] = args[i+1]
+		}
+		if command == "HMSET" {
+			return true
+		}
+		return added
+	case "HGET":
+		value, err := c.get(args[0], "hash")
+		if err != "" {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+		field, has := value.hash[args[1]]
+		if !has {
+			return nil
+		}
+		return field
+	case "HMGET":
+		value, err := c.get(args[0], "hash")
+		if err != "" {
+			return err
+		}
+		results := make([]interface{}, len(args)-1)
+		for i, field := range args[1:] {
+			if value != nil {
+				fieldValue, has := value.hash[field]
+				if has {
+					results[i] = fieldValue
+				}
+			}
+		}
+		return results
+	case "HGETALL":
+		value, err := c.get(args[0], "hash")
+		if err != "" {
+			return err
+		}
+		results := make([]string, 0)
+		if value != nil {
+			fields := make([]string, 0, len(value.hash))
+			for field := range value.hash {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				results = append(results, field, value.hash[field])
+			}
+		}
+		return results
+	case "HDEL":
+		value, err := c.get(args[0], "hash")
+		if err != "" || value == nil {
+			return 0
+		}
+		deleted := 0
+		for _, field := range args[1:] {
+			_, has := value.hash[field]
+			if has {
+				delete(value.hash, field)
+				deleted++
+			}
+		}
+		c.cleanup(args[0], value)
+		return deleted
+	case "HLEN":
+		value, _ := c.get(args[0], "hash")
+		if value == nil {
+			return 0
+		}
+		return len(value.hash)
+	case "HINCRBY":
+		value, err := c.getOrCreate(args[0], "hash")
+		if err != "" {
+			return err
+		}
+		by, parseErr := strconv.ParseInt(args[2], 10, 64)
+		if parseErr != nil {
+			return errors.New("value is not an integer or out of range")
+		}
+		current, _ := strconv.ParseInt(value.hash[args[1]], 10, 64)
+		current += by
+		value.hash[args[1]] = strconv.FormatInt(current, 10)
+		return current
+	case "LPUSH", "RPUSH":
+		value, err := c.getOrCreate(args[0], "list")
+		if err != "" {
+			return err
+		}
+		for _, item := range args[1:] {
+			if command == "LPUSH" {
+				value.list = append([]string{item}, value.list...)
+			} else {
+				value.list = append(value.list, item)
+			}
+		}
+		return len(value.list)
+	case "LPOP", "RPOP":
+		value, err := c.get(args[0], "list")
+		if err != "" {
+			return err
+		}
+		if value == nil || len(value.list) == 0 {
+			return nil
+		}
+		var item string
+		if command == "LPOP" {
+			item = value.list[0]
+			value.list = value.list[1:]
+		} else {
+			item = value.list[len(value.list)-1]
+			value.list = value.list[0 : len(value.list)-1]
+		}
+		c.cleanup(args[0], value)
+		return item
+	case "LLEN":
+		value, _ := c.get(args[0], "list")
+		if value == nil {
+			return 0
+		}
+		return len(value.list)
+	case "LRANGE":
+		value, err := c.get(args[0], "list")
+		if err != "" {
+			return err
+		}
+		if value == nil {
+			return []string{}
+		}
+		start, stop := redisRange(args[1], args[2], len(value.list))
+		if start > stop {
+			return []string{}
+		}
+		return value.list[start : stop+1]
+	case "LREM":
+		value, err := c.get(args[0], "list")
+		if err != "" || value == nil {
+			return 0
+		}
+		count, _ := strconv.Atoi(args[1])
+		removed := 0
+		rest := make([]string, 0, len(value.list))
+		for _, item := range value.list {
+			if item == args[2] && (count == 0 || removed < abs(count)) {
+				removed++
+				continue
+			}
+			rest = append(rest, item)
+		}
+		value.list = rest
+		c.cleanup(args[0], value)
+		return removed
+	case "LSET":
+		value, err := c.get(args[0], "list")
+		if err != "" {
+			return err
+		}
+		index, _ := strconv.Atoi(args[1])
+		if index < 0 && value != nil {
+			index += len(value.list)
+		}
+		if value == nil || index < 0 || index >= len(value.list) {
+			return errors.New("index out of range")
+		}
+		value.list[index] = args[2]
+		return true
+	case "LTRIM":
+		value, err := c.get(args[0], "list")
+		if err != "" {
+			return err
+		}
+		if value != nil {
+			start, stop := redisRange(args[1], args[2], len(value.list))
+			if start > stop {
+				value.list = nil
+			} else {
+				value.list = value.list[start : stop+1]
+			}
+			c.cleanup(args[0], value)
+		}
+		return true
+	case "SADD":
+		value, err := c.getOrCreate(args[0], "set")
+		if err != "" {
+			return err
+		}
+		added := 0
+		for _, member := range args[1:] {
+			if !value.set[member] {
+				value.set[member] = true
+				added++
+			}
+		}
+		return added
+	case "SREM":
+		value, err := c.get(args[0], "set")
+		if err != "" || value == nil {
+			return 0
+		}
+		removed := 0
+		for _, member := range args[1:] {
+			if value.set[member] {
+				delete(value.set, member)
+				removed++
+			}
+		}
+		c.cleanup(args[0], value)
+		return removed
+	case "SCARD":
+		value, _ := c.get(args[0], "set")
+		if value == nil {
+			return 0
+		}
+		return len(value.set)
+	case "SISMEMBER":
+		value, _ := c.get(args[0], "set")
+		if value != nil && value.set[args[1]] {
+			return 1
+		}
+		return 0
+	case "SMEMBERS", "SPOP":
+		value, err := c.get(args[0], "set")
+		if err != "" {
+			return err
+		}
+		members := make([]string, 0)
+		if value != nil {
+			for member := range value.set {
+				members = append(members, member)
+			}
+		}
+		sort.Strings(members)
+		if command == "SMEMBERS" {
+			return members
+		}
+		count := 1
+		if len(args) > 1 {
+			count, _ = strconv.Atoi(args[1])
+		}
+		if count > len(members) {
+			count = len(members)
+		}
+		for _, member := range members[0:count] {
+			delete(value.set, member)
+		}
+		if value != nil {
+			c.cleanup(args[0], value)
+		}
+		if len(args) == 1 {
+			if count == 0 {
+				return nil
+			}
+			return members[0]
+		}
+		return members[0:count]
+	}
+	return c.executeSorted(command, args)
+}
+
+func (c *redisConn) executeSorted(command string, args []string) interface{} {
+	switch command {
+	case "ZADD":
+		value, err := c.getOrCreate(args[0], "zset")
+		if err != "" {
+			return err
+		}
+		added := 0
+		i := 1
+		for i < len(args) && isRedisFlag(args[i]) {
+			i++
+		}
+		for ; i+1 < len(args); i += 2 {
+			score, parseErr := strconv.ParseFloat(args[i], 64)
+			if parseErr != nil {
+				return errors.New("value is not a valid float")
+			}
+			_, has := value.zset[args[i+1]]
+			if !has {
+				added++
+			}
+			value.zset[args[i+1]] = score
+		}
+		return added
+	case "ZREM":
+		value, err := c.get(args[0], "zset")
+		if err != "" || value == nil {
+			return 0
+		}
+		removed := 0
+		for _, member := range args[1:] {
+			_, has := value.zset[member]
+			if has {
+				delete(value.zset, member)
+				removed++
+			}
+		}
+		c.cleanup(args[0], value)
+		return removed
+	case "ZCARD":
+		value, _ := c.get(args[0], "zset")
+		if value == nil {
+			return 0
+		}
+		return len(value.zset)
+	case "ZSCORE":
+		value, _ := c.get(args[0], "zset")
+		if value == nil {
+			return nil
+		}
+		score, has := value.zset[args[1]]
+		if !has {
+			return nil
+		}
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	case "ZCOUNT":
+		value, _ := c.get(args[0], "zset")
+		if value == nil {
+			return 0
+		}
+		min, max := parseScore(args[1]), parseScore(args[2])
+		total := 0
+		for _, score := range value.zset {
+			if score >= min && score <= max {
+				total++
+			}
+		}
+		return total
+	case "ZRANGE", "ZREVRANGE":
+		value, err := c.get(args[0], "zset")
+		if err != "" {
+			return err
+		}
+		if value == nil {
+			return []string{}
+		}
+		members := make([]string, 0, len(value.zset))
+		for member := range value.zset {
+			members = append(members, member)
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if value.zset[members[i]] == value.zset[members[j]] {
+				return members[i] < members[j]
+			}
+			return value.zset[members[i]] < value.zset[members[j]]
+		})
+		if command == "ZREVRANGE" {
+			for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+				members[i], members[j] = members[j], members[i]
+			}
+		}
+		start, stop := redisRange(args[1], args[2], len(members))
+		results := make([]string, 0)
+		if start > stop {
+			return results
+		}
+		withScores := len(args) > 3 && strings.EqualFold(args[3], "WITHSCORES")
+		for _, member := range members[start : stop+1] {
+			results = append(results, member)
+			if withScores {
+				results = append(results, strconv.FormatFloat(value.zset[member], 'f', -1, 64))
+			}
+		}
+		return results
+	}
+	return c.executeStream(command, args)
+}
+
+func (c *redisConn) executeStream(command string, args []string) interface{} {
+	switch command {
+	case "XADD":
+		value, err := c.getOrCreate(args[0], "stream")
+		if err != "" {
+			return err
+		}
+		i := 1
+		maxLen := -1
+		if strings.EqualFold(args[i], "MAXLEN") {
+			i++
+			if args[i] == "~" || args[i] == "=" {
+				i++
+			}
+			maxLen, _ = strconv.Atoi(args[i])
+			i++
+		}
+		if args[i] != "*" {
+			return errors.New("only auto generated stream IDs are supported")
+		}
+		value.lastID++
+		entry := redisStreamEntry{id: strconv.FormatInt(value.lastID, 10) + "-0", fields: args[i+1:]}
+		value.stream = append(value.stream, entry)
+		if maxLen >= 0 && len(value.stream) > maxLen {
+			value.stream = value.stream[len(value.stream)-maxLen:]
+		}
+		return entry.id
+	case "XLEN":
+		value, _ := c.get(args[0], "stream")
+		if value == nil {
+			return 0
+		}
+		return len(value.stream)
+	case "XDEL":
+		value, _ := c.get(args[0], "stream")
+		if value == nil {
+			return 0
+		}
+		deleted := 0
+		for _, id := range args[1:] {
+			for i, entry := range value.stream {
+				if entry.id == id {
+					value.stream = append(value.stream[0:i], value.stream[i+1:]...)
+					deleted++
+					break
+				}
+			}
+		}
+		return deleted
+	case "XTRIM":
+		value, _ := c.get(args[0], "stream")
+		if value == nil {
+			return 0
+		}
+		maxLen, _ := strconv.Atoi(args[len(args)-1])
+		trimmed := 0
+		if len(value.stream) > maxLen {
+			trimmed = len(value.stream) - maxLen
+			value.stream = value.stream[trimmed:]
+		}
+		return trimmed
+	case "XRANGE", "XREVRANGE":
+		value, _ := c.get(args[0], "stream")
+		results := make([]interface{}, 0)
+		if value == nil {
+			return results
+		}
+		count := len(value.stream)
+		if len(args) > 4 && strings.EqualFold(args[3], "COUNT") {
+			count, _ = strconv.Atoi(args[4])
+		}
+		entries := make([]redisStreamEntry, len(value.stream))
+		copy(entries, value.stream)
+		if command == "XREVRANGE" {
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+		}
+		for _, entry := range entries {
+			if len(results) >= count {
+				break
+			}
+			results = append(results, []interface{}{entry.id, entry.fields})
+		}
+		return results
+	}
+	return fmt.Errorf("unknown command '%s'", strings.ToLower(command))
+}
+
+func redisRange(startArg, stopArg string, length int) (int, int) {
+	start, _ := strconv.Atoi(startArg)
+	stop, _ := strconv.Atoi(stopArg)
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	return start, stop
+}
+
+func parseScore(value string) float64 {
+	switch value {
+	case "-inf":
+		return -1e308
+	case "+inf", "inf":
+		return 1e308
+	}
+	score, _ := strconv.ParseFloat(strings.TrimPrefix(value, "("), 64)
+	return score
+}
+
+func isRedisFlag(value string) bool {
+	switch strings.ToUpper(value) {
+	case "NX", "XX", "GT", "LT", "CH", "INCR":
+		return true
+	}
+	return false
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package ormtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/latolukasz/orm"
+)
+
+const UpdateSnapshotsEnv = "ORM_UPDATE_SNAPSHOTS"
+
+func AssertSchemaSnapshot(t testing.TB, engine *orm.Engine, path string) bool {
+	t.Helper()
+	snapshot := engine.GetSchemaSnapshot()
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) || os.Getenv(UpdateSnapshotsEnv) != "" {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(snapshot), 0644)
+		}
+		if err != nil {
+			t.Fatalf("unable to write schema snapshot %s: %s", path, err)
+		}
+		return true
+	}
+	if err != nil {
+		t.Fatalf("unable to read schema snapshot %s: %s", path, err)
+	}
+	if string(expected) == snapshot {
+		return true
+	}
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(snapshot, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		expectedLine, actualLine := "", ""
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine != actualLine {
+			t.Errorf("schema snapshot %s changed at line %d:\n- %s\n+ %s\nrun tests with %s=1 to update it",
+				path, i+1, expectedLine, actualLine, UpdateSnapshotsEnv)
+			break
+		}
+	}
+	return false
+}
@@ -0,0 +1,859 @@
+package ormtest
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenQuoted
+	tokenNumber
+	tokenString
+	tokenParam
+	tokenOperator
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	args   []driver.Value
+	arg    int
+}
+
+type expression interface {
+	eval(ctx *evalContext) driver.Value
+}
+
+type evalContext struct {
+	db       *database
+	row      map[string]driver.Value
+	inserted map[string]driver.Value
+}
+
+type literalExpression struct {
+	value driver.Value
+}
+
+type columnExpression struct {
+	name string
+}
+
+type binaryExpression struct {
+	operator string
+	left     expression
+	right    expression
+}
+
+type notExpression struct {
+	expression expression
+}
+
+type inExpression struct {
+	expression expression
+	list       []expression
+	query      *selectStatement
+	negate     bool
+}
+
+type isNullExpression struct {
+	expression expression
+	negate     bool
+}
+
+type likeExpression struct {
+	expression expression
+	pattern    expression
+	negate     bool
+}
+
+type betweenExpression struct {
+	expression expression
+	from       expression
+	to         expression
+	negate     bool
+}
+
+type existsExpression struct {
+	query  *selectStatement
+	negate bool
+}
+
+type functionExpression struct {
+	name      string
+	arguments []expression
+	star      bool
+}
+
+func tokenize(query string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed comment in query %s", query)
+			}
+			i += end + 4
+		case c == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed identifier in query %s", query)
+			}
+			tokens = append(tokens, token{kind: tokenQuoted, text: query[i+1 : i+1+end]})
+			i += end + 2
+		case c == '\'' || c == '"':
+			value := strings.Builder{}
+			j := i + 1
+			for ; j < len(query); j++ {
+				if query[j] == '\\' && j+1 < len(query) {
+					j++
+					switch query[j] {
+					case 'n':
+						value.WriteByte('\n')
+					case 'r':
+						value.WriteByte('\r')
+					case 't':
+						value.WriteByte('\t')
+					case '0':
+						value.WriteByte(0)
+					case 'Z':
+						value.WriteByte(26)
+					default:
+						value.WriteByte(query[j])
+					}
+					continue
+				}
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						value.WriteByte(c)
+						j++
+						continue
+					}
+					break
+				}
+				value.WriteByte(query[j])
+			}
+			if j >= len(query) {
+				return nil, fmt.Errorf("unclosed string in query %s", query)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String()})
+			i = j + 1
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9'):
+			j := i
+			for j < len(query) && (query[j] >= '0' && query[j] <= '9' || query[j] == '.' || query[j] == 'e' || query[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: query[i:j]})
+			i = j
+		case c == '_' || c == '@' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(query) && (query[j] == '_' || query[j] == '@' || query[j] == '$' || query[j] >= 'a' && query[j] <= 'z' ||
+				query[j] >= 'A' && query[j] <= 'Z' || query[j] >= '0' && query[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: query[i:j]})
+			i = j
+		case c == '?':
+			tokens = append(tokens, token{kind: tokenParam, text: "?"})
+			i++
+		default:
+			if i+1 < len(query) {
+				two := query[i : i+2]
+				if two == "!=" || two == "<>" || two == "<=" || two == ">=" || two == "||" || two == "&&" {
+					tokens = append(tokens, token{kind: tokenOperator, text: two})
+					i += 2
+					continue
+				}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func splitStatements(tokens []token) [][]token {
+	statements := make([][]token, 0)
+	start := 0
+	for i, t := range tokens {
+		if t.kind == tokenOperator && t.text == ";" {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) isKeyword(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.pos+i]
+		if t.kind != tokenIdent || !strings.EqualFold(t.text, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) acceptKeyword(keywords ...string) bool {
+	if p.isKeyword(keywords...) {
+		p.pos += len(keywords)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keywords ...string) {
+	if !p.acceptKeyword(keywords...) {
+		panic(fmt.Errorf("expected %s near '%s'", strings.Join(keywords, " "), p.peek().text))
+	}
+}
+
+func (p *parser) isOperator(operator string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && t.text == operator
+}
+
+func (p *parser) acceptOperator(operator string) bool {
+	if p.isOperator(operator) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectOperator(operator string) {
+	if !p.acceptOperator(operator) {
+		panic(fmt.Errorf("expected '%s' near '%s'", operator, p.peek().text))
+	}
+}
+
+func (p *parser) parseIdentifier() string {
+	t := p.next()
+	if t.kind != tokenIdent && t.kind != tokenQuoted {
+		panic(fmt.Errorf("expected identifier near '%s'", t.text))
+	}
+	name := t.text
+	for p.isOperator(".") {
+		p.pos++
+		name = p.parseIdentifier()
+	}
+	return name
+}
+
+func (p *parser) parseExpression() expression {
+	left := p.parseAnd()
+	for p.acceptKeyword("OR") || p.acceptOperator("||") {
+		left = &binaryExpression{operator: "OR", left: left, right: p.parseAnd()}
+	}
+	return left
+}
+
+func (p *parser) parseAnd() expression {
+	left := p.parseNot()
+	for p.acceptKeyword("AND") || p.acceptOperator("&&") {
+		left = &binaryExpression{operator: "AND", left: left, right: p.parseNot()}
+	}
+	return left
+}
+
+func (p *parser) parseNot() expression {
+	if p.acceptKeyword("NOT") || p.acceptOperator("!") {
+		return &notExpression{expression: p.parseNot()}
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() expression {
+	if p.isKeyword("EXISTS") || p.isKeyword("NOT", "EXISTS") {
+		negate := p.acceptKeyword("NOT")
+		p.expectKeyword("EXISTS")
+		p.expectOperator("(")
+		query := p.parseSelect()
+		p.expectOperator(")")
+		return &existsExpression{query: query, negate: negate}
+	}
+	left := p.parseAdditive()
+	for {
+		t := p.peek()
+		if t.kind == tokenOperator {
+			switch t.text {
+			case "=", "!=", "<>", "<", "<=", ">", ">=":
+				p.pos++
+				left = &binaryExpression{operator: t.text, left: left, right: p.parseAdditive()}
+				continue
+			}
+		}
+		negate := false
+		if p.isKeyword("NOT", "IN") || p.isKeyword("NOT", "LIKE") || p.isKeyword("NOT", "BETWEEN") {
+			p.pos++
+			negate = true
+		}
+		switch {
+		case p.acceptKeyword("IN"):
+			in := &inExpression{expression: left, negate: negate}
+			p.expectOperator("(")
+			if p.isKeyword("SELECT") {
+				in.query = p.parseSelect()
+			} else if !p.isOperator(")") {
+				in.list = append(in.list, p.parseExpression())
+				for p.acceptOperator(",") {
+					in.list = append(in.list, p.parseExpression())
+				}
+			}
+			p.expectOperator(")")
+			left = in
+		case p.acceptKeyword("LIKE"):
+			left = &likeExpression{expression: left, pattern: p.parseAdditive(), negate: negate}
+		case p.acceptKeyword("BETWEEN"):
+			from := p.parseAdditive()
+			p.expectKeyword("AND")
+			left = &betweenExpression{expression: left, from: from, to: p.parseAdditive(), negate: negate}
+		case p.acceptKeyword("IS"):
+			negate = p.acceptKeyword("NOT")
+			p.expectKeyword("NULL")
+			left = &isNullExpression{expression: left, negate: negate}
+		default:
+			return left
+		}
+	}
+}
+
+func (p *parser) parseAdditive() expression {
+	left := p.parsePrimary()
+	for {
+		t := p.peek()
+		if t.kind == tokenOperator && (t.text == "+" || t.text == "-" || t.text == "*" || t.text == "/") {
+			p.pos++
+			left = &binaryExpression{operator: t.text, left: left, right: p.parsePrimary()}
+			continue
+		}
+		return left
+	}
+}
+
+func (p *parser) parsePrimary() expression {
+	t := p.next()
+	switch t.kind {
+	case tokenParam:
+		if p.arg >= len(p.args) {
+			panic(fmt.Errorf("missing argument for placeholder %d", p.arg+1))
+		}
+		value := p.args[p.arg]
+		p.arg++
+		return &literalExpression{value: normalizeValue(value)}
+	case tokenNumber:
+		return &literalExpression{value: parseNumber(t.text)}
+	case tokenString:
+		return &literalExpression{value: t.text}
+	case tokenQuoted:
+		p.pos--
+		return &columnExpression{name: p.parseIdentifier()}
+	case tokenIdent:
+		switch strings.ToUpper(t.text) {
+		case "NULL":
+			return &literalExpression{}
+		case "TRUE":
+			return &literalExpression{value: int64(1)}
+		case "FALSE":
+			return &literalExpression{value: int64(0)}
+		}
+		if p.isOperator("(") {
+			p.pos++
+			function := &functionExpression{name: strings.ToUpper(t.text)}
+			if p.acceptOperator("*") {
+				function.star = true
+			} else if !p.isOperator(")") {
+				p.acceptKeyword("DISTINCT")
+				function.arguments = append(function.arguments, p.parseExpression())
+				for p.acceptOperator(",") {
+					function.arguments = append(function.arguments, p.parseExpression())
+				}
+			}
+			p.expectOperator(")")
+			return function
+		}
+		p.pos--
+		return &columnExpression{name: p.parseIdentifier()}
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			if p.isKeyword("SELECT") {
+				query := p.parseSelect()
+				p.expectOperator(")")
+				return &functionExpression{name: "SUBQUERY", arguments: []expression{&literalExpression{value: query}}}
+			}
+			e := p.parseExpression()
+			p.expectOperator(")")
+			return e
+		case "-":
+			return &binaryExpression{operator: "-", left: &literalExpression{value: int64(0)}, right: p.parsePrimary()}
+		}
+	}
+	panic(fmt.Errorf("unexpected '%s'", t.text))
+}
+
+func parseNumber(text string) driver.Value {
+	asInt, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		return asInt
+	}
+	asFloat, err := strconv.ParseFloat(text, 64)
+	if err == nil {
+		return asFloat
+	}
+	return text
+}
+
+func normalizeValue(value driver.Value) driver.Value {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	}
+	return value
+}
+
+func (e *literalExpression) eval(_ *evalContext) driver.Value {
+	return e.value
+}
+
+func (e *columnExpression) eval(ctx *evalContext) driver.Value {
+	return lookupColumn(ctx.row, e.name)
+}
+
+func lookupColumn(row map[string]driver.Value, name string) driver.Value {
+	value, has := row[name]
+	if has {
+		return value
+	}
+	for column, value := range row {
+		if strings.EqualFold(column, name) {
+			return value
+		}
+	}
+	return nil
+}
+
+func (e *binaryExpression) eval(ctx *evalContext) driver.Value {
+	switch e.operator {
+	case "AND":
+		left := e.left.eval(ctx)
+		if left != nil && !isTrue(left) {
+			return int64(0)
+		}
+		right := e.right.eval(ctx)
+		if right != nil && !isTrue(right) {
+			return int64(0)
+		}
+		if left == nil || right == nil {
+			return nil
+		}
+		return int64(1)
+	case "OR":
+		left := e.left.eval(ctx)
+		if left != nil && isTrue(left) {
+			return int64(1)
+		}
+		right := e.right.eval(ctx)
+		if right != nil && isTrue(right) {
+			return int64(1)
+		}
+		if left == nil || right == nil {
+			return nil
+		}
+		return int64(0)
+	}
+	left := e.left.eval(ctx)
+	right := e.right.eval(ctx)
+	if left == nil || right == nil {
+		return nil
+	}
+	switch e.operator {
+	case "+", "-", "*", "/":
+		return arithmetic(e.operator, left, right)
+	}
+	result := compareValues(left, right)
+	var matched bool
+	switch e.operator {
+	case "=":
+		matched = result == 0
+	case "!=", "<>":
+		matched = result != 0
+	case "<":
+		matched = result < 0
+	case "<=":
+		matched = result <= 0
+	case ">":
+		matched = result > 0
+	case ">=":
+		matched = result >= 0
+	}
+	return boolValue(matched)
+}
+
+func (e *notExpression) eval(ctx *evalContext) driver.Value {
+	value := e.expression.eval(ctx)
+	if value == nil {
+		return nil
+	}
+	return boolValue(!isTrue(value))
+}
+
+func (e *inExpression) eval(ctx *evalContext) driver.Value {
+	value := e.expression.eval(ctx)
+	if value == nil {
+		return nil
+	}
+	values := make([]driver.Value, 0, len(e.list))
+	if e.query != nil {
+		rows := ctx.db.selectRows(e.query, ctx)
+		for _, row := range rows.values {
+			values = append(values, row[0])
+		}
+	} else {
+		for _, item := range e.list {
+			values = append(values, item.eval(ctx))
+		}
+	}
+	for _, item := range values {
+		if item != nil && compareValues(value, item) == 0 {
+			return boolValue(!e.negate)
+		}
+	}
+	return boolValue(e.negate)
+}
+
+func (e *isNullExpression) eval(ctx *evalContext) driver.Value {
+	return boolValue((e.expression.eval(ctx) == nil) != e.negate)
+}
+
+func (e *likeExpression) eval(ctx *evalContext) driver.Value {
+	value := e.expression.eval(ctx)
+	pattern := e.pattern.eval(ctx)
+	if value == nil || pattern == nil {
+		return nil
+	}
+	return boolValue(matchLike(strings.ToLower(toString(value)), strings.ToLower(toString(pattern))) != e.negate)
+}
+
+func (e *betweenExpression) eval(ctx *evalContext) driver.Value {
+	value := e.expression.eval(ctx)
+	from := e.from.eval(ctx)
+	to := e.to.eval(ctx)
+	if value == nil || from == nil || to == nil {
+		return nil
+	}
+	return boolValue((compareValues(value, from) >= 0 && compareValues(value, to) <= 0) != e.negate)
+}
+
+func (e *existsExpression) eval(ctx *evalContext) driver.Value {
+	rows := ctx.db.selectRows(e.query, ctx)
+	return boolValue((len(rows.values) > 0) != e.negate)
+}
+
+func (e *functionExpression) eval(ctx *evalContext) driver.Value {
+	arguments := make([]driver.Value, len(e.arguments))
+	if e.name != "SUBQUERY" {
+		for i, argument := range e.arguments {
+			arguments[i] = argument.eval(ctx)
+		}
+	}
+	switch e.name {
+	case "SUBQUERY":
+		rows := ctx.db.selectRows(e.arguments[0].(*literalExpression).value.(*selectStatement), ctx)
+		if len(rows.values) == 0 || len(rows.values[0]) == 0 {
+			return nil
+		}
+		return rows.values[0][0]
+	case "VERSION":
+		return "8.0.0-ormtest"
+	case "DATABASE":
+		return ctx.db.name
+	case "NOW", "CURRENT_TIMESTAMP", "UTC_TIMESTAMP":
+		return time.Now().UTC().Format("2006-01-02 15:04:05")
+	case "LAST_INSERT_ID":
+		if len(arguments) > 0 {
+			ctx.db.lastInsertID = toInt(arguments[0])
+			return arguments[0]
+		}
+		return ctx.db.lastInsertID
+	case "VALUES":
+		column, isColumn := e.arguments[0].(*columnExpression)
+		if isColumn && ctx.inserted != nil {
+			return lookupColumn(ctx.inserted, column.name)
+		}
+		return nil
+	case "IFNULL", "COALESCE":
+		for _, argument := range arguments {
+			if argument != nil {
+				return argument
+			}
+		}
+		return nil
+	case "IF":
+		if len(arguments) == 3 {
+			if arguments[0] != nil && isTrue(arguments[0]) {
+				return arguments[1]
+			}
+			return arguments[2]
+		}
+	case "LOWER":
+		if len(arguments) == 1 && arguments[0] != nil {
+			return strings.ToLower(toString(arguments[0]))
+		}
+	case "UPPER":
+		if len(arguments) == 1 && arguments[0] != nil {
+			return strings.ToUpper(toString(arguments[0]))
+		}
+	case "CONCAT":
+		result := strings.Builder{}
+		for _, argument := range arguments {
+			if argument == nil {
+				return nil
+			}
+			result.WriteString(toString(argument))
+		}
+		return result.String()
+	case "FIND_IN_SET":
+		if len(arguments) == 2 && arguments[0] != nil && arguments[1] != nil {
+			for i, item := range strings.Split(toString(arguments[1]), ",") {
+				if strings.EqualFold(item, toString(arguments[0])) {
+					return int64(i + 1)
+				}
+			}
+			return int64(0)
+		}
+	}
+	if len(arguments) > 0 {
+		return arguments[0]
+	}
+	return nil
+}
+
+func isAggregate(e expression) bool {
+	function, isFunction := e.(*functionExpression)
+	if !isFunction {
+		return false
+	}
+	switch function.name {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		return true
+	}
+	return false
+}
+
+func evalAggregate(e expression, db *database, rows []map[string]driver.Value) driver.Value {
+	function := e.(*functionExpression)
+	values := make([]driver.Value, 0, len(rows))
+	for _, row := range rows {
+		if function.star || len(function.arguments) == 0 {
+			values = append(values, int64(1))
+			continue
+		}
+		value := function.arguments[0].eval(&evalContext{db: db, row: row})
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+	switch function.name {
+	case "COUNT":
+		return int64(len(values))
+	case "SUM", "AVG":
+		if len(values) == 0 {
+			return nil
+		}
+		sum := float64(0)
+		for _, value := range values {
+			sum += toFloat(value)
+		}
+		if function.name == "AVG" {
+			return sum / float64(len(values))
+		}
+		return sum
+	}
+	var result driver.Value
+	for _, value := range values {
+		if result == nil || (function.name == "MIN" && compareValues(value, result) < 0) ||
+			(function.name == "MAX" && compareValues(value, result) > 0) {
+			result = value
+		}
+	}
+	return result
+}
+
+func matchLike(value, pattern string) bool {
+	if pattern == "" {
+		return value == ""
+	}
+	switch pattern[0] {
+	case '%':
+		for i := 0; i <= len(value); i++ {
+			if matchLike(value[i:], pattern[1:]) {
+				return true
+			}
+		}
+		return false
+	case '_':
+		return value != "" && matchLike(value[1:], pattern[1:])
+	case '\\':
+		if len(pattern) > 1 {
+			return value != "" && value[0] == pattern[1] && matchLike(value[1:], pattern[2:])
+		}
+	}
+	return value != "" && value[0] == pattern[0] && matchLike(value[1:], pattern[1:])
+}
+
+func boolValue(value bool) driver.Value {
+	if value {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+func isTrue(value driver.Value) bool {
+	switch v := value.(type) {
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		asFloat, err := strconv.ParseFloat(v, 64)
+		return err == nil && asFloat != 0
+	}
+	return value != nil
+}
+
+func isNumeric(value driver.Value) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func compareValues(left, right driver.Value) int {
+	leftNumber, leftNumeric := isNumeric(left)
+	rightNumber, rightNumeric := isNumeric(right)
+	if leftNumeric || rightNumeric {
+		if !leftNumeric {
+			leftNumber = toFloat(left)
+		}
+		if !rightNumeric {
+			rightNumber = toFloat(right)
+		}
+		switch {
+		case leftNumber < rightNumber:
+			return -1
+		case leftNumber > rightNumber:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(toString(left)), strings.ToLower(toString(right)))
+}
+
+func arithmetic(operator string, left, right driver.Value) driver.Value {
+	_, leftIsInt := left.(int64)
+	_, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt && operator != "/" {
+		a, b := left.(int64), right.(int64)
+		switch operator {
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		}
+		return a * b
+	}
+	a, b := toFloat(left), toFloat(right)
+	switch operator {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	}
+	if b == 0 {
+		return nil
+	}
+	return a / b
+}
+
+func toFloat(value driver.Value) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	asFloat, _ := strconv.ParseFloat(strings.TrimSpace(toString(value)), 64)
+	return asFloat
+}
+
+func toInt(value driver.Value) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	asInt, _ := strconv.ParseInt(toString(value), 10, 64)
+	return asInt
+}
+
+func toString(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -3,6 +3,8 @@ package orm
 type Pager struct {
 	CurrentPage int
 	PageSize    int
+	TotalRows   int
+	TotalPages  int
 }
 
 func NewPager(currentPage, pageSize int) *Pager {
@@ -23,3 +25,38 @@ func (pager *Pager) GetCurrentPage() int {
 func (pager *Pager) IncrementPage() {
 	pager.CurrentPage++
 }
+
+func (pager *Pager) HasNext() bool {
+	return pager.CurrentPage < pager.TotalPages
+}
+
+func (pager *Pager) HasPrevious() bool {
+	return pager.CurrentPage > 1
+}
+
+func (pager *Pager) setTotalRows(totalRows int) {
+	pager.TotalRows = totalRows
+	pager.TotalPages = 0
+	if pager.PageSize > 0 {
+		pager.TotalPages = (totalRows + pager.PageSize - 1) / pager.PageSize
+	}
+}
+
+func preparePager(pager *Pager, defaultPageSize int) *Pager {
+	if pager == nil {
+		pager = NewPager(1, defaultPageSize)
+	}
+	return pager
+}
+
+func (e *Engine) withPagerLimit(pager *Pager, run func(pager *Pager)) {
+	if e.registry != nil && e.registry.registry != nil && e.registry.registry.maxPageSize > 0 {
+		maxPageSize := e.registry.registry.maxPageSize
+		if pager == nil {
+			pager = NewPager(1, maxPageSize)
+		} else if pager.PageSize > maxPageSize {
+			pager.PageSize = maxPageSize
+		}
+	}
+	run(pager)
+}
@@ -6,10 +6,87 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+type pagerEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Name     string
+	IndexAll *CachedQuery `query:""`
+}
+
 func TestPager(t *testing.T) {
 	pager := NewPager(2, 100)
 	assert.Equal(t, 2, pager.GetCurrentPage())
 	assert.Equal(t, 100, pager.GetPageSize())
 	pager.IncrementPage()
 	assert.Equal(t, 3, pager.GetCurrentPage())
+	assert.False(t, pager.HasNext())
+	assert.True(t, pager.HasPrevious())
+}
+
+func TestPagerMetadata(t *testing.T) {
+	var entity *pagerEntity
+	registry := &Registry{}
+	registry.SetMaxPageSize(3)
+	engine := PrepareTables(t, registry, 5, entity)
+	for i := 0; i < 7; i++ {
+		engine.Flush(&pagerEntity{Name: "a"})
+	}
+	var rows []*pagerEntity
+	pager := NewPager(1, 2)
+	total := engine.SearchWithCount(NewWhere("1"), pager, &rows)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, 7, pager.TotalRows)
+	assert.Equal(t, 4, pager.TotalPages)
+	assert.True(t, pager.HasNext())
+	assert.False(t, pager.HasPrevious())
+
+	pager = NewPager(4, 2)
+	engine.SearchWithCount(NewWhere("1"), pager, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, 7, pager.TotalRows)
+	assert.False(t, pager.HasNext())
+	assert.True(t, pager.HasPrevious())
+
+	pager = NewPager(1, 100)
+	engine.SearchWithCount(NewWhere("1"), pager, &rows)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 3, pager.GetPageSize())
+	assert.Equal(t, 3, pager.TotalPages)
+
+	ids, total := engine.SearchIDsWithCount(NewWhere("1"), NewPager(1, 50), entity)
+	assert.Len(t, ids, 3)
+	assert.Equal(t, 7, total)
+}
+
+func TestPagerMaxPageSizeDefault(t *testing.T) {
+	var entity *pagerEntity
+	registry := &Registry{}
+	registry.SetMaxPageSize(3)
+	engine := PrepareTables(t, registry, 5, entity)
+	for i := 0; i < 7; i++ {
+		engine.Flush(&pagerEntity{Name: "a"})
+	}
+	var rows []*pagerEntity
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 3)
+	assert.Len(t, engine.SearchIDs(NewWhere("1"), nil, entity), 3)
+	assert.Equal(t, 7, engine.Count(NewWhere("1"), entity))
+
+	pager := NewPager(1, 100)
+	total := engine.CachedSearch(&rows, "IndexAll", pager)
+	assert.Equal(t, 7, total)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 3, pager.GetPageSize())
+	assert.Equal(t, 3, pager.TotalPages)
+	total = engine.CachedSearch(&rows, "IndexAll", NewPager(2, 3))
+	assert.Equal(t, 7, total)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, uint(4), rows[0].ID)
+	total = engine.CachedSearch(&rows, "IndexAll", NewPager(3, 3))
+	assert.Equal(t, 7, total)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(7), rows[0].ID)
+	total = engine.CachedSearch(&rows, "IndexAll", nil)
+	assert.Equal(t, 7, total)
+	assert.Len(t, rows, 3)
 }
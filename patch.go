@@ -0,0 +1,196 @@
+package orm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type PatchError struct {
+	Entity   string
+	Field    string
+	Unknown  bool
+	ReadOnly bool
+	Err      error
+}
+
+func (err *PatchError) Error() string {
+	if err.Unknown {
+		return fmt.Sprintf("unknown field '%s' in entity '%s'", err.Field, err.Entity)
+	}
+	if err.ReadOnly {
+		return fmt.Sprintf("field '%s' in entity '%s' is read-only", err.Field, err.Entity)
+	}
+	return fmt.Sprintf("invalid value of field '%s' in entity '%s': %s", err.Field, err.Entity, err.Err.Error())
+}
+
+func (err *PatchError) Unwrap() error {
+	return err.Err
+}
+
+var patchJSON = jsoniter.Config{UseNumber: true}.Froze()
+
+func (e *Engine) ApplyPatch(entity Entity, patch []byte) error {
+	orm := initIfNeeded(e.registry, entity)
+	schema := orm.tableSchema
+	fields := make(map[string]jsoniter.RawMessage)
+	err := patchJSON.Unmarshal(patch, &fields)
+	if err != nil {
+		return err
+	}
+	writable := make(map[string]bool)
+	for _, field := range getExportFields(schema) {
+		writable[field] = true
+	}
+	for _, virtual := range schema.virtualFields {
+		writable[schema.t.Field(virtual.index).Name] = false
+	}
+	writable["ID"] = false
+	permissions := schema.getFieldPermissions(e)
+	for field := range fields {
+		canWrite, has := writable[field]
+		if !has {
+			return &PatchError{Entity: schema.t.String(), Field: field, Unknown: true}
+		}
+		if !canWrite || (permissions != nil && !permissions.write[field]) {
+			return &PatchError{Entity: schema.t.String(), Field: field, ReadOnly: true}
+		}
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	scratch := initIfNeeded(e.registry, reflect.New(schema.t).Interface().(Entity))
+	for i := 1; i < schema.t.NumField(); i++ {
+		if scratch.elem.Field(i).CanSet() {
+			scratch.elem.Field(i).Set(orm.elem.Field(i))
+		}
+	}
+	for _, field := range names {
+		err = e.applyPatchField(scratch, field, fields[field])
+		if err != nil {
+			return &PatchError{Entity: schema.t.String(), Field: field, Err: err}
+		}
+	}
+	for _, field := range names {
+		orm.elem.FieldByName(field).Set(scratch.elem.FieldByName(field))
+	}
+	return nil
+}
+
+func (e *Engine) applyPatchField(orm *ORM, field string, raw jsoniter.RawMessage) error {
+	f := orm.elem.FieldByName(field)
+	if string(raw) == "null" {
+		switch f.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Array, reflect.Slice, reflect.Ptr:
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		return orm.SetField(field, nil)
+	}
+	if _, isCustom := e.registry.registry.getFieldType(f.Type()); isCustom {
+		target := reflect.New(f.Type())
+		err := patchJSON.Unmarshal(raw, target.Interface())
+		if err != nil {
+			return err
+		}
+		f.Set(target.Elem())
+		return nil
+	}
+	switch f.Type().String() {
+	case "time.Time", "*time.Time":
+		var value string
+		err := patchJSON.Unmarshal(raw, &value)
+		if err != nil {
+			return err
+		}
+		parsed, err := parsePatchTime(value)
+		if err != nil {
+			return err
+		}
+		if f.Kind() == reflect.Ptr {
+			return orm.SetField(field, &parsed)
+		}
+		return orm.SetField(field, parsed)
+	case "string":
+		var value string
+		err := patchJSON.Unmarshal(raw, &value)
+		if err != nil {
+			return err
+		}
+		f.SetString(value)
+		return nil
+	case "orm.Decimal", "*orm.Decimal":
+	default:
+		kind := f.Kind()
+		entityType := reflect.TypeOf((*Entity)(nil)).Elem()
+		if kind == reflect.Slice && f.Type().Elem().Implements(entityType) {
+			var ids []uint64
+			err := patchJSON.Unmarshal(raw, &ids)
+			if err != nil {
+				return err
+			}
+			slice := reflect.MakeSlice(f.Type(), len(ids), len(ids))
+			for i, id := range ids {
+				reference := reflect.New(f.Type().Elem().Elem())
+				initIfNeeded(e.registry, reference.Interface().(Entity)).idElem.SetUint(id)
+				slice.Index(i).Set(reference)
+			}
+			f.Set(slice)
+			return nil
+		}
+		if kind == reflect.Struct || kind == reflect.Map || kind == reflect.Array || kind == reflect.Slice ||
+			(kind == reflect.Ptr && !f.Type().Implements(entityType) && f.Type().Elem().Kind() == reflect.Struct) {
+			target := reflect.New(f.Type())
+			switch kind {
+			case reflect.Struct, reflect.Array:
+				target.Elem().Set(f)
+			case reflect.Map:
+				if !f.IsNil() {
+					copied := reflect.MakeMapWithSize(f.Type(), f.Len())
+					iterator := f.MapRange()
+					for iterator.Next() {
+						copied.SetMapIndex(iterator.Key(), iterator.Value())
+					}
+					target.Elem().Set(copied)
+				}
+			case reflect.Ptr:
+				if !f.IsNil() {
+					copied := reflect.New(f.Type().Elem())
+					copied.Elem().Set(f.Elem())
+					target.Elem().Set(copied)
+				}
+			}
+			err := patchJSON.Unmarshal(raw, target.Interface())
+			if err != nil {
+				return err
+			}
+			f.Set(target.Elem())
+			return nil
+		}
+	}
+	var value interface{}
+	err := patchJSON.Unmarshal(raw, &value)
+	if err != nil {
+		return err
+	}
+	if number, isNumber := value.(json.Number); isNumber {
+		value = number.String()
+	}
+	return orm.SetField(field, value)
+}
+
+func parsePatchTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s'", value)
+}
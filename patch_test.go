@@ -0,0 +1,89 @@
+package orm
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchEntityAddress struct {
+	City   string
+	Street string
+}
+
+type patchEntity struct {
+	ORM
+	ID        uint
+	Name      string
+	Age       uint8
+	Score     *float64
+	Active    bool
+	Tags      []string
+	Born      *time.Time
+	Address   patchEntityAddress
+	Reference *patchEntityReference
+	IP        net.IP
+	Internal  string `orm:"ignore"`
+}
+
+type patchEntityReference struct {
+	ORM
+	ID uint
+}
+
+func TestApplyPatch(t *testing.T) {
+	var entity *patchEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity, &patchEntityReference{})
+	engine.Flush(&patchEntityReference{})
+	entity = &patchEntity{Name: "John", Age: 18, Address: patchEntityAddress{City: "Berlin", Street: "Main"}}
+	engine.Flush(entity)
+
+	err := engine.ApplyPatch(entity, []byte(`{"Name":"Tom","Age":20,"Score":4.5,"Active":true,"Tags":["a","b"],`+
+		`"Born":"2000-01-02","Address":{"Street":"Second"},"Reference":1}`))
+	assert.NoError(t, err)
+	assert.True(t, entity.IsDirty())
+	engine.Flush(entity)
+
+	entity = &patchEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Tom", entity.Name)
+	assert.Equal(t, uint8(20), entity.Age)
+	assert.Equal(t, 4.5, *entity.Score)
+	assert.True(t, entity.Active)
+	assert.Equal(t, []string{"a", "b"}, entity.Tags)
+	assert.Equal(t, "2000-01-02", entity.Born.Format("2006-01-02"))
+	assert.Equal(t, patchEntityAddress{City: "Berlin", Street: "Second"}, entity.Address)
+	assert.Equal(t, uint(1), entity.Reference.ID)
+
+	assert.NoError(t, engine.ApplyPatch(entity, []byte(`{"Score":null,"Reference":null,"Name":"null"}`)))
+	assert.Nil(t, entity.Score)
+	assert.Nil(t, entity.Reference)
+	assert.Equal(t, "null", entity.Name)
+
+	err = engine.ApplyPatch(entity, []byte(`{"Missing":1}`))
+	assert.EqualError(t, err, "unknown field 'Missing' in entity 'orm.patchEntity'")
+	err = engine.ApplyPatch(entity, []byte(`{"Internal":"a"}`))
+	assert.EqualError(t, err, "unknown field 'Internal' in entity 'orm.patchEntity'")
+	err = engine.ApplyPatch(entity, []byte(`{"ID":2}`))
+	assert.EqualError(t, err, "field 'ID' in entity 'orm.patchEntity' is read-only")
+	err = engine.ApplyPatch(entity, []byte(`{"Age":"abc"}`))
+	var patchError *PatchError
+	assert.True(t, errors.As(err, &patchError))
+	assert.Equal(t, "Age", patchError.Field)
+
+	err = engine.ApplyPatch(entity, []byte(`{"Name":"Adam","Tags":["c"],"Age":"abc"}`))
+	assert.EqualError(t, err, patchError.Error())
+	assert.Equal(t, "null", entity.Name)
+	assert.Equal(t, []string{"a", "b"}, entity.Tags)
+
+	assert.NoError(t, engine.ApplyPatch(entity, []byte(`{"IP":"1.2.3.4"}`)))
+	assert.Equal(t, "1.2.3.4", entity.IP.String())
+	engine.Flush(entity)
+	entity = &patchEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "1.2.3.4", entity.IP.String())
+	assert.Error(t, engine.ApplyPatch(entity, []byte(`[1]`)))
+}
@@ -0,0 +1,51 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type prepareTablesEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestPrepareTablesWithOptions(t *testing.T) {
+	var entity *prepareTablesEntity
+	options := &PrepareTablesOptions{UniquePrefix: true, Cleanup: true, RedisDBs: []int{13, 12, 11}}
+	engine := PrepareTablesWithOptions(t, &Registry{}, 5, options, entity)
+	database := engine.GetMysql().GetPoolConfig().GetDatabase()
+	assert.NotEqual(t, "test", database)
+	assert.Regexp(t, "^t[0-9a-z]+_test$", database)
+	assert.Equal(t, database+"_log", engine.GetMysql("log").GetPoolConfig().GetDatabase())
+	assert.Equal(t, 13, engine.GetRedis().GetPoolConfig().GetDB())
+	engine.Flush(&prepareTablesEntity{Name: "a"})
+
+	options.KeepData = true
+	engine = PrepareTablesWithOptions(t, &Registry{}, 5, options, entity)
+	assert.Equal(t, database, engine.GetMysql().GetPoolConfig().GetDatabase())
+	entity = &prepareTablesEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+
+	options.KeepData = false
+	engine = PrepareTablesWithOptions(t, &Registry{}, 5, options, entity)
+	assert.False(t, engine.LoadByID(1, &prepareTablesEntity{}))
+
+	options = &PrepareTablesOptions{UniquePrefix: true, Cleanup: true}
+	engine = PrepareTablesWithOptions(t, &Registry{}, 5, options, entity)
+	for _, code := range []string{"default", "default_queue", "search"} {
+		db := engine.GetRedis(code).GetPoolConfig().GetDB()
+		assert.True(t, db >= 1 && db <= 12)
+	}
+}
+
+func TestPrepareTablesWithOptionsRedisDBs(t *testing.T) {
+	assert.PanicsWithError(t, "RedisDBs must contain 3 redis databases, got 2", func() {
+		PrepareTablesWithOptions(t, &Registry{}, 5, &PrepareTablesOptions{RedisDBs: []int{13, 12}}, &prepareTablesEntity{})
+	})
+	assert.PanicsWithError(t, "RedisDBs must contain 3 redis databases, got 0", func() {
+		PrepareTablesWithOptions(t, &Registry{}, 5, &PrepareTablesOptions{RedisDBs: []int{}}, &prepareTablesEntity{})
+	})
+}
@@ -0,0 +1,150 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type QueryBuilder struct {
+	db         *DB
+	recursive  bool
+	with       []string
+	withParams []interface{}
+	query      string
+	params     []interface{}
+	windows    []string
+	orderBy    []string
+	pager      *Pager
+}
+
+func (db *DB) NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{db: db}
+}
+
+func (b *QueryBuilder) With(name, query string, parameters ...interface{}) *QueryBuilder {
+	b.with = append(b.with, "`"+name+"` AS ("+query+")")
+	b.withParams = append(b.withParams, parameters...)
+	return b
+}
+
+func (b *QueryBuilder) WithRecursive(name, query string, parameters ...interface{}) *QueryBuilder {
+	b.recursive = true
+	return b.With(name, query, parameters...)
+}
+
+func (b *QueryBuilder) Select(query string, parameters ...interface{}) *QueryBuilder {
+	b.query = query
+	b.params = parameters
+	return b
+}
+
+func (b *QueryBuilder) Window(name, definition string) *QueryBuilder {
+	b.windows = append(b.windows, "`"+name+"` AS ("+definition+")")
+	return b
+}
+
+func (b *QueryBuilder) OrderBy(order ...string) *QueryBuilder {
+	b.orderBy = append(b.orderBy, order...)
+	return b
+}
+
+func (b *QueryBuilder) Limit(pager *Pager) *QueryBuilder {
+	b.pager = pager
+	return b
+}
+
+func (b *QueryBuilder) String() string {
+	query := ""
+	if len(b.with) > 0 {
+		query = "WITH "
+		if b.recursive {
+			query += "RECURSIVE "
+		}
+		query += strings.Join(b.with, ", ") + " "
+	}
+	query += b.query
+	if len(b.windows) > 0 {
+		query += " WINDOW " + strings.Join(b.windows, ", ")
+	}
+	if len(b.orderBy) > 0 {
+		query += " ORDER BY " + strings.Join(b.orderBy, ", ")
+	}
+	if b.pager != nil {
+		query += " LIMIT " + strconv.Itoa((b.pager.CurrentPage-1)*b.pager.PageSize) + "," + strconv.Itoa(b.pager.PageSize)
+	}
+	return query
+}
+
+func (b *QueryBuilder) GetParameters() []interface{} {
+	return append(append([]interface{}{}, b.withParams...), b.params...)
+}
+
+func (b *QueryBuilder) Query() (rows Rows, deferF func()) {
+	if b.query == "" {
+		panic(fmt.Errorf("missing select query in query builder"))
+	}
+	if (len(b.with) > 0 || len(b.windows) > 0) && b.db.GetPoolConfig().GetVersion() < 8 {
+		panic(fmt.Errorf("CTE and window functions require MySQL 8 in pool '%s'", b.db.GetPoolConfig().GetCode()))
+	}
+	return b.db.Query(b.String(), b.GetParameters()...)
+}
+
+func (b *QueryBuilder) Fill(results interface{}) {
+	value := reflect.ValueOf(results)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		panic(fmt.Errorf("results must be a pointer to slice of structs"))
+	}
+	slice := value.Elem()
+	elemType := slice.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPointer {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Errorf("results must be a pointer to slice of structs"))
+	}
+	rows, def := b.Query()
+	defer def()
+	columns := rows.Columns()
+	fields := make([][]int, len(columns))
+	for i, column := range columns {
+		fields[i] = queryBuilderField(structType, column)
+	}
+	slice.SetLen(0)
+	for rows.Next() {
+		row := reflect.New(structType).Elem()
+		pointers := make([]interface{}, len(columns))
+		for i, index := range fields {
+			if index == nil {
+				pointers[i] = new(interface{})
+			} else {
+				pointers[i] = row.FieldByIndex(index).Addr().Interface()
+			}
+		}
+		rows.Scan(pointers...)
+		if isPointer {
+			slice = reflect.Append(slice, row.Addr())
+		} else {
+			slice = reflect.Append(slice, row)
+		}
+	}
+	def()
+	value.Elem().Set(slice)
+}
+
+func queryBuilderField(structType reflect.Type, column string) []int {
+	normalized := strings.ToLower(strings.ReplaceAll(column, "_", ""))
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if strings.ToLower(field.Name) == normalized {
+			return field.Index
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package orm
+
+import (
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type queryBuilderEntity struct {
+	ORM
+	ID       uint
+	Category string
+	Score    int
+}
+
+type queryBuilderRow struct {
+	ID        uint
+	Category  string
+	Score     int
+	RowNumber int
+}
+
+func TestQueryBuilder(t *testing.T) {
+	var entity *queryBuilderEntity
+	engine := PrepareTables(t, &Registry{}, 8, entity)
+	engine.FlushMany(&queryBuilderEntity{Category: "a", Score: 10}, &queryBuilderEntity{Category: "a", Score: 30},
+		&queryBuilderEntity{Category: "b", Score: 20}, &queryBuilderEntity{Category: "a", Score: 5})
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+
+	builder := engine.GetMysql().NewQueryBuilder().
+		With("scored", "SELECT `ID`, `Category`, `Score` FROM `queryBuilderEntity` WHERE `Score` > ?", 6).
+		Select("SELECT `ID`, `Category`, `Score`, ROW_NUMBER() OVER w AS `row_number` FROM `scored`").
+		Window("w", "PARTITION BY `Category` ORDER BY `Score` DESC").
+		OrderBy("`Category`", "`row_number`")
+	assert.Equal(t, "WITH `scored` AS (SELECT `ID`, `Category`, `Score` FROM `queryBuilderEntity` WHERE `Score` > ?) "+
+		"SELECT `ID`, `Category`, `Score`, ROW_NUMBER() OVER w AS `row_number` FROM `scored` "+
+		"WINDOW `w` AS (PARTITION BY `Category` ORDER BY `Score` DESC) ORDER BY `Category`, `row_number`", builder.String())
+	var rows []*queryBuilderRow
+	builder.Fill(&rows)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, queryBuilderRow{ID: 2, Category: "a", Score: 30, RowNumber: 1}, *rows[0])
+	assert.Equal(t, queryBuilderRow{ID: 1, Category: "a", Score: 10, RowNumber: 2}, *rows[1])
+	assert.Equal(t, queryBuilderRow{ID: 3, Category: "b", Score: 20, RowNumber: 1}, *rows[2])
+	assert.Len(t, dbLogger.Entries, 1)
+
+	var values []queryBuilderRow
+	engine.GetMysql().NewQueryBuilder().
+		WithRecursive("seq", "SELECT 1 AS `ID` UNION ALL SELECT `ID` + 1 FROM `seq` WHERE `ID` < ?", 5).
+		Select("SELECT `ID` FROM `seq`").OrderBy("`ID` DESC").Limit(NewPager(1, 2)).Fill(&values)
+	assert.Len(t, values, 2)
+	assert.Equal(t, uint(5), values[0].ID)
+	assert.Equal(t, uint(4), values[1].ID)
+
+	engine = PrepareTables(t, &Registry{}, 5, entity)
+	assert.PanicsWithError(t, "CTE and window functions require MySQL 8 in pool 'default'", func() {
+		engine.GetMysql().NewQueryBuilder().With("a", "SELECT 1").Select("SELECT * FROM `a`").Fill(&values)
+	})
+}
@@ -0,0 +1,52 @@
+package orm
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+func (e *Engine) SetQueryTag(tag string) {
+	e.SetQueryTagAttribute("tag", tag)
+}
+
+func (e *Engine) SetQueryTraceID(traceParent string) {
+	e.SetQueryTagAttribute("traceparent", traceParent)
+}
+
+func (e *Engine) SetQueryTagAttribute(key string, value string) {
+	if e.queryTags == nil {
+		e.queryTags = make(map[string]string)
+	}
+	if value == "" {
+		delete(e.queryTags, key)
+	} else {
+		e.queryTags[key] = value
+	}
+	e.queryComment = buildQueryComment(e.queryTags)
+}
+
+func (e *Engine) GetQueryTags() map[string]string {
+	return e.queryTags
+}
+
+func buildQueryComment(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = escapeQueryTag(key) + "='" + escapeQueryTag(tags[key]) + "'"
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/ "
+}
+
+func escapeQueryTag(value string) string {
+	value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	return strings.ReplaceAll(value, "'", "\\'")
+}
@@ -0,0 +1,83 @@
+package orm
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+type RecoveryInfo struct {
+	Error   error
+	Stack   []byte
+	Source  string
+	Entity  string
+	Stream  string
+	Group   string
+	EventID string
+}
+
+type RecoveryHandler func(info *RecoveryInfo) (swallow bool)
+
+func (e *Engine) SetRecoveryHandler(handler RecoveryHandler) {
+	e.recoveryHandler = handler
+}
+
+func (b *eventConsumerBase) SetRecoveryHandler(handler RecoveryHandler) {
+	b.recoveryHandler = handler
+}
+
+func newRecoveryInfo(rec interface{}, source string) *RecoveryInfo {
+	asErr, is := rec.(error)
+	if !is {
+		asErr = fmt.Errorf("%v", rec)
+	}
+	return &RecoveryInfo{Error: asErr, Stack: debug.Stack(), Source: source}
+}
+
+func (f *flusher) flushWithRecovery(lazy bool, transaction bool) {
+	if f.engine.recoveryHandler == nil {
+		f.flushTrackedEntities(lazy, transaction)
+		return
+	}
+	entities := make([]string, 0)
+	unique := make(map[string]bool)
+	for _, entity := range f.trackedEntities {
+		name := initIfNeeded(f.engine.registry, entity).tableSchema.t.String()
+		if !unique[name] {
+			unique[name] = true
+			entities = append(entities, name)
+		}
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			info := newRecoveryInfo(rec, "flush")
+			info.Entity = strings.Join(entities, ",")
+			if !f.engine.recoveryHandler(info) {
+				panic(rec)
+			}
+			f.Clear()
+		}
+	}()
+	f.flushTrackedEntities(lazy, transaction)
+}
+
+func (r *eventsConsumer) handleRecovery(rec interface{}, events []Event) bool {
+	handler := r.recoveryHandler
+	if handler == nil {
+		handler = r.redis.engine.recoveryHandler
+	}
+	if handler == nil {
+		return false
+	}
+	info := newRecoveryInfo(rec, "consumer")
+	info.Group = r.group
+	for _, row := range events {
+		e := row.(*event)
+		if !e.ack && !e.skip {
+			info.Stream = e.stream
+			info.EventID = e.message.ID
+			break
+		}
+	}
+	return handler(info)
+}
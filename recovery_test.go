@@ -0,0 +1,94 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recoveryEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"unique=Name"`
+}
+
+func TestRecoveryHandlerFlush(t *testing.T) {
+	var entity *recoveryEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.Flush(&recoveryEntity{Name: "a"})
+
+	var info *RecoveryInfo
+	swallow := true
+	engine.SetRecoveryHandler(func(recovered *RecoveryInfo) bool {
+		info = recovered
+		return swallow
+	})
+	flusher := engine.NewFlusher()
+	flusher.Track(&recoveryEntity{Name: "a"})
+	flusher.Flush()
+	assert.NotNil(t, info)
+	assert.Equal(t, "flush", info.Source)
+	assert.Equal(t, "orm.recoveryEntity", info.Entity)
+	assert.True(t, errors.Is(info.Error, ErrDuplicateKey))
+	assert.NotEmpty(t, info.Stack)
+
+	info = nil
+	assert.Error(t, engine.FlushWithCheck(&recoveryEntity{Name: "a"}))
+	assert.Nil(t, info)
+
+	swallow = false
+	assert.PanicsWithError(t, "Duplicate entry 'a' for key 'Name'", func() {
+		engine.Clone().Flush(&recoveryEntity{Name: "a"})
+	})
+	assert.NotNil(t, info)
+}
+
+func TestRecoveryHandlerConsumer(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedisStream("recovery-stream", "default", []string{"test-group"})
+	engine := PrepareTables(t, registry, 5)
+	eventFlusher := engine.GetEventBroker().NewFlusher()
+	for i := 1; i <= 3; i++ {
+		eventFlusher.PublishMap("recovery-stream", EventAsMap{"name": fmt.Sprintf("a%d", i)})
+	}
+	eventFlusher.Flush()
+	first := engine.GetRedis().XRange("recovery-stream", "-", "+", 1)[0].ID
+
+	var info *RecoveryInfo
+	consumer := engine.GetEventBroker().Consumer("test-consumer", "test-group")
+	consumer.DisableLoop()
+	consumer.SetRecoveryHandler(func(recovered *RecoveryInfo) bool {
+		info = recovered
+		return true
+	})
+	consumer.Consume(context.Background(), 10, true, func(events []Event) {
+		panic(fmt.Errorf("test err"))
+	})
+	assert.NotNil(t, info)
+	assert.Equal(t, "consumer", info.Source)
+	assert.Equal(t, "recovery-stream", info.Stream)
+	assert.Equal(t, "test-group", info.Group)
+	assert.Equal(t, first, info.EventID)
+	assert.EqualError(t, info.Error, "test err")
+	assert.Equal(t, int64(3), engine.GetRedis().XInfoGroups("recovery-stream")[0].Pending)
+
+	info = nil
+	engine.SetRecoveryHandler(func(recovered *RecoveryInfo) bool {
+		info = recovered
+		return false
+	})
+	consumer = engine.GetEventBroker().Consumer("test-consumer-2", "test-group")
+	consumer.DisableLoop()
+	eventFlusher.PublishMap("recovery-stream", EventAsMap{"name": "a4"})
+	eventFlusher.Flush()
+	assert.PanicsWithError(t, "test err", func() {
+		consumer.Consume(context.Background(), 10, true, func(events []Event) {
+			panic(fmt.Errorf("test err"))
+		})
+	})
+	assert.NotNil(t, info)
+	assert.Equal(t, "recovery-stream", info.Stream)
+}
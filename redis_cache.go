@@ -663,6 +663,16 @@ func (r *RedisCache) XGroupDestroy(stream, group string) int64 {
 	return res
 }
 
+func (r *RedisCache) XGroupSetID(stream, group, id string) {
+	start := time.Now()
+	_, err := r.client.XGroupSetID(r.ctx, stream, group, id).Result()
+	if r.engine.hasStreamsLogger {
+		r.fillStreamsLogFields("[ORM][STREAMS][XGROUP]", start, "xgroup",
+			map[string]interface{}{"arg": "setid", "stream": stream, "group": group, "id": id}, err)
+	}
+	checkError(err)
+}
+
 func (r *RedisCache) XRead(a *redis.XReadArgs) []redis.XStream {
 	start := time.Now()
 	info, err := r.client.XRead(r.ctx, a).Result()
@@ -738,7 +748,11 @@ func (r *RedisCache) XPendingExt(a *redis.XPendingExtArgs) []redis.XPendingExt {
 }
 
 func (r *RedisCache) xAdd(stream string, values interface{}) (id string) {
-	a := &redis.XAddArgs{Stream: stream, ID: "*", Values: values}
+	return r.XAdd(stream, "*", values)
+}
+
+func (r *RedisCache) XAdd(stream, id string, values interface{}) string {
+	a := &redis.XAddArgs{Stream: stream, ID: id, Values: values}
 	start := time.Now()
 	id, err := r.client.XAdd(r.ctx, a).Result()
 	if r.engine.hasStreamsLogger {
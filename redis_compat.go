@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func (r *Registry) EnableRedisCompatibilityMode() {
+	r.redisCompatibility = true
+}
+
+func (e *Engine) HasRedisSearch(code ...string) bool {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if _, has := e.registry.redisServers[dbCode]; !has {
+		panic(&PoolNotRegisteredError{Type: "redis cache", Code: dbCode})
+	}
+	return !e.registry.redisSearchDisabled[dbCode]
+}
+
+func (r *validatedRegistry) detectRedisSearch() {
+	if !r.registry.redisCompatibility {
+		return
+	}
+	r.redisSearchDisabled = make(map[string]bool)
+	for pool, config := range r.redisServers {
+		if !hasRedisSearchModule(config.getClient()) {
+			r.redisSearchDisabled[pool] = true
+		}
+	}
+	for pool := range r.redisSearchIndexes {
+		if _, has := r.redisServers[pool]; !has || r.redisSearchDisabled[pool] {
+			r.redisSearchDisabled[pool] = true
+			delete(r.redisSearchIndexes, pool)
+		}
+	}
+	for _, schema := range r.tableSchemas {
+		if schema.redisSearchIndex != nil && r.redisSearchDisabled[schema.redisSearchIndex.RedisPool] {
+			schema.hasSearchCache = false
+		}
+	}
+}
+
+func hasRedisSearchModule(client *redis.Client) bool {
+	info, err := client.Info(context.Background(), "modules").Result()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(info, "\r\n") {
+		if strings.HasPrefix(line, "module:name=search") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type redisCompatEntity struct {
+	ORM  `orm:"redisCache;redisSearch=search"`
+	ID   uint
+	Name string `orm:"searchable"`
+}
+
+func TestRedisCompatibilityMode(t *testing.T) {
+	var entity *redisCompatEntity
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterRedis("localhost:6399", 0, "search")
+	registry.RegisterEntity(entity)
+	registry.EnableRedisCompatibilityMode()
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	assert.Len(t, validatedRegistry.GetRedisSearchIndices(), 0)
+	_, has := validatedRegistry.ListStreams()[redisSearchIndexerChannelName]
+	assert.False(t, has)
+	engine := validatedRegistry.CreateEngine()
+	assert.True(t, engine.HasRedisSearch())
+	assert.False(t, engine.HasRedisSearch("search"))
+	assert.Len(t, engine.GetRedisSearchIndexAlters(), 0)
+
+	engine.GetRedis().FlushDB()
+	for _, alter := range engine.GetAlters() {
+		alter.Exec()
+	}
+	validatedRegistry.GetTableSchemaForEntity(entity).TruncateTable(engine)
+	engine.Flush(&redisCompatEntity{Name: "a"})
+	entity = &redisCompatEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	engine.Delete(entity)
+
+	assert.PanicsWithError(t, "redis search is not available in redis pool 'search'", func() {
+		engine.GetRedisSearch("search")
+	})
+	assert.PanicsWithError(t, "redis search is not available in redis pool 'search'", func() {
+		var rows []*redisCompatEntity
+		engine.RedisSearch(&rows, NewRedisSearchQuery(), NewPager(1, 10))
+	})
+}
+
+func TestHasRedisSearchProbesEveryPool(t *testing.T) {
+	first, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer first.Close()
+	second, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer second.Close()
+	registry := &Registry{}
+	registry.RegisterRedis(first.Addr(), 0)
+	registry.RegisterRedis(second.Addr(), 0, "second")
+	registry.EnableRedisCompatibilityMode()
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	assert.False(t, engine.HasRedisSearch())
+	assert.False(t, engine.HasRedisSearch("second"))
+	assert.PanicsWithError(t, "unregistered redis cache pool 'missing'", func() {
+		engine.HasRedisSearch("missing")
+	})
+
+	registry = &Registry{}
+	registry.RegisterRedis(first.Addr(), 0)
+	validatedRegistry, err = registry.Validate()
+	assert.NoError(t, err)
+	assert.True(t, validatedRegistry.CreateEngine().HasRedisSearch())
+}
@@ -10,6 +10,9 @@ const (
 	commandDelete = iota
 	commandXAdd   = iota
 	commandHSet   = iota
+	commandZSet   = iota
+	commandIncr   = iota
+	commandSug    = iota
 )
 
 type RedisFlusher interface {
@@ -26,6 +29,10 @@ type redisFlusherCommands struct {
 	deletes []string
 	hSets   map[string][]interface{}
 	events  map[string][]EventAsMap
+	zKeys   []string
+	zArgs   []interface{}
+	incrs   map[string]int64
+	sugs    []redisSuggestCommand
 }
 
 type redisFlusher struct {
@@ -112,10 +119,75 @@ func (f *redisFlusher) HSet(redisPool, key string, values ...interface{}) {
 	commands.hSets[key] = values
 }
 
+func (f *redisFlusher) zUpdate(redisPool, key, operation, member, score string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pipelines == nil {
+		f.pipelines = make(map[string]*redisFlusherCommands)
+	}
+	commands, has := f.pipelines[redisPool]
+	if !has {
+		commands = &redisFlusherCommands{diffs: map[int]bool{commandZSet: true}}
+		f.pipelines[redisPool] = commands
+	}
+	commands.diffs[commandZSet] = true
+	commands.zKeys = append(commands.zKeys, key)
+	commands.zArgs = append(commands.zArgs, operation, member, score)
+}
+
+func (f *redisFlusher) incrBy(redisPool, key string, delta int64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pipelines == nil {
+		f.pipelines = make(map[string]*redisFlusherCommands)
+	}
+	commands, has := f.pipelines[redisPool]
+	if !has {
+		commands = &redisFlusherCommands{diffs: map[int]bool{commandIncr: true}}
+		f.pipelines[redisPool] = commands
+	}
+	commands.diffs[commandIncr] = true
+	if commands.incrs == nil {
+		commands.incrs = make(map[string]int64)
+	}
+	commands.incrs[key] += delta
+}
+
 func (f *redisFlusher) Flush() {
+	f.flush(nil)
+}
+
+func (f *redisFlusher) flush(phases *flusher) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 	for poolCode, commands := range f.pipelines {
+		phaseStart := phases.phaseStart()
+		if len(commands.zKeys) > 0 {
+			f.engine.GetRedis(poolCode).Eval(sortedIndexUpdateScript, commands.zKeys, commands.zArgs...)
+			delete(commands.diffs, commandZSet)
+		}
+		if len(commands.incrs) > 0 {
+			keys := make([]string, 0, len(commands.incrs))
+			deltas := make([]interface{}, 0, len(commands.incrs))
+			for key, delta := range commands.incrs {
+				keys = append(keys, key)
+				deltas = append(deltas, delta)
+			}
+			f.engine.GetRedis(poolCode).Eval(counterUpdateScript, keys, deltas...)
+			delete(commands.diffs, commandIncr)
+		}
+		if len(commands.sugs) > 0 {
+			redisCache := f.engine.GetRedis(poolCode)
+			for _, sug := range commands.sugs {
+				add := "1"
+				if sug.remove {
+					add = "0"
+				}
+				keys := []string{sug.key, sug.key + ":v:" + sug.value, sug.key + ":values"}
+				redisCache.Eval(searchSuggestScript, keys, sug.value, sug.payload, add)
+			}
+			delete(commands.diffs, commandSug)
+		}
 		usePool := commands.usePool || len(commands.diffs) > 1 || len(commands.events) > 1
 		if usePool {
 			p := f.engine.GetRedis(poolCode).PipeLine()
@@ -125,6 +197,15 @@ func (f *redisFlusher) Flush() {
 			for key, values := range commands.hSets {
 				p.HSet(key, values...)
 			}
+			if phases.hasStats() && len(commands.events) > 0 {
+				// stats need cache and stream commands in separate pipelines
+				if p.commands > 0 {
+					p.Exec()
+				}
+				phases.phaseEnd(flushPhaseCache, phaseStart)
+				phaseStart = phases.phaseStart()
+				p = f.engine.GetRedis(poolCode).PipeLine()
+			}
 			for stream, events := range commands.events {
 				for _, event := range events {
 					var v map[string]interface{} = event
@@ -142,6 +223,10 @@ func (f *redisFlusher) Flush() {
 					r.HSet(key, values...)
 				}
 			}
+			if len(commands.events) > 0 {
+				phases.phaseEnd(flushPhaseCache, phaseStart)
+				phaseStart = phases.phaseStart()
+			}
 			for stream, events := range commands.events {
 				for _, event := range events {
 					var v map[string]interface{} = event
@@ -149,6 +234,26 @@ func (f *redisFlusher) Flush() {
 				}
 			}
 		}
+		if len(commands.events) > 0 {
+			phases.phaseEnd(flushPhaseStreams, phaseStart)
+		} else {
+			phases.phaseEnd(flushPhaseCache, phaseStart)
+		}
 	}
 	f.pipelines = nil
 }
+
+func (f *redisFlusher) suggest(redisPool string, command redisSuggestCommand) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.pipelines == nil {
+		f.pipelines = make(map[string]*redisFlusherCommands)
+	}
+	commands, has := f.pipelines[redisPool]
+	if !has {
+		commands = &redisFlusherCommands{diffs: map[int]bool{commandSug: true}}
+		f.pipelines[redisPool] = commands
+	}
+	commands.diffs[commandSug] = true
+	commands.sugs = append(commands.sugs, command)
+}
@@ -230,6 +230,8 @@ func (r *RedisSearchResult) Value(field string) interface{} {
 	return nil
 }
 
+type RedisSearchHighlights map[string]string
+
 func (q *RedisSearchQuery) Query(query string) *RedisSearchQuery {
 	q.query = EscapeRedisSearchString(query)
 	return q
@@ -1165,6 +1167,9 @@ func getRedisSearchAlters(engine *Engine) (alters []RedisSearchIndexAlter) {
 		if r.GetPoolConfig().GetDB() > 0 {
 			continue
 		}
+		if engine.registry.registry.redisCompatibility && !hasRedisSearchModule(r.GetPoolConfig().getClient()) {
+			continue
+		}
 		info := r.Info("Modules")
 		lines := strings.Split(info, "\r\n")
 		hasModule := false
@@ -0,0 +1,102 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type RedisSearchComputeFunc func(entity Entity) interface{}
+
+type redisSearchComputedField struct {
+	field   RedisSearchIndexField
+	sources []string
+	compute RedisSearchComputeFunc
+}
+
+func (r *Registry) RegisterRedisSearchComputedField(entity Entity, field RedisSearchIndexField, compute RedisSearchComputeFunc, sources ...string) {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if field.Name == "" || compute == nil {
+		panic(fmt.Errorf("computed search field in entity '%s' requires name and compute function", t.String()))
+	}
+	if field.Type != redisSearchIndexFieldText && field.Type != redisSearchIndexFieldNumeric && field.Type != redisSearchIndexFieldTAG {
+		panic(fmt.Errorf("computed search field '%s' has unsupported type '%s'", field.Name, field.Type))
+	}
+	if len(sources) == 0 {
+		panic(fmt.Errorf("computed search field '%s' requires at least one source column", field.Name))
+	}
+	if r.redisSearchComputed == nil {
+		r.redisSearchComputed = make(map[string][]*redisSearchComputedField)
+	}
+	r.redisSearchComputed[t.String()] = append(r.redisSearchComputed[t.String()],
+		&redisSearchComputedField{field: field, sources: sources, compute: compute})
+}
+
+func checkRedisSearchComputedFields(computed []*redisSearchComputedField, columnMapping map[string]int, entityType reflect.Type) error {
+	for _, field := range computed {
+		if _, has := columnMapping[field.field.Name]; has {
+			return fmt.Errorf("computed search field '%s' in entity '%s' duplicates column name", field.field.Name, entityType.String())
+		}
+		for _, source := range field.sources {
+			if _, has := columnMapping[source]; !has {
+				return fmt.Errorf("unknown source column '%s' for computed search field '%s' in entity '%s'",
+					source, field.field.Name, entityType.String())
+			}
+		}
+	}
+	return nil
+}
+
+func (t *tableSchema) isRedisSearchComputedField(name string) bool {
+	for _, computed := range t.redisSearchComputed {
+		if computed.field.Name == name {
+			return true
+		}
+	}
+	for _, reference := range t.redisSearchReferences {
+		if reference.field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *redisSearchComputedField) isChanged(bind map[string]interface{}) bool {
+	for _, source := range c.sources {
+		if _, has := bind[source]; has {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *redisSearchComputedField) value(entity Entity) interface{} {
+	return redisSearchFieldValue(c.field, c.compute(entity))
+}
+
+func redisSearchFieldValue(field RedisSearchIndexField, val interface{}) interface{} {
+	if field.Type == redisSearchIndexFieldNumeric {
+		return defaultRedisSearchMapperNullableNumeric(val)
+	}
+	if val == nil {
+		return "NULL"
+	}
+	return EscapeRedisSearchString(fmt.Sprintf("%v", val))
+}
+
+func loadRedisSearchEntities(engine *Engine, entityType reflect.Type, ids []uint64) map[uint64]Entity {
+	entities := reflect.New(reflect.SliceOf(reflect.PtrTo(entityType)))
+	engine.LoadByIDs(ids, entities.Interface())
+	result := make(map[uint64]Entity, len(ids))
+	for i := 0; i < entities.Elem().Len(); i++ {
+		row := entities.Elem().Index(i)
+		if row.IsNil() {
+			continue
+		}
+		entity := row.Interface().(Entity)
+		result[entity.GetID()] = entity
+	}
+	return result
+}
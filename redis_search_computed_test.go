@@ -0,0 +1,82 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisSearchComputedEntity struct {
+	ORM       `orm:"redisSearch=search"`
+	ID        uint   `orm:"searchable"`
+	FirstName string `orm:"searchable"`
+	LastName  string
+	Score     uint
+}
+
+func TestRedisSearchComputedFields(t *testing.T) {
+	var entity *redisSearchComputedEntity
+	registry := &Registry{}
+	registry.RegisterRedisSearchComputedField(entity, RedisSearchIndexField{Type: "TEXT", Name: "FullName"},
+		func(e Entity) interface{} {
+			entity := e.(*redisSearchComputedEntity)
+			return entity.FirstName + " " + entity.LastName
+		}, "FirstName", "LastName")
+	registry.RegisterRedisSearchComputedField(entity, RedisSearchIndexField{Type: "NUMERIC", Name: "ScoreBucket", Sortable: true},
+		func(e Entity) interface{} {
+			return e.(*redisSearchComputedEntity).Score / 10
+		}, "Score")
+	engine := PrepareTables(t, registry, 5, entity)
+
+	flusher := engine.NewFlusher()
+	flusher.Track(&redisSearchComputedEntity{FirstName: "John", LastName: "Smith", Score: 15})
+	flusher.Track(&redisSearchComputedEntity{FirstName: "Adam", LastName: "Jones", Score: 27})
+	flusher.Flush()
+
+	query := NewRedisSearchQuery()
+	query.QueryField("FullName", "smith")
+	ids, total := engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, uint64(1), total)
+	assert.Equal(t, []uint64{1}, ids)
+
+	query = NewRedisSearchQuery()
+	query.FilterUint("ScoreBucket", 2)
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{2}, ids)
+
+	entity = &redisSearchComputedEntity{}
+	engine.LoadByID(1, entity)
+	entity.LastName = "Brown"
+	entity.Score = 21
+	engine.Flush(entity)
+	query = NewRedisSearchQuery()
+	query.QueryField("FullName", "brown")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{1}, ids)
+	query = NewRedisSearchQuery()
+	query.FilterUint("ScoreBucket", 2)
+	_, total = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, uint64(2), total)
+
+	indexName := engine.GetRedisSearch("search").ListIndices()[0]
+	engine.GetRedisSearch("search").ForceReindex(indexName)
+	indexer := NewBackgroundConsumer(engine)
+	indexer.DisableLoop()
+	indexer.blockTime = time.Millisecond
+	indexer.Digest(context.Background())
+	query = NewRedisSearchQuery()
+	query.QueryField("FullName", "jones")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{2}, ids)
+
+	registry = &Registry{}
+	registry.RegisterRedisSearchComputedField(entity, RedisSearchIndexField{Type: "TEXT", Name: "Other"},
+		func(e Entity) interface{} { return nil }, "Missing")
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 0, "search")
+	registry.RegisterEntity(entity)
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "unknown source column 'Missing' for computed search field 'Other' in entity 'orm.redisSearchComputedEntity'")
+}
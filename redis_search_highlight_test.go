@@ -0,0 +1,56 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisSearchHighlightEntity struct {
+	ORM         `orm:"redisSearch=search"`
+	ID          uint   `orm:"searchable"`
+	Title       string `orm:"searchable"`
+	Description string `orm:"searchable"`
+	Age         uint   `orm:"searchable;sortable"`
+}
+
+func TestRedisSearchWithHighlights(t *testing.T) {
+	var entity *redisSearchHighlightEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+
+	flusher := engine.NewFlusher()
+	flusher.Track(&redisSearchHighlightEntity{Title: "red apple", Description: "fresh fruit from the garden", Age: 1})
+	flusher.Track(&redisSearchHighlightEntity{Title: "green pear", Description: "an apple a day keeps the doctor away", Age: 2})
+	flusher.Track(&redisSearchHighlightEntity{Title: "yellow banana", Description: "tropical", Age: 3})
+	flusher.Flush()
+
+	var rows []*redisSearchHighlightEntity
+	query := NewRedisSearchQuery()
+	query.Query("apple").Highlight("Title").HighlightTags("<em>", "</em>").Sort("Age", false)
+	total, highlights := engine.RedisSearchWithHighlights(&rows, query, NewPager(1, 10))
+	assert.Equal(t, uint64(2), total)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "red apple", rows[0].Title)
+	assert.Equal(t, RedisSearchHighlights{"Title": "red <em>apple</em>"}, highlights[1])
+	assert.Equal(t, RedisSearchHighlights{"Title": "green pear"}, highlights[2])
+
+	query = NewRedisSearchQuery()
+	query.Query("apple").Highlight().FilterUint("Age", 2)
+	_, highlights = engine.RedisSearchWithHighlights(&rows, query, NewPager(1, 10))
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "an <b>apple</b> a day keeps the doctor away", highlights[2]["Description"])
+	assert.Equal(t, "green pear", highlights[2]["Title"])
+
+	query = NewRedisSearchQuery()
+	query.Query("apple").Summarize("Description").SummarizeOptions("...", 1, 2).FilterUint("Age", 2)
+	_, highlights = engine.RedisSearchWithHighlights(&rows, query, NewPager(1, 10))
+	assert.Len(t, highlights[2], 1)
+	assert.Contains(t, highlights[2]["Description"], "apple")
+
+	query = NewRedisSearchQuery()
+	query.Query("missing").Highlight()
+	total, highlights = engine.RedisSearchWithHighlights(&rows, query, NewPager(1, 10))
+	assert.Equal(t, uint64(0), total)
+	assert.Len(t, rows, 0)
+	assert.Len(t, highlights, 0)
+}
@@ -0,0 +1,18 @@
+package orm
+
+import (
+	"reflect"
+)
+
+type RedisSearchPredicate func(entity Entity) bool
+
+func (r *Registry) SetRedisSearchPredicate(entity Entity, predicate RedisSearchPredicate) {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if r.redisSearchPredicates == nil {
+		r.redisSearchPredicates = make(map[string]RedisSearchPredicate)
+	}
+	r.redisSearchPredicates[t.String()] = predicate
+}
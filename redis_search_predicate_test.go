@@ -0,0 +1,61 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisSearchPredicateEntity struct {
+	ORM    `orm:"redisSearch=search"`
+	ID     uint   `orm:"searchable"`
+	Name   string `orm:"searchable"`
+	Status string `orm:"enum=orm.TestEnum;required;searchable"`
+}
+
+func TestRedisSearchPredicate(t *testing.T) {
+	var entity *redisSearchPredicateEntity
+	registry := &Registry{}
+	registry.RegisterEnumStruct("orm.TestEnum", TestEnum)
+	registry.SetRedisSearchPredicate(entity, func(e Entity) bool {
+		return e.(*redisSearchPredicateEntity).Status == TestEnum.A
+	})
+	engine := PrepareTables(t, registry, 5, entity)
+
+	flusher := engine.NewFlusher()
+	flusher.Track(&redisSearchPredicateEntity{Name: "dog", Status: TestEnum.A})
+	flusher.Track(&redisSearchPredicateEntity{Name: "cat", Status: TestEnum.B})
+	flusher.Flush()
+
+	ids, total := engine.RedisSearchIds(entity, NewRedisSearchQuery(), NewPager(1, 10))
+	assert.Equal(t, uint64(1), total)
+	assert.Equal(t, []uint64{1}, ids)
+
+	entity = &redisSearchPredicateEntity{}
+	engine.LoadByID(2, entity)
+	entity.Status = TestEnum.A
+	engine.Flush(entity)
+	query := NewRedisSearchQuery()
+	query.QueryField("Name", "cat")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{2}, ids)
+
+	entity = &redisSearchPredicateEntity{}
+	engine.LoadByID(1, entity)
+	entity.Status = TestEnum.C
+	engine.Flush(entity)
+	ids, total = engine.RedisSearchIds(entity, NewRedisSearchQuery(), NewPager(1, 10))
+	assert.Equal(t, uint64(1), total)
+	assert.Equal(t, []uint64{2}, ids)
+
+	indexName := engine.GetRedisSearch("search").ListIndices()[0]
+	engine.GetRedisSearch("search").ForceReindex(indexName)
+	indexer := NewBackgroundConsumer(engine)
+	indexer.DisableLoop()
+	indexer.blockTime = time.Millisecond
+	indexer.Digest(context.Background())
+	ids, _ = engine.RedisSearchIds(entity, NewRedisSearchQuery(), NewPager(1, 10))
+	assert.Equal(t, []uint64{2}, ids)
+}
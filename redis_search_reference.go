@@ -0,0 +1,216 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const redisSearchReferenceChannelName = "orm-redis-search-reference-channel"
+
+type redisSearchReferenceField struct {
+	field          RedisSearchIndexField
+	reference      string
+	referenceField string
+	referenceType  reflect.Type
+}
+
+type redisSearchDependent struct {
+	schema    *tableSchema
+	reference string
+	fields    []*redisSearchReferenceField
+}
+
+func (r *Registry) RegisterRedisSearchReferenceField(entity Entity, reference, field string, indexField RedisSearchIndexField) {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if indexField.Name == "" {
+		indexField.Name = reference + "_" + field
+	}
+	if indexField.Type != redisSearchIndexFieldText && indexField.Type != redisSearchIndexFieldNumeric && indexField.Type != redisSearchIndexFieldTAG {
+		panic(fmt.Errorf("reference search field '%s' has unsupported type '%s'", indexField.Name, indexField.Type))
+	}
+	if r.redisSearchReferences == nil {
+		r.redisSearchReferences = make(map[string][]*redisSearchReferenceField)
+	}
+	r.redisSearchReferences[t.String()] = append(r.redisSearchReferences[t.String()],
+		&redisSearchReferenceField{field: indexField, reference: reference, referenceField: field})
+}
+
+func checkRedisSearchReferenceFields(references []*redisSearchReferenceField, columnMapping map[string]int, entityType reflect.Type) error {
+	entityInterface := reflect.TypeOf((*Entity)(nil)).Elem()
+	for _, reference := range references {
+		if _, has := columnMapping[reference.field.Name]; has {
+			return fmt.Errorf("reference search field '%s' in entity '%s' duplicates column name", reference.field.Name, entityType.String())
+		}
+		field, has := entityType.FieldByName(reference.reference)
+		if !has || field.Type.Kind() != reflect.Ptr || !field.Type.Implements(entityInterface) {
+			return fmt.Errorf("unknown reference '%s' for search field '%s' in entity '%s'",
+				reference.reference, reference.field.Name, entityType.String())
+		}
+		reference.referenceType = field.Type.Elem()
+		if _, has = reference.referenceType.FieldByName(reference.referenceField); !has {
+			return fmt.Errorf("unknown field '%s' in entity '%s' for search field '%s' in entity '%s'",
+				reference.referenceField, reference.referenceType.String(), reference.field.Name, entityType.String())
+		}
+	}
+	return nil
+}
+
+func initRedisSearchDependents(registry *validatedRegistry) error {
+	for _, schema := range registry.tableSchemas {
+		for _, reference := range schema.redisSearchReferences {
+			referenced, has := registry.tableSchemas[reference.referenceType]
+			if !has {
+				return fmt.Errorf("entity '%s' referenced by search field '%s' in entity '%s' is not registered",
+					reference.referenceType.String(), reference.field.Name, schema.t.String())
+			}
+			if _, has = referenced.columnMapping[reference.referenceField]; !has {
+				return fmt.Errorf("unknown column '%s' in entity '%s' for search field '%s' in entity '%s'",
+					reference.referenceField, referenced.t.String(), reference.field.Name, schema.t.String())
+			}
+			var dependent *redisSearchDependent
+			for _, row := range referenced.redisSearchDependents {
+				if row.schema == schema && row.reference == reference.reference {
+					dependent = row
+					break
+				}
+			}
+			if dependent == nil {
+				dependent = &redisSearchDependent{schema: schema, reference: reference.reference}
+				referenced.redisSearchDependents = append(referenced.redisSearchDependents, dependent)
+			}
+			dependent.fields = append(dependent.fields, reference)
+			referenced.dirtyFields[redisSearchReferenceChannelName] = append(referenced.dirtyFields[redisSearchReferenceChannelName],
+				reference.referenceField)
+		}
+	}
+	for _, schema := range registry.tableSchemas {
+		if len(schema.redisSearchDependents) > 0 && schema.hasFakeDelete {
+			schema.dirtyFields[redisSearchReferenceChannelName] = append(schema.dirtyFields[redisSearchReferenceChannelName], "FakeDelete")
+		}
+	}
+	return nil
+}
+
+func (r *redisSearchReferenceField) isChanged(bind map[string]interface{}) bool {
+	_, has := bind[r.reference]
+	return has
+}
+
+func (r *redisSearchReferenceField) getReferencedID(entity Entity) uint64 {
+	reference := reflect.ValueOf(entity).Elem().FieldByName(r.reference)
+	if reference.IsNil() {
+		return 0
+	}
+	return reference.Interface().(Entity).GetID()
+}
+
+func (r *redisSearchReferenceField) getReferenced(engine *Engine, entity Entity) Entity {
+	reference := reflect.ValueOf(entity).Elem().FieldByName(r.reference)
+	if reference.IsNil() {
+		return nil
+	}
+	referenced := reference.Interface().(Entity)
+	if referenced.IsLoaded() {
+		return referenced
+	}
+	loaded := reflect.New(r.referenceType).Interface().(Entity)
+	if !engine.LoadByID(referenced.GetID(), loaded) {
+		return nil
+	}
+	return loaded
+}
+
+func (r *redisSearchReferenceField) value(referenced Entity) interface{} {
+	if referenced == nil {
+		return redisSearchFieldValue(r.field, nil)
+	}
+	return redisSearchFieldValue(r.field, reflect.ValueOf(referenced).Elem().FieldByName(r.referenceField).Interface())
+}
+
+func loadRedisSearchReferences(engine *Engine, references []*redisSearchReferenceField, entities map[uint64]Entity) map[string]map[uint64]Entity {
+	result := make(map[string]map[uint64]Entity)
+	for _, reference := range references {
+		if _, has := result[reference.reference]; has {
+			continue
+		}
+		ids := make([]uint64, 0)
+		for _, entity := range entities {
+			id := reference.getReferencedID(entity)
+			if id > 0 {
+				ids = append(ids, id)
+			}
+		}
+		result[reference.reference] = make(map[uint64]Entity)
+		if len(ids) > 0 {
+			result[reference.reference] = loadRedisSearchEntities(engine, reference.referenceType, ids)
+		}
+	}
+	return result
+}
+
+func (r *BackgroundConsumer) handleRedisSearchReferenceEvent(event Event) {
+	dirty := EventDirtyEntity(event)
+	schema := dirty.TableSchema().(*tableSchema)
+	engine := r.engine.withEventTenant(dirty.Tenant())
+	var referenced Entity
+	if !dirty.Deleted() {
+		referenced = reflect.New(schema.t).Interface().(Entity)
+		if !engine.LoadByID(dirty.ID(), referenced) {
+			referenced = nil
+		}
+	}
+	for _, dependent := range schema.redisSearchDependents {
+		r.refreshRedisSearchDependent(engine, dependent, dirty.ID(), referenced)
+	}
+	event.Ack()
+}
+
+func (r *BackgroundConsumer) refreshRedisSearchDependent(engine *Engine, dependent *redisSearchDependent, id uint64, referenced Entity) {
+	schema := dependent.schema
+	values := make([]interface{}, 0)
+	for _, field := range dependent.fields {
+		values = append(values, field.field.Name, field.value(referenced))
+	}
+	/* #nosec */
+	query := "SELECT `ID` FROM `" + schema.tableName + "` WHERE `" + dependent.reference + "` = ? AND `ID` > ?"
+	if schema.hasFakeDelete {
+		query += " AND `FakeDelete` = 0"
+	}
+	query += " ORDER BY `ID` LIMIT 5000"
+	lastID := uint64(0)
+	for {
+		ids := make([]uint64, 0)
+		func() {
+			results, def := schema.GetMysql(engine).Query(query, id, lastID)
+			defer def()
+			for results.Next() {
+				results.Scan(&lastID)
+				ids = append(ids, lastID)
+			}
+		}()
+		if len(ids) == 0 {
+			return
+		}
+		var entities map[uint64]Entity
+		if schema.redisSearchPredicate != nil {
+			entities = loadRedisSearchEntities(engine, schema.t, ids)
+		}
+		for _, dependentID := range ids {
+			if schema.redisSearchPredicate != nil {
+				entity := entities[dependentID]
+				if entity == nil || !schema.redisSearchPredicate(entity) {
+					continue
+				}
+			}
+			r.redisFlusher.HSet(schema.searchCacheName, schema.redisSearchPrefix+strconv.FormatUint(dependentID, 10), values...)
+		}
+		r.redisFlusher.Flush()
+		if len(ids) < 5000 {
+			return
+		}
+	}
+}
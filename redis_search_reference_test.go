@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisSearchReferenceCategory struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Name     string
+	Priority uint
+}
+
+type redisSearchReferenceProduct struct {
+	ORM      `orm:"redisSearch=search"`
+	ID       uint   `orm:"searchable"`
+	Name     string `orm:"searchable"`
+	Category *redisSearchReferenceCategory
+}
+
+func TestRedisSearchReferenceFields(t *testing.T) {
+	var entity *redisSearchReferenceProduct
+	var category *redisSearchReferenceCategory
+	registry := &Registry{}
+	registry.RegisterRedisSearchReferenceField(entity, "Category", "Name", RedisSearchIndexField{Type: "TEXT"})
+	registry.RegisterRedisSearchReferenceField(entity, "Category", "Priority",
+		RedisSearchIndexField{Type: "NUMERIC", Name: "CategoryPriority", Sortable: true})
+	engine := PrepareTables(t, registry, 5, entity, category)
+
+	shoes := &redisSearchReferenceCategory{Name: "Shoes", Priority: 3}
+	hats := &redisSearchReferenceCategory{Name: "Hats", Priority: 7}
+	engine.FlushMany(shoes, hats)
+	flusher := engine.NewFlusher()
+	flusher.Track(&redisSearchReferenceProduct{Name: "Runner", Category: shoes})
+	flusher.Track(&redisSearchReferenceProduct{Name: "Boot", Category: &redisSearchReferenceCategory{ID: 1}})
+	flusher.Track(&redisSearchReferenceProduct{Name: "Cap", Category: hats})
+	flusher.Track(&redisSearchReferenceProduct{Name: "Other"})
+	flusher.Flush()
+
+	query := NewRedisSearchQuery()
+	query.QueryField("Category_Name", "shoes")
+	ids, total := engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, uint64(2), total)
+	assert.Equal(t, []uint64{1, 2}, ids)
+	query = NewRedisSearchQuery()
+	query.FilterUint("CategoryPriority", 7)
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{3}, ids)
+
+	product := &redisSearchReferenceProduct{}
+	engine.LoadByID(2, product)
+	product.Category = hats
+	engine.Flush(product)
+	query = NewRedisSearchQuery()
+	query.QueryField("Category_Name", "hats")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{2, 3}, ids)
+
+	consumer := NewBackgroundConsumer(engine)
+	consumer.DisableLoop()
+	consumer.blockTime = time.Millisecond
+	hats.Name = "Caps"
+	hats.Priority = 9
+	engine.Flush(hats)
+	consumer.Digest(context.Background())
+	query = NewRedisSearchQuery()
+	query.QueryField("Category_Name", "caps")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{2, 3}, ids)
+	query = NewRedisSearchQuery()
+	query.FilterUint("CategoryPriority", 9)
+	_, total = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, uint64(2), total)
+
+	indexName := engine.GetRedisSearch("search").ListIndices()[0]
+	engine.GetRedisSearch("search").ForceReindex(indexName)
+	consumer.Digest(context.Background())
+	query = NewRedisSearchQuery()
+	query.QueryField("Category_Name", "shoes")
+	ids, _ = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, []uint64{1}, ids)
+	query = NewRedisSearchQuery()
+	query.QueryField("Category_Name", "caps")
+	_, total = engine.RedisSearchIds(entity, query, NewPager(1, 10))
+	assert.Equal(t, uint64(2), total)
+
+	registry = &Registry{}
+	registry.RegisterRedisSearchReferenceField(entity, "Category", "Missing", RedisSearchIndexField{Type: "TEXT"})
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 0, "search")
+	registry.RegisterEntity(entity, category)
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "unknown field 'Missing' in entity 'orm.redisSearchReferenceCategory' for search field 'Category_Missing' in entity 'orm.redisSearchReferenceProduct'")
+}
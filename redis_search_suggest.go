@@ -0,0 +1,241 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	apexLog "github.com/apex/log"
+	"github.com/go-redis/redis/v8"
+)
+
+const searchSuggestScript = `
+if ARGV[3] == '1' then
+	redis.call('sadd', KEYS[2], ARGV[2])
+	redis.call('sadd', KEYS[3], ARGV[1])
+	return redis.call('FT.SUGADD', KEYS[1], ARGV[1], 1, 'PAYLOAD', ARGV[2])
+end
+redis.call('srem', KEYS[2], ARGV[2])
+local left = redis.call('srandmember', KEYS[2])
+if left then
+	return redis.call('FT.SUGADD', KEYS[1], ARGV[1], 1, 'PAYLOAD', left)
+end
+redis.call('srem', KEYS[3], ARGV[1])
+return redis.call('FT.SUGDEL', KEYS[1], ARGV[1])
+`
+
+const searchSuggestClearScript = `
+for _, key in ipairs(KEYS) do
+	for _, value in ipairs(redis.call('smembers', key .. ':values')) do
+		redis.call('del', key .. ':v:' .. value)
+	end
+	redis.call('del', key, key .. ':values')
+end
+return 1
+`
+
+type RedisSearchSuggestion struct {
+	Value   string
+	Score   float64
+	Payload string
+}
+
+type RedisSearchEntitySuggestion struct {
+	Value string
+	Score float64
+	ID    uint64
+}
+
+type redisSuggestCommand struct {
+	key     string
+	value   string
+	payload string
+	remove  bool
+}
+
+func (r *RedisSearch) SuggestAdd(key, value string, score float64, payload string) {
+	args := []interface{}{"FT.SUGADD", key, value, score}
+	if payload != "" {
+		args = append(args, "PAYLOAD", payload)
+	}
+	cmd := redis.NewIntCmd(r.ctx, args...)
+	start := time.Now()
+	err := r.redis.client.Process(r.ctx, cmd)
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("[ORM][REDIS-SEARCH][FT.SUGADD]", start, "ft_sugadd", 1,
+			apexLog.Fields{"Key": key, "Value": value}, err)
+	}
+	checkError(err)
+}
+
+func (r *RedisSearch) SuggestDel(key, value string) bool {
+	cmd := redis.NewIntCmd(r.ctx, "FT.SUGDEL", key, value)
+	start := time.Now()
+	err := r.redis.client.Process(r.ctx, cmd)
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("[ORM][REDIS-SEARCH][FT.SUGDEL]", start, "ft_sugdel", 1,
+			apexLog.Fields{"Key": key, "Value": value}, err)
+	}
+	checkError(err)
+	return cmd.Val() == 1
+}
+
+func (r *RedisSearch) SuggestGet(key, prefix string, fuzzy bool, max int) []*RedisSearchSuggestion {
+	args := []interface{}{"FT.SUGGET", key, prefix}
+	if fuzzy {
+		args = append(args, "FUZZY")
+	}
+	args = append(args, "WITHSCORES", "WITHPAYLOADS")
+	if max > 0 {
+		args = append(args, "MAX", max)
+	}
+	cmd := redis.NewSliceCmd(r.ctx, args...)
+	start := time.Now()
+	err := r.redis.client.Process(r.ctx, cmd)
+	if err == redis.Nil {
+		err = nil
+	}
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("[ORM][REDIS-SEARCH][FT.SUGGET]", start, "ft_sugget", 1,
+			apexLog.Fields{"Key": key, "Prefix": prefix}, err)
+	}
+	checkError(err)
+	res := cmd.Val()
+	suggestions := make([]*RedisSearchSuggestion, 0, len(res)/3)
+	for i := 0; i+2 < len(res); i += 3 {
+		suggestion := &RedisSearchSuggestion{Value: res[i].(string)}
+		suggestion.Score, _ = strconv.ParseFloat(res[i+1].(string), 64)
+		if res[i+2] != nil {
+			suggestion.Payload = res[i+2].(string)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+func (e *Engine) RedisSearchSuggest(entity Entity, field, prefix string, fuzzy bool, max int) []*RedisSearchEntitySuggestion {
+	schema := e.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	if !schema.hasSearchSuggest(field) {
+		panic(fmt.Errorf("missing `searchSuggest` tag for field %s", field))
+	}
+	rows := e.GetRedisSearch(schema.searchCacheName).SuggestGet(schema.getSearchSuggestKey(field), prefix, fuzzy, max)
+	suggestions := make([]*RedisSearchEntitySuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = &RedisSearchEntitySuggestion{Value: row.Value, Score: row.Score}
+		suggestions[i].ID, _ = strconv.ParseUint(row.Payload, 10, 64)
+	}
+	return suggestions
+}
+
+func initSearchSuggest(tags map[string]map[string]string, entityType reflect.Type, hasSearch bool) ([]string, error) {
+	fields := make([]string, 0)
+	for column, columnTags := range tags {
+		if _, has := columnTags["searchSuggest"]; !has {
+			continue
+		}
+		field, has := entityType.FieldByName(column)
+		if !has || field.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("searchSuggest tag requires string field, found '%s' in entity '%s'", column, entityType.String())
+		}
+		if !hasSearch {
+			return nil, fmt.Errorf("searchSuggest tag on field '%s' requires redisSearch in entity '%s'", column, entityType.String())
+		}
+		fields = append(fields, column)
+	}
+	return fields, nil
+}
+
+func (t *tableSchema) hasSearchSuggest(field string) bool {
+	for _, column := range t.searchSuggest {
+		if column == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tableSchema) getSearchSuggestKey(field string) string {
+	return t.redisSearchPrefix[0:5] + "_sug:" + field
+}
+
+func (t *tableSchema) clearSearchSuggestions(engine *Engine) {
+	if len(t.searchSuggest) == 0 {
+		return
+	}
+	keys := make([]string, len(t.searchSuggest))
+	for i, column := range t.searchSuggest {
+		keys[i] = t.getSearchSuggestKey(column)
+	}
+	engine.GetRedis(t.searchCacheName).Eval(searchSuggestClearScript, keys)
+}
+
+func (t *tableSchema) pushSearchSuggestions(f *redisFlusher, id uint64, data []interface{}) {
+	payload := strconv.FormatUint(id, 10)
+	for _, column := range t.searchSuggest {
+		value, _ := data[t.columnMapping[column]].(string)
+		if value != "" {
+			f.suggest(t.searchCacheName, redisSuggestCommand{key: t.getSearchSuggestKey(column), value: value, payload: payload})
+		}
+	}
+}
+
+func (f *flusher) addLazySearchSuggestions(schema *tableSchema, id uint64, row int, data []interface{}) {
+	if len(schema.searchSuggest) == 0 {
+		return
+	}
+	if schema.hasFakeDelete && isFakeDeleted(data[schema.columnMapping["FakeDelete"]]) {
+		return
+	}
+	if id > 0 {
+		schema.pushSearchSuggestions(f.getRedisFlusher(), id, data)
+		return
+	}
+	lazyMap := f.getLazyMap()
+	queries, _ := lazyMap["q"].([]interface{})
+	suggestions, _ := lazyMap["s"].([]interface{})
+	for _, column := range schema.searchSuggest {
+		value, _ := data[schema.columnMapping[column]].(string)
+		if value != "" {
+			suggestions = append(suggestions, map[string]interface{}{"P": schema.searchCacheName,
+				"K": schema.getSearchSuggestKey(column), "V": value, "Q": len(queries), "N": row})
+		}
+	}
+	if len(suggestions) > 0 {
+		lazyMap["s"] = suggestions
+	}
+}
+
+func (f *flusher) updateSearchSuggestions(schema *tableSchema, id uint64, before, after []interface{}) {
+	if len(schema.searchSuggest) == 0 || id == 0 {
+		return
+	}
+	if schema.hasFakeDelete {
+		if before != nil && isFakeDeleted(before[schema.columnMapping["FakeDelete"]]) {
+			before = nil
+		}
+		if after != nil && isFakeDeleted(after[schema.columnMapping["FakeDelete"]]) {
+			after = nil
+		}
+	}
+	payload := strconv.FormatUint(id, 10)
+	for _, column := range schema.searchSuggest {
+		oldValue, newValue := "", ""
+		if before != nil {
+			oldValue, _ = before[schema.columnMapping[column]].(string)
+		}
+		if after != nil {
+			newValue, _ = after[schema.columnMapping[column]].(string)
+		}
+		if oldValue == newValue && (before == nil) == (after == nil) {
+			continue
+		}
+		key := schema.getSearchSuggestKey(column)
+		if oldValue != "" {
+			f.getRedisFlusher().suggest(schema.searchCacheName, redisSuggestCommand{key: key, value: oldValue, payload: payload, remove: true})
+		}
+		if newValue != "" {
+			f.getRedisFlusher().suggest(schema.searchCacheName, redisSuggestCommand{key: key, value: newValue, payload: payload})
+		}
+	}
+}
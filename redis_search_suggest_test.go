@@ -0,0 +1,111 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisSearchSuggestEntity struct {
+	ORM        `orm:"redisSearch=search"`
+	ID         uint
+	Name       string `orm:"searchable;searchSuggest"`
+	Other      string
+	FakeDelete bool
+}
+
+type redisSearchSuggestInvalidEntity struct {
+	ORM  `orm:"redisSearch=search"`
+	ID   uint
+	Age  uint `orm:"searchable;searchSuggest"`
+	Name string
+}
+
+func TestRedisSearchSuggest(t *testing.T) {
+	var entity *redisSearchSuggestEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+
+	flusher := engine.NewFlusher()
+	flusher.Track(&redisSearchSuggestEntity{Name: "apple"})
+	flusher.Track(&redisSearchSuggestEntity{Name: "apricot"})
+	flusher.Track(&redisSearchSuggestEntity{Name: "banana"})
+	flusher.Flush()
+
+	suggestions := engine.RedisSearchSuggest(entity, "Name", "ap", false, 10)
+	assert.Len(t, suggestions, 2)
+	values := map[string]uint64{}
+	for _, suggestion := range suggestions {
+		values[suggestion.Value] = suggestion.ID
+		assert.Greater(t, suggestion.Score, float64(0))
+	}
+	assert.Equal(t, map[string]uint64{"apple": 1, "apricot": 2}, values)
+
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "bnana", true, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "banana", suggestions[0].Value)
+	assert.Equal(t, uint64(3), suggestions[0].ID)
+
+	entity = &redisSearchSuggestEntity{}
+	engine.LoadByID(2, entity)
+	entity.Name = "avocado"
+	engine.Flush(entity)
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "ap", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "apple", suggestions[0].Value)
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "avo", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, uint64(2), suggestions[0].ID)
+
+	entity = &redisSearchSuggestEntity{}
+	engine.LoadByID(1, entity)
+	engine.Delete(entity)
+	assert.Len(t, engine.RedisSearchSuggest(entity, "Name", "ap", false, 10), 0)
+	entity = &redisSearchSuggestEntity{}
+	engine.LoadByID(3, entity)
+	engine.ForceDelete(entity)
+	assert.Len(t, engine.RedisSearchSuggest(entity, "Name", "ban", false, 10), 0)
+
+	engine.FlushMany(&redisSearchSuggestEntity{Name: "cherry"}, &redisSearchSuggestEntity{Name: "cherry"})
+	entity = &redisSearchSuggestEntity{}
+	engine.LoadByID(4, entity)
+	engine.ForceDelete(entity)
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "che", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, uint64(5), suggestions[0].ID)
+
+	engine.FlushLazy(&redisSearchSuggestEntity{Name: "date"})
+	assert.Len(t, engine.RedisSearchSuggest(entity, "Name", "da", false, 10), 0)
+	consumer := NewBackgroundConsumer(engine)
+	consumer.DisableLoop()
+	consumer.blockTime = time.Millisecond
+	consumer.Digest(context.Background())
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "da", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, uint64(6), suggestions[0].ID)
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	engine.GetRedis("search").Del(schema.getSearchSuggestKey("Name"))
+	assert.Len(t, engine.RedisSearchSuggest(entity, "Name", "avo", false, 10), 0)
+	engine.GetRedisSearch("search").ForceReindex(engine.GetRedisSearch("search").ListIndices()[0])
+	consumer.Digest(context.Background())
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "avo", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, uint64(2), suggestions[0].ID)
+	suggestions = engine.RedisSearchSuggest(entity, "Name", "che", false, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, uint64(5), suggestions[0].ID)
+	assert.Len(t, engine.RedisSearchSuggest(entity, "Name", "ap", false, 10), 0)
+
+	assert.PanicsWithError(t, "missing `searchSuggest` tag for field Other", func() {
+		engine.RedisSearchSuggest(entity, "Other", "a", false, 10)
+	})
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 0, "search")
+	registry.RegisterEntity(&redisSearchSuggestInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "searchSuggest tag requires string field, found 'Age' in entity 'orm.redisSearchSuggestInvalidEntity'")
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,18 +21,42 @@ import (
 )
 
 type Registry struct {
-	mysqlPools         map[string]MySQLPoolConfig
-	clickHouseClients  map[string]*ClickHouseConfig
-	localCachePools    map[string]LocalCachePoolConfig
-	redisPools         map[string]RedisPoolConfig
-	elasticServers     map[string]*ElasticConfig
-	entities           map[string]reflect.Type
-	redisSearchIndices map[string]map[string]*RedisSearchIndex
-	elasticIndices     map[string]map[string]ElasticIndexDefinition
-	enums              map[string]Enum
-	defaultEncoding    string
-	redisStreamGroups  map[string]map[string]map[string]bool
-	redisStreamPools   map[string]string
+	mysqlPools            map[string]MySQLPoolConfig
+	clickHouseClients     map[string]*ClickHouseConfig
+	localCachePools       map[string]LocalCachePoolConfig
+	redisPools            map[string]RedisPoolConfig
+	elasticServers        map[string]*ElasticConfig
+	entities              map[string]reflect.Type
+	redisSearchIndices    map[string]map[string]*RedisSearchIndex
+	elasticIndices        map[string]map[string]ElasticIndexDefinition
+	enums                 map[string]Enum
+	defaultEncoding       string
+	redisStreamGroups     map[string]map[string]map[string]bool
+	redisStreamPools      map[string]string
+	redisStreamsMutex     sync.RWMutex
+	embeddedPrefixes      map[string]string
+	timeZone              *time.Location
+	clock                 Clock
+	rowPolicies           map[string]RowPolicy
+	fieldPermissions      map[string]map[string]FieldPermissions
+	lazyFlushOptions      *LazyFlushOptions
+	idGenerators          map[string]IDGenerator
+	maxPageSize           int
+	namingStrategy        NamingStrategy
+	tenantPrefixes        map[string]string
+	onlineAlterRows       uint64
+	alterExecutor         AlterExecutor
+	ddlPolicy             DDLPolicy
+	ddlAuditStream        string
+	redisCompatibility    bool
+	circuitBreakers       map[string]circuitBreakerConfig
+	maxSearchRows         int
+	maxBindSize           int
+	fieldTypes            map[reflect.Type]*FieldTypeDefinition
+	virtualFields         map[string]map[string]VirtualFieldComputer
+	redisSearchComputed   map[string][]*redisSearchComputedField
+	redisSearchPredicates map[string]RedisSearchPredicate
+	redisSearchReferences map[string][]*redisSearchReferenceField
 }
 
 func NewRegistry() *Registry {
@@ -51,7 +76,13 @@ func (r *Registry) Validate() (ValidatedRegistry, error) {
 		registry.mySQLServers = make(map[string]MySQLPoolConfig)
 	}
 	for k, v := range r.mysqlPools {
-		db, err := sql.Open("mysql", v.GetDataSourceURI())
+		var db *sql.DB
+		var err error
+		if v.(*mySQLPoolConfig).credentials != nil {
+			db, err = openWithCredentials(v.(*mySQLPoolConfig).driverName, v.GetDataSourceURI(), v.(*mySQLPoolConfig).credentials)
+		} else {
+			db, err = sql.Open(v.(*mySQLPoolConfig).driverName, v.GetDataSourceURI())
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -99,7 +130,8 @@ func (r *Registry) Validate() (ValidatedRegistry, error) {
 		db.SetMaxIdleConns(maxLimit)
 		db.SetConnMaxLifetime(time.Duration(waitTimeout) * time.Second)
 		v.(*mySQLPoolConfig).client = db
-		registry.mySQLServers[k] = v
+		pool := *v.(*mySQLPoolConfig)
+		registry.mySQLServers[k] = &pool
 	}
 	if registry.clickHouseClients == nil {
 		registry.clickHouseClients = make(map[string]*ClickHouseConfig)
@@ -110,7 +142,8 @@ func (r *Registry) Validate() (ValidatedRegistry, error) {
 			return nil, err
 		}
 		v.db = db
-		registry.clickHouseClients[k] = v
+		pool := *v
+		registry.clickHouseClients[k] = &pool
 	}
 
 	if registry.localCacheServers == nil {
@@ -169,6 +202,12 @@ func (r *Registry) Validate() (ValidatedRegistry, error) {
 			hasLog = true
 		}
 	}
+	err := initRedisSearchDependents(registry)
+	if err != nil {
+		return nil, err
+	}
+	registry.detectRedisSearch()
+	registry.initCircuitBreakers()
 	_, has := r.redisStreamPools[lazyChannelName]
 	if !has {
 		r.RegisterRedisStream(lazyChannelName, "default", []string{asyncConsumerGroupName})
@@ -185,6 +224,12 @@ func (r *Registry) Validate() (ValidatedRegistry, error) {
 			r.RegisterRedisStream(redisSearchIndexerChannelName, "default", []string{asyncConsumerGroupName})
 		}
 	}
+	if len(r.redisSearchReferences) > 0 {
+		_, has = r.redisStreamPools[redisSearchReferenceChannelName]
+		if !has {
+			r.RegisterRedisStream(redisSearchReferenceChannelName, "default", []string{asyncConsumerGroupName})
+		}
+	}
 	registry.redisStreamGroups = r.redisStreamGroups
 	registry.redisStreamPools = r.redisStreamPools
 	engine := registry.CreateEngine()
@@ -214,6 +259,29 @@ func (r *Registry) RegisterEntity(entity ...Entity) {
 	}
 }
 
+func (r *Registry) SetTimeZone(location *time.Location) {
+	r.timeZone = location
+}
+
+func (r *Registry) SetClock(clock Clock) {
+	r.clock = clock
+}
+
+func (r *Registry) SetMaxPageSize(size int) {
+	r.maxPageSize = size
+}
+
+func (r *Registry) RegisterEmbedded(val interface{}, prefix string) {
+	if r.embeddedPrefixes == nil {
+		r.embeddedPrefixes = make(map[string]string)
+	}
+	t := reflect.TypeOf(val)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r.embeddedPrefixes[t.String()] = prefix
+}
+
 func (r *Registry) RegisterRedisSearchIndex(index ...*RedisSearchIndex) {
 	if r.redisSearchIndices == nil {
 		r.redisSearchIndices = make(map[string]map[string]*RedisSearchIndex)
@@ -266,7 +334,11 @@ func (r *Registry) RegisterEnum(code string, values []string, defaultValue ...st
 }
 
 func (r *Registry) RegisterMySQLPool(dataSourceName string, code ...string) {
-	r.registerSQLPool(dataSourceName, code...)
+	r.registerSQLPool("mysql", dataSourceName, code...)
+}
+
+func (r *Registry) RegisterMySQLPoolWithDriver(driverName, dataSourceName string, code ...string) {
+	r.registerSQLPool(driverName, dataSourceName, code...)
 }
 
 func (r *Registry) RegisterElastic(url string, code ...string) {
@@ -297,6 +369,13 @@ func (r *Registry) RegisterRedis(address string, db int, code ...string) {
 	r.registerRedis(client, code, address, db)
 }
 
+func (r *Registry) RegisterRedisWithOptions(options *redis.Options, code ...string) {
+	if options.MaxConnAge == 0 {
+		options.MaxConnAge = time.Minute * 2
+	}
+	r.registerRedis(redis.NewClient(options), code, options.Addr, options.DB)
+}
+
 func (r *Registry) RegisterRedisSentinel(masterName string, db int, sentinels []string, code ...string) {
 	client := redis.NewFailoverClient(&redis.FailoverOptions{
 		MasterName:    masterName,
@@ -327,7 +406,7 @@ func (r *Registry) RegisterRedisStream(name string, redisPool string, groups []s
 	r.redisStreamGroups[redisPool][name] = groupsMap
 }
 
-func (r *Registry) registerSQLPool(dataSourceName string, code ...string) {
+func (r *Registry) registerSQLPool(driverName, dataSourceName string, code ...string) {
 	dbCode := "default"
 	if len(code) > 0 {
 		dbCode = code[0]
@@ -337,7 +416,7 @@ func (r *Registry) registerSQLPool(dataSourceName string, code ...string) {
 		and = "&"
 	}
 	dataSourceName += and + "multiStatements=true"
-	db := &mySQLPoolConfig{code: dbCode, dataSourceName: dataSourceName}
+	db := &mySQLPoolConfig{code: dbCode, dataSourceName: dataSourceName, driverName: driverName}
 	if r.mysqlPools == nil {
 		r.mysqlPools = make(map[string]MySQLPoolConfig)
 	}
@@ -411,10 +490,11 @@ type RedisPoolConfig interface {
 }
 
 type redisCacheConfig struct {
-	code    string
-	client  *redis.Client
-	db      int
-	address string
+	code        string
+	client      *redis.Client
+	db          int
+	address     string
+	credentials *credentials
 }
 
 func (p *redisCacheConfig) GetCode() string {
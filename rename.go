@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func initRenames(tags map[string]map[string]string, columnMapping map[string]int, entityType reflect.Type) (map[string]string, error) {
+	renamed := make(map[string]string)
+	for field, values := range tags {
+		old, has := values["renamedFrom"]
+		if !has || field == "ORM" {
+			continue
+		}
+		_, isColumn := columnMapping[field]
+		if !isColumn || old == "" || old == "true" {
+			return nil, fmt.Errorf("invalid renamedFrom '%s' for field '%s' in entity '%s'", old, field, entityType.String())
+		}
+		if _, exists := columnMapping[old]; exists {
+			return nil, fmt.Errorf("renamedFrom '%s' for field '%s' in entity '%s' is still defined as field", old, field, entityType.String())
+		}
+		renamed[field] = old
+	}
+	return renamed, nil
+}
+
+func (tableSchema *tableSchema) addRenamedInsertColumns(columns []string) []string {
+	for _, column := range columns {
+		if old, has := tableSchema.renamedColumns[column]; has {
+			columns = append(columns, old)
+		}
+	}
+	return columns
+}
+
+func (tableSchema *tableSchema) getInsertBindValue(bind Bind, column string) interface{} {
+	if value, has := bind[column]; has {
+		return value
+	}
+	for current, old := range tableSchema.renamedColumns {
+		if old == column {
+			return bind[current]
+		}
+	}
+	return nil
+}
+
+func (tableSchema *tableSchema) addRenamedUpdateColumns(updateBind map[string]string) {
+	for current, old := range tableSchema.renamedColumns {
+		if value, has := updateBind[current]; has {
+			updateBind[old] = value
+		}
+	}
+}
+
+func (tableSchema *tableSchema) getRenamedCopyAssignment(column string) string {
+	if old, has := tableSchema.renamedColumns[column]; has {
+		return ",`" + old + "` = `" + column + "`"
+	}
+	return ""
+}
+
+func (tableSchema *tableSchema) addRenamedColumnDefinitions(columns [][2]string) [][2]string {
+	if len(tableSchema.renamedColumns) == 0 {
+		return columns
+	}
+	result := make([][2]string, 0, len(columns)+len(tableSchema.renamedColumns))
+	for _, column := range columns {
+		result = append(result, column)
+		if old, has := tableSchema.renamedColumns[column[0]]; has {
+			result = append(result, [2]string{old, "`" + old + "`" + column[1][len(column[0])+2:]})
+		}
+	}
+	return result
+}
+
+func getRenameTableAlter(engine *Engine, tableSchema *tableSchema) (Alter, bool) {
+	if tableSchema.renamedTable == "" {
+		return Alter{}, false
+	}
+	pool := engine.GetMysql(tableSchema.mysqlPoolName)
+	if isView(pool, tableSchema.renamedTable) {
+		return Alter{}, false
+	}
+	var skip string
+	if !pool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", tableSchema.renamedTable)), &skip) {
+		return Alter{}, false
+	}
+	database := pool.GetPoolConfig().GetDatabase()
+	/* #nosec */
+	sql := fmt.Sprintf("RENAME TABLE `%s`.`%s` TO `%s`.`%s`;\nCREATE VIEW `%s`.`%s` AS SELECT * FROM `%s`.`%s`;",
+		database, tableSchema.renamedTable, database, tableSchema.tableName, database, tableSchema.renamedTable, database, tableSchema.tableName)
+	return Alter{SQL: sql, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine}, true
+}
+
+func isView(pool *DB, tableName string) bool {
+	var tableType string
+	pool.QueryRow(NewWhere("SELECT `TABLE_TYPE` FROM `information_schema`.`TABLES` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` = ?",
+		pool.GetPoolConfig().GetDatabase(), tableName), &tableType)
+	return tableType == "VIEW"
+}
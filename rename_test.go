@@ -0,0 +1,126 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renameColumnEntity struct {
+	ORM
+	ID      uint
+	Name    string `orm:"renamedFrom=Title"`
+	Age     int
+	Counter int `orm:"renamedFrom=Hits"`
+}
+
+type renameTableEntity struct {
+	ORM  `orm:"renamedFrom=renameTableOld"`
+	ID   uint
+	Name string
+}
+
+type renameInvalidEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"renamedFrom"`
+}
+
+func TestRenameColumnDualWrite(t *testing.T) {
+	var entity *renameColumnEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	entity = &renameColumnEntity{Name: "Tom", Age: 10}
+	engine.Flush(entity)
+	var name, title string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name`, `Title` FROM `renameColumnEntity` WHERE `ID` = 1"), &name, &title)
+	assert.Equal(t, "Tom", name)
+	assert.Equal(t, "Tom", title)
+
+	entity.Name = "John"
+	engine.Flush(entity)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name`, `Title` FROM `renameColumnEntity` WHERE `ID` = 1"), &name, &title)
+	assert.Equal(t, "John", name)
+	assert.Equal(t, "John", title)
+
+	entity.Age = 12
+	engine.Flush(entity)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Title` FROM `renameColumnEntity` WHERE `ID` = 1"), &title)
+	assert.Equal(t, "John", title)
+
+	var hits int
+	engine.IncrementField(entity, "Counter", 3)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Hits` FROM `renameColumnEntity` WHERE `ID` = 1"), &hits)
+	assert.Equal(t, 3, hits)
+	engine.IncrementFieldLazy(entity, "Counter", 2)
+	consumer := NewBackgroundConsumer(engine)
+	consumer.DisableLoop()
+	consumer.blockTime = time.Millisecond
+	consumer.Digest(context.Background())
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Hits` FROM `renameColumnEntity` WHERE `ID` = 1"), &hits)
+	assert.Equal(t, 5, hits)
+
+	engine.UpdateByQuery(NewWhere("`ID` = ?", 1), &renameColumnEntity{}, Bind{"Name": "Bulk"}, 10)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Title` FROM `renameColumnEntity` WHERE `ID` = 1"), &title)
+	assert.Equal(t, "Bulk", title)
+
+	duplicate := &renameColumnEntity{ID: 1, Name: "Tom"}
+	duplicate.SetOnDuplicateKeyUpdate(Bind{"Name": "Duplicate"})
+	engine.Flush(duplicate)
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name`, `Title` FROM `renameColumnEntity` WHERE `ID` = 1"), &name, &title)
+	assert.Equal(t, "Duplicate", name)
+	assert.Equal(t, "Duplicate", title)
+
+	engine.GetMysql().Exec("ALTER TABLE `renameColumnEntity` DROP COLUMN `Name`")
+	engine.GetMysql().Exec("UPDATE `renameColumnEntity` SET `Title` = 'Old' WHERE `ID` = 1")
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.Contains(t, alters[0].SQL, "ADD COLUMN `Name`")
+	assert.Contains(t, alters[0].SQL, "UPDATE `test`.`renameColumnEntity` SET `Name` = `Title`;")
+	alters[0].Exec()
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `renameColumnEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "Old", name)
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&renameInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid renamedFrom 'true' for field 'Name' in entity 'orm.renameInvalidEntity'")
+}
+
+func TestRenameTable(t *testing.T) {
+	var entity *renameTableEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	db := engine.GetMysql()
+	db.Exec("DROP VIEW IF EXISTS `renameTableOld`")
+	db.Exec("DROP TABLE IF EXISTS `renameTableOld`")
+	db.Exec("CREATE TABLE `renameTableOld` LIKE `renameTableEntity`")
+	db.Exec("DROP TABLE `renameTableEntity`")
+	db.Exec("INSERT INTO `renameTableOld` (`ID`, `Name`) VALUES (1, 'Tom')")
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.Equal(t, "RENAME TABLE `test`.`renameTableOld` TO `test`.`renameTableEntity`;\n"+
+		"CREATE VIEW `test`.`renameTableOld` AS SELECT * FROM `test`.`renameTableEntity`;", alters[0].SQL)
+	alters[0].Exec()
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	entity = &renameTableEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Tom", entity.Name)
+	db.Exec("INSERT INTO `renameTableOld` (`ID`, `Name`) VALUES (2, 'John')")
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, "John", entity.Name)
+	db.Exec("DROP VIEW `renameTableOld`")
+}
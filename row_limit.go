@@ -0,0 +1,39 @@
+package orm
+
+func (r *Registry) SetMaxSearchRows(rows int) {
+	r.maxSearchRows = rows
+}
+
+func (e *Engine) SetMaxSearchRows(rows int) {
+	e.maxSearchRows = rows
+}
+
+func WithMaxSearchRows(rows int) EngineOption {
+	return func(engine *Engine) {
+		engine.SetMaxSearchRows(rows)
+	}
+}
+
+func (where *Where) WithMaxRows(rows int) *Where {
+	where.maxRows = rows
+	return where
+}
+
+func getMaxSearchRows(engine *Engine, where *Where) int {
+	if where != nil && where.maxRows != 0 {
+		return where.maxRows
+	}
+	if engine.maxSearchRows != 0 {
+		return engine.maxSearchRows
+	}
+	if engine.registry != nil && engine.registry.registry != nil {
+		return engine.registry.registry.maxSearchRows
+	}
+	return 0
+}
+
+func checkMaxSearchRows(schema *tableSchema, limit, rows int) {
+	if limit > 0 && rows >= limit {
+		panic(&RowLimitError{Entity: schema.t.String(), Limit: limit})
+	}
+}
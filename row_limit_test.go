@@ -0,0 +1,51 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rowLimitEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestMaxSearchRows(t *testing.T) {
+	registry := &Registry{}
+	registry.SetMaxSearchRows(3)
+	engine := PrepareTables(t, registry, 5, &rowLimitEntity{})
+	for i := 0; i < 5; i++ {
+		engine.Flush(&rowLimitEntity{Name: "a"})
+	}
+
+	var rows []*rowLimitEntity
+	engine.Search(NewWhere("1"), NewPager(1, 3), &rows)
+	assert.Len(t, rows, 3)
+	assert.PanicsWithError(t, "search for entity 'orm.rowLimitEntity' exceeded limit of 3 rows, use pager to load rows in smaller pages or raise limit with Where.WithMaxRows", func() {
+		engine.Search(NewWhere("1"), nil, &rows)
+	})
+	err := engine.SearchE(NewWhere("1"), nil, &rows)
+	assert.True(t, errors.Is(err, ErrRowLimit))
+	var rowLimitError *RowLimitError
+	assert.True(t, errors.As(err, &rowLimitError))
+	assert.Equal(t, 3, rowLimitError.Limit)
+	assert.Panics(t, func() {
+		engine.SearchRaw(NewWhere("SELECT * FROM `rowLimitEntity`"), &rows)
+	})
+
+	engine.Search(NewWhere("1").WithMaxRows(10), nil, &rows)
+	assert.Len(t, rows, 5)
+	engine.SearchRaw(NewWhere("SELECT * FROM `rowLimitEntity`").WithMaxRows(-1), &rows)
+	assert.Len(t, rows, 5)
+
+	unlimited := engine.WithOptions(WithMaxSearchRows(-1))
+	unlimited.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 5)
+	assert.Len(t, unlimited.Clone().SearchIDs(NewWhere("1"), nil, &rowLimitEntity{}), 5)
+	assert.PanicsWithError(t, "search for entity 'orm.rowLimitEntity' exceeded limit of 1 rows, use pager to load rows in smaller pages or raise limit with Where.WithMaxRows", func() {
+		unlimited.Clone().Search(NewWhere("1").WithMaxRows(1), nil, &rows)
+	})
+}
@@ -0,0 +1,145 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type RowPolicy func(engine *Engine) *Where
+
+type RowPolicyError struct {
+	Entity string
+	ID     uint64
+}
+
+func (err *RowPolicyError) Error() string {
+	return fmt.Sprintf("row policy violation for entity '%s' [%d]", err.Entity, err.ID)
+}
+
+func (r *Registry) RegisterRowPolicy(entity Entity, policy RowPolicy) {
+	if r.rowPolicies == nil {
+		r.rowPolicies = make(map[string]RowPolicy)
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r.rowPolicies[t.String()] = policy
+}
+
+func (tableSchema *tableSchema) getRowPolicy(engine *Engine) *Where {
+	if tableSchema.rowPolicy == nil {
+		return nil
+	}
+	return tableSchema.rowPolicy(engine)
+}
+
+func applyRowPolicy(engine *Engine, schema *tableSchema, where *Where) *Where {
+	policy := schema.getRowPolicy(engine)
+	if policy == nil {
+		return where
+	}
+	condition, tail := splitWhereTail(where.query)
+	if strings.TrimSpace(condition) == "" {
+		condition = "1"
+	}
+	combined := &Where{query: "(" + policy.query + ") AND (" + strings.TrimSpace(condition) + ")" + tail, lock: where.lock}
+	combined.merge(policy)
+	combined.merge(where)
+	return combined
+}
+
+func splitWhereTail(query string) (condition, tail string) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		char := query[i]
+		if quote != 0 {
+			if char == '\\' && quote != '`' {
+				i++
+			} else if char == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch char {
+		case '\'', '"', '`':
+			quote = char
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth > 0 || (i > 0 && query[i-1] != ' ' && query[i-1] != '\n' && query[i-1] != '\t') {
+				continue
+			}
+			rest := strings.ToUpper(query[i:])
+			for _, keyword := range []string{"ORDER BY ", "GROUP BY ", "HAVING ", "LIMIT "} {
+				if strings.HasPrefix(rest, keyword) {
+					return query[0:i], " " + query[i:]
+				}
+			}
+		}
+	}
+	return query, ""
+}
+
+func getRowPolicyAllowedIDs(engine *Engine, schema *tableSchema, ids []uint64) map[uint64]bool {
+	allowed := make(map[uint64]bool, len(ids))
+	if len(ids) == 0 {
+		return allowed
+	}
+	where := applyRowPolicy(engine, schema, NewWhere("`ID` IN ?", ids))
+	/* #nosec */
+	query := "SELECT `ID` FROM `" + schema.tableName + "` WHERE " + where.resolve(engine.registry, schema)
+	results, def := schema.GetMysql(engine).Query(query, where.GetParameters()...)
+	defer def()
+	for results.Next() {
+		var id uint64
+		results.Scan(&id)
+		allowed[id] = true
+	}
+	def()
+	return allowed
+}
+
+func checkRowPolicy(engine *Engine, schema *tableSchema, ids ...uint64) {
+	if schema.rowPolicy == nil || schema.getRowPolicy(engine) == nil {
+		return
+	}
+	allowed := getRowPolicyAllowedIDs(engine, schema, ids)
+	for _, id := range ids {
+		if !allowed[id] {
+			panic(&RowPolicyError{Entity: schema.t.String(), ID: id})
+		}
+	}
+}
+
+func isAllowedByRowPolicy(engine *Engine, schema *tableSchema, id uint64) bool {
+	if schema.rowPolicy == nil || schema.getRowPolicy(engine) == nil {
+		return true
+	}
+	return getRowPolicyAllowedIDs(engine, schema, []uint64{id})[id]
+}
+
+func filterByRowPolicy(engine *Engine, schema *tableSchema, entities reflect.Value) (removed bool) {
+	if schema == nil || schema.rowPolicy == nil || schema.getRowPolicy(engine) == nil {
+		return false
+	}
+	ids := make([]uint64, 0, entities.Len())
+	for i := 0; i < entities.Len(); i++ {
+		if !entities.Index(i).IsNil() {
+			ids = append(ids, entities.Index(i).Interface().(Entity).GetID())
+		}
+	}
+	allowed := getRowPolicyAllowedIDs(engine, schema, ids)
+	for i := 0; i < entities.Len(); i++ {
+		row := entities.Index(i)
+		if !row.IsNil() && !allowed[row.Interface().(Entity).GetID()] {
+			row.Set(reflect.Zero(row.Type()))
+			removed = true
+		}
+	}
+	return removed
+}
@@ -0,0 +1,84 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rowPolicyEntity struct {
+	ORM   `orm:"localCache"`
+	ID    uint
+	Name  string
+	Owner uint
+}
+
+type rowPolicyOwnerKey struct{}
+
+func TestRowPolicy(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRowPolicy(&rowPolicyEntity{}, func(engine *Engine) *Where {
+		owner := engine.Ctx().Value(rowPolicyOwnerKey{})
+		if owner == nil {
+			return nil
+		}
+		return NewWhere("`Owner` = ?", owner)
+	})
+	engine := PrepareTables(t, registry, 5, &rowPolicyEntity{})
+	engine.FlushMany(&rowPolicyEntity{Name: "a", Owner: 1}, &rowPolicyEntity{Name: "b", Owner: 2}, &rowPolicyEntity{Name: "c", Owner: 1})
+
+	user := NewContext(context.WithValue(context.Background(), rowPolicyOwnerKey{}, 1), engine)
+	var rows []*rowPolicyEntity
+	user.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 2)
+	user.Search(NewWhere("`Name` = ? OR `Name` = ? ORDER BY `ID` DESC", "a", "b"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "a", rows[0].Name)
+	user.SearchRaw(NewWhere("SELECT * FROM `rowPolicyEntity` ORDER BY `ID`"), &rows)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "c", rows[1].Name)
+	engine.SearchRaw(NewWhere("SELECT * FROM `rowPolicyEntity`"), &rows)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 2, user.Count(NewWhere("1"), &rowPolicyEntity{}))
+	assert.Equal(t, 3, engine.Count(NewWhere("1"), &rowPolicyEntity{}))
+	assert.Equal(t, []uint64{1, 3}, user.SearchIDs(NewWhere("1 ORDER BY `ID`"), nil, &rowPolicyEntity{}))
+	assert.False(t, user.SearchOne(NewWhere("`Name` = ?", "b"), &rowPolicyEntity{}))
+	assert.True(t, user.LoadByID(1, &rowPolicyEntity{}))
+	assert.False(t, user.LoadByID(2, &rowPolicyEntity{}))
+	assert.True(t, engine.LoadByID(2, &rowPolicyEntity{}))
+	assert.True(t, user.LoadByIDs([]uint64{1, 2, 3}, &rows))
+	assert.Len(t, rows, 3)
+	assert.NotNil(t, rows[0])
+	assert.Nil(t, rows[1])
+	assert.NotNil(t, rows[2])
+
+	other := &rowPolicyEntity{}
+	engine.LoadByID(2, other)
+	other.Name = "b2"
+	assert.PanicsWithError(t, "row policy violation for entity 'orm.rowPolicyEntity' [2]", func() {
+		user.Flush(other)
+	})
+	assert.PanicsWithError(t, "row policy violation for entity 'orm.rowPolicyEntity' [2]", func() {
+		user.Delete(other)
+	})
+	own := &rowPolicyEntity{}
+	user.LoadByID(1, own)
+	own.Name = "a2"
+	user.Flush(own)
+	user.Delete(own)
+	assert.Equal(t, 2, engine.Count(NewWhere("1"), &rowPolicyEntity{}))
+}
+
+func TestSplitWhereTail(t *testing.T) {
+	condition, tail := splitWhereTail("`Name` = 'x ORDER BY y' AND `ID` IN (SELECT `ID` FROM `a` LIMIT 1) ORDER BY `ID` LIMIT 2")
+	assert.Equal(t, "`Name` = 'x ORDER BY y' AND `ID` IN (SELECT `ID` FROM `a` LIMIT 1) ", condition)
+	assert.Equal(t, " ORDER BY `ID` LIMIT 2", tail)
+	condition, tail = splitWhereTail("order by `ID`")
+	assert.Equal(t, "", condition)
+	assert.Equal(t, " order by `ID`", tail)
+	condition, tail = splitWhereTail("`Limited` = 1")
+	assert.Equal(t, "`Limited` = 1", condition)
+	assert.Equal(t, "", tail)
+}
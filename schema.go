@@ -14,6 +14,9 @@ type Alter struct {
 	SQL    string
 	Safe   bool
 	Pool   string
+	Table  string
+	Rows   uint64
+	Large  bool
 	engine *Engine
 }
 
@@ -23,11 +26,13 @@ type indexDB struct {
 	KeyName   string
 	Seq       int
 	Column    string
+	IndexType string
 }
 
 type index struct {
-	Unique  bool
-	Columns map[int]string
+	Unique   bool
+	FullText bool
+	Columns  map[int]string
 }
 
 type foreignIndex struct {
@@ -48,7 +53,7 @@ type foreignKeyDB struct {
 const defaultCollate = "0900_ai_ci"
 
 func (a Alter) Exec() {
-	a.engine.GetMysql(a.Pool).Exec(a.SQL)
+	a.exec(false)
 }
 
 func getAlters(engine *Engine) (alters []Alter) {
@@ -62,6 +67,9 @@ func getAlters(engine *Engine) (alters []Alter) {
 			pool := engine.GetMysql(poolName)
 			tables := getAllTables(pool.client)
 			for _, table := range tables {
+				if table == sequenceTableName {
+					continue
+				}
 				tablesInDB[poolName][table] = true
 			}
 			tablesInEntities[poolName] = make(map[string]bool)
@@ -72,6 +80,9 @@ func getAlters(engine *Engine) (alters []Alter) {
 		for _, t := range engine.registry.entities {
 			tableSchema := getTableSchema(engine.registry, t)
 			tablesInEntities[tableSchema.mysqlPoolName][tableSchema.tableName] = true
+			if tableSchema.renamedTable != "" {
+				tablesInEntities[tableSchema.mysqlPoolName][tableSchema.renamedTable] = true
+			}
 			has, newAlters := tableSchema.GetSchemaChanges(engine)
 			if tableSchema.hasLog {
 				logPool := engine.GetMysql(tableSchema.logPoolName)
@@ -107,6 +118,10 @@ func getAlters(engine *Engine) (alters []Alter) {
 				}
 				tablesInEntities[tableSchema.logPoolName][tableSchema.logTableName] = true
 			}
+			for _, m2m := range tableSchema.manyToMany {
+				alters = append(alters, getManyToManyAlters(engine, tableSchema, m2m)...)
+				tablesInEntities[tableSchema.mysqlPoolName][m2m.table] = true
+			}
 			if !has {
 				continue
 			}
@@ -118,11 +133,16 @@ func getAlters(engine *Engine) (alters []Alter) {
 		for tableName := range tables {
 			_, has := tablesInEntities[poolName][tableName]
 			if !has {
+				pool := engine.GetMysql(poolName)
+				if isView(pool, tableName) {
+					dropSQL := fmt.Sprintf("DROP VIEW IF EXISTS `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), tableName)
+					alters = append(alters, Alter{SQL: dropSQL, Safe: true, Pool: poolName, engine: engine})
+					continue
+				}
 				dropForeignKeyAlter := getDropForeignKeysAlter(engine, tableName, poolName)
 				if dropForeignKeyAlter != "" {
 					alters = append(alters, Alter{SQL: dropForeignKeyAlter, Safe: true, Pool: poolName, engine: engine})
 				}
-				pool := engine.GetMysql(poolName)
 				dropSQL := fmt.Sprintf("DROP TABLE IF EXISTS `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), tableName)
 				isEmpty := isTableEmptyInPool(engine, poolName, tableName)
 				alters = append(alters, Alter{SQL: dropSQL, Safe: isEmpty, Pool: poolName, engine: engine})
@@ -160,6 +180,118 @@ func getAlters(engine *Engine) (alters []Alter) {
 	return final
 }
 
+func getSchemaSnapshot(engine *Engine) string {
+	tables := make(map[string]map[string]string)
+	foreignKeys := make(map[string]map[string]string)
+	for _, t := range engine.registry.entities {
+		tableSchema := getTableSchema(engine.registry, t)
+		pool := tableSchema.mysqlPoolName
+		if tables[pool] == nil {
+			tables[pool] = make(map[string]string)
+			foreignKeys[pool] = make(map[string]string)
+		}
+		indexes := make(map[string]*index)
+		foreignIndexes := make(map[string]*foreignIndex)
+		columns, err := checkStruct(tableSchema, engine, tableSchema.t, indexes, foreignIndexes, "")
+		checkError(err)
+		columns[0][1] += " AUTO_INCREMENT"
+		createTableSQL, createTableForeignKeysSQL := buildCreateTableSQL(engine, tableSchema, "", columns, indexes, foreignIndexes)
+		tables[pool][tableSchema.tableName] = createTableSQL
+		if createTableForeignKeysSQL != "" {
+			foreignKeys[pool][tableSchema.tableName] = createTableForeignKeysSQL
+		}
+		version := tableSchema.GetMysql(engine).GetPoolConfig().GetVersion()
+		for _, m2m := range tableSchema.manyToMany {
+			tables[pool][m2m.table] = buildManyToManyTableSQL(engine, version, "", m2m.table)
+		}
+	}
+	pools := make([]string, 0, len(tables))
+	for pool := range tables {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	snapshot := ""
+	for _, pool := range pools {
+		snapshot += fmt.Sprintf("-- pool: %s\n\n", pool)
+		for _, group := range []map[string]string{tables[pool], foreignKeys[pool]} {
+			names := make([]string, 0, len(group))
+			for name := range group {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				snapshot += group[name] + "\n\n"
+			}
+		}
+	}
+	return snapshot
+}
+
+func getManyToManyAlters(engine *Engine, tableSchema *tableSchema, m2m *manyToManyDefinition) []Alter {
+	pool := tableSchema.GetMysql(engine)
+	var tableDef string
+	hasTable := pool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", m2m.table)), &tableDef)
+	tableSQL := buildManyToManyTableSQL(engine, pool.GetPoolConfig().GetVersion(), fmt.Sprintf("`%s`.", pool.GetPoolConfig().GetDatabase()), m2m.table)
+	if !hasTable {
+		return []Alter{{SQL: tableSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine}}
+	}
+	var skip, createTableDB string
+	pool.QueryRow(NewWhere(fmt.Sprintf("SHOW CREATE TABLE `%s`", m2m.table)), &skip, &createTableDB)
+	createTableDB = strings.Replace(createTableDB, "CREATE TABLE ", fmt.Sprintf("CREATE TABLE `%s`.", pool.GetPoolConfig().GetDatabase()), 1) + ";"
+	if tableSQL == createTableDB {
+		return nil
+	}
+	isEmpty := isTableEmptyInPool(engine, tableSchema.mysqlPoolName, m2m.table)
+	dropTableSQL := fmt.Sprintf("DROP TABLE `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), m2m.table)
+	return []Alter{{SQL: dropTableSQL, Safe: isEmpty, Pool: tableSchema.mysqlPoolName, engine: engine},
+		{SQL: tableSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine}}
+}
+
+func buildCreateTableSQL(engine *Engine, tableSchema *tableSchema, tablePrefix string, columns [][2]string,
+	indexes map[string]*index, foreignKeys map[string]*foreignIndex) (createTableSQL string, createTableForeignKeysSQL string) {
+	createTableSQL = fmt.Sprintf("CREATE TABLE %s`%s` (\n", tablePrefix, tableSchema.tableName)
+	for _, value := range columns {
+		createTableSQL += fmt.Sprintf("  %s,\n", value[1])
+	}
+	var newIndexes []string
+	for keyName, indexEntity := range indexes {
+		newIndexes = append(newIndexes, buildCreateIndexSQL(keyName, indexEntity))
+	}
+	sort.Strings(newIndexes)
+	for _, value := range newIndexes {
+		createTableSQL += fmt.Sprintf("  %s,\n", value[4:])
+	}
+	createTableSQL += "  PRIMARY KEY (`ID`)\n"
+	version := tableSchema.GetMysql(engine).GetPoolConfig().GetVersion()
+	createTableSQL += ") ENGINE=InnoDB" + tableSchema.getTableCharsetSQL(engine.registry, version) + ";"
+
+	if len(foreignKeys) == 0 {
+		return createTableSQL, ""
+	}
+	var newForeignKeys []string
+	for keyName, foreignKey := range foreignKeys {
+		newForeignKeys = append(newForeignKeys, buildCreateForeignKeySQL(keyName, foreignKey))
+	}
+	sort.Strings(newForeignKeys)
+	createTableForeignKeysSQL = fmt.Sprintf("ALTER TABLE %s`%s`\n", tablePrefix, tableSchema.tableName)
+	for _, value := range newForeignKeys {
+		createTableForeignKeysSQL += fmt.Sprintf("  %s,\n", value)
+	}
+	createTableForeignKeysSQL = strings.TrimRight(createTableForeignKeysSQL, ",\n") + ";"
+	return createTableSQL, createTableForeignKeysSQL
+}
+
+func buildManyToManyTableSQL(engine *Engine, version int, tablePrefix string, table string) string {
+	if version == 5 {
+		return fmt.Sprintf("CREATE TABLE %s`%s` (\n  `SourceID` bigint(20) unsigned NOT NULL,\n  `TargetID` bigint(20) unsigned NOT NULL,\n  "+
+			"PRIMARY KEY (`SourceID`,`TargetID`),\n  KEY `TargetID` (`TargetID`)\n) ENGINE=InnoDB DEFAULT CHARSET=%s;",
+			tablePrefix, table, engine.registry.registry.defaultEncoding)
+	}
+	return fmt.Sprintf("CREATE TABLE %s`%s` (\n  `SourceID` bigint unsigned NOT NULL,\n  `TargetID` bigint unsigned NOT NULL,\n  "+
+		"PRIMARY KEY (`SourceID`,`TargetID`),\n  KEY `TargetID` (`TargetID`)\n) ENGINE=InnoDB DEFAULT CHARSET=%s COLLATE=%s_%s;",
+		tablePrefix, table, engine.registry.registry.defaultEncoding, engine.registry.registry.defaultEncoding, defaultCollate)
+}
+
 func isTableEmptyInPool(engine *Engine, poolName string, tableName string) bool {
 	return isTableEmpty(engine.GetMysql(poolName).client, tableName)
 }
@@ -186,51 +318,27 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 	indexes := make(map[string]*index)
 	foreignKeys := make(map[string]*foreignIndex)
 	columns, _ := checkStruct(tableSchema, engine, tableSchema.t, indexes, foreignKeys, "")
-	var newIndexes []string
-	var newForeignKeys []string
 	pool := engine.GetMysql(tableSchema.mysqlPoolName)
-	createTableSQL := fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n", pool.GetPoolConfig().GetDatabase(), tableSchema.tableName)
-	createTableForeignKeysSQL := fmt.Sprintf("ALTER TABLE `%s`.`%s`\n", pool.GetPoolConfig().GetDatabase(), tableSchema.tableName)
 	columns[0][1] += " AUTO_INCREMENT"
-	for _, value := range columns {
-		createTableSQL += fmt.Sprintf("  %s,\n", value[1])
-	}
-	for keyName, indexEntity := range indexes {
-		newIndexes = append(newIndexes, buildCreateIndexSQL(keyName, indexEntity))
-	}
-	sort.Strings(newIndexes)
-	for _, value := range newIndexes {
-		createTableSQL += fmt.Sprintf("  %s,\n", value[4:])
-	}
-	for keyName, foreignKey := range foreignKeys {
-		newForeignKeys = append(newForeignKeys, buildCreateForeignKeySQL(keyName, foreignKey))
-	}
-	sort.Strings(newForeignKeys)
-	for _, value := range newForeignKeys {
-		createTableForeignKeysSQL += fmt.Sprintf("  %s,\n", value)
-	}
-
-	createTableSQL += "  PRIMARY KEY (`ID`)\n"
-	collate := ""
-	if pool.GetPoolConfig().GetVersion() == 8 {
-		collate += " COLLATE=" + engine.registry.registry.defaultEncoding + "_" + defaultCollate
-	}
-	createTableSQL += fmt.Sprintf(") ENGINE=InnoDB DEFAULT CHARSET=%s%s;", engine.registry.registry.defaultEncoding, collate)
+	tablePrefix := fmt.Sprintf("`%s`.", pool.GetPoolConfig().GetDatabase())
+	createTableSQL, createTableForeignKeysSQL := buildCreateTableSQL(engine, tableSchema, tablePrefix, columns, indexes, foreignKeys)
 
 	var skip string
 	hasTable := pool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", tableSchema.tableName)), &skip)
 
 	if !hasTable {
+		if renameAlter, isRenamed := getRenameTableAlter(engine, tableSchema); isRenamed {
+			return true, []Alter{renameAlter}
+		}
 		alters = []Alter{{SQL: createTableSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine}}
-		if len(newForeignKeys) > 0 {
-			createTableForeignKeysSQL = strings.TrimRight(createTableForeignKeysSQL, ",\n") + ";"
+		if createTableForeignKeysSQL != "" {
 			alters = append(alters, Alter{SQL: createTableForeignKeysSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine})
 		}
 		has = true
 		return
 	}
-	newIndexes = make([]string, 0)
-	newForeignKeys = make([]string, 0)
+	newIndexes := make([]string, 0)
+	newForeignKeys := make([]string, 0)
 
 	var tableDBColumns = make([][2]string, 0)
 	var createTableDB string
@@ -239,17 +347,30 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 	hasAlters := false
 	hasAlterNormal := false
 	hasAlterEngineCharset := false
+	version := pool.GetPoolConfig().GetVersion()
+	tableCharset := tableSchema.getCharset(engine.registry)
+	tableCollation := tableSchema.getCollation(engine.registry, version)
 	lines := strings.Split(createTableDB, "\n")
 	for x := 1; x < len(lines); x++ {
 		if lines[x][2] != 96 {
+			hasCharset := false
+			collation := ""
 			for _, field := range strings.Split(lines[x], " ") {
 				if strings.HasPrefix(field, "CHARSET=") {
-					if field[8:] != engine.registry.registry.defaultEncoding {
+					hasCharset = true
+					if field[8:] != tableCharset {
 						hasAlters = true
 						hasAlterEngineCharset = true
 					}
+				} else if strings.HasPrefix(field, "COLLATE=") {
+					collation = field[8:]
 				}
 			}
+			if hasCharset && ((collation != "" && collation != tableCollation) ||
+				(collation == "" && tableCollation != getDefaultCollation(version, tableCharset))) {
+				hasAlters = true
+				hasAlterEngineCharset = true
+			}
 			continue
 		}
 		var line = strings.TrimRight(lines[x], ",")
@@ -265,9 +386,9 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 	for results.Next() {
 		var row indexDB
 		if pool.GetPoolConfig().GetVersion() == 5 {
-			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
+			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.IndexType, &row.Skip, &row.Skip)
 		} else {
-			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
+			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.IndexType, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
 		}
 		rows = append(rows, row)
 	}
@@ -276,7 +397,7 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 	for _, value := range rows {
 		current, has := indexesDB[value.KeyName]
 		if !has {
-			current = &index{Unique: value.NonUnique == 0, Columns: map[int]string{value.Seq: value.Column}}
+			current = &index{Unique: value.NonUnique == 0, FullText: value.IndexType == "FULLTEXT", Columns: map[int]string{value.Seq: value.Column}}
 			indexesDB[value.KeyName] = current
 		} else {
 			current.Columns[value.Seq] = value.Column
@@ -287,6 +408,7 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 
 	var newColumns []string
 	var changedColumns [][2]string
+	var backfills []string
 
 	for key, value := range columns {
 		var tableColumn string
@@ -313,6 +435,15 @@ func getSchemaChanges(engine *Engine, tableSchema *tableSchema) (has bool, alter
 			}
 			newColumns = append(newColumns, alter)
 			hasAlters = true
+			if old, isRenamed := tableSchema.renamedColumns[value[0]]; isRenamed {
+				for _, v := range tableDBColumns {
+					if v[0] == old {
+						/* #nosec */
+						backfills = append(backfills, fmt.Sprintf("\nUPDATE `%s`.`%s` SET `%s` = `%s`;",
+							pool.GetPoolConfig().GetDatabase(), tableSchema.tableName, value[0], old))
+					}
+				}
+			}
 		} else {
 			if hasDefinition == -1 {
 				alter := fmt.Sprintf("CHANGE COLUMN `%s` %s", value[0], value[1])
@@ -483,13 +614,10 @@ OUTER:
 			isEmpty := isTableEmpty(db.client, tableSchema.tableName)
 			safe = isEmpty
 		}
+		alterSQL += strings.Join(backfills, "")
 		alters = append(alters, Alter{SQL: alterSQL, Safe: safe, Pool: tableSchema.mysqlPoolName, engine: engine})
 	} else if hasAlterEngineCharset {
-		collate := ""
-		if pool.GetPoolConfig().GetVersion() == 8 {
-			collate += " COLLATE=" + engine.registry.registry.defaultEncoding + "_" + defaultCollate
-		}
-		alterSQL += fmt.Sprintf(" ENGINE=InnoDB DEFAULT CHARSET=%s%s;", engine.registry.registry.defaultEncoding, collate)
+		alterSQL += " ENGINE=InnoDB" + tableSchema.getTableCharsetSQL(engine.registry, pool.GetPoolConfig().GetVersion()) + ";"
 		alters = append(alters, Alter{SQL: alterSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine})
 	}
 	if hasAlterRemoveForeignKey {
@@ -501,6 +629,7 @@ OUTER:
 		alters = append(alters, Alter{SQL: alterSQLAddForeignKey, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine})
 	}
 
+	markLargeAlters(engine, tableSchema, alters)
 	has = true
 	return has, alters
 }
@@ -580,21 +709,37 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 	addDefaultNullIfNullable := true
 	defaultValue := "nil"
 	var typeAsString = field.Type.String()
+	if isStringSlice(field.Type) {
+		typeAsString = "[]string"
+	}
 	columnName := prefix + field.Name
 
 	attributes := schema.tags[columnName]
 	version := schema.GetMysql(engine).GetPoolConfig().GetVersion()
 
 	_, has := attributes["ignore"]
+	if has || isVirtualField(attributes) {
+		return nil, nil
+	}
+	_, has = attributes["m2m"]
 	if has {
 		return nil, nil
 	}
 
-	keys := []string{"index", "unique"}
+	keys := []string{"index", "unique", "fulltext"}
 	var refOneSchema *tableSchema
 	for _, key := range keys {
 		indexAttribute, has := attributes[key]
 		unique := key == "unique"
+		fullText := key == "fulltext"
+		if has && fullText {
+			if field.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("fulltext index not supported for field '%s'", field.Name)
+			}
+			if indexAttribute == "true" {
+				indexAttribute = field.Name
+			}
+		}
 		if key == "index" && field.Type.Kind() == reflect.Ptr {
 			refOneSchema = getTableSchema(engine.registry, field.Type.Elem())
 			if refOneSchema != nil {
@@ -628,7 +773,7 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 				}
 				current, has := indexes[indexColumn[0]]
 				if !has {
-					current = &index{Unique: unique, Columns: map[int]string{location: field.Name}}
+					current = &index{Unique: unique, FullText: fullText, Columns: map[int]string{location: field.Name}}
 					indexes[indexColumn[0]] = current
 				} else {
 					current.Columns[location] = field.Name
@@ -657,6 +802,10 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 	isRequired := hasRequired && required == "true"
 
 	var err error
+	fieldType, isCustom := engine.registry.registry.getFieldType(field.Type)
+	if isCustom {
+		typeAsString = customFieldTypeName
+	}
 	switch typeAsString {
 	case "uint",
 		"uint8",
@@ -702,7 +851,7 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 	case "*bool":
 		definition, addNotNullIfNotSet, defaultValue = "tinyint(1)", false, "nil"
 	case "string", "[]string":
-		definition, addNotNullIfNotSet, addDefaultNullIfNullable, defaultValue, err = handleString(version, engine.registry, attributes, !isRequired)
+		definition, addNotNullIfNotSet, addDefaultNullIfNullable, defaultValue, err = handleString(version, engine.registry, schema, attributes, !isRequired)
 		if err != nil {
 			return nil, err
 		}
@@ -720,12 +869,21 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 		definition, addNotNullIfNotSet, addDefaultNullIfNullable, defaultValue = handleTime(attributes, true)
 	case "[]uint8":
 		definition, addDefaultNullIfNullable = handleBlob(attributes)
+	case "orm.Decimal":
+		definition, addNotNullIfNotSet, defaultValue = handleDecimal(attributes, false)
+	case "*orm.Decimal":
+		definition, addNotNullIfNotSet, defaultValue = handleDecimal(attributes, true)
+	case "orm.Point", "*orm.Point", "orm.Polygon":
+		return [][2]string{{columnName, fmt.Sprintf("`%s` %s", columnName, handleSpatial(version, typeAsString, attributes, isRequired))}}, nil
+	case customFieldTypeName:
+		definition = fieldType.SQLType
+		addNotNullIfNotSet = field.Type.Kind() != reflect.Ptr && field.Type.Kind() != reflect.Slice
 	case "*orm.CachedQuery":
 		return nil, nil
 	default:
 		kind := field.Type.Kind().String()
 		if kind == "struct" {
-			structFields, err := checkStruct(schema, engine, field.Type, indexes, foreignKeys, field.Name)
+			structFields, err := checkStruct(schema, engine, field.Type, indexes, foreignKeys, getStructPrefix(engine.registry.registry, *field))
 			checkError(err)
 			return structFields, nil
 		} else if kind == "ptr" {
@@ -735,6 +893,17 @@ func checkColumn(engine *Engine, schema *tableSchema, field *reflect.StructField
 				addNotNullIfNotSet = false
 				addDefaultNullIfNullable = true
 			}
+		} else if isScalarArray(*field) {
+			columns := make([][2]string, 0, field.Type.Len())
+			for k := 0; k < field.Type.Len(); k++ {
+				element := reflect.StructField{Name: getArrayColumnName(field.Name, k), Type: field.Type.Elem()}
+				elementColumns, err := checkColumn(engine, schema, &element, indexes, foreignKeys, prefix)
+				if err != nil {
+					return nil, err
+				}
+				columns = append(columns, elementColumns...)
+			}
+			return columns, nil
 		} else {
 			definition = "json"
 		}
@@ -781,6 +950,35 @@ func handleFloat(floatDefinition string, attributes map[string]string, nullable
 	return definition, true, defaultValue
 }
 
+func handleDecimal(attributes map[string]string, nullable bool) (string, bool, string) {
+	precision, scale := getDecimalDefinition(attributes)
+	definition := fmt.Sprintf("decimal(%d,%d)", precision, scale)
+	if attributes["unsigned"] == "true" {
+		definition += " unsigned"
+	}
+	if nullable {
+		return definition, false, "nil"
+	}
+	return definition, true, "'" + NewDecimalFromInt(0, scale).String() + "'"
+}
+
+func handleSpatial(version int, typeAsString string, attributes map[string]string, isRequired bool) string {
+	definition := "point"
+	if typeAsString == "orm.Polygon" {
+		definition = "polygon"
+	}
+	if isRequired || typeAsString == "orm.Point" {
+		definition += " NOT NULL"
+	} else {
+		definition += " DEFAULT NULL"
+	}
+	srid, hasSRID := attributes["srid"]
+	if hasSRID && version == 8 {
+		definition += " /*!80003 SRID " + srid + " */"
+	}
+	return definition
+}
+
 func handleBlob(attributes map[string]string) (string, bool) {
 	definition := "blob"
 	if attributes["mediumblob"] == "true" {
@@ -793,15 +991,16 @@ func handleBlob(attributes map[string]string) (string, bool) {
 	return definition, false
 }
 
-func handleString(version int, registry *validatedRegistry, attributes map[string]string, nullable bool) (string, bool, bool, string, error) {
+func handleString(version int, registry *validatedRegistry, schema *tableSchema, attributes map[string]string, nullable bool) (string, bool, bool, string, error) {
 	var definition string
+	charset := schema.getColumnCharsetSQL(registry, version, attributes)
 	enum, hasEnum := attributes["enum"]
 	if hasEnum {
-		return handleSetEnum(version, registry, "enum", enum, nullable)
+		return handleSetEnum(registry, "enum", enum, charset, nullable)
 	}
 	set, haSet := attributes["set"]
 	if haSet {
-		return handleSetEnum(version, registry, "set", set, nullable)
+		return handleSetEnum(registry, "set", set, charset, nullable)
 	}
 	length, hasLength := attributes["length"]
 	if !hasLength {
@@ -809,23 +1008,14 @@ func handleString(version int, registry *validatedRegistry, attributes map[strin
 	}
 	addDefaultNullIfNullable := true
 	if length == "max" {
-		definition = "mediumtext"
-		if version == 8 {
-			encoding := registry.registry.defaultEncoding
-			definition += " CHARACTER SET " + encoding + " COLLATE " + encoding + "_" + defaultCollate
-		}
+		definition = "mediumtext" + charset
 		addDefaultNullIfNullable = false
 	} else {
 		i, err := strconv.Atoi(length)
 		if err != nil || i > 65535 {
 			return "", false, false, "", fmt.Errorf("invalid max string: %s", length)
 		}
-		if version == 5 {
-			definition = fmt.Sprintf("varchar(%s)", strconv.Itoa(i))
-		} else {
-			definition = fmt.Sprintf("varchar(%s) CHARACTER SET %s COLLATE %s_"+defaultCollate, strconv.Itoa(i),
-				registry.registry.defaultEncoding, registry.registry.defaultEncoding)
-		}
+		definition = fmt.Sprintf("varchar(%s)", strconv.Itoa(i)) + charset
 	}
 
 	defaultValue := "nil"
@@ -835,7 +1025,7 @@ func handleString(version int, registry *validatedRegistry, attributes map[strin
 	return definition, !nullable, addDefaultNullIfNullable, defaultValue, nil
 }
 
-func handleSetEnum(version int, registry *validatedRegistry, fieldType string, attribute string, nullable bool) (string, bool, bool, string, error) {
+func handleSetEnum(registry *validatedRegistry, fieldType string, attribute string, charset string, nullable bool) (string, bool, bool, string, error) {
 	if registry.enums == nil || registry.enums[attribute] == nil {
 		return "", false, false, "", fmt.Errorf("unregistered enum %s", attribute)
 	}
@@ -847,11 +1037,7 @@ func handleSetEnum(version int, registry *validatedRegistry, fieldType string, a
 		}
 		definition += fmt.Sprintf("'%s'", value)
 	}
-	definition += ")"
-	if version == 8 {
-		encoding := registry.registry.defaultEncoding
-		definition += " CHARACTER SET " + encoding + " COLLATE " + encoding + "_0900_ai_ci"
-	}
+	definition += ")" + charset
 	defaultValue := "nil"
 	if !nullable {
 		defaultValue = fmt.Sprintf("'%s'", enum.GetDefault())
@@ -970,6 +1156,9 @@ func checkStruct(tableSchema *tableSchema, engine *Engine, t reflect.Type, index
 		def := fmt.Sprintf("`FakeDelete` %s unsigned NOT NULL DEFAULT '0'", strings.Split(columns[0][1], " ")[1])
 		columns = append(columns, [2]string{"FakeDelete", def})
 	}
+	if prefix == "" {
+		columns = tableSchema.addRenamedColumnDefinitions(columns)
+	}
 	return columns, nil
 }
 
@@ -986,6 +1175,8 @@ func buildCreateIndexSQL(keyName string, definition *index) string {
 	indexType := "INDEX"
 	if definition.Unique {
 		indexType = "UNIQUE " + indexType
+	} else if definition.FullText {
+		indexType = "FULLTEXT " + indexType
 	}
 	return fmt.Sprintf("ADD %s `%s` (%s)", indexType, keyName, strings.Join(indexColumns, ","))
 }
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +32,13 @@ type schemaInvalidMaxStringEntity struct {
 	Name string `orm:"length=invalid"`
 }
 
+type schemaSnapshotEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"index=Name"`
+	Ref  *schemaEntityRef
+}
+
 type schemaToDropEntity struct {
 	ORM `orm:"log"`
 	ID  uint
@@ -377,3 +385,74 @@ func testSchema(t *testing.T, version int) {
 	_, err = registry.Validate()
 	assert.EqualError(t, err, "missing index for cached query 'IndexName' in orm.invalidSchema9")
 }
+
+type schemaAuditFields struct {
+	CreatedBy uint32
+	Note      string `orm:"length=50"`
+}
+
+type schemaAddressFields struct {
+	Street string
+	City   string `orm:"required"`
+}
+
+type schemaEmbeddedEntity struct {
+	ORM
+	ID       uint
+	Name     string
+	Audit    schemaAuditFields `orm:"prefix="`
+	Home     schemaAddressFields
+	Delivery schemaAddressFields `orm:"prefix=Ship"`
+}
+
+type schemaEmbeddedDuplicatedEntity struct {
+	ORM
+	ID    uint
+	Note  string
+	Audit schemaAuditFields `orm:"prefix="`
+}
+
+func TestSchemaEmbedded(t *testing.T) {
+	var entity *schemaEmbeddedEntity
+	registry := &Registry{}
+	registry.RegisterEmbedded(schemaAddressFields{}, "Address")
+	engine := PrepareTables(t, registry, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	assert.Equal(t, []string{"ID", "Name", "CreatedBy", "Note", "AddressStreet", "AddressCity", "ShipStreet", "ShipCity"}, schema.GetColumns())
+	has, _ := schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	entity = &schemaEmbeddedEntity{Name: "a"}
+	entity.Audit.CreatedBy = 7
+	entity.Home.City = "Warsaw"
+	entity.Delivery.Street = "Main"
+	engine.Flush(entity)
+	entity = &schemaEmbeddedEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint32(7), entity.Audit.CreatedBy)
+	assert.Equal(t, "Warsaw", entity.Home.City)
+	assert.Equal(t, "Main", entity.Delivery.Street)
+	entity.Delivery.City = "Berlin"
+	engine.Flush(entity)
+	entity = &schemaEmbeddedEntity{}
+	found := engine.SearchOne(NewWhere("`ShipCity` = ?", "Berlin"), entity)
+	assert.True(t, found)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&schemaEmbeddedDuplicatedEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "duplicated column 'Note' in entity 'orm.schemaEmbeddedDuplicatedEntity'")
+}
+
+func TestSchemaSnapshot(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &schemaSnapshotEntity{}, &schemaEntityRef{})
+	snapshot := engine.GetSchemaSnapshot()
+	assert.True(t, strings.HasPrefix(snapshot, "-- pool: default\n\nCREATE TABLE `schemaEntityRef` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n"+
+		"  `Name` varchar(255) NOT NULL DEFAULT '',\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n\nCREATE TABLE `schemaSnapshotEntity` (\n"))
+	assert.Contains(t, snapshot, "  KEY `Name` (`Name`),\n")
+	assert.True(t, strings.HasSuffix(snapshot, "\n\nALTER TABLE `schemaSnapshotEntity`\n  ADD CONSTRAINT `test:schemaSnapshotEntity:Ref` "+
+		"FOREIGN KEY (`Ref`) REFERENCES `test`.`schemaEntityRef` (`ID`) ON DELETE RESTRICT;\n\n"))
+	assert.Equal(t, snapshot, engine.GetSchemaSnapshot())
+	assert.Len(t, engine.GetAlters(), 0)
+}
@@ -6,7 +6,6 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
 
 	jsoniter "github.com/json-iterator/go"
 )
@@ -98,6 +97,21 @@ func prepareScanForFields(fields *tableFields, start int, pointers []interface{}
 		pointers[start] = &v
 		start++
 	}
+	for i := 0; i < len(fields.spatials); i++ {
+		v := sql.NullString{}
+		pointers[start] = &v
+		start++
+	}
+	for i := 0; i < len(fields.decimals); i++ {
+		v := sql.NullString{}
+		pointers[start] = &v
+		start++
+	}
+	for i := 0; i < len(fields.customs); i++ {
+		v := sql.NullString{}
+		pointers[start] = &v
+		start++
+	}
 	for i := 0; i < len(fields.refs); i++ {
 		v := sql.NullInt64{}
 		pointers[start] = &v
@@ -108,6 +122,7 @@ func prepareScanForFields(fields *tableFields, start int, pointers []interface{}
 		pointers[start] = &v
 		start++
 	}
+	start = prepareScanForArrays(fields, start, pointers)
 	for _, subFields := range fields.structs {
 		start = prepareScanForFields(subFields, start, pointers)
 	}
@@ -220,6 +235,33 @@ func convertScan(fields *tableFields, start int, pointers []interface{}) int {
 		}
 		start++
 	}
+	for i := 0; i < len(fields.spatials); i++ {
+		v := pointers[start].(*sql.NullString)
+		if v.Valid {
+			pointers[start] = v.String
+		} else {
+			pointers[start] = nil
+		}
+		start++
+	}
+	for i := 0; i < len(fields.decimals); i++ {
+		v := pointers[start].(*sql.NullString)
+		if v.Valid {
+			pointers[start] = v.String
+		} else {
+			pointers[start] = nil
+		}
+		start++
+	}
+	for i := 0; i < len(fields.customs); i++ {
+		v := pointers[start].(*sql.NullString)
+		if v.Valid {
+			pointers[start] = v.String
+		} else {
+			pointers[start] = nil
+		}
+		start++
+	}
 	for i := 0; i < len(fields.refs); i++ {
 		v := pointers[start].(*sql.NullInt64)
 		if v.Valid {
@@ -238,6 +280,7 @@ func convertScan(fields *tableFields, start int, pointers []interface{}) int {
 		}
 		start++
 	}
+	start = convertScanForArrays(fields, start, pointers)
 	for _, subFields := range fields.structs {
 		start = convertScan(subFields, start, pointers)
 	}
@@ -247,14 +290,15 @@ func convertScan(fields *tableFields, start int, pointers []interface{}) int {
 func searchRow(skipFakeDelete bool, engine *Engine, where *Where, entity Entity, lazy bool, references []string) (bool, *tableSchema, []interface{}) {
 	orm := initIfNeeded(engine.registry, entity)
 	schema := orm.tableSchema
-	whereQuery := where.String()
+	where = applyRowPolicy(engine, schema, where)
+	whereQuery := where.resolve(engine.registry, schema)
 	if skipFakeDelete && schema.hasFakeDelete {
 		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
 	}
 	/* #nosec */
-	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT 1"
-
 	pool := schema.GetMysql(engine)
+	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT 1" + where.lockClause(pool)
+
 	results, def := pool.Query(query, where.GetParameters()...)
 	defer def()
 	if !results.Next() {
@@ -273,24 +317,16 @@ func searchRow(skipFakeDelete bool, engine *Engine, where *Where, entity Entity,
 }
 
 func search(skipFakeDelete bool, engine *Engine, where *Where, pager *Pager, withCount, lazy, checkIsSlice bool, entities reflect.Value, references ...string) (totalRows int) {
-	if pager == nil {
-		pager = NewPager(1, 50000)
-	}
+	pager = preparePager(pager, 50000)
 	entities.SetLen(0)
 	entityType, has, name := getEntityTypeForSlice(engine.registry, entities.Type(), checkIsSlice)
 	if !has {
 		panic(fmt.Errorf("entity '%s' is not registered", name))
 	}
 	schema := getTableSchema(engine.registry, entityType)
-	whereQuery := where.String()
-	if skipFakeDelete && schema.hasFakeDelete {
-		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
-	}
-	/* #nosec */
-	pageStart := strconv.Itoa((pager.CurrentPage - 1) * pager.PageSize)
-	pageEnd := strconv.Itoa(pager.PageSize)
-	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT " + pageStart + "," + pageEnd
 	pool := schema.GetMysql(engine)
+	maxRows := getMaxSearchRows(engine, where)
+	query, where := buildSearchQuery(skipFakeDelete, engine, schema, where, pager)
 	results, def := pool.Query(query, where.GetParameters()...)
 	defer def()
 
@@ -298,6 +334,7 @@ func search(skipFakeDelete bool, engine *Engine, where *Where, pager *Pager, wit
 	val := valOrigin
 	i := 0
 	for results.Next() {
+		checkMaxSearchRows(schema, maxRows, i)
 		pointers := prepareScan(schema)
 		results.Scan(pointers...)
 		convertScan(schema.fields, 0, pointers)
@@ -308,6 +345,7 @@ func search(skipFakeDelete bool, engine *Engine, where *Where, pager *Pager, wit
 		i++
 	}
 	def()
+	applyIdentityMap(engine, val)
 	totalRows = getTotalRows(engine, withCount, pager, where, schema, i)
 	if len(references) > 0 && i > 0 {
 		warmUpReferences(engine, schema, val, references, true, lazy)
@@ -316,16 +354,161 @@ func search(skipFakeDelete bool, engine *Engine, where *Where, pager *Pager, wit
 	return totalRows
 }
 
+func buildSearchQuery(skipFakeDelete bool, engine *Engine, schema *tableSchema, where *Where, pager *Pager) (string, *Where) {
+	where = applyRowPolicy(engine, schema, where)
+	whereQuery := where.resolve(engine.registry, schema)
+	if skipFakeDelete && schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	}
+	/* #nosec */
+	pageStart := strconv.Itoa((pager.CurrentPage - 1) * pager.PageSize)
+	pageEnd := strconv.Itoa(pager.PageSize)
+	pool := schema.GetMysql(engine)
+	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT " + pageStart + "," + pageEnd + where.lockClause(pool)
+	return query, where
+}
+
+func searchRaw(engine *Engine, where *Where, lazy bool, entities reflect.Value, references ...string) {
+	entities.SetLen(0)
+	entityType, has, name := getEntityTypeForSlice(engine.registry, entities.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	schema := getTableSchema(engine.registry, entityType)
+	pool := schema.GetMysql(engine)
+	results, def := pool.Query(where.resolve(engine.registry, schema), where.GetParameters()...)
+	defer def()
+
+	columns := results.Columns()
+	mapping := make([]int, len(columns))
+	used := make(map[int]bool, len(columns))
+	for i, column := range columns {
+		index, has := schema.columnMapping[column]
+		if !has || used[index] {
+			mapping[i] = -1
+			continue
+		}
+		mapping[i] = index
+		used[index] = true
+	}
+	if !used[0] {
+		panic(fmt.Errorf("missing `ID` column in raw query for entity '%s'", name))
+	}
+	maxRows := getMaxSearchRows(engine, where)
+	val := entities
+	i := 0
+	for results.Next() {
+		checkMaxSearchRows(schema, maxRows, i)
+		pointers := prepareScan(schema)
+		target := make([]interface{}, len(columns))
+		for k, index := range mapping {
+			if index == -1 {
+				var skip interface{}
+				target[k] = &skip
+			} else {
+				target[k] = pointers[index]
+			}
+		}
+		results.Scan(target...)
+		convertScan(schema.fields, 0, pointers)
+		value := reflect.New(entityType)
+		id := pointers[0].(uint64)
+		fillFromDBRow(id, engine, pointers, value.Interface().(Entity), lazy)
+		val = reflect.Append(val, value)
+		i++
+	}
+	def()
+	if filterByRowPolicy(engine, schema, val) {
+		allowed := reflect.MakeSlice(val.Type(), 0, val.Len())
+		for k := 0; k < val.Len(); k++ {
+			if !val.Index(k).IsNil() {
+				allowed = reflect.Append(allowed, val.Index(k))
+			}
+		}
+		val = allowed
+		i = val.Len()
+	}
+	applyIdentityMap(engine, val)
+	if len(references) > 0 && i > 0 {
+		warmUpReferences(engine, schema, val, references, true, lazy)
+	}
+	entities.Set(val)
+}
+
+func searchCount(engine *Engine, where *Where, entity Entity) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	where = applyRowPolicy(engine, schema, where)
+	whereQuery := where.resolve(engine.registry, schema)
+	if schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	}
+	indexName, has := schema.getCachedIndexForQuery(engine, whereQuery, false)
+	if has {
+		total, _ := cachedSearch(engine, entity, indexName, NewPager(1, 1), where.GetParameters(), false, false, nil)
+		return total
+	}
+	/* #nosec */
+	query := "SELECT count(1) FROM `" + schema.tableName + "` WHERE " + whereQuery
+	var total int
+	schema.GetMysql(engine).QueryRow(NewWhere(query, where.GetParameters()...), &total)
+	return total
+}
+
+func searchExists(engine *Engine, where *Where, entity Entity) bool {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	where = applyRowPolicy(engine, schema, where)
+	whereQuery := where.resolve(engine.registry, schema)
+	if schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	}
+	indexName, has := schema.getCachedIndexForQuery(engine, whereQuery, true)
+	if has {
+		if _, isOne := schema.cachedIndexesOne[indexName]; isOne {
+			found, _ := cachedSearchOne(engine, entity, indexName, false, false, where.GetParameters(), nil)
+			return found
+		}
+		total, _ := cachedSearch(engine, entity, indexName, NewPager(1, 1), where.GetParameters(), false, false, nil)
+		return total > 0
+	}
+	/* #nosec */
+	query := "SELECT 1 FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT 1"
+	var found int
+	return schema.GetMysql(engine).QueryRow(NewWhere(query, where.GetParameters()...), &found)
+}
+
+func getReferenced(engine *Engine, parent Entity, children interface{}, field string, pager *Pager, lazy bool, references []string) int {
+	parentSchema := initIfNeeded(engine.registry, parent).tableSchema
+	value := reflect.ValueOf(children).Elem()
+	entityType, has, name := getEntityTypeForSlice(engine.registry, value.Type(), true)
+	if !has {
+		panic(fmt.Errorf("entity '%s' is not registered", name))
+	}
+	schema := getTableSchema(engine.registry, entityType)
+	if schema.tags[field]["ref"] != parentSchema.t.String() {
+		panic(fmt.Errorf("field '%s' in entity '%s' is not reference to '%s'", field, schema.t.String(), parentSchema.t.String()))
+	}
+	query := "`" + field + "` = ?"
+	whereQuery := query
+	if schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	}
+	indexName, has := schema.getCachedIndexForQuery(engine, whereQuery, false)
+	if has && schema.rowPolicy == nil {
+		total, _ := cachedSearch(engine, children, indexName, pager, []interface{}{parent.GetID()}, lazy, true, references)
+		return total
+	}
+	return search(true, engine, NewWhere(query+" ORDER BY `ID`", parent.GetID()), pager, true, lazy, true, value, references...)
+}
+
 func searchOne(skipFakeDelete bool, engine *Engine, where *Where, entity Entity, lazy bool, references []string) (bool, *tableSchema, []interface{}) {
 	return searchRow(skipFakeDelete, engine, where, entity, lazy, references)
 }
 
 func searchIDs(skipFakeDelete bool, engine *Engine, where *Where, pager *Pager, withCount bool, entityType reflect.Type) (ids []uint64, total int) {
-	if pager == nil {
-		pager = NewPager(1, 50000)
-	}
+	pager = preparePager(pager, 50000)
 	schema := getTableSchema(engine.registry, entityType)
-	whereQuery := where.String()
+	where = applyRowPolicy(engine, schema, where)
+	whereQuery := where.resolve(engine.registry, schema)
 	if skipFakeDelete && schema.hasFakeDelete {
 		/* #nosec */
 		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
@@ -354,7 +537,7 @@ func getTotalRows(engine *Engine, withCount bool, pager *Pager, where *Where, sc
 		totalRows = foundRows
 		if totalRows == pager.GetPageSize() || (foundRows == 0 && pager.CurrentPage > 1) {
 			/* #nosec */
-			query := "SELECT count(1) FROM `" + schema.tableName + "` WHERE " + where.String()
+			query := "SELECT count(1) FROM `" + schema.tableName + "` WHERE " + where.resolve(engine.registry, schema)
 			var foundTotal string
 			pool := schema.GetMysql(engine)
 			pool.QueryRow(NewWhere(query, where.GetParameters()...), &foundTotal)
@@ -362,6 +545,7 @@ func getTotalRows(engine *Engine, withCount bool, pager *Pager, where *Where, sc
 		} else {
 			totalRows += (pager.GetCurrentPage() - 1) * pager.GetPageSize()
 		}
+		pager.setTotalRows(totalRows)
 	}
 	return totalRows
 }
@@ -370,8 +554,15 @@ func fillFromDBRow(id uint64, engine *Engine, data []interface{}, entity Entity,
 	orm := initIfNeeded(engine.registry, entity)
 	elem := orm.elem
 	orm.idElem.SetUint(id)
-	if !lazy {
+	if !lazy && orm.tableSchema.generated {
+		entity.(GeneratedEntity).OrmFill(data)
+		fillGeneratedReferences(engine.registry, data, orm)
+		applyReadPermissions(engine, orm.tableSchema, elem)
+		computeVirtualFields(orm.tableSchema, entity, elem)
+	} else if !lazy {
 		_ = fillStruct(engine.registry, 0, data, orm.tableSchema.fields, orm, elem)
+		applyReadPermissions(engine, orm.tableSchema, elem)
+		computeVirtualFields(orm.tableSchema, entity, elem)
 	}
 	orm.inDB = true
 	orm.loaded = true
@@ -460,7 +651,11 @@ func fillStruct(registry *validatedRegistry, index uint16, data []interface{}, f
 				}
 			} else {
 				var values = strings.Split(data[index].(string), ",")
-				field.Set(reflect.ValueOf(values))
+				slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+				for j, v := range values {
+					slice.Index(j).SetString(v)
+				}
+				field.Set(slice)
 			}
 		} else if !field.IsZero() {
 			field.Set(reflect.Zero(field.Type()))
@@ -529,7 +724,7 @@ func fillStruct(registry *validatedRegistry, index uint16, data []interface{}, f
 			if len(v) == 19 {
 				layout += " 15:04:05"
 			}
-			value, _ := time.ParseInLocation(layout, v, time.Local)
+			value := orm.tableSchema.parseTime(fields.prefix+fields.fields[i].Name, layout, v)
 			field.Set(reflect.ValueOf(&value))
 		}
 		index++
@@ -548,7 +743,7 @@ func fillStruct(registry *validatedRegistry, index uint16, data []interface{}, f
 			index++
 			continue
 		}
-		val, _ := time.ParseInLocation(layout, v, time.Local)
+		val := orm.tableSchema.parseTime(fields.prefix+fields.fields[i].Name, layout, v)
 		field.Set(reflect.ValueOf(val))
 		index++
 	}
@@ -563,6 +758,57 @@ func fillStruct(registry *validatedRegistry, index uint16, data []interface{}, f
 		}
 		index++
 	}
+	for _, i := range fields.spatials {
+		field := value.Field(i)
+		if data[index] == nil {
+			if !field.IsZero() {
+				field.Set(reflect.Zero(field.Type()))
+			}
+			index++
+			continue
+		}
+		switch field.Type().String() {
+		case "orm.Polygon":
+			polygon, _ := ParsePolygon(data[index].(string))
+			field.Set(reflect.ValueOf(polygon))
+		case "*orm.Point":
+			point, _ := ParsePoint(data[index].(string))
+			field.Set(reflect.ValueOf(&point))
+		default:
+			point, _ := ParsePoint(data[index].(string))
+			field.Set(reflect.ValueOf(point))
+		}
+		index++
+	}
+	for _, i := range fields.decimals {
+		field := value.Field(i)
+		if data[index] == nil {
+			if !field.IsZero() {
+				field.Set(reflect.Zero(field.Type()))
+			}
+			index++
+			continue
+		}
+		val, _ := NewDecimal(data[index].(string))
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.ValueOf(&val))
+		} else {
+			field.Set(reflect.ValueOf(val))
+		}
+		index++
+	}
+	for k, i := range fields.customs {
+		field := value.Field(i)
+		if data[index] == nil {
+			if !field.IsZero() {
+				field.Set(reflect.Zero(field.Type()))
+			}
+			index++
+			continue
+		}
+		fields.customTypes[k].setField(field, data[index].(string))
+		index++
+	}
 	for k, i := range fields.refs {
 		field := value.Field(i)
 		integer := uint64(0)
@@ -614,6 +860,7 @@ func fillStruct(registry *validatedRegistry, index uint16, data []interface{}, f
 		}
 		index++
 	}
+	index = fillArrays(data, index, fields, value)
 	for i, subFields := range fields.structs {
 		field := value.Field(i)
 		newVal := reflect.New(field.Type())
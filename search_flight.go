@@ -0,0 +1,54 @@
+package orm
+
+import "sync"
+
+type searchFlight struct {
+	wg    sync.WaitGroup
+	total int
+	ids   []uint64
+	err   interface{}
+}
+
+type searchFlightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*searchFlight
+}
+
+func (g *searchFlightGroup) do(key string, fn func() (total int, ids []uint64)) (total int, ids []uint64) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*searchFlight)
+	}
+	call, has := g.calls[key]
+	if has {
+		g.mutex.Unlock()
+		call.wg.Wait()
+		if call.err != nil {
+			panic(call.err)
+		}
+		return call.total, call.ids
+	}
+	call = &searchFlight{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mutex.Unlock()
+	defer func() {
+		call.err = recover()
+		call.wg.Done()
+		g.mutex.Lock()
+		delete(g.calls, key)
+		g.mutex.Unlock()
+		if call.err != nil {
+			panic(call.err)
+		}
+	}()
+	call.total, call.ids = fn()
+	return call.total, call.ids
+}
+
+func (e *Engine) searchFlight(schema *tableSchema, key string, fn func() (total int, ids []uint64)) (total int, ids []uint64) {
+	if schema.getRowPolicy(e) != nil || schema.GetMysql(e).inTransaction {
+		return fn()
+	}
+	return e.registry.searchFlights.do(e.registry.tenant+":"+schema.t.String()+":"+key, fn)
+}
@@ -0,0 +1,51 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type searchFlightEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestSearchFlight(t *testing.T) {
+	var entity *searchFlightEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+
+	calls := 0
+	total, ids := engine.searchFlight(schema, "key", func() (int, []uint64) {
+		calls++
+		assert.Len(t, engine.registry.searchFlights.calls, 1)
+		return 1, []uint64{7}
+	})
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []uint64{7}, ids)
+	assert.Len(t, engine.registry.searchFlights.calls, 0)
+
+	other := engine.GetRegistry().CreateEngine()
+	other.searchFlight(schema, "key", func() (int, []uint64) {
+		assert.Len(t, engine.registry.searchFlights.calls, 1)
+		return 0, nil
+	})
+
+	db := engine.GetMysql()
+	db.Begin()
+	engine.searchFlight(schema, "key", func() (int, []uint64) {
+		assert.Len(t, engine.registry.searchFlights.calls, 0)
+		return 0, nil
+	})
+	db.Rollback()
+
+	engine.registry.tenant = "1"
+	engine.searchFlight(schema, "key", func() (int, []uint64) {
+		assert.Contains(t, engine.registry.searchFlights.calls, "1:"+schema.t.String()+":key")
+		return 0, nil
+	})
+	engine.registry.tenant = ""
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -115,3 +117,139 @@ func TestSearch(t *testing.T) {
 		engine.Search(NewWhere("ID > 0"), nil, &rows)
 	})
 }
+
+func TestSearchRaw(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	engine := PrepareTables(t, &Registry{}, 5, entity, reference)
+
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 5; i++ {
+		flusher.Track(&searchEntity{Name: fmt.Sprintf("name %d", i), ReferenceOne: &searchEntityReference{Name: fmt.Sprintf("ref %d", i)}})
+	}
+	flusher.Flush()
+
+	var rows []*searchEntity
+	engine.SearchRaw(NewWhere("SELECT e.`ID`, e.`Name`, e.`ReferenceOne`, r.`Name` AS `RefName` FROM `searchEntity` e "+
+		"JOIN `searchEntityReference` r ON r.`ID` = e.`ReferenceOne` WHERE r.`Name` IN ? ORDER BY e.`ID`", []string{"ref 2", "ref 4"}), &rows, "ReferenceOne")
+	assert.Len(t, rows, 2)
+	assert.Equal(t, uint(2), rows[0].ID)
+	assert.Equal(t, "name 2", rows[0].Name)
+	assert.Equal(t, "ref 2", rows[0].ReferenceOne.Name)
+	assert.Equal(t, uint(4), rows[1].ID)
+	assert.True(t, rows[1].IsLoaded())
+
+	engine.SearchRawLazy(NewWhere("SELECT `ID`, `Name` FROM `searchEntity` WHERE `ID` = ?", 3), &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "name 3", rows[0].GetFieldLazy("Name"))
+
+	assert.PanicsWithError(t, "missing `ID` column in raw query for entity 'orm.searchEntity'", func() {
+		engine.SearchRaw(NewWhere("SELECT `Name` FROM `searchEntity`"), &rows)
+	})
+}
+
+func TestSearchByReference(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	engine := PrepareTables(t, &Registry{}, 5, entity, reference)
+
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 5; i++ {
+		flusher.Track(&searchEntity{Name: fmt.Sprintf("name %d", i), ReferenceOne: &searchEntityReference{Name: fmt.Sprintf("ref %d", i)}})
+	}
+	flusher.Flush()
+
+	var rows []*searchEntity
+	total := engine.SearchWithCount(W.RefIn("ReferenceOne.Name", []string{"ref 2", "ref 3"}).And(W.NotEq("Name", "name 3")), nil, &rows)
+	assert.Equal(t, 1, total)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "name 2", rows[0].Name)
+	ids := engine.SearchIDs(W.RefEq("ReferenceOne.Name", "ref 5"), nil, entity)
+	assert.Equal(t, []uint64{5}, ids)
+	ids = engine.SearchIDs(W.Exists(reference, "searchEntityReference.ID = searchEntity.ReferenceOne AND searchEntityReference.Name IN ?",
+		[]string{"ref 1", "ref 4"}), nil, entity)
+	assert.Equal(t, []uint64{1, 4}, ids)
+	ids = engine.SearchIDs(W.NotExists(reference, "searchEntityReference.ID = searchEntity.ReferenceOne AND searchEntityReference.Name != ?",
+		"ref 3"), nil, entity)
+	assert.Equal(t, []uint64{3}, ids)
+	assert.PanicsWithError(t, "reference 'Name' not found in entity 'orm.searchEntity'", func() {
+		engine.Search(W.RefEq("Name.Name", "ref 5"), nil, &rows)
+	})
+}
+
+func TestSearchForUpdate(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	engine := PrepareTables(t, &Registry{}, 8, entity, reference)
+	engine.FlushMany(&searchEntity{Name: "a"}, &searchEntity{Name: "b"})
+
+	assert.PanicsWithError(t, "FOR UPDATE requires transaction in mysql pool 'default'", func() {
+		engine.LoadByIDForUpdate(1, &searchEntity{}, ForUpdate)
+	})
+	db := engine.GetMysql()
+	db.Begin()
+	entity = &searchEntity{}
+	assert.True(t, engine.LoadByIDForUpdate(1, entity, ForUpdate))
+	assert.Equal(t, "a", entity.Name)
+	var rows []*searchEntity
+	engine.SearchForUpdate(NewWhere("1 ORDER BY `ID`"), nil, &rows, ForShareSkipLocked)
+	assert.Len(t, rows, 2)
+	assert.True(t, engine.SearchOneForUpdate(NewWhere("`Name` = ?", "b"), entity, ForUpdateNoWait))
+	assert.Equal(t, uint(2), entity.ID)
+	db.Commit()
+
+	engine = PrepareTables(t, &Registry{}, 5, entity, reference)
+	db = engine.GetMysql()
+	db.Begin()
+	defer db.Rollback()
+	engine.SearchForUpdate(NewWhere("1"), nil, &rows, ForShare)
+	assert.PanicsWithError(t, "FOR UPDATE SKIP LOCKED is not supported in MySQL 5", func() {
+		engine.SearchForUpdate(NewWhere("1"), nil, &rows, ForUpdateSkipLocked)
+	})
+}
+
+type getReferencedChildEntity struct {
+	ORM         `orm:"localCache"`
+	ID          uint
+	Name        string
+	Parent      *searchEntityReference
+	IndexParent *CachedQuery `query:":Parent = ? ORDER BY :Name"`
+}
+
+func TestGetReferenced(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	var child *getReferencedChildEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity, reference, child)
+	parent := &searchEntityReference{Name: "parent"}
+	other := &searchEntityReference{Name: "other"}
+	engine.FlushMany(parent, other)
+	engine.FlushMany(&searchEntity{Name: "a", ReferenceOne: parent}, &searchEntity{Name: "b", ReferenceOne: other},
+		&searchEntity{Name: "c", ReferenceOne: parent})
+	engine.FlushMany(&getReferencedChildEntity{Name: "y", Parent: parent}, &getReferencedChildEntity{Name: "x", Parent: parent},
+		&getReferencedChildEntity{Name: "z", Parent: other})
+
+	var rows []*searchEntity
+	assert.Equal(t, 2, engine.GetReferenced(parent, &rows, "ReferenceOne", nil))
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "c", rows[1].Name)
+	assert.Equal(t, 2, engine.GetReferenced(parent, &rows, "ReferenceOne", NewPager(2, 1)))
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "c", rows[0].Name)
+
+	var children []*getReferencedChildEntity
+	assert.Equal(t, 2, engine.GetReferenced(parent, &children, "Parent", nil))
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+	assert.Equal(t, 2, engine.GetReferencedLazy(parent, &children, "Parent", nil))
+	assert.Len(t, dbLogger.Entries, 0)
+	assert.Len(t, children, 2)
+	assert.Equal(t, "x", children[0].Name)
+	assert.Equal(t, "y", children[1].Name)
+	assert.True(t, children[0].IsLazy())
+
+	assert.PanicsWithError(t, "field 'Name' in entity 'orm.searchEntity' is not reference to 'orm.searchEntityReference'", func() {
+		engine.GetReferenced(parent, &rows, "Name", nil)
+	})
+}
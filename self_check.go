@@ -0,0 +1,84 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type SelfCheckReport struct {
+	Errors []HealthCheckError
+	Valid  []HealthCheckStep
+}
+
+func (r *SelfCheckReport) IsValid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *SelfCheckReport) add(step HealthCheckStep, err error) {
+	if err != nil {
+		r.Errors = append(r.Errors, HealthCheckError{step, err.Error()})
+	} else {
+		r.Valid = append(r.Valid, step)
+	}
+}
+
+func (e *Engine) SelfCheck(ctx context.Context) *SelfCheckReport {
+	engine := e.Clone()
+	engine.context = ctx
+	report := &SelfCheckReport{}
+	for pool, def := range e.registry.mySQLServers {
+		step := HealthCheckStep{Name: "ping MySQL " + strings.ToUpper(pool), Description: "MySQL " + def.GetDatabase() + " database is reachable"}
+		report.add(step, def.getClient().PingContext(ctx))
+	}
+	for pool, def := range e.registry.redisServers {
+		step := HealthCheckStep{Name: "ping Redis " + strings.ToUpper(pool), Description: "Redis " + def.GetAddress() + " is reachable"}
+		report.add(step, def.getClient().Ping(ctx).Err())
+	}
+	for pool, def := range e.registry.clickHouseClients {
+		step := HealthCheckStep{Name: "ping ClickHouse " + strings.ToUpper(pool), Description: "ClickHouse " + pool + " is reachable"}
+		report.add(step, def.db.PingContext(ctx))
+	}
+	for pool, def := range e.registry.elasticServers {
+		step := HealthCheckStep{Name: "ping Elastic " + strings.ToUpper(pool), Description: "Elastic " + pool + " is reachable"}
+		_, err := def.client.ClusterHealth().Do(ctx)
+		report.add(step, err)
+	}
+	for _, name := range e.registry.ListEntities() {
+		schema := e.registry.tableSchemas[e.registry.entities[name]]
+		step := HealthCheckStep{Name: "table " + schema.tableName, Description: "table " + schema.tableName + " matches entity " + name}
+		report.add(step, healthCheck(func() {
+			has, alters := schema.GetSchemaChanges(engine)
+			if !has {
+				return
+			}
+			for _, alter := range alters {
+				if strings.HasPrefix(alter.SQL, "CREATE TABLE") {
+					panic(fmt.Errorf("table '%s' does not exist", schema.tableName))
+				}
+			}
+			panic(fmt.Errorf("table '%s' requires %d schema changes", schema.tableName, len(alters)))
+		}))
+	}
+	for stream, pool := range e.registry.ListStreams() {
+		step := HealthCheckStep{Name: "stream " + stream, Description: "stream " + stream + " in Redis " + pool + " has all consumer groups"}
+		report.add(step, healthCheck(func() {
+			existing := make(map[string]bool)
+			for _, group := range engine.GetRedis(pool).XInfoGroups(stream) {
+				existing[group.Name] = true
+			}
+			missing := make([]string, 0)
+			for group := range e.registry.getRedisStreamGroups(pool, stream) {
+				if !existing[group] {
+					missing = append(missing, group)
+				}
+			}
+			if len(missing) > 0 {
+				sort.Strings(missing)
+				panic(fmt.Errorf("stream '%s' is missing consumer groups: %s", stream, strings.Join(missing, ",")))
+			}
+		}))
+	}
+	return report
+}
@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type selfCheckEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestSelfCheck(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedisStream("self-check-stream", "default", []string{"test-group"})
+	engine := PrepareTables(t, registry, 5, &selfCheckEntity{})
+
+	report := engine.SelfCheck(context.Background())
+	assert.False(t, report.IsValid())
+	assert.Len(t, report.Errors, 1)
+	assert.Equal(t, "stream self-check-stream", report.Errors[0].Name)
+	assert.Equal(t, "stream 'self-check-stream' is missing consumer groups: test-group", report.Errors[0].Message)
+
+	engine.GetRedis().XGroupCreateMkStream("self-check-stream", "test-group", "0")
+	report = engine.SelfCheck(context.Background())
+	assert.True(t, report.IsValid())
+	names := make([]string, 0)
+	for _, step := range report.Valid {
+		names = append(names, step.Name)
+	}
+	assert.Contains(t, names, "ping MySQL DEFAULT")
+	assert.Contains(t, names, "ping MySQL LOG")
+	assert.Contains(t, names, "ping Redis DEFAULT")
+	assert.Contains(t, names, "table selfCheckEntity")
+	assert.Contains(t, names, "stream self-check-stream")
+
+	engine.GetMysql().Exec("ALTER TABLE `selfCheckEntity` DROP COLUMN `Name`")
+	report = engine.SelfCheck(context.Background())
+	assert.Len(t, report.Errors, 1)
+	assert.Equal(t, "table 'selfCheckEntity' requires 1 schema changes", report.Errors[0].Message)
+
+	engine.GetMysql().Exec("DROP TABLE `selfCheckEntity`")
+	report = engine.SelfCheck(context.Background())
+	assert.Len(t, report.Errors, 1)
+	assert.Equal(t, "table 'selfCheckEntity' does not exist", report.Errors[0].Message)
+}
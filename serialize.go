@@ -0,0 +1,72 @@
+package orm
+
+import (
+	"bytes"
+	"strings"
+)
+
+type SerializeOptions struct {
+	View string
+	Mask func(entity Entity, field string, value interface{}) interface{}
+}
+
+func (e *Engine) Serialize(entity Entity, options *SerializeOptions) map[string]interface{} {
+	fields, values := e.serialize(entity, options)
+	result := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		result[field] = values[i]
+	}
+	return result
+}
+
+func (e *Engine) SerializeJSON(entity Entity, options *SerializeOptions) ([]byte, error) {
+	fields, values := e.serialize(entity, options)
+	buffer := &bytes.Buffer{}
+	err := writeExportJSON(buffer, fields, values)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+func (e *Engine) serialize(entity Entity, options *SerializeOptions) ([]string, []interface{}) {
+	if options == nil {
+		options = &SerializeOptions{}
+	}
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	permissions := schema.getFieldPermissions(e)
+	fields := make([]string, 0)
+	for _, field := range getExportFields(schema) {
+		if field != "ID" && !schema.hasView(field, options.View) {
+			continue
+		}
+		if permissions != nil && !permissions.read[field] {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i] = getExportValue(schema, entity, field)
+		if options.Mask != nil {
+			values[i] = options.Mask(entity, field, values[i])
+		}
+	}
+	return fields, values
+}
+
+func (tableSchema *tableSchema) hasView(field, view string) bool {
+	if view == "" {
+		return true
+	}
+	views, has := tableSchema.tags[field]["view"]
+	if !has {
+		return false
+	}
+	for _, name := range strings.Split(views, ",") {
+		if strings.TrimSpace(name) == view {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type serializeEntity struct {
+	ORM
+	ID       uint
+	Name     string `orm:"view=public,admin"`
+	Email    string `orm:"view=admin"`
+	Password string
+	Age      int `orm:"view=public"`
+}
+
+func TestSerialize(t *testing.T) {
+	var entity *serializeEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	entity = &serializeEntity{Name: "John", Email: "john@example.com", Password: "secret", Age: 18}
+	engine.Flush(entity)
+
+	assert.Equal(t, map[string]interface{}{"ID": uint(1), "Name": "John", "Age": 18},
+		engine.Serialize(entity, &SerializeOptions{View: "public"}))
+	assert.Equal(t, map[string]interface{}{"ID": uint(1), "Name": "John", "Email": "john@example.com"},
+		engine.Serialize(entity, &SerializeOptions{View: "admin"}))
+	assert.Len(t, engine.Serialize(entity, nil), 5)
+
+	asJSON, err := engine.SerializeJSON(entity, &SerializeOptions{View: "admin", Mask: func(entity Entity, field string, value interface{}) interface{} {
+		if field == "Email" {
+			return "***"
+		}
+		return value
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ID":1,"Name":"John","Email":"***"}`, string(asJSON))
+}
@@ -0,0 +1,271 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sortedIndexComplete = "c"
+const sortedIndexPartial = "p"
+
+const sortedIndexReadScript = `
+local card = redis.call('zcard', KEYS[1])
+if card == 0 then
+	return {}
+end
+local total = redis.call('get', KEYS[2]) or ''
+local ids
+if ARGV[3] == '1' then
+	ids = redis.call('zrevrange', KEYS[1], ARGV[1], ARGV[2])
+else
+	ids = redis.call('zrange', KEYS[1], ARGV[1], ARGV[2])
+end
+return {card, total, ids}
+`
+
+const sortedIndexBuildScript = `
+redis.call('del', KEYS[1], KEYS[2])
+for i = 3, #ARGV, 2 do
+	redis.call('zadd', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+if ARGV[1] ~= '' then
+	redis.call('set', KEYS[2], ARGV[1])
+end
+if ARGV[2] ~= '0' then
+	redis.call('expire', KEYS[1], ARGV[2])
+	if ARGV[1] ~= '' then
+		redis.call('expire', KEYS[2], ARGV[2])
+	end
+end
+return 1
+`
+
+const sortedIndexUpdateScript = `
+for i = 1, #KEYS do
+	local op = ARGV[i * 3 - 2]
+	local member = ARGV[i * 3 - 1]
+	if redis.call('exists', KEYS[i]) == 1 then
+		local totalKey = KEYS[i] .. ':t'
+		if op == 'a' then
+			if redis.call('zadd', KEYS[i], ARGV[i * 3], member) == 1 and redis.call('exists', totalKey) == 1 then
+				redis.call('incr', totalKey)
+			end
+		elseif redis.call('zrem', KEYS[i], member) == 1 and redis.call('zscore', KEYS[i], 'p') then
+			redis.call('del', KEYS[i], totalKey)
+		end
+	end
+end
+return 1
+`
+
+var sortedIndexWhereRegexp = regexp.MustCompile(`^\s*:[A-Za-z0-9]+\s*=\s*\?\s*$`)
+var sortedIndexAndRegexp = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+func initSortedIndex(def *cachedQueryDefinition, indexName, query string, posOrderBy int, entityType reflect.Type, hasRedis bool) error {
+	if !hasRedis {
+		return fmt.Errorf("sorted cached index '%s' in entity '%s' requires redis cache", indexName, entityType.String())
+	}
+	if len(def.OrderFields) != 1 {
+		return fmt.Errorf("sorted cached index '%s' in entity '%s' requires exactly one ORDER BY field", indexName, entityType.String())
+	}
+	where := query
+	if posOrderBy > -1 {
+		where = query[0:posOrderBy]
+	}
+	if strings.TrimSpace(where) != "" {
+		for _, condition := range sortedIndexAndRegexp.Split(where, -1) {
+			if !sortedIndexWhereRegexp.MatchString(condition) {
+				return fmt.Errorf("sorted cached index '%s' in entity '%s' supports only equality conditions", indexName, entityType.String())
+			}
+		}
+	}
+	field, has := entityType.FieldByName(def.OrderFields[0])
+	valid := false
+	if has {
+		switch field.Type.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+			valid = true
+		default:
+			valid = field.Type.String() == "time.Time"
+		}
+	}
+	if !valid {
+		return fmt.Errorf("sorted cached index '%s' in entity '%s' must be ordered by not nullable number or time field", indexName, entityType.String())
+	}
+	def.sorted = true
+	def.sortField = def.OrderFields[0]
+	def.sortDesc = strings.Contains(strings.ToLower(query[posOrderBy:]), "desc")
+	return nil
+}
+
+func sortedIndexScore(value interface{}) float64 {
+	switch v := value.(type) {
+	case uint64:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		if len(v) >= 10 && v[4] == '-' {
+			layout := "2006-01-02 15:04:05"
+			if len(v) == 10 {
+				layout = "2006-01-02"
+			}
+			t, err := time.ParseInLocation(layout, v, time.UTC)
+			if err == nil {
+				return float64(t.Unix())
+			}
+		}
+		score, _ := strconv.ParseFloat(v, 64)
+		return score
+	}
+	return 0
+}
+
+func isFakeDeleted(value interface{}) bool {
+	switch v := value.(type) {
+	case uint64:
+		return v > 0
+	case bool:
+		return v
+	}
+	return false
+}
+
+func (tableSchema *tableSchema) getSortedIndexKey(indexName string, definition *cachedQueryDefinition, data []interface{}) (key string, member bool) {
+	if tableSchema.hasFakeDelete && isFakeDeleted(data[tableSchema.columnMapping["FakeDelete"]]) {
+		return "", false
+	}
+	attributes := make([]interface{}, 0, len(definition.QueryFields))
+	for _, field := range definition.QueryFields {
+		if !tableSchema.hasFakeDelete || field != "FakeDelete" {
+			attributes = append(attributes, data[tableSchema.columnMapping[field]])
+		}
+	}
+	return getCacheKeySearch(tableSchema, indexName, attributes...), true
+}
+
+func (tableSchema *tableSchema) getSortedIndexKeys(data []interface{}) []string {
+	keys := make([]string, 0)
+	for indexName, definition := range tableSchema.cachedIndexes {
+		if definition.sorted {
+			if key, member := tableSchema.getSortedIndexKey(indexName, definition, data); member {
+				keys = append(keys, key, key+":t")
+			}
+		}
+	}
+	return keys
+}
+
+func (f *flusher) updateSortedIndexes(schema *tableSchema, redisCache *RedisCache, id uint64, before, after []interface{}, lazy bool) {
+	code := redisCache.config.GetCode()
+	member := strconv.FormatUint(id, 10)
+	for indexName, definition := range schema.cachedIndexes {
+		if !definition.sorted {
+			continue
+		}
+		oldKey, newKey := "", ""
+		if before != nil {
+			oldKey, _ = schema.getSortedIndexKey(indexName, definition, before)
+		}
+		if after != nil {
+			newKey, _ = schema.getSortedIndexKey(indexName, definition, after)
+		}
+		if lazy {
+			if oldKey != "" {
+				f.getRedisFlusher().Del(code, oldKey, oldKey+":t")
+			}
+			if newKey != "" && newKey != oldKey {
+				f.getRedisFlusher().Del(code, newKey, newKey+":t")
+			}
+			continue
+		}
+		if oldKey != "" && oldKey != newKey {
+			f.getRedisFlusher().zUpdate(code, oldKey, "r", member, "")
+		}
+		if newKey != "" {
+			score := sortedIndexScore(after[schema.columnMapping[definition.sortField]])
+			f.getRedisFlusher().zUpdate(code, newKey, "a", member, strconv.FormatFloat(score, 'f', -1, 64))
+		}
+	}
+}
+
+func cachedSearchSorted(engine *Engine, schema *tableSchema, entities interface{}, definition *cachedQueryDefinition, cacheKey string,
+	where *Where, pager *Pager, lazy bool, references []string) (totalRows int, ids []uint64) {
+	redisCache, _ := schema.GetRedisCache(engine)
+	start := (pager.GetCurrentPage() - 1) * pager.GetPageSize()
+	stop := start + pager.GetPageSize() - 1
+	desc := "0"
+	if definition.sortDesc {
+		desc = "1"
+	}
+	keys := []string{cacheKey, cacheKey + ":t"}
+	result := redisCache.Eval(sortedIndexReadScript, keys, start, stop, desc).([]interface{})
+	if len(result) == 0 {
+		engine.searchFlight(schema, cacheKey+":z", func() (int, []uint64) {
+			buildSortedIndex(engine, schema, redisCache, definition, keys, where)
+			return 0, nil
+		})
+		result = redisCache.Eval(sortedIndexReadScript, keys, start, stop, desc).([]interface{})
+	}
+	totalRows = int(result[0].(int64)) - 1
+	if total := result[1].(string); total != "" {
+		totalRows, _ = strconv.Atoi(total)
+	}
+	members := result[2].([]interface{})
+	ids = make([]uint64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member.(string), 10, 64)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+	_, is := entities.(Entity)
+	if !is {
+		value := reflect.ValueOf(entities)
+		tryByIDs(engine, ids, value.Elem(), references, lazy)
+		filterByRowPolicy(engine, schema, value.Elem())
+	}
+	return totalRows, ids
+}
+
+func buildSortedIndex(engine *Engine, schema *tableSchema, redisCache *RedisCache, definition *cachedQueryDefinition, keys []string, where *Where) {
+	pool := schema.GetMysql(engine)
+	/* #nosec */
+	query := "SELECT `ID`,`" + definition.sortField + "` FROM `" + schema.tableName + "` WHERE " + where.String() + " LIMIT " + strconv.Itoa(definition.Max+1)
+	rows, def := pool.Query(query, where.GetParameters()...)
+	defer def()
+	args := []interface{}{"", int(schema.redisCacheTTL.Seconds())}
+	count := 0
+	for rows.Next() {
+		var id uint64
+		var value string
+		rows.Scan(&id, &value)
+		count++
+		if count > definition.Max {
+			break
+		}
+		args = append(args, strconv.FormatFloat(sortedIndexScore(value), 'f', -1, 64), strconv.FormatUint(id, 10))
+	}
+	def()
+	sentinel := sortedIndexComplete
+	if count > definition.Max {
+		sentinel = sortedIndexPartial
+		var total int
+		/* #nosec */
+		pool.QueryRow(NewWhere("SELECT COUNT(1) FROM `"+schema.tableName+"` WHERE "+where.String(), where.GetParameters()...), &total)
+		args[0] = strconv.Itoa(total)
+	}
+	sentinelScore := "+inf"
+	if definition.sortDesc {
+		sentinelScore = "-inf"
+	}
+	args = append(args, sentinelScore, sentinel)
+	redisCache.Eval(sortedIndexBuildScript, keys, args...)
+}
@@ -0,0 +1,115 @@
+package orm
+
+import (
+	"testing"
+
+	apexLog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type sortedIndexEntity struct {
+	ORM         `orm:"redisCache"`
+	ID          uint
+	Category    uint
+	Score       int
+	IndexLatest *CachedQuery `query:":Category = ? ORDER BY :Score DESC" sorted:"true"`
+	IndexSmall  *CachedQuery `query:":Category = ? ORDER BY :ID" sorted:"true" max:"2"`
+}
+
+type sortedIndexNoRedisEntity struct {
+	ORM         `orm:"localCache"`
+	ID          uint
+	Score       int
+	IndexLatest *CachedQuery `query:":Score = ? ORDER BY :ID" sorted:"true"`
+}
+
+type sortedIndexInvalidEntity struct {
+	ORM         `orm:"redisCache"`
+	ID          uint
+	Score       int
+	IndexLatest *CachedQuery `query:":Score > ? ORDER BY :ID" sorted:"true"`
+}
+
+func TestSortedCachedIndex(t *testing.T) {
+	var entity *sortedIndexEntity
+	engine := PrepareTables(t, &Registry{}, 5, entity)
+	engine.FlushMany(&sortedIndexEntity{Category: 1, Score: 10}, &sortedIndexEntity{Category: 1, Score: 30},
+		&sortedIndexEntity{Category: 1, Score: 20}, &sortedIndexEntity{Category: 2, Score: 5},
+		&sortedIndexEntity{Category: 1, Score: 40})
+	dbLogger := memory.New()
+	engine.AddQueryLogger(dbLogger, apexLog.InfoLevel, QueryLoggerSourceDB)
+
+	total, ids := engine.CachedSearchIDs(entity, "IndexLatest", NewPager(1, 10), 1)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []uint64{5, 2, 3, 1}, ids)
+	assert.Len(t, dbLogger.Entries, 1)
+	total, ids = engine.CachedSearchIDs(entity, "IndexLatest", NewPager(2, 2), 1)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []uint64{3, 1}, ids)
+	assert.Len(t, dbLogger.Entries, 1)
+
+	engine.Flush(&sortedIndexEntity{Category: 1, Score: 25})
+	dbLogger.Entries = nil
+	total, ids = engine.CachedSearchIDs(entity, "IndexLatest", NewPager(1, 10), 1)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []uint64{5, 2, 6, 3, 1}, ids)
+	assert.Len(t, dbLogger.Entries, 0)
+
+	entity = &sortedIndexEntity{}
+	engine.LoadByID(2, entity)
+	entity.Score = 1
+	engine.Flush(entity)
+	engine.LoadByID(5, entity)
+	entity.Category = 2
+	engine.Flush(entity)
+	dbLogger.Entries = nil
+	total, ids = engine.CachedSearchIDs(entity, "IndexLatest", NewPager(1, 10), 1)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []uint64{6, 3, 1, 2}, ids)
+	assert.Len(t, dbLogger.Entries, 0)
+	total, ids = engine.CachedSearchIDs(entity, "IndexLatest", NewPager(1, 10), 2)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []uint64{5, 4}, ids)
+	assert.Len(t, dbLogger.Entries, 1)
+
+	engine.LoadByID(1, entity)
+	engine.Delete(entity)
+	dbLogger.Entries = nil
+	total, ids = engine.CachedSearchIDs(entity, "IndexLatest", NewPager(1, 10), 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uint64{6, 3, 2}, ids)
+	assert.Len(t, dbLogger.Entries, 0)
+
+	total, ids = engine.CachedSearchIDs(entity, "IndexSmall", NewPager(1, 2), 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uint64{2, 3}, ids)
+	assert.Len(t, dbLogger.Entries, 2)
+	engine.Flush(&sortedIndexEntity{Category: 1, Score: 50})
+	dbLogger.Entries = nil
+	total, ids = engine.CachedSearchIDs(entity, "IndexSmall", NewPager(1, 2), 1)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []uint64{2, 3}, ids)
+	assert.Len(t, dbLogger.Entries, 0)
+	engine.LoadByID(2, entity)
+	engine.Delete(entity)
+	dbLogger.Entries = nil
+	total, ids = engine.CachedSearchIDs(entity, "IndexSmall", NewPager(1, 2), 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uint64{3, 6}, ids)
+	assert.Len(t, dbLogger.Entries, 2)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterLocalCache(100)
+	registry.RegisterEntity(&sortedIndexNoRedisEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "sorted cached index 'IndexLatest' in entity 'orm.sortedIndexNoRedisEntity' requires redis cache")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterEntity(&sortedIndexInvalidEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "sorted cached index 'IndexLatest' in entity 'orm.sortedIndexInvalidEntity' supports only equality conditions")
+}
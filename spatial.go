@@ -0,0 +1,113 @@
+package orm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+type Polygon []Point
+
+func (p Point) WKT() string {
+	return "POINT(" + p.coordinates() + ")"
+}
+
+func (p Polygon) WKT() string {
+	if len(p) == 0 {
+		return ""
+	}
+	points := make([]string, 0, len(p)+1)
+	for _, point := range p {
+		points = append(points, point.coordinates())
+	}
+	if p[0] != p[len(p)-1] {
+		points = append(points, p[0].coordinates())
+	}
+	return "POLYGON((" + strings.Join(points, ",") + "))"
+}
+
+func (p Point) coordinates() string {
+	return strconv.FormatFloat(p.Lng, 'f', -1, 64) + " " + strconv.FormatFloat(p.Lat, 'f', -1, 64)
+}
+
+func ParsePoint(wkt string) (Point, error) {
+	body, err := trimWKT(wkt, "POINT(", ")")
+	if err != nil {
+		return Point{}, err
+	}
+	return parseWKTCoordinates(body)
+}
+
+func ParsePolygon(wkt string) (Polygon, error) {
+	body, err := trimWKT(wkt, "POLYGON((", "))")
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(body, "(") {
+		return nil, fmt.Errorf("polygon with inner rings is not supported: %s", wkt)
+	}
+	parts := strings.Split(body, ",")
+	polygon := make(Polygon, 0, len(parts))
+	for _, part := range parts {
+		point, err := parseWKTCoordinates(part)
+		if err != nil {
+			return nil, err
+		}
+		polygon = append(polygon, point)
+	}
+	if len(polygon) > 1 && polygon[0] == polygon[len(polygon)-1] {
+		polygon = polygon[0 : len(polygon)-1]
+	}
+	return polygon, nil
+}
+
+func trimWKT(wkt, prefix, suffix string) (string, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(wkt), " (", "("))
+	if !strings.HasPrefix(normalized, prefix) || !strings.HasSuffix(normalized, suffix) {
+		return "", fmt.Errorf("invalid WKT value: %s", wkt)
+	}
+	return normalized[len(prefix) : len(normalized)-len(suffix)], nil
+}
+
+func parseWKTCoordinates(value string) (Point, error) {
+	coordinates := strings.Fields(value)
+	if len(coordinates) != 2 {
+		return Point{}, fmt.Errorf("invalid WKT coordinates: %s", value)
+	}
+	lng, err := strconv.ParseFloat(coordinates[0], 64)
+	if err != nil {
+		return Point{}, err
+	}
+	lat, err := strconv.ParseFloat(coordinates[1], 64)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{Lat: lat, Lng: lng}, nil
+}
+
+func normalizeWKT(wkt string) string {
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(wkt)), "POINT") {
+		point, err := ParsePoint(wkt)
+		if err != nil {
+			return wkt
+		}
+		return point.WKT()
+	}
+	polygon, err := ParsePolygon(wkt)
+	if err != nil {
+		return wkt
+	}
+	return polygon.WKT()
+}
+
+func getSpatialSQL(version int, column string, srid string) (read string, write string) {
+	if version == 8 {
+		return "ST_AsText(`" + column + "`, 'axis-order=long-lat')", "ST_GeomFromText(?, " + srid + ", 'axis-order=long-lat')"
+	}
+	return "ST_AsText(`" + column + "`)", "ST_GeomFromText(?, " + srid + ")"
+}
@@ -0,0 +1,84 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type spatialEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Name     string
+	Location Point   `orm:"srid=4326"`
+	Pickup   *Point  `orm:"srid=4326"`
+	Area     Polygon `orm:"srid=4326"`
+}
+
+func TestSpatialWKT(t *testing.T) {
+	point := Point{Lat: 52.2297, Lng: 21.0122}
+	assert.Equal(t, "POINT(21.0122 52.2297)", point.WKT())
+	parsed, err := ParsePoint("POINT (21.0122 52.2297)")
+	assert.NoError(t, err)
+	assert.Equal(t, point, parsed)
+	_, err = ParsePoint("LINESTRING(1 2,3 4)")
+	assert.EqualError(t, err, "invalid WKT value: LINESTRING(1 2,3 4)")
+
+	polygon := Polygon{{0, 0}, {0, 1}, {1, 1}}
+	assert.Equal(t, "POLYGON((0 0,1 0,1 1,0 0))", polygon.WKT())
+	assert.Equal(t, "", Polygon{}.WKT())
+	parsedPolygon, err := ParsePolygon("POLYGON((0 0,1 0,1 1,0 0))")
+	assert.NoError(t, err)
+	assert.Equal(t, polygon, parsedPolygon)
+	_, err = ParsePolygon("POLYGON((0 0,1 0,1 1,0 0),(0.1 0.1,0.2 0.1,0.2 0.2,0.1 0.1))")
+	assert.Error(t, err)
+}
+
+func TestSpatial(t *testing.T) {
+	var entity *spatialEntity
+	engine := PrepareTables(t, &Registry{}, 8, entity)
+
+	warsaw := Point{Lat: 52.2297, Lng: 21.0122}
+	area := Polygon{{52, 20.5}, {52, 21.5}, {52.5, 21.5}, {52.5, 20.5}}
+	entity = &spatialEntity{Name: "Warsaw", Location: warsaw, Area: area}
+	engine.Flush(entity)
+	berlin := &spatialEntity{Name: "Berlin", Location: Point{Lat: 52.52, Lng: 13.405}, Pickup: &Point{Lat: 52.5, Lng: 13.4}}
+	engine.Flush(berlin)
+
+	loaded := &spatialEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, warsaw, loaded.Location)
+	assert.Nil(t, loaded.Pickup)
+	assert.Equal(t, area, loaded.Area)
+	assert.False(t, loaded.IsDirty())
+	engine.GetLocalCache().Clear()
+	loaded = &spatialEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, Point{Lat: 52.5, Lng: 13.4}, *loaded.Pickup)
+	assert.Nil(t, loaded.Area)
+
+	loaded.Pickup = nil
+	loaded.Location = Point{Lat: 52.53, Lng: 13.41}
+	assert.True(t, loaded.IsDirty())
+	engine.Flush(loaded)
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	loaded = &spatialEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Nil(t, loaded.Pickup)
+	assert.Equal(t, Point{Lat: 52.53, Lng: 13.41}, loaded.Location)
+
+	var rows []*spatialEntity
+	engine.Search(W.DistanceSphere("Location", Point{Lat: 52.23, Lng: 21.01}, 10000), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Warsaw", rows[0].Name)
+	engine.Search(W.Contains("Area", warsaw), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Warsaw", rows[0].Name)
+	engine.Search(W.Within("Location", area), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Warsaw", rows[0].Name)
+	assert.PanicsWithError(t, "field 'Name' in entity 'orm.spatialEntity' is not spatial", func() {
+		engine.Search(W.Contains("Name", warsaw), nil, &rows)
+	})
+}
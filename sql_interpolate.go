@@ -0,0 +1,126 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (e *Engine) EnableInterpolatedQueryLog(maxValueLength int) {
+	e.interpolateQueries = true
+	e.interpolateMaxLength = maxValueLength
+}
+
+func InterpolateSQL(query string, args []interface{}, maxValueLength int) string {
+	if len(args) == 0 {
+		return query
+	}
+	var builder strings.Builder
+	argIndex := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		char := query[i]
+		if quote != 0 {
+			builder.WriteByte(char)
+			if char == '\\' && quote != '`' && i+1 < len(query) {
+				i++
+				builder.WriteByte(query[i])
+			} else if char == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch char {
+		case '\'', '"', '`':
+			quote = char
+			builder.WriteByte(char)
+		case '?':
+			if argIndex < len(args) {
+				builder.WriteString(interpolateValue(args[argIndex], maxValueLength))
+				argIndex++
+			} else {
+				builder.WriteByte(char)
+			}
+		default:
+			builder.WriteByte(char)
+		}
+	}
+	return builder.String()
+}
+
+func interpolateValue(value interface{}, maxValueLength int) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return interpolateString(v, maxValueLength)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		return interpolateString(string(v), maxValueLength)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		if v.Nanosecond() == 0 {
+			return "'" + v.Format("2006-01-02 15:04:05") + "'"
+		}
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case *time.Time:
+		if v == nil {
+			return "NULL"
+		}
+		return interpolateValue(*v, maxValueLength)
+	}
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return "NULL"
+		}
+		return interpolateValue(reflectValue.Elem().Interface(), maxValueLength)
+	}
+	return interpolateString(fmt.Sprintf("%v", value), maxValueLength)
+}
+
+func interpolateString(value string, maxValueLength int) string {
+	if maxValueLength > 0 && len(value) > maxValueLength {
+		cut := maxValueLength
+		for cut > 0 && cut < len(value) && value[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		value = value[0:cut] + "..."
+	}
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case 0:
+			builder.WriteString(`\0`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\'':
+			builder.WriteString(`\'`)
+		case 0x1a:
+			builder.WriteString(`\Z`)
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	builder.WriteByte('\'')
+	return builder.String()
+}
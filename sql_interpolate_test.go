@@ -0,0 +1,19 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolateSQL(t *testing.T) {
+	when := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	var nilPointer *uint
+	id := uint(7)
+	query := InterpolateSQL("SELECT * FROM `a?` WHERE `Name` = ? AND `Note` != '?' AND `ID` IN (?,?) AND `Active` = ? AND `Time` = ? AND `Ref` = ? AND `Score` > ? AND ?",
+		[]interface{}{"it's\n", 3, &id, true, when, nilPointer, 1.5}, 0)
+	assert.Equal(t, "SELECT * FROM `a?` WHERE `Name` = 'it\\'s\\n' AND `Note` != '?' AND `ID` IN (3,7) AND `Active` = 1 AND `Time` = '2021-03-04 05:06:07' AND `Ref` = NULL AND `Score` > 1.5 AND ?", query)
+	assert.Equal(t, "UPDATE `a` SET `Name` = 'zażó...'", InterpolateSQL("UPDATE `a` SET `Name` = ?", []interface{}{"zażółć"}, 6))
+	assert.Equal(t, "SELECT 1", InterpolateSQL("SELECT 1", nil, 0))
+}
@@ -19,6 +19,11 @@ type cachedQueryDefinition struct {
 	TrackedFields []string
 	QueryFields   []string
 	OrderFields   []string
+	queries       uint64
+	fallbacks     uint64
+	sorted        bool
+	sortField     string
+	sortDesc      bool
 }
 
 type Enum interface {
@@ -78,40 +83,88 @@ type TableSchema interface {
 	GetColumns() []string
 	GetUsage(registry ValidatedRegistry) map[reflect.Type][]string
 	GetSchemaChanges(engine *Engine) (has bool, alters []Alter)
+	GetColumnDefinitions(engine *Engine) []ColumnDefinition
+	GetIndexDefinitions(engine *Engine) []IndexDefinition
+	GetReferenceDefinitions(engine *Engine) []ReferenceDefinition
+	GetCacheDefinition() CacheDefinition
+	GetCachedIndexStats() map[string]CachedIndexStats
+	GetApproxCount(engine *Engine) int
+	ReconcileCounter(engine *Engine) int
+	GetCacheVersion(engine *Engine) uint64
+	GetCacheCorruptions() uint64
+	InvalidateAllCache(engine *Engine)
 }
 
 type tableSchema struct {
-	tableName            string
-	mysqlPoolName        string
-	t                    reflect.Type
-	fields               *tableFields
-	fieldsQuery          string
-	tags                 map[string]map[string]string
-	cachedIndexes        map[string]*cachedQueryDefinition
-	cachedIndexesOne     map[string]*cachedQueryDefinition
-	cachedIndexesAll     map[string]*cachedQueryDefinition
-	columnNames          []string
-	columnMapping        map[string]int
-	uniqueIndices        map[string][]string
-	uniqueIndicesGlobal  map[string][]string
-	dirtyFields          map[string][]string
-	refOne               []string
-	refMany              []string
-	localCacheName       string
-	hasLocalCache        bool
-	redisCacheName       string
-	hasRedisCache        bool
-	searchCacheName      string
-	hasSearchCache       bool
-	cachePrefix          string
-	hasFakeDelete        bool
-	hasLog               bool
-	logPoolName          string //name of redis
-	logTableName         string
-	skipLogs             []string
-	redisSearchPrefix    string
-	redisSearchIndex     *RedisSearchIndex
-	mapBindToRedisSearch mapBindToRedisSearch
+	tableName             string
+	mysqlPoolName         string
+	t                     reflect.Type
+	fields                *tableFields
+	fieldsQuery           string
+	tags                  map[string]map[string]string
+	cachedIndexes         map[string]*cachedQueryDefinition
+	cachedIndexesOne      map[string]*cachedQueryDefinition
+	cachedIndexesAll      map[string]*cachedQueryDefinition
+	columnNames           []string
+	columnMapping         map[string]int
+	uniqueIndices         map[string][]string
+	uniqueIndicesGlobal   map[string][]string
+	uniqueCheck           bool
+	dirtyFields           map[string][]string
+	refOne                []string
+	refMany               []string
+	manyToMany            map[string]*manyToManyDefinition
+	localCacheName        string
+	hasLocalCache         bool
+	redisCacheName        string
+	hasRedisCache         bool
+	redisCacheTTL         time.Duration
+	searchCacheName       string
+	hasSearchCache        bool
+	cachePrefix           string
+	hasCacheVersion       bool
+	counterPool           string
+	renamedTable          string
+	renamedColumns        map[string]string
+	validators            map[string]*fieldValidator
+	virtualFields         []virtualField
+	cacheMode             string
+	cacheDelay            time.Duration
+	cacheVersion          uint64
+	cacheVersionListening int32
+	cacheCorruptions      uint64
+	cacheFormat           string
+	charset               string
+	collation             string
+	hasFakeDelete         bool
+	rowPolicy             RowPolicy
+	idGenerator           IDGenerator
+	fieldPermissions      map[string]*fieldPermissions
+	columnFields          map[string]string
+	generated             bool
+	hasLog                bool
+	logPoolName           string //name of redis
+	logTableName          string
+	skipLogs              []string
+	sets                  map[string]Enum
+	spatials              map[string]string
+	decimals              map[string]bool
+	largeColumns          map[string]bool
+	timeZones             map[string]*time.Location
+	redisSearchPrefix     string
+	redisSearchIndex      *RedisSearchIndex
+	mapBindToRedisSearch  mapBindToRedisSearch
+	redisSearchComputed   []*redisSearchComputedField
+	redisSearchPredicate  RedisSearchPredicate
+	redisSearchReferences []*redisSearchReferenceField
+	redisSearchDependents []*redisSearchDependent
+	searchSuggest         []string
+	tenant                string
+}
+
+type manyToManyDefinition struct {
+	table   string
+	refType string
 }
 
 type mapBindToRedisSearch map[string]func(val interface{}) interface{}
@@ -137,11 +190,16 @@ type tableFields struct {
 	timesNullable     []int
 	times             []int
 	jsons             []int
+	spatials          []int
+	decimals          []int
+	customs           []int
+	customTypes       []*FieldTypeDefinition
 	structs           map[int]*tableFields
 	refs              []int
 	refsTypes         []reflect.Type
 	refsMany          []int
 	refsManyTypes     []reflect.Type
+	arrays            []int
 }
 
 func getTableSchema(registry *validatedRegistry, entityType reflect.Type) *tableSchema {
@@ -159,12 +217,18 @@ func (tableSchema *tableSchema) GetType() reflect.Type {
 func (tableSchema *tableSchema) DropTable(engine *Engine) {
 	pool := tableSchema.GetMysql(engine)
 	pool.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), tableSchema.tableName))
+	for _, m2m := range tableSchema.manyToMany {
+		pool.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), m2m.table))
+	}
 }
 
 func (tableSchema *tableSchema) TruncateTable(engine *Engine) {
 	pool := tableSchema.GetMysql(engine)
 	_ = pool.Exec(fmt.Sprintf("DELETE FROM `%s`.`%s`", pool.GetPoolConfig().GetDatabase(), tableSchema.tableName))
 	_ = pool.Exec(fmt.Sprintf("ALTER TABLE `%s`.`%s` AUTO_INCREMENT = 1", pool.GetPoolConfig().GetDatabase(), tableSchema.tableName))
+	for _, m2m := range tableSchema.manyToMany {
+		_ = pool.Exec(fmt.Sprintf("DELETE FROM `%s`.`%s`", pool.GetPoolConfig().GetDatabase(), m2m.table))
+	}
 }
 
 func (tableSchema *tableSchema) UpdateSchema(engine *Engine) {
@@ -245,6 +309,7 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	tags := extractTags(registry, entityType, "")
 	oneRefs := make([]string, 0)
 	manyRefs := make([]string, 0)
+	manyToMany := make(map[string]*manyToManyDefinition)
 	mapBindToRedisSearch := mapBindToRedisSearch{}
 	mapBindToScanPointer := mapBindToScanPointer{}
 	mapPointerToValue := mapPointerToValue{}
@@ -259,6 +324,9 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	table, has := tags["ORM"]["table"]
 	if !has {
 		table = entityType.Name()
+		if registry.namingStrategy != nil {
+			table = registry.namingStrategy(table)
+		}
 	}
 	localCache := ""
 	redisCache := ""
@@ -276,6 +344,7 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 			return nil, fmt.Errorf("local cache pool '%s' not found", localCache)
 		}
 	}
+	var redisCacheTTL time.Duration
 	userValue, has = tags["ORM"]["redisCache"]
 	if has {
 		if userValue == "true" {
@@ -283,6 +352,17 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 		}
 		redisCache = userValue
 	}
+	userValue, has = tags["ORM"]["redisCacheTTL"]
+	if has {
+		if redisCache == "" {
+			return nil, fmt.Errorf("redis cache TTL requires redisCache in entity '%s'", entityType.String())
+		}
+		ttl, err := strconv.Atoi(userValue)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid redis cache TTL '%s' in entity '%s'", userValue, entityType.String())
+		}
+		redisCacheTTL = time.Duration(ttl) * time.Second
+	}
 	if redisCache != "" {
 		_, has = registry.mysqlPools[redisCache]
 		if !has {
@@ -304,6 +384,22 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	} else {
 		redisSearch = "default"
 	}
+	cacheMode, cacheDelay, err := initCacheMode(tags["ORM"], entityType)
+	if err != nil {
+		return nil, err
+	}
+	_, uniqueCheck := tags["ORM"]["uniqueCheck"]
+	counterPool, err := initCounter(registry, tags["ORM"], redisCache, entityType)
+	if err != nil {
+		return nil, err
+	}
+	_, hasCacheVersion := tags["ORM"]["cacheVersion"]
+	if hasCacheVersion {
+		_, has = registry.redisPools["default"]
+		if redisCache == "" && !has {
+			return nil, fmt.Errorf("cache version in entity '%s' requires redis pool", entityType.String())
+		}
+	}
 	cachePrefix := ""
 	if mysql != "default" {
 		cachePrefix = mysql
@@ -325,6 +421,11 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 			query, has = values["queryOne"]
 			isOne = true
 		}
+		if !has && values["ref"] != "" && values["cached"] == "true" {
+			query = ":" + key + " = ? ORDER BY :ID"
+			has = true
+			isOne = false
+		}
 		queryOrigin := query
 		fields := make([]string, 0)
 		fieldsTracked := make([]string, 0)
@@ -379,11 +480,24 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 			}
 
 			if !isOne {
-				def := &cachedQueryDefinition{50000, query, fieldsTracked, fieldsQuery, fieldsOrder}
+				maxRows := 50000
+				if maxValue, hasMax := values["max"]; hasMax {
+					maxRows, _ = strconv.Atoi(maxValue)
+					if maxRows <= 0 {
+						return nil, fmt.Errorf("invalid max '%s' in cached index '%s' in entity '%s'", maxValue, key, entityType.String())
+					}
+				}
+				def := &cachedQueryDefinition{Max: maxRows, Query: query, TrackedFields: fieldsTracked, QueryFields: fieldsQuery, OrderFields: fieldsOrder}
+				if values["sorted"] == "true" {
+					err := initSortedIndex(def, key, queryOrigin, posOrderBy, entityType, redisCache != "")
+					if err != nil {
+						return nil, err
+					}
+				}
 				cachedQueries[key] = def
 				cachedQueriesAll[key] = def
 			} else {
-				def := &cachedQueryDefinition{1, query, fieldsTracked, fieldsQuery, fieldsOrder}
+				def := &cachedQueryDefinition{Max: 1, Query: query, TrackedFields: fieldsTracked, QueryFields: fieldsQuery, OrderFields: fieldsOrder}
 				cachedQueriesOne[key] = def
 				cachedQueriesAll[key] = def
 			}
@@ -396,6 +510,14 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 		if has {
 			manyRefs = append(manyRefs, key)
 		}
+		m2mTable, has := values["m2m"]
+		if has {
+			m2mRef, hasRef := values["m2mRef"]
+			if !hasRef || m2mTable == "" || m2mTable == "true" {
+				return nil, fmt.Errorf("invalid m2m definition for field '%s' in entity '%s'", key, entityType.String())
+			}
+			manyToMany[key] = &manyToManyDefinition{table: m2mTable, refType: m2mRef}
+		}
 		dirtyValues, has := values["dirty"]
 		if has {
 			for _, v := range strings.Split(dirtyValues, ",") {
@@ -412,6 +534,7 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	uniqueIndicesSimpleGlobal := make(map[string][]string)
 	indices := make(map[string]map[int]string)
 	skipLogs := make([]string, 0)
+	sets := make(map[string]Enum)
 	uniqueGlobal, has := tags["ORM"]["unique"]
 	if has {
 		parts := strings.Split(uniqueGlobal, "|")
@@ -466,6 +589,13 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 		if has {
 			skipLogs = append(skipLogs, k)
 		}
+		setCode, has := v["set"]
+		if has {
+			enum, has := registry.enums[setCode]
+			if has {
+				sets[k] = enum
+			}
+		}
 	}
 	for _, ref := range oneRefs {
 		has := false
@@ -490,6 +620,15 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	redisSearchIndex := &RedisSearchIndex{}
 	fields := buildTableFields(entityType, registry, redisSearchIndex, mapBindToRedisSearch, mapBindToScanPointer,
 		mapPointerToValue, 1, "", tags)
+	searchComputed := registry.redisSearchComputed[entityType.String()]
+	searchPredicate := registry.redisSearchPredicates[entityType.String()]
+	searchReferences := registry.redisSearchReferences[entityType.String()]
+	for _, computed := range searchComputed {
+		redisSearchIndex.Fields = append(redisSearchIndex.Fields, computed.field)
+	}
+	for _, reference := range searchReferences {
+		redisSearchIndex.Fields = append(redisSearchIndex.Fields, reference.field)
+	}
 	searchPrefix := ""
 	if len(redisSearchIndex.Fields) > 0 {
 		redisSearchIndex.Name = entityType.String()
@@ -512,6 +651,10 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 		}
 		indexQuery += " ORDER BY `ID` LIMIT 5000"
 		redisSearchIndex.Indexer = func(engine *Engine, lastID uint64, pusher RedisSearchIndexPusher) (newID uint64, hasMore bool) {
+			schema := getTableSchema(engine.registry, entityType)
+			if lastID == 0 {
+				schema.clearSearchSuggestions(engine)
+			}
 			results, def := engine.GetMysql(mysql).Query(indexQuery, lastID)
 			defer def()
 			total := 0
@@ -521,17 +664,52 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 			for i, column := range indexColumns {
 				pointers[i+1] = mapBindToScanPointer[column]()
 			}
+			ids := make([]uint64, 0)
+			documents := make([][]interface{}, 0)
 			for results.Next() {
 				results.Scan(pointers...)
 				lastID = *pointers[0].(*uint64)
-				pusher.NewDocument(redisSearchIndex.Prefixes[0] + strconv.FormatUint(lastID, 10))
+				document := make([]interface{}, len(indexColumns))
 				for i, column := range indexColumns {
-					val := mapPointerToValue[column](pointers[i+1])
-					pusher.setField(column, mapBindToRedisSearch[column](val))
+					document[i] = mapBindToRedisSearch[column](mapPointerToValue[column](pointers[i+1]))
 				}
-				pusher.PushDocument()
+				ids = append(ids, lastID)
+				documents = append(documents, document)
 				total++
 			}
+			var entities map[uint64]Entity
+			var references map[string]map[uint64]Entity
+			if (len(searchComputed) > 0 || len(searchReferences) > 0 || searchPredicate != nil || len(schema.searchSuggest) > 0) && len(ids) > 0 {
+				entities = loadRedisSearchEntities(engine, entityType, ids)
+				if len(searchReferences) > 0 {
+					references = loadRedisSearchReferences(engine, searchReferences, entities)
+				}
+			}
+			suggestions := &redisFlusher{engine: engine}
+			for k, id := range ids {
+				entity := entities[id]
+				if entity != nil && len(schema.searchSuggest) > 0 {
+					schema.pushSearchSuggestions(suggestions, id, entity.getORM().dBData)
+				}
+				if searchPredicate != nil && (entity == nil || !searchPredicate(entity)) {
+					continue
+				}
+				pusher.NewDocument(redisSearchIndex.Prefixes[0] + strconv.FormatUint(id, 10))
+				for i, column := range indexColumns {
+					pusher.setField(column, documents[k][i])
+				}
+				if entity != nil {
+					for _, computed := range searchComputed {
+						pusher.setField(computed.field.Name, computed.value(entity))
+					}
+					for _, reference := range searchReferences {
+						referenced := references[reference.reference][reference.getReferencedID(entity)]
+						pusher.setField(reference.field.Name, reference.value(referenced))
+					}
+				}
+				pusher.PushDocument()
+			}
+			suggestions.Flush()
 			return lastID, total == 5000
 		}
 	} else {
@@ -540,10 +718,33 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	columns := fields.getColumnNames()
 	columnMapping := make(map[string]int)
 	for i, name := range columns {
+		_, has := columnMapping[name]
+		if has {
+			return nil, fmt.Errorf("duplicated column '%s' in entity '%s'", name, entityType.String())
+		}
 		columnMapping[name] = i
 	}
+	version := registry.mysqlPools[mysql].GetVersion()
+	spatials := make(map[string]string)
+	spatialColumns := fields.getColumnsOf(func(fields *tableFields) []int {
+		return fields.spatials
+	})
 	fieldsQuery := ""
 	for _, column := range columns {
+		if spatialColumns[column] {
+			srid, has := tags[column]["srid"]
+			if !has {
+				srid = "0"
+			}
+			_, err := strconv.ParseUint(srid, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid srid '%s' for field '%s' in entity '%s'", srid, column, entityType.String())
+			}
+			read, write := getSpatialSQL(version, column, srid)
+			spatials[column] = write
+			fieldsQuery += "," + read
+			continue
+		}
 		fieldsQuery += ",`" + column + "`"
 	}
 	cachePrefix = fmt.Sprintf("%x", sha256.Sum256([]byte(cachePrefix+fieldsQuery)))
@@ -551,6 +752,50 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 	if redisSearchIndex == nil {
 		redisSearch = ""
 	}
+	decimals := fields.getColumnsOf(func(fields *tableFields) []int {
+		return fields.decimals
+	})
+	largeColumns := fields.getColumnsOf(func(fields *tableFields) []int {
+		large := append(append([]int{}, fields.bytes...), fields.jsons...)
+		for _, i := range fields.strings {
+			if tags[fields.prefix+fields.fields[i].Name]["length"] == "max" {
+				large = append(large, i)
+			}
+		}
+		for k, i := range fields.customs {
+			sqlType := strings.ToLower(fields.customTypes[k].SQLType)
+			if strings.Contains(sqlType, "blob") || strings.Contains(sqlType, "text") || strings.HasPrefix(sqlType, "json") {
+				large = append(large, i)
+			}
+		}
+		return large
+	})
+	timeZones := make(map[string]*time.Location)
+	timeColumns := fields.getColumnsOf(func(fields *tableFields) []int {
+		return append(append([]int{}, fields.times...), fields.timesNullable...)
+	})
+	for column := range timeColumns {
+		location := registry.timeZone
+		zone, has := tags[column]["timezone"]
+		if has {
+			userLocation, err := time.LoadLocation(zone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone '%s' for field '%s' in entity '%s'", zone, column, entityType.String())
+			}
+			location = userLocation
+		}
+		if location != nil {
+			timeZones[column] = location
+			_, hasSearch := mapBindToRedisSearch[column]
+			if hasSearch {
+				mapBindToRedisSearch[column] = getRedisSearchMapperTimeZone(location)
+			}
+		}
+	}
+	idGenerator := registry.idGenerators[entityType.String()]
+	if _, isSnowflake := idGenerator.(*snowflakeIDGenerator); isSnowflake && entityType.Field(1).Type.Kind() != reflect.Uint64 {
+		return nil, fmt.Errorf("snowflake ID generator in entity '%s' requires bigint unsigned ID", entityType.String())
+	}
 	tableSchema := &tableSchema{tableName: table,
 		mysqlPoolName:        mysql,
 		t:                    entityType,
@@ -570,18 +815,65 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 		hasLocalCache:        localCache != "",
 		redisCacheName:       redisCache,
 		hasRedisCache:        redisCache != "",
+		redisCacheTTL:        redisCacheTTL,
 		searchCacheName:      redisSearch,
 		hasSearchCache:       redisSearchIndex != nil,
 		refOne:               oneRefs,
 		refMany:              manyRefs,
+		manyToMany:           manyToMany,
 		cachePrefix:          cachePrefix,
+		cacheFormat:          getCacheFormat(fields),
+		hasCacheVersion:      hasCacheVersion,
+		counterPool:          counterPool,
+		renamedTable:         tags["ORM"]["renamedFrom"],
+		charset:              tags["ORM"]["charset"],
+		collation:            tags["ORM"]["collation"],
+		uniqueCheck:          uniqueCheck,
+		cacheMode:            cacheMode,
+		cacheDelay:           cacheDelay,
 		uniqueIndices:        uniqueIndicesSimple,
 		uniqueIndicesGlobal:  uniqueIndicesSimpleGlobal,
 		hasFakeDelete:        hasFakeDelete,
+		rowPolicy:            registry.rowPolicies[entityType.String()],
+		idGenerator:          idGenerator,
 		hasLog:               logPoolName != "",
 		logPoolName:          logPoolName,
 		logTableName:         fmt.Sprintf("_log_%s_%s", mysql, table),
-		skipLogs:             skipLogs}
+		skipLogs:             skipLogs,
+		sets:                 sets,
+		spatials:             spatials,
+		decimals:             decimals,
+		largeColumns:         largeColumns,
+		timeZones:            timeZones}
+	tableSchema.renamedColumns, err = initRenames(tags, columnMapping, entityType)
+	if err != nil {
+		return nil, err
+	}
+	tableSchema.validators, err = initValidators(tags, columnMapping, entityType)
+	if err != nil {
+		return nil, err
+	}
+	tableSchema.virtualFields, err = initVirtualFields(registry, tags, entityType)
+	if err != nil {
+		return nil, err
+	}
+	tableSchema.searchSuggest, err = initSearchSuggest(tags, entityType, redisSearchIndex != nil)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRedisSearchComputedFields(searchComputed, columnMapping, entityType)
+	if err != nil {
+		return nil, err
+	}
+	err = checkRedisSearchReferenceFields(searchReferences, columnMapping, entityType)
+	if err != nil {
+		return nil, err
+	}
+	if redisSearchIndex != nil {
+		tableSchema.redisSearchComputed = searchComputed
+		tableSchema.redisSearchPredicate = searchPredicate
+		tableSchema.redisSearchReferences = searchReferences
+	}
 
 	all := make(map[string]map[int]string)
 	for k, v := range uniqueIndices {
@@ -673,6 +965,14 @@ func initTableSchema(registry *Registry, entityType reflect.Type) (*tableSchema,
 			return nil, fmt.Errorf("missing index for cached query '%s' in %s", k, entityType.String())
 		}
 	}
+	err = initFieldPermissions(registry, tableSchema)
+	if err != nil {
+		return nil, err
+	}
+	err = initGeneratedEntity(tableSchema)
+	if err != nil {
+		return nil, err
+	}
 	return tableSchema, nil
 }
 
@@ -682,19 +982,30 @@ func buildTableFields(t reflect.Type, registry *Registry, index *RedisSearchInde
 	fields := &tableFields{t: t, prefix: prefix, uintegers: make([]int, 0), uintegersNullable: make([]int, 0),
 		integers: make([]int, 0), integersNullable: make([]int, 0), strings: make([]int, 0), fields: make(map[int]reflect.StructField),
 		sliceStrings: make([]int, 0), bytes: make([]int, 0), booleans: make([]int, 0), booleansNullable: make([]int, 0), floats: make([]int, 0),
-		timesNullable: make([]int, 0), times: make([]int, 0), jsons: make([]int, 0), structs: make(map[int]*tableFields),
+		timesNullable: make([]int, 0), times: make([]int, 0), jsons: make([]int, 0), spatials: make([]int, 0), decimals: make([]int, 0), structs: make(map[int]*tableFields),
 		floatsNullable: make([]int, 0), refs: make([]int, 0), refsTypes: make([]reflect.Type, 0), refsMany: make([]int, 0), refsManyTypes: make([]reflect.Type, 0)}
 	for i := start; i < t.NumField(); i++ {
 		f := t.Field(i)
 		fields.fields[i] = f
-		tags := schemaTags[f.Name]
+		tags := schemaTags[prefix+f.Name]
 		typeName := f.Type.String()
+		if isStringSlice(f.Type) {
+			typeName = "[]string"
+		}
 		_, has := tags["ignore"]
+		if has || isVirtualField(tags) {
+			continue
+		}
+		_, has = tags["m2m"]
 		if has {
 			continue
 		}
 		_, hasSearchable := tags["searchable"]
 		_, hasSortable := tags["sortable"]
+		fieldType, isCustom := registry.getFieldType(f.Type)
+		if isCustom {
+			typeName = customFieldTypeName
+		}
 		switch typeName {
 		case "uint",
 			"uint8",
@@ -831,11 +1142,21 @@ func buildTableFields(t reflect.Type, registry *Registry, index *RedisSearchInde
 			}
 			mapBindToScanPointer[prefix+f.Name] = scanStringPointer
 			mapPointerToValue[prefix+f.Name] = pointerStringScan
+		case "orm.Point",
+			"*orm.Point",
+			"orm.Polygon":
+			fields.spatials = append(fields.spatials, i)
+		case "orm.Decimal",
+			"*orm.Decimal":
+			fields.decimals = append(fields.decimals, i)
+		case customFieldTypeName:
+			fields.customs = append(fields.customs, i)
+			fields.customTypes = append(fields.customTypes, fieldType)
 		default:
 			k := f.Type.Kind().String()
 			if k == "struct" {
 				fields.structs[i] = buildTableFields(f.Type, registry, index, mapBindToRedisSearch,
-					mapBindToScanPointer, mapPointerToValue, 0, f.Name, schemaTags)
+					mapBindToScanPointer, mapPointerToValue, 0, getStructPrefix(registry, f), schemaTags)
 			} else if k == "ptr" {
 				modelType := reflect.TypeOf((*Entity)(nil)).Elem()
 				if f.Type.Implements(modelType) {
@@ -848,6 +1169,17 @@ func buildTableFields(t reflect.Type, registry *Registry, index *RedisSearchInde
 					mapBindToScanPointer[prefix+f.Name] = scanIntNullablePointer
 					mapPointerToValue[prefix+f.Name] = pointerUintNullableScan
 				}
+			} else if isScalarArray(f) {
+				fields.arrays = append(fields.arrays, i)
+				for k := 0; k < f.Type.Len(); k++ {
+					elementTags := make(map[string]string, len(tags))
+					for key, value := range tags {
+						if key != "index" && key != "unique" && key != "fulltext" && key != "columns" {
+							elementTags[key] = value
+						}
+					}
+					schemaTags[getArrayColumnName(prefix+f.Name, k)] = elementTags
+				}
 			} else {
 				if typeName[0:3] == "[]*" {
 					modelType := reflect.TypeOf((*Entity)(nil)).Elem()
@@ -873,7 +1205,7 @@ func extractTags(registry *Registry, entityType reflect.Type, prefix string) (fi
 			fields[prefix+k] = v
 		}
 		_, hasIgnore := fields[field.Name]["ignore"]
-		if hasIgnore {
+		if hasIgnore || isVirtualField(fields[field.Name]) {
 			continue
 		}
 		refOne := ""
@@ -901,6 +1233,14 @@ func extractTags(registry *Registry, entityType reflect.Type, prefix string) (fi
 				fields[field.Name] = make(map[string]string)
 			}
 			fields[field.Name]["query"] = query
+			maxRows, hasMax := field.Tag.Lookup("max")
+			if hasMax {
+				fields[field.Name]["max"] = maxRows
+			}
+			sorted, hasSorted := field.Tag.Lookup("sorted")
+			if hasSorted {
+				fields[field.Name]["sorted"] = sorted
+			}
 		}
 		if hasQueryOne {
 			if fields[field.Name] == nil {
@@ -918,38 +1258,65 @@ func extractTags(registry *Registry, entityType reflect.Type, prefix string) (fi
 			if fields[field.Name] == nil {
 				fields[field.Name] = make(map[string]string)
 			}
-			fields[field.Name]["refs"] = refMany
+			_, isManyToMany := fields[field.Name]["m2m"]
+			if isManyToMany {
+				fields[field.Name]["m2mRef"] = refMany
+			} else {
+				fields[field.Name]["refs"] = refMany
+			}
 		}
 	}
 	return
 }
 
 func extractTag(registry *Registry, field reflect.StructField) map[string]map[string]string {
+	fields := make(map[string]map[string]string)
+	if field.Type.Kind().String() == "struct" {
+		t := field.Type.String()
+		if t != "orm.ORM" && t != "time.Time" && t != "orm.Point" && t != "orm.Decimal" {
+			fields = extractTags(registry, field.Type, getStructPrefix(registry, field))
+		}
+	}
 	tag, ok := field.Tag.Lookup("orm")
 	if ok {
 		args := strings.Split(tag, ";")
 		length := len(args)
 		var attributes = make(map[string]string, length)
 		for j := 0; j < length; j++ {
-			arg := strings.Split(args[j], "=")
+			arg := strings.SplitN(args[j], "=", 2)
 			if len(arg) == 1 {
 				attributes[arg[0]] = "true"
 			} else {
 				attributes[arg[0]] = arg[1]
 			}
 		}
-		return map[string]map[string]string{field.Name: attributes}
-	} else if field.Type.Kind().String() == "struct" {
-		t := field.Type.String()
-		if t != "orm.ORM" && t != "time.Time" {
-			return extractTags(registry, field.Type, field.Name)
+		fields[field.Name] = attributes
+	}
+	return fields
+}
+
+func isStringSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String
+}
+
+func getStructPrefix(registry *Registry, field reflect.StructField) string {
+	tag, has := field.Tag.Lookup("orm")
+	if has {
+		for _, arg := range strings.Split(tag, ";") {
+			if strings.HasPrefix(arg, "prefix=") {
+				return arg[7:]
+			}
 		}
 	}
-	return make(map[string]map[string]string)
+	prefix, has := registry.embeddedPrefixes[field.Type.String()]
+	if has {
+		return prefix
+	}
+	return field.Name
 }
 
 func (tableSchema *tableSchema) getCacheKey(id uint64) string {
-	return tableSchema.cachePrefix + ":" + strconv.FormatUint(id, 10)
+	return tableSchema.getCachePrefix() + ":" + strconv.FormatUint(id, 10)
 }
 
 func (tableSchema *tableSchema) newEntity() Entity {
@@ -983,18 +1350,72 @@ func (fields *tableFields) getColumnNames() []string {
 	ids = append(ids, fields.timesNullable...)
 	ids = append(ids, fields.times...)
 	ids = append(ids, fields.jsons...)
+	ids = append(ids, fields.spatials...)
+	ids = append(ids, fields.decimals...)
+	ids = append(ids, fields.customs...)
 	ids = append(ids, fields.refs...)
 	ids = append(ids, fields.refsMany...)
 	for _, i := range ids {
 		name := fields.prefix + fields.fields[i].Name
 		columns = append(columns, name)
 	}
+	for _, i := range fields.arrays {
+		for k := 0; k < fields.fields[i].Type.Len(); k++ {
+			columns = append(columns, getArrayColumnName(fields.prefix+fields.fields[i].Name, k))
+		}
+	}
 	for _, subFields := range fields.structs {
 		columns = append(columns, subFields.getColumnNames()...)
 	}
 	return columns
 }
 
+func (fields *tableFields) getColumnsOf(list func(fields *tableFields) []int) map[string]bool {
+	columns := make(map[string]bool)
+	for _, i := range list(fields) {
+		columns[fields.prefix+fields.fields[i].Name] = true
+	}
+	for _, subFields := range fields.structs {
+		for column := range subFields.getColumnsOf(list) {
+			columns[column] = true
+		}
+	}
+	return columns
+}
+
+func (tableSchema *tableSchema) convertTimeForBind(column string, value time.Time) time.Time {
+	location, has := tableSchema.timeZones[column]
+	if !has || value.Year() == 1 {
+		return value
+	}
+	if tableSchema.tags[column]["time"] == "true" {
+		return value.UTC()
+	}
+	return value.In(location)
+}
+
+func (tableSchema *tableSchema) parseTime(column string, layout string, value string) time.Time {
+	location, has := tableSchema.timeZones[column]
+	if !has {
+		t, _ := time.ParseInLocation(layout, value, time.Local)
+		return t
+	}
+	if len(layout) > 10 {
+		t, _ := time.ParseInLocation(layout, value, time.UTC)
+		return t.In(location)
+	}
+	t, _ := time.ParseInLocation(layout, value, location)
+	return t
+}
+
+func (tableSchema *tableSchema) getBindPlaceholder(column string) string {
+	placeholder, has := tableSchema.spatials[column]
+	if has {
+		return placeholder
+	}
+	return "?"
+}
+
 var defaultRedisSearchMapper = func(val interface{}) interface{} {
 	return val
 }
@@ -1023,6 +1444,24 @@ var defaultRedisSearchMapperNullableBool = func(val interface{}) interface{} {
 	return "false"
 }
 
+func getRedisSearchMapperTimeZone(location *time.Location) func(val interface{}) interface{} {
+	return func(val interface{}) interface{} {
+		if val == nil {
+			return RedisSearchNullNumber
+		}
+		v := val.(string)
+		if v[0:10] == "0001-01-01" {
+			return 0
+		}
+		if len(v) == 19 {
+			t, _ := time.ParseInLocation("2006-01-02 15:04:05", v, time.UTC)
+			return t.Unix()
+		}
+		t, _ := time.ParseInLocation("2006-01-02", v, location)
+		return t.Unix()
+	}
+}
+
 var defaultRedisSearchMapperNullableTime = func(val interface{}) interface{} {
 	if val == nil {
 		return RedisSearchNullNumber
@@ -0,0 +1,183 @@
+package orm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var tenantNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+type tenantRegistries struct {
+	mutex      sync.Mutex
+	registries map[string]*validatedRegistry
+}
+
+func (r *Registry) SetTenantDatabasePrefix(prefix string, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if _, has := r.mysqlPools[dbCode]; !has {
+		panic(fmt.Errorf("mysql pool '%s' is not registered", dbCode))
+	}
+	if r.tenantPrefixes == nil {
+		r.tenantPrefixes = make(map[string]string)
+	}
+	r.tenantPrefixes[dbCode] = prefix
+}
+
+func (e *Engine) WithTenant(tenant string) *Engine {
+	clone := e.Clone()
+	clone.registry = e.registry.getTenantRegistry(tenant)
+	clone.tenant = tenant
+	return clone
+}
+
+func (e *Engine) GetTenant() string {
+	return e.tenant
+}
+
+func (e *Engine) withEventTenant(tenant interface{}) *Engine {
+	name, _ := tenant.(string)
+	if name == e.tenant {
+		return e
+	}
+	return e.WithTenant(name)
+}
+
+func (r *validatedRegistry) getTenantRegistry(tenant string) *validatedRegistry {
+	root := r
+	if r.tenantRoot != nil {
+		root = r.tenantRoot
+	}
+	if tenant == "" {
+		return root
+	}
+	if !tenantNameRegexp.MatchString(tenant) {
+		panic(fmt.Errorf("invalid tenant name '%s'", tenant))
+	}
+	if len(root.registry.tenantPrefixes) == 0 {
+		panic(fmt.Errorf("tenant database prefix is not registered"))
+	}
+	root.tenants.mutex.Lock()
+	defer root.tenants.mutex.Unlock()
+	registry, has := root.tenants.registries[tenant]
+	if has {
+		return registry
+	}
+	registry = &validatedRegistry{
+		registry:            root.registry,
+		tenantRoot:          root,
+		tenant:              tenant,
+		entities:            root.entities,
+		redisSearchIndexes:  root.redisSearchIndexes,
+		clickHouseClients:   root.clickHouseClients,
+		localCacheServers:   root.localCacheServers,
+		redisServers:        root.redisServers,
+		redisStreamGroups:   root.redisStreamGroups,
+		redisStreamPools:    root.redisStreamPools,
+		redisSearchDisabled: root.redisSearchDisabled,
+		circuitBreakers:     root.circuitBreakers,
+		elasticServers:      root.elasticServers,
+		enums:               root.enums,
+	}
+	registry.mySQLServers = make(map[string]MySQLPoolConfig, len(root.mySQLServers))
+	for code, pool := range root.mySQLServers {
+		prefix, isTenantPool := root.registry.tenantPrefixes[code]
+		if !isTenantPool {
+			registry.mySQLServers[code] = pool
+			continue
+		}
+		registry.mySQLServers[code] = newTenantPoolConfig(pool.(*mySQLPoolConfig), prefix+tenant)
+	}
+	registry.tableSchemas = make(map[reflect.Type]*tableSchema, len(root.tableSchemas))
+	for t, schema := range root.tableSchemas {
+		if _, isTenantPool := root.registry.tenantPrefixes[schema.mysqlPoolName]; !isTenantPool {
+			registry.tableSchemas[t] = schema
+			continue
+		}
+		tenantSchema := *schema
+		tenantSchema.cachePrefix = "t" + tenant + ":" + schema.cachePrefix
+		tenantSchema.tenant = tenant
+		registry.tableSchemas[t] = &tenantSchema
+	}
+	if root.tenants.registries == nil {
+		root.tenants.registries = make(map[string]*validatedRegistry)
+	}
+	root.tenants.registries[tenant] = registry
+	return registry
+}
+
+func newTenantPoolConfig(pool *mySQLPoolConfig, database string) *mySQLPoolConfig {
+	dataSourceName := pool.dataSourceName
+	pos := strings.LastIndex(dataSourceName, "/"+pool.databaseName)
+	dataSourceName = dataSourceName[0:pos+1] + database + dataSourceName[pos+1+len(pool.databaseName):]
+	var db *sql.DB
+	var err error
+	if pool.credentials != nil {
+		db, err = openWithCredentials(pool.driverName, dataSourceName, pool.credentials)
+	} else {
+		db, err = sql.Open(pool.driverName, dataSourceName)
+	}
+	checkError(err)
+	maxConnections := pool.client.Stats().MaxOpenConnections
+	db.SetMaxOpenConns(maxConnections)
+	db.SetMaxIdleConns(maxConnections)
+	db.SetConnMaxLifetime(3 * time.Minute)
+	return &mySQLPoolConfig{
+		dataSourceName: dataSourceName,
+		driverName:     pool.driverName,
+		code:           pool.code,
+		databaseName:   database,
+		client:         db,
+		autoincrement:  pool.autoincrement,
+		version:        pool.version,
+		maxConnections: pool.maxConnections,
+		credentials:    pool.credentials,
+	}
+}
+
+func (r *validatedRegistry) CloseTenant(tenant string) error {
+	root := r
+	if r.tenantRoot != nil {
+		root = r.tenantRoot
+	}
+	root.tenants.mutex.Lock()
+	defer root.tenants.mutex.Unlock()
+	registry, has := root.tenants.registries[tenant]
+	if !has {
+		return nil
+	}
+	delete(root.tenants.registries, tenant)
+	return root.closeTenantPools(registry)
+}
+
+func (r *validatedRegistry) closeTenants() error {
+	r.tenants.mutex.Lock()
+	defer r.tenants.mutex.Unlock()
+	var firstErr error
+	for _, registry := range r.tenants.registries {
+		if err := r.closeTenantPools(registry); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	r.tenants.registries = nil
+	return firstErr
+}
+
+func (r *validatedRegistry) closeTenantPools(registry *validatedRegistry) error {
+	var firstErr error
+	for code, pool := range registry.mySQLServers {
+		if _, isTenantPool := r.registry.tenantPrefixes[code]; isTenantPool {
+			if err := pool.getClient().Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
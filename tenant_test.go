@@ -0,0 +1,86 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantEntity struct {
+	ORM  `orm:"redisCache;localCache"`
+	ID   uint
+	Name string
+}
+
+func TestTenantDatabase(t *testing.T) {
+	var entity *tenantEntity
+	registry := &Registry{}
+	engine := PrepareTables(t, registry, 5, entity)
+	assert.PanicsWithError(t, "tenant database prefix is not registered", func() {
+		engine.WithTenant("1")
+	})
+	registry.SetTenantDatabasePrefix("test_tenant_")
+	assert.PanicsWithError(t, "invalid tenant name '1`; DROP'", func() {
+		engine.WithTenant("1`; DROP")
+	})
+	engine.GetMysql().Exec("DROP DATABASE IF EXISTS `test_tenant_1`")
+	engine.GetMysql().Exec("CREATE DATABASE `test_tenant_1`")
+
+	tenant := engine.WithTenant("1")
+	assert.Equal(t, "1", tenant.GetTenant())
+	assert.Equal(t, "", engine.GetTenant())
+	assert.Equal(t, "test_tenant_1", tenant.GetMysql().GetPoolConfig().GetDatabase())
+	assert.Equal(t, "test_log", tenant.GetMysql("log").GetPoolConfig().GetDatabase())
+	for _, alter := range tenant.GetAlters() {
+		alter.Exec()
+	}
+	assert.Same(t, tenant.GetRegistry(), engine.WithTenant("1").GetRegistry())
+	assert.Same(t, engine.GetRegistry(), tenant.WithTenant("").GetRegistry())
+
+	engine.Flush(&tenantEntity{Name: "main"})
+	tenant.Flush(&tenantEntity{Name: "tenant"})
+	entity = &tenantEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "main", entity.Name)
+	entity = &tenantEntity{}
+	assert.True(t, tenant.LoadByID(1, entity))
+	assert.Equal(t, "tenant", entity.Name)
+	entity = &tenantEntity{}
+	assert.True(t, tenant.Clone().LoadByID(1, entity))
+	assert.Equal(t, "tenant", entity.Name)
+	var name string
+	tenant.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `test_tenant_1`.`tenantEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "tenant", name)
+
+	entity = &tenantEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	entity.Name = "tenant updated"
+	tenant.Flush(entity)
+	entity = &tenantEntity{}
+	assert.True(t, tenant.LoadByID(1, entity))
+	assert.Equal(t, "tenant updated", entity.Name)
+	entity = &tenantEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "main", entity.Name)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableLoop()
+	receiver.blockTime = time.Millisecond
+	tenant.FlushLazy(&tenantEntity{Name: "lazy"})
+	receiver.Digest(context.Background())
+	entity = &tenantEntity{}
+	assert.True(t, tenant.LoadByID(2, entity))
+	assert.Equal(t, "lazy", entity.Name)
+	assert.False(t, engine.LoadByID(2, &tenantEntity{}))
+	tenant.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `test_tenant_1`.`tenantEntity` WHERE `ID` = 2"), &name)
+	assert.Equal(t, "lazy", name)
+
+	tenantRegistry := tenant.GetRegistry()
+	assert.NoError(t, engine.GetRegistry().CloseTenant("1"))
+	assert.NoError(t, engine.GetRegistry().CloseTenant("1"))
+	assert.NotSame(t, tenantRegistry, engine.WithTenant("1").GetRegistry())
+	assert.NoError(t, engine.GetRegistry().(*validatedRegistry).closeTenants())
+	engine.GetMysql().Exec("DROP DATABASE `test_tenant_1`")
+}
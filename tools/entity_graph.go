@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/latolukasz/orm"
+)
+
+type entityGraphNode struct {
+	table      string
+	columns    []orm.ColumnDefinition
+	indexes    []orm.IndexDefinition
+	references []orm.ReferenceDefinition
+}
+
+func ExportEntityGraphDOT(engine *orm.Engine) string {
+	nodes := getEntityGraphNodes(engine)
+	builder := strings.Builder{}
+	builder.WriteString("digraph orm {\n")
+	builder.WriteString("  node [shape=record];\n")
+	for _, node := range nodes {
+		columns := make([]string, len(node.columns))
+		for i, column := range node.columns {
+			columns[i] = column.Name + ": " + escapeDOT(column.SQLType) + "\\l"
+		}
+		indexes := make([]string, len(node.indexes))
+		for i, index := range node.indexes {
+			prefix := "INDEX"
+			if index.Unique {
+				prefix = "UNIQUE"
+			}
+			indexes[i] = prefix + " " + index.Name + " (" + strings.Join(index.Columns, ",") + ")\\l"
+		}
+		builder.WriteString(fmt.Sprintf("  \"%s\" [label=\"{%s|%s|%s}\"];\n", node.table, node.table,
+			strings.Join(columns, ""), strings.Join(indexes, "")))
+	}
+	for _, node := range nodes {
+		for _, reference := range node.references {
+			target := getEntityGraphTable(engine, reference.Entity)
+			label := reference.Column
+			if reference.OnDelete != "" {
+				label += " (" + reference.OnDelete + ")"
+			}
+			style := ""
+			if reference.ManyToMany {
+				style = ", dir=both, style=dashed"
+			} else if reference.Many {
+				style = ", style=dashed"
+			}
+			builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [label=\"%s\"%s];\n", node.table, target, label, style))
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func ExportEntityGraphMermaid(engine *orm.Engine) string {
+	nodes := getEntityGraphNodes(engine)
+	builder := strings.Builder{}
+	builder.WriteString("erDiagram\n")
+	for _, node := range nodes {
+		unique := make(map[string]bool)
+		for _, index := range node.indexes {
+			if index.Unique && len(index.Columns) == 1 {
+				unique[index.Columns[0]] = true
+			}
+		}
+		references := make(map[string]bool)
+		for _, reference := range node.references {
+			if !reference.Many {
+				references[reference.Column] = true
+			}
+		}
+		builder.WriteString("    " + node.table + " {\n")
+		for _, column := range node.columns {
+			sqlType := strings.Split(column.SQLType, " ")[0]
+			if pos := strings.Index(sqlType, "("); pos > 0 {
+				sqlType = sqlType[0:pos]
+			}
+			builder.WriteString("        " + sqlType + " " + column.Name)
+			keys := make([]string, 0)
+			if column.Name == "ID" {
+				keys = append(keys, "PK")
+			}
+			if references[column.Name] {
+				keys = append(keys, "FK")
+			}
+			if unique[column.Name] {
+				keys = append(keys, "UK")
+			}
+			if len(keys) > 0 {
+				builder.WriteString(" " + strings.Join(keys, ","))
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString("    }\n")
+	}
+	for _, node := range nodes {
+		for _, reference := range node.references {
+			target := getEntityGraphTable(engine, reference.Entity)
+			relation := "}o--o|"
+			if reference.ManyToMany {
+				relation = "}o--o{"
+			} else if reference.Many {
+				relation = "||--o{"
+			}
+			label := reference.Column
+			if reference.OnDelete != "" {
+				label += " " + reference.OnDelete
+			}
+			builder.WriteString(fmt.Sprintf("    %s %s %s : \"%s\"\n", node.table, relation, target, label))
+		}
+	}
+	return builder.String()
+}
+
+func getEntityGraphNodes(engine *orm.Engine) []*entityGraphNode {
+	registry := engine.GetRegistry()
+	entities := registry.ListEntities()
+	nodes := make([]*entityGraphNode, len(entities))
+	for i, name := range entities {
+		schema := registry.GetTableSchema(name)
+		nodes[i] = &entityGraphNode{
+			table:      schema.GetTableName(),
+			columns:    schema.GetColumnDefinitions(engine),
+			indexes:    schema.GetIndexDefinitions(engine),
+			references: schema.GetReferenceDefinitions(engine),
+		}
+	}
+	return nodes
+}
+
+func getEntityGraphTable(engine *orm.Engine, entity string) string {
+	schema := engine.GetRegistry().GetTableSchema(entity)
+	if schema == nil {
+		return entity
+	}
+	return schema.GetTableName()
+}
+
+func escapeDOT(value string) string {
+	replacer := strings.NewReplacer("{", "\\{", "}", "\\}", "|", "\\|", "<", "\\<", ">", "\\>", "\"", "\\\"")
+	return replacer.Replace(value)
+}
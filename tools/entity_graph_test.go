@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+type graphCategoryEntity struct {
+	orm.ORM
+	ID   uint
+	Name string `orm:"unique=Name"`
+}
+
+type graphProductEntity struct {
+	orm.ORM
+	ID       uint
+	Name     string
+	Category *graphCategoryEntity `orm:"cascade"`
+	Related  []*graphCategoryEntity
+}
+
+func TestExportEntityGraph(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&graphCategoryEntity{}, &graphProductEntity{})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+
+	dot := ExportEntityGraphDOT(engine)
+	assert.Contains(t, dot, "digraph orm {\n  node [shape=record];\n")
+	assert.Contains(t, dot, "  \"graphCategoryEntity\" [label=\"{graphCategoryEntity|ID: int(10) unsigned\\lName: varchar(255)\\l|UNIQUE Name (Name)\\l}\"];\n")
+	assert.Contains(t, dot, "  \"graphProductEntity\" -> \"graphCategoryEntity\" [label=\"Category (CASCADE)\"];\n")
+	assert.Contains(t, dot, "  \"graphProductEntity\" -> \"graphCategoryEntity\" [label=\"Related\", style=dashed];\n")
+
+	mermaid := ExportEntityGraphMermaid(engine)
+	assert.Contains(t, mermaid, "erDiagram\n")
+	assert.Contains(t, mermaid, "    graphCategoryEntity {\n        int ID PK\n        varchar Name UK\n    }\n")
+	assert.Contains(t, mermaid, "        int Category FK\n")
+	assert.Contains(t, mermaid, "    graphProductEntity }o--o| graphCategoryEntity : \"Category CASCADE\"\n")
+	assert.Contains(t, mermaid, "    graphProductEntity ||--o{ graphCategoryEntity : \"Related\"\n")
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/latolukasz/orm"
+)
+
+const queryStatsSamples = 1000
+
+var queryStatsCommentRegexp = regexp.MustCompile(`/\*.*?\*/`)
+var queryStatsListRegexp = regexp.MustCompile(`\(\?(\s*,\s*\?)*\)`)
+var queryStatsRowsRegexp = regexp.MustCompile(`\(\.\.\.\)(\s*,\s*\(\.\.\.\))+`)
+
+type QueryStats struct {
+	Pool        string
+	Fingerprint string
+	Example     string
+	Count       uint64
+	Errors      uint64
+	Rows        uint64
+	TotalTime   time.Duration
+	AvgTime     time.Duration
+	P95Time     time.Duration
+	MaxTime     time.Duration
+	samples     []time.Duration
+}
+
+type QueryStatsCollector struct {
+	mutex sync.Mutex
+	stats map[string]map[string]*QueryStats
+}
+
+func NewQueryStatsCollector() *QueryStatsCollector {
+	return &QueryStatsCollector{stats: make(map[string]map[string]*QueryStats)}
+}
+
+func (c *QueryStatsCollector) Attach(engine *orm.Engine) {
+	engine.AddQueryLogger(c, log.InfoLevel, orm.QueryLoggerSourceDB)
+}
+
+func (c *QueryStatsCollector) HandleLog(e *log.Entry) error {
+	query, isQuery := e.Fields["Query"].(string)
+	if !isQuery || e.Fields["target"] != "mysql" {
+		return nil
+	}
+	pool, _ := e.Fields["pool"].(string)
+	microseconds, _ := e.Fields["microseconds"].(int64)
+	duration := time.Duration(microseconds) * time.Microsecond
+	fingerprint := QueryFingerprint(query)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	poolStats, has := c.stats[pool]
+	if !has {
+		poolStats = make(map[string]*QueryStats)
+		c.stats[pool] = poolStats
+	}
+	stats, has := poolStats[fingerprint]
+	if !has {
+		stats = &QueryStats{Pool: pool, Fingerprint: fingerprint, Example: query}
+		poolStats[fingerprint] = stats
+	}
+	stats.Count++
+	stats.TotalTime += duration
+	if duration > stats.MaxTime {
+		stats.MaxTime = duration
+	}
+	if e.Level == log.ErrorLevel {
+		stats.Errors++
+	}
+	if rows, has := e.Fields["rows"].(int64); has && rows > 0 {
+		stats.Rows += uint64(rows)
+	}
+	if len(stats.samples) < queryStatsSamples {
+		stats.samples = append(stats.samples, duration)
+	} else {
+		stats.samples[stats.Count%queryStatsSamples] = duration
+	}
+	return nil
+}
+
+func (c *QueryStatsCollector) TopQueries(limit int) map[string][]*QueryStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result := make(map[string][]*QueryStats, len(c.stats))
+	for pool, poolStats := range c.stats {
+		list := make([]*QueryStats, 0, len(poolStats))
+		for _, stats := range poolStats {
+			row := *stats
+			row.samples = nil
+			row.AvgTime = stats.TotalTime / time.Duration(stats.Count)
+			row.P95Time = percentile(stats.samples, 0.95)
+			list = append(list, &row)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].TotalTime == list[j].TotalTime {
+				return list[i].Count > list[j].Count
+			}
+			return list[i].TotalTime > list[j].TotalTime
+		})
+		if limit > 0 && len(list) > limit {
+			list = list[0:limit]
+		}
+		result[pool] = list
+	}
+	return result
+}
+
+func (c *QueryStatsCollector) Report(limit int) string {
+	top := c.TopQueries(limit)
+	pools := make([]string, 0, len(top))
+	for pool := range top {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	builder := strings.Builder{}
+	for _, pool := range pools {
+		builder.WriteString("pool " + pool + "\n")
+		for _, stats := range top[pool] {
+			builder.WriteString(fmt.Sprintf("%8d %12s %12s %12s %8d %s\n", stats.Count, stats.TotalTime, stats.AvgTime,
+				stats.P95Time, stats.Rows, stats.Fingerprint))
+		}
+	}
+	return builder.String()
+}
+
+func (c *QueryStatsCollector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stats = make(map[string]map[string]*QueryStats)
+}
+
+func QueryFingerprint(query string) string {
+	query = queryStatsCommentRegexp.ReplaceAllString(query, "")
+	result := make([]byte, 0, len(query))
+	for i := 0; i < len(query); i++ {
+		char := query[i]
+		switch {
+		case char == '\'' || char == '"':
+			for i++; i < len(query) && query[i] != char; i++ {
+				if query[i] == '\\' {
+					i++
+				}
+			}
+			result = append(result, '?')
+		case char == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end == -1 {
+				end = len(query) - i - 2
+			}
+			result = append(result, query[i:i+end+2]...)
+			i += end + 1
+		case char >= '0' && char <= '9' && !isIdentifierEnd(result):
+			for i+1 < len(query) && (query[i+1] >= '0' && query[i+1] <= '9' || query[i+1] == '.') {
+				i++
+			}
+			result = append(result, '?')
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			if len(result) > 0 && result[len(result)-1] != ' ' {
+				result = append(result, ' ')
+			}
+		default:
+			result = append(result, char)
+		}
+	}
+	fingerprint := strings.TrimSpace(string(result))
+	fingerprint = queryStatsListRegexp.ReplaceAllString(fingerprint, "(...)")
+	return queryStatsRowsRegexp.ReplaceAllString(fingerprint, "(...)")
+}
+
+func isIdentifierEnd(value []byte) bool {
+	if len(value) == 0 {
+		return false
+	}
+	last := value[len(value)-1]
+	return last == '_' || last == '$' || (last >= 'a' && last <= 'z') || (last >= 'A' && last <= 'Z') || (last >= '0' && last <= '9')
+}
+
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	assert.Equal(t, "SELECT `ID` FROM `t1` WHERE `ID` IN (...) AND `Name` = ? LIMIT ?",
+		QueryFingerprint("/* app */ SELECT `ID`  FROM `t1`\n WHERE `ID` IN (1, 2, 3) AND `Name` = 'a\\'b' LIMIT 10"))
+	assert.Equal(t, "INSERT INTO `t1`(`Name`,`Age`) VALUES (...)",
+		QueryFingerprint("INSERT INTO `t1`(`Name`,`Age`) VALUES (?,?),(?,?),('a',12)"))
+	assert.Equal(t, "SELECT ? FROM t2", QueryFingerprint("SELECT 1.5 FROM t2"))
+}
+
+func TestQueryStatsCollector(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterRedis("localhost:6382", 11)
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	collector := NewQueryStatsCollector()
+	collector.Attach(engine)
+
+	db := engine.GetMysql()
+	db.Exec("DROP TABLE IF EXISTS `query_stats`")
+	db.Exec("CREATE TABLE `query_stats` (`ID` int NOT NULL AUTO_INCREMENT, `Name` varchar(20), PRIMARY KEY (`ID`))")
+	db.Exec("INSERT INTO `query_stats`(`Name`) VALUES ('a'),('b'),('c')")
+	for i := 0; i < 5; i++ {
+		var id int
+		db.QueryRow(orm.NewWhere("SELECT `ID` FROM `query_stats` WHERE `ID` = ?", i), &id)
+	}
+	db.Exec("UPDATE `query_stats` SET `Name` = 'd' WHERE `ID` > 1")
+	rows, def := db.Query("SELECT `ID`, `Name` FROM `query_stats` WHERE `ID` > ?", 0)
+	for rows.Next() {
+	}
+	def()
+
+	top := collector.TopQueries(0)
+	assert.Len(t, top, 1)
+	assert.Len(t, top["default"], 6)
+	var selectStats, updateStats, queryStats *QueryStats
+	for _, stats := range top["default"] {
+		assert.Equal(t, "default", stats.Pool)
+		switch stats.Fingerprint {
+		case "SELECT `ID` FROM `query_stats` WHERE `ID` = ?":
+			selectStats = stats
+		case "UPDATE `query_stats` SET `Name` = ? WHERE `ID` > ?":
+			updateStats = stats
+		case "SELECT `ID`, `Name` FROM `query_stats` WHERE `ID` > ?":
+			queryStats = stats
+		}
+	}
+	assert.NotNil(t, selectStats)
+	assert.Equal(t, uint64(5), selectStats.Count)
+	assert.Equal(t, uint64(3), selectStats.Rows)
+	assert.Greater(t, selectStats.TotalTime.Nanoseconds(), int64(0))
+	assert.Equal(t, selectStats.TotalTime/5, selectStats.AvgTime)
+	assert.LessOrEqual(t, selectStats.P95Time, selectStats.MaxTime)
+	assert.NotNil(t, updateStats)
+	assert.Equal(t, uint64(2), updateStats.Rows)
+	assert.NotNil(t, queryStats)
+	assert.Equal(t, uint64(3), queryStats.Rows)
+
+	assert.Len(t, collector.TopQueries(2)["default"], 2)
+	report := collector.Report(10)
+	assert.True(t, strings.HasPrefix(report, "pool default\n"))
+	assert.Contains(t, report, "SELECT `ID` FROM `query_stats` WHERE `ID` = ?")
+
+	collector.Reset()
+	assert.Len(t, collector.TopQueries(0), 0)
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/latolukasz/orm"
+)
+
+const moveStreamBatchSize = 1000
+
+func TrimStream(engine *orm.Engine, stream string, maxLen int64) int64 {
+	return getRedisForStream(engine, stream).XTrim(stream, maxLen, false)
+}
+
+func DeleteConsumer(engine *orm.Engine, stream, group, consumer string) int64 {
+	return getRedisForStream(engine, stream).XGroupDelConsumer(stream, group, consumer)
+}
+
+func ResetGroupToID(engine *orm.Engine, stream, group, id string) {
+	getRedisForStream(engine, stream).XGroupSetID(stream, group, id)
+}
+
+func MoveStreamToPool(engine *orm.Engine, stream, redisPool string) int64 {
+	source := getRedisForStream(engine, stream)
+	target := engine.GetRedis(redisPool)
+	if source.GetPoolConfig().GetCode() == redisPool {
+		return 0
+	}
+	copied, lastID := copyStreamMessages(source, target, stream, "-", true)
+	for _, group := range source.XInfoGroups(stream) {
+		lastDelivered := group.LastDeliveredID
+		if group.Pending > 0 {
+			lastDelivered = previousStreamID(source.XPending(stream, group.Name).Lower)
+		}
+		target.XGroupCreateMkStream(stream, group.Name, lastDelivered)
+	}
+	engine.GetRegistry().MoveRedisStream(stream, redisPool)
+	start := "-"
+	if lastID != "" {
+		start = nextStreamID(lastID)
+	}
+	late, _ := copyStreamMessages(source, target, stream, start, false)
+	return copied + late
+}
+
+func copyStreamMessages(source, target *orm.RedisCache, stream, start string, keepIDs bool) (copied int64, lastID string) {
+	for {
+		messages := source.XRange(stream, start, "+", moveStreamBatchSize)
+		for _, message := range messages {
+			id := "*"
+			if keepIDs {
+				id = message.ID
+			}
+			target.XAdd(stream, id, message.Values)
+			lastID = message.ID
+		}
+		copied += int64(len(messages))
+		if len(messages) < moveStreamBatchSize {
+			return copied, lastID
+		}
+		start = nextStreamID(lastID)
+	}
+}
+
+func getRedisForStream(engine *orm.Engine, stream string) *orm.RedisCache {
+	pool, has := engine.GetRegistry().ListStreams()[stream]
+	if !has {
+		panic(fmt.Errorf("unregistered stream %s", stream))
+	}
+	return engine.GetRedis(pool)
+}
+
+func nextStreamID(id string) string {
+	ms, seq := parseStreamID(id)
+	return strconv.FormatUint(ms, 10) + "-" + strconv.FormatUint(seq+1, 10)
+}
+
+func previousStreamID(id string) string {
+	ms, seq := parseStreamID(id)
+	if seq > 0 {
+		return strconv.FormatUint(ms, 10) + "-" + strconv.FormatUint(seq-1, 10)
+	}
+	if ms == 0 {
+		return "0-0"
+	}
+	return strconv.FormatUint(ms-1, 10) + "-18446744073709551615"
+}
+
+func parseStreamID(id string) (uint64, uint64) {
+	parts := strings.Split(id, "-")
+	ms, _ := strconv.ParseUint(parts[0], 10, 64)
+	seq := uint64(0)
+	if len(parts) > 1 {
+		seq, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+	return ms, seq
+}
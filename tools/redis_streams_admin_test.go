@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStreamsAdmin(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterRedis("localhost:6382", 11)
+	registry.RegisterRedis("localhost:6382", 10, "second")
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group"})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	r := engine.GetRedis()
+	r.FlushDB()
+	engine.GetRedis("second").FlushDB()
+
+	r.XGroupCreateMkStream("test-stream", "test-group", "0")
+	flusher := engine.GetEventBroker().NewFlusher()
+	for i := 1; i <= 20; i++ {
+		flusher.PublishMap("test-stream", orm.EventAsMap{"a": "b"})
+	}
+	flusher.Flush()
+
+	consumer := engine.GetEventBroker().Consumer("test-consumer", "test-group")
+	consumer.DisableLoop()
+	consumer.Consume(context.Background(), 5, false, func(events []orm.Event) {
+		for _, event := range events {
+			event.Skip()
+		}
+	})
+	assert.Equal(t, int64(5), DeleteConsumer(engine, "test-stream", "test-group", "test-consumer-1"))
+	assert.Equal(t, int64(0), r.XPending("test-stream", "test-group").Count)
+
+	ResetGroupToID(engine, "test-stream", "test-group", "0")
+	assert.Equal(t, "0-0", r.XInfoGroups("test-stream")[0].LastDeliveredID)
+
+	assert.Equal(t, int64(10), TrimStream(engine, "test-stream", 10))
+	assert.Equal(t, int64(10), r.XLen("test-stream"))
+
+	messages := r.XRange("test-stream", "-", "+", 10)
+	ResetGroupToID(engine, "test-stream", "test-group", messages[4].ID)
+	assert.Equal(t, int64(10), MoveStreamToPool(engine, "test-stream", "second"))
+	assert.Equal(t, "second", engine.GetRegistry().ListStreams()["test-stream"])
+	second := engine.GetRedis("second")
+	assert.Equal(t, int64(10), second.XLen("test-stream"))
+	moved := second.XRange("test-stream", "-", "+", 10)
+	assert.Equal(t, messages[0].ID, moved[0].ID)
+	assert.Equal(t, messages[9].ID, moved[9].ID)
+	groups := second.XInfoGroups("test-stream")
+	assert.Len(t, groups, 1)
+	assert.Equal(t, messages[4].ID, groups[0].LastDeliveredID)
+
+	valid := false
+	for _, stream := range GetRedisStreamsStatistics(engine) {
+		if stream.Stream == "test-stream" {
+			assert.Equal(t, "second", stream.RedisPool)
+			assert.Equal(t, uint64(10), stream.Len)
+			valid = true
+		}
+	}
+	assert.True(t, valid)
+
+	assert.PanicsWithError(t, "unregistered stream invalid", func() {
+		TrimStream(engine, "invalid", 10)
+	})
+}
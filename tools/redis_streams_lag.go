@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"time"
+
+	"github.com/latolukasz/orm"
+)
+
+type RedisStreamLagViolationType string
+
+const (
+	LagViolationPending    RedisStreamLagViolationType = "pending"
+	LagViolationPendingAge RedisStreamLagViolationType = "pending_age"
+	LagViolationLag        RedisStreamLagViolationType = "lag"
+)
+
+type RedisStreamLagThreshold struct {
+	Stream        string
+	Group         string
+	MaxPending    uint64
+	MaxPendingAge time.Duration
+	MaxLag        time.Duration
+}
+
+type RedisStreamLagViolation struct {
+	Stream    string
+	Group     string
+	RedisPool string
+	Type      RedisStreamLagViolationType
+	Value     uint64
+	Duration  time.Duration
+	Threshold *RedisStreamLagThreshold
+}
+
+func CheckRedisStreamsLag(engine *orm.Engine, thresholds ...*RedisStreamLagThreshold) []*RedisStreamLagViolation {
+	violations := make([]*RedisStreamLagViolation, 0)
+	if len(thresholds) == 0 {
+		return violations
+	}
+	now := time.Now()
+	for _, stream := range GetRedisStreamsStatistics(engine) {
+		for _, group := range stream.Groups {
+			threshold := getRedisStreamLagThreshold(thresholds, stream.Stream, group.Group)
+			if threshold == nil {
+				continue
+			}
+			violation := func(violationType RedisStreamLagViolationType) *RedisStreamLagViolation {
+				return &RedisStreamLagViolation{Stream: stream.Stream, Group: group.Group, RedisPool: stream.RedisPool,
+					Type: violationType, Threshold: threshold}
+			}
+			if threshold.MaxPending > 0 && group.Pending > threshold.MaxPending {
+				v := violation(LagViolationPending)
+				v.Value = group.Pending
+				violations = append(violations, v)
+			}
+			if threshold.MaxPendingAge > 0 && group.Pending > 0 {
+				if age := now.Sub(streamIDTime(group.Lower)); age > threshold.MaxPendingAge {
+					v := violation(LagViolationPendingAge)
+					v.Duration = age
+					violations = append(violations, v)
+				}
+			}
+			if threshold.MaxLag > 0 && stream.LastID != "" {
+				lag := streamIDTime(stream.LastID).Sub(streamIDTime(group.LastDeliveredID))
+				if lag > threshold.MaxLag {
+					v := violation(LagViolationLag)
+					v.Duration = lag
+					violations = append(violations, v)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func getRedisStreamLagThreshold(thresholds []*RedisStreamLagThreshold, stream, group string) *RedisStreamLagThreshold {
+	var best *RedisStreamLagThreshold
+	bestScore := -1
+	for _, threshold := range thresholds {
+		if (threshold.Stream != "" && threshold.Stream != stream) || (threshold.Group != "" && threshold.Group != group) {
+			continue
+		}
+		score := 0
+		if threshold.Stream != "" {
+			score += 2
+		}
+		if threshold.Group != "" {
+			score++
+		}
+		if score > bestScore {
+			best = threshold
+			bestScore = score
+		}
+	}
+	return best
+}
+
+func streamIDTime(id string) time.Time {
+	ms, _ := parseStreamID(id)
+	return time.Unix(0, int64(ms)*int64(time.Millisecond))
+}
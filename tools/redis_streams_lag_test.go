@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/latolukasz/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStreamsLag(t *testing.T) {
+	registry := &orm.Registry{}
+	registry.RegisterRedis("localhost:6382", 11)
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group", "test-group-2"})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	r := engine.GetRedis()
+	r.FlushDB()
+	r.XGroupCreateMkStream("test-stream", "test-group", "0")
+	r.XGroupCreateMkStream("test-stream", "test-group-2", "0")
+
+	threshold := &RedisStreamLagThreshold{Stream: "test-stream", MaxPending: 5, MaxPendingAge: time.Millisecond * 200, MaxLag: time.Millisecond * 200}
+	assert.Len(t, CheckRedisStreamsLag(engine, threshold), 0)
+
+	flusher := engine.GetEventBroker().NewFlusher()
+	for i := 1; i <= 10; i++ {
+		flusher.PublishMap("test-stream", orm.EventAsMap{"a": "b"})
+	}
+	flusher.Flush()
+	time.Sleep(time.Millisecond * 300)
+	flusher.PublishMap("test-stream", orm.EventAsMap{"a": "b"})
+	flusher.Flush()
+
+	consumer := engine.GetEventBroker().Consumer("test-consumer", "test-group")
+	consumer.DisableLoop()
+	consumer.Consume(context.Background(), 10, false, func(events []orm.Event) {
+		for _, event := range events {
+			event.Skip()
+		}
+	})
+
+	violations := CheckRedisStreamsLag(engine, threshold, &RedisStreamLagThreshold{Stream: "test-stream", Group: "test-group-2"})
+	assert.Len(t, violations, 3)
+	types := make(map[RedisStreamLagViolationType]*RedisStreamLagViolation)
+	for _, violation := range violations {
+		assert.Equal(t, "test-stream", violation.Stream)
+		assert.Equal(t, "test-group", violation.Group)
+		assert.Equal(t, "default", violation.RedisPool)
+		assert.Equal(t, threshold, violation.Threshold)
+		types[violation.Type] = violation
+	}
+	assert.Equal(t, uint64(10), types[LagViolationPending].Value)
+	assert.GreaterOrEqual(t, types[LagViolationPendingAge].Duration, time.Millisecond*300)
+	assert.GreaterOrEqual(t, types[LagViolationLag].Duration, time.Millisecond*300)
+
+	violations = CheckRedisStreamsLag(engine, &RedisStreamLagThreshold{MaxPending: 100, MaxPendingAge: time.Hour})
+	assert.Len(t, violations, 0)
+}
@@ -12,6 +12,7 @@ type RedisStreamStatistics struct {
 	Stream    string
 	RedisPool string
 	Len       uint64
+	LastID    string
 	Hours     int
 	Minutes   int
 	Seconds   int
@@ -57,6 +58,9 @@ func GetRedisStreamsStatistics(engine *orm.Engine) []*RedisStreamStatistics {
 			results = append(results, stat)
 			stat.Groups = make([]*RedisStreamGroupStatistics, 0)
 			stat.Len = uint64(r.XLen(stream))
+			if last := r.XRevRange(stream, "+", "-", 1); len(last) > 0 {
+				stat.LastID = last[0].ID
+			}
 			minPending := -1
 			for _, group := range r.XInfoGroups(stream) {
 				groupStats := &RedisStreamGroupStatistics{Group: group.Name, Pending: uint64(group.Pending)}
@@ -0,0 +1,72 @@
+package orm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func checkUniqueIndices(engine *Engine, schema *tableSchema, bind Bind) {
+	names := make([]string, 0, len(schema.uniqueIndices))
+	for name := range schema.uniqueIndices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+OUTER:
+	for _, name := range names {
+		columns := schema.uniqueIndices[name]
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			value := bind[column]
+			if value == nil {
+				continue OUTER
+			}
+			values[i] = value
+		}
+		id := findUniqueConflict(engine, schema, columns, values)
+		if id > 0 {
+			asStrings := make([]string, len(values))
+			for i, value := range values {
+				asStrings[i] = fmt.Sprintf("%v", value)
+			}
+			value := strings.Join(asStrings, "-")
+			message := fmt.Sprintf("Duplicate entry '%s' for key '%s'", value, name)
+			panic(&DuplicatedKeyError{Message: message, Index: name, Columns: columns, Value: value, ID: id})
+		}
+	}
+}
+
+func findUniqueConflict(engine *Engine, schema *tableSchema, columns []string, values []interface{}) uint64 {
+	positions := make(map[string]int, len(columns))
+	for i, column := range columns {
+		positions[column] = i
+	}
+	for indexName, definition := range schema.cachedIndexesOne {
+		if len(definition.QueryFields) != len(columns) {
+			continue
+		}
+		arguments := make([]interface{}, len(columns))
+		matched := true
+		for i, field := range definition.QueryFields {
+			position, has := positions[field]
+			if !has {
+				matched = false
+				break
+			}
+			arguments[i] = values[position]
+		}
+		if matched {
+			_, id := cachedSearchOne(engine, schema.newEntity(), indexName, false, false, arguments, nil)
+			return id
+		}
+	}
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = "`" + column + "` = ?"
+	}
+	/* #nosec */
+	query := "SELECT `ID` FROM `" + schema.tableName + "` WHERE " + strings.Join(conditions, " AND ") + " LIMIT 1"
+	var id uint64
+	schema.GetMysql(engine).QueryRow(NewWhere(query, values...), &id)
+	return id
+}
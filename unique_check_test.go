@@ -0,0 +1,35 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type uniqueCheckEntity struct {
+	ORM       `orm:"localCache;redisCache;uniqueCheck;unique=NameAge:Name,Age"`
+	ID        uint
+	Name      string
+	Age       uint
+	Code      string       `orm:"unique=Code"`
+	IndexCode *CachedQuery `queryOne:":Code = ?"`
+}
+
+func TestUniqueCheck(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &uniqueCheckEntity{})
+	engine.FlushMany(&uniqueCheckEntity{Name: "a", Age: 1, Code: "x"}, &uniqueCheckEntity{Name: "b", Age: 1})
+
+	err := engine.NewFlusher().Track(&uniqueCheckEntity{Name: "c", Code: "x"}).FlushWithCheck()
+	assert.EqualError(t, err, "Duplicate entry 'x' for key 'Code'")
+	duplicated, is := err.(*DuplicatedKeyError)
+	assert.True(t, is)
+	assert.Equal(t, "Code", duplicated.Index)
+	assert.Equal(t, uint64(1), duplicated.ID)
+
+	err = engine.NewFlusher().Track(&uniqueCheckEntity{Name: "b", Age: 1, Code: "y"}).FlushWithCheck()
+	assert.EqualError(t, err, "Duplicate entry 'b-1' for key 'NameAge'")
+	assert.Equal(t, uint64(2), err.(*DuplicatedKeyError).ID)
+
+	engine.Flush(&uniqueCheckEntity{Name: "b", Age: 2, Code: "y"})
+	assert.Equal(t, 3, engine.Count(NewWhere("1"), &uniqueCheckEntity{}))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 type ValidatedRegistry interface {
@@ -18,21 +19,34 @@ type ValidatedRegistry interface {
 	GetRedisPools() map[string]RedisPoolConfig
 	GetRedisSearchIndices() map[string][]*RedisSearchIndex
 	GetEntities() map[string]reflect.Type
+	ListPools() map[string][]string
+	ListEntities() []string
+	ListStreams() map[string]string
+	MoveRedisStream(stream, redisPool string)
+	CloseTenant(tenant string) error
+	Close() error
 }
 
 type validatedRegistry struct {
-	registry           *Registry
-	tableSchemas       map[reflect.Type]*tableSchema
-	entities           map[string]reflect.Type
-	redisSearchIndexes map[string]map[string]*RedisSearchIndex
-	clickHouseClients  map[string]*ClickHouseConfig
-	localCacheServers  map[string]LocalCachePoolConfig
-	mySQLServers       map[string]MySQLPoolConfig
-	redisServers       map[string]RedisPoolConfig
-	redisStreamGroups  map[string]map[string]map[string]bool
-	redisStreamPools   map[string]string
-	elasticServers     map[string]*ElasticConfig
-	enums              map[string]Enum
+	registry              *Registry
+	tableSchemas          map[reflect.Type]*tableSchema
+	entities              map[string]reflect.Type
+	redisSearchIndexes    map[string]map[string]*RedisSearchIndex
+	clickHouseClients     map[string]*ClickHouseConfig
+	localCacheServers     map[string]LocalCachePoolConfig
+	mySQLServers          map[string]MySQLPoolConfig
+	redisServers          map[string]RedisPoolConfig
+	redisStreamGroups     map[string]map[string]map[string]bool
+	redisStreamPools      map[string]string
+	redisSearchDisabled   map[string]bool
+	circuitBreakers       map[string]*circuitBreaker
+	elasticServers        map[string]*ElasticConfig
+	enums                 map[string]Enum
+	tenantRoot            *validatedRegistry
+	tenants               tenantRegistries
+	tenant                string
+	searchFlights         searchFlightGroup
+	cacheVersionListeners cacheVersionListeners
 }
 
 func (r *validatedRegistry) GetSourceRegistry() *Registry {
@@ -55,6 +69,8 @@ func (r *validatedRegistry) GetRedisSearchIndices() map[string][]*RedisSearchInd
 }
 
 func (r *validatedRegistry) GetRedisStreams() map[string]map[string][]string {
+	r.registry.redisStreamsMutex.RLock()
+	defer r.registry.redisStreamsMutex.RUnlock()
 	res := make(map[string]map[string][]string)
 	for redisPool, row := range r.redisStreamGroups {
 		res[redisPool] = make(map[string][]string)
@@ -82,6 +98,102 @@ func (r *validatedRegistry) GetRedisPools() map[string]RedisPoolConfig {
 	return r.redisServers
 }
 
+func (r *validatedRegistry) ListPools() map[string][]string {
+	pools := map[string][]string{"mysql": {}, "redis": {}, "local_cache": {}, "clickhouse": {}, "elastic": {}}
+	for code := range r.mySQLServers {
+		pools["mysql"] = append(pools["mysql"], code)
+	}
+	for code := range r.redisServers {
+		pools["redis"] = append(pools["redis"], code)
+	}
+	for code := range r.localCacheServers {
+		pools["local_cache"] = append(pools["local_cache"], code)
+	}
+	for code := range r.clickHouseClients {
+		pools["clickhouse"] = append(pools["clickhouse"], code)
+	}
+	for code := range r.elasticServers {
+		pools["elastic"] = append(pools["elastic"], code)
+	}
+	for _, codes := range pools {
+		sort.Strings(codes)
+	}
+	return pools
+}
+
+func (r *validatedRegistry) ListEntities() []string {
+	entities := make([]string, 0, len(r.entities))
+	for name := range r.entities {
+		entities = append(entities, name)
+	}
+	sort.Strings(entities)
+	return entities
+}
+
+func (r *validatedRegistry) ListStreams() map[string]string {
+	r.registry.redisStreamsMutex.RLock()
+	defer r.registry.redisStreamsMutex.RUnlock()
+	streams := make(map[string]string, len(r.redisStreamPools))
+	for stream, pool := range r.redisStreamPools {
+		streams[stream] = pool
+	}
+	return streams
+}
+
+func (r *validatedRegistry) MoveRedisStream(stream, redisPool string) {
+	r.registry.redisStreamsMutex.Lock()
+	defer r.registry.redisStreamsMutex.Unlock()
+	current, has := r.redisStreamPools[stream]
+	if !has {
+		panic(fmt.Errorf("unregistered stream %s", stream))
+	}
+	if _, has = r.redisServers[redisPool]; !has {
+		panic(&PoolNotRegisteredError{Type: "redis cache", Code: redisPool})
+	}
+	if current == redisPool {
+		return
+	}
+	groups := r.redisStreamGroups[current][stream]
+	delete(r.redisStreamGroups[current], stream)
+	if r.redisStreamGroups[redisPool] == nil {
+		r.redisStreamGroups[redisPool] = make(map[string]map[string]bool)
+	}
+	r.redisStreamGroups[redisPool][stream] = groups
+	r.redisStreamPools[stream] = redisPool
+}
+
+func (r *validatedRegistry) getRedisStreamPool(stream string) (pool string, has bool) {
+	r.registry.redisStreamsMutex.RLock()
+	defer r.registry.redisStreamsMutex.RUnlock()
+	pool, has = r.redisStreamPools[stream]
+	return pool, has
+}
+
+func (r *validatedRegistry) getRedisStreamGroups(pool, stream string) map[string]bool {
+	r.registry.redisStreamsMutex.RLock()
+	defer r.registry.redisStreamsMutex.RUnlock()
+	return r.redisStreamGroups[pool][stream]
+}
+
+func (r *validatedRegistry) Close() error {
+	r.stopCacheVersionListeners()
+	firstErr := r.closeTenants()
+	for _, pool := range r.mySQLServers {
+		if client := pool.getClient(); client != nil {
+			if err := client.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	for _, pool := range r.clickHouseClients {
+		if err := pool.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	// redis and elastic clients are created in Registry and shared by every Validate call
+	return firstErr
+}
+
 func (r *validatedRegistry) CreateEngine() *Engine {
 	return &Engine{registry: r, context: context.Background()}
 }
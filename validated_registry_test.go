@@ -2,6 +2,7 @@ package orm
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,72 @@ func TestValidatedRegistry(t *testing.T) {
 		validated.GetTableSchemaForEntity(&validatedRegistryNotRegisteredEntity{})
 	})
 }
+
+func TestValidatedRegistryIntrospection(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test_log", "log")
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterLocalCache(100)
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group"})
+	registry.RegisterEntity(&validatedRegistryEntity{})
+	validated, err := registry.Validate()
+	assert.NoError(t, err)
+
+	pools := validated.ListPools()
+	assert.Equal(t, []string{"default", "log"}, pools["mysql"])
+	assert.Equal(t, []string{"default"}, pools["redis"])
+	assert.Equal(t, []string{"default"}, pools["local_cache"])
+	assert.Len(t, pools["clickhouse"], 0)
+	assert.Len(t, pools["elastic"], 0)
+	assert.Equal(t, []string{"orm.validatedRegistryEntity"}, validated.ListEntities())
+	assert.Equal(t, "default", validated.ListStreams()["test-stream"])
+
+	engine := validated.CreateEngine()
+	engine.GetRedis().Set("introspection", "ok", 10)
+	other, err := registry.Validate()
+	assert.NoError(t, err)
+	assert.NoError(t, validated.Close())
+	assert.Panics(t, func() {
+		engine.GetMysql().Exec("SELECT 1")
+	})
+	other.CreateEngine().GetMysql().Exec("SELECT 1")
+	value, has := other.CreateEngine().GetRedis().Get("introspection")
+	assert.True(t, has)
+	assert.Equal(t, "ok", value)
+	assert.NoError(t, other.Close())
+}
+
+func TestValidatedRegistryMoveRedisStream(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", 15)
+	registry.RegisterRedis("localhost:6382", 14, "second")
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group"})
+	validated, err := registry.Validate()
+	assert.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		pool := "default"
+		if i%2 == 0 {
+			pool = "second"
+		}
+		go func() {
+			defer wg.Done()
+			validated.MoveRedisStream("test-stream", pool)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Len(t, validated.ListStreams(), 2)
+			assert.Contains(t, validated.GetRedisStreams(), "default")
+		}()
+	}
+	wg.Wait()
+	validated.MoveRedisStream("test-stream", "second")
+	assert.Equal(t, "second", validated.ListStreams()["test-stream"])
+	assert.Equal(t, map[string]bool{"test-group": true}, validated.(*validatedRegistry).getRedisStreamGroups("second", "test-stream"))
+	assert.PanicsWithError(t, "unregistered stream invalid", func() {
+		validated.MoveRedisStream("invalid", "second")
+	})
+}
@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+var emailValidator = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+type fieldValidator struct {
+	rule    string
+	pattern *regexp.Regexp
+}
+
+func initValidators(tags map[string]map[string]string, columnMapping map[string]int, entityType reflect.Type) (map[string]*fieldValidator, error) {
+	var validators map[string]*fieldValidator
+	for field, values := range tags {
+		rule, hasRule := values["validate"]
+		pattern, hasPattern := values["pattern"]
+		if !hasRule && !hasPattern {
+			continue
+		}
+		if _, isColumn := columnMapping[field]; !isColumn {
+			return nil, fmt.Errorf("validator defined for not existing column '%s' in entity '%s'", field, entityType.String())
+		}
+		validator := &fieldValidator{rule: rule}
+		if hasPattern {
+			if hasRule {
+				return nil, fmt.Errorf("validate and pattern defined together for field '%s' in entity '%s'", field, entityType.String())
+			}
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' for field '%s' in entity '%s': %s", pattern, field, entityType.String(), err.Error())
+			}
+			validator.rule = "pattern"
+			validator.pattern = compiled
+		} else if rule != "email" && rule != "url" {
+			return nil, fmt.Errorf("invalid validator '%s' for field '%s' in entity '%s'", rule, field, entityType.String())
+		}
+		if validators == nil {
+			validators = make(map[string]*fieldValidator)
+		}
+		validators[field] = validator
+	}
+	return validators, nil
+}
+
+func (v *fieldValidator) isValid(value string) bool {
+	switch v.rule {
+	case "email":
+		return emailValidator.MatchString(value)
+	case "url":
+		parsed, err := url.ParseRequestURI(value)
+		return err == nil && parsed.Scheme != "" && parsed.Host != ""
+	}
+	return v.pattern.MatchString(value)
+}
+
+func checkBindValidators(schema *tableSchema, bind Bind) {
+	for column, validator := range schema.validators {
+		value, has := bind[column]
+		if !has || value == nil {
+			continue
+		}
+		asString, isString := value.(string)
+		if !isString || asString == "" || validator.isValid(asString) {
+			continue
+		}
+		err := &BindError{Entity: schema.t.String(), Field: column, Rule: validator.rule}
+		if validator.pattern != nil {
+			err.Pattern = validator.pattern.String()
+		}
+		panic(err)
+	}
+}
@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validatorsEntity struct {
+	ORM
+	ID       uint
+	Email    string  `orm:"validate=email"`
+	Website  string  `orm:"validate=url"`
+	Passport string  `orm:"pattern=^[A-Z]{2}\\d{6}$"`
+	Code     *string `orm:"pattern=^[a-z=]+$"`
+}
+
+type validatorsInvalidEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"validate=phone"`
+}
+
+type validatorsInvalidPatternEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"pattern=[a-z"`
+}
+
+func TestValidators(t *testing.T) {
+	engine := PrepareTables(t, &Registry{}, 5, &validatorsEntity{})
+
+	entity := &validatorsEntity{Email: "john@example.com", Website: "https://example.com/page", Passport: "AB123456"}
+	engine.Flush(entity)
+	assert.Equal(t, uint(1), entity.ID)
+
+	entity.Email = "john"
+	assert.PanicsWithError(t, "value of field 'Email' in entity 'orm.validatorsEntity' is not valid email", func() {
+		engine.Flush(entity)
+	})
+	entity.Email = ""
+	entity.Website = "example.com"
+	assert.PanicsWithError(t, "value of field 'Website' in entity 'orm.validatorsEntity' is not valid url", func() {
+		engine.Flush(entity)
+	})
+	entity.Website = "http://example.com"
+	entity.Passport = "A1234567"
+	err := engine.FlushE(entity)
+	assert.EqualError(t, err, "value of field 'Passport' in entity 'orm.validatorsEntity' does not match pattern '^[A-Z]{2}\\d{6}$'")
+	var bindError *BindError
+	assert.True(t, errors.As(err, &bindError))
+	assert.Equal(t, "pattern", bindError.Rule)
+	assert.Equal(t, "Passport", bindError.Field)
+
+	entity.Passport = "XY000001"
+	code := "A"
+	entity.Code = &code
+	assert.Panics(t, func() {
+		engine.Flush(entity)
+	})
+	code = "a=b"
+	engine.Flush(entity)
+	entity = &validatorsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "XY000001", entity.Passport)
+	assert.Equal(t, "a=b", *entity.Code)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&validatorsInvalidEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "invalid validator 'phone' for field 'Name' in entity 'orm.validatorsInvalidEntity'")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&validatorsInvalidPatternEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "invalid pattern '[a-z' for field 'Name' in entity 'orm.validatorsInvalidPatternEntity': error parsing regexp: missing closing ]: `[a-z`")
+}
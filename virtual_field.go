@@ -0,0 +1,78 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type VirtualFieldComputer func(entity Entity) interface{}
+
+type virtualField struct {
+	index    int
+	computer VirtualFieldComputer
+}
+
+func (r *Registry) RegisterVirtualField(entity Entity, field string, computer VirtualFieldComputer) {
+	if computer == nil {
+		panic(fmt.Errorf("computer for virtual field '%s' is nil", field))
+	}
+	if r.virtualFields == nil {
+		r.virtualFields = make(map[string]map[string]VirtualFieldComputer)
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if r.virtualFields[t.String()] == nil {
+		r.virtualFields[t.String()] = make(map[string]VirtualFieldComputer)
+	}
+	r.virtualFields[t.String()][field] = computer
+}
+
+func isVirtualField(tags map[string]string) bool {
+	_, has := tags["virtual"]
+	return has
+}
+
+func initVirtualFields(registry *Registry, tags map[string]map[string]string, entityType reflect.Type) ([]virtualField, error) {
+	computers := registry.virtualFields[entityType.String()]
+	for name := range computers {
+		field, has := entityType.FieldByName(name)
+		if !has || len(field.Index) > 1 || !isVirtualField(tags[name]) {
+			return nil, fmt.Errorf("virtual field '%s' not found in entity '%s'", name, entityType.String())
+		}
+	}
+	var fields []virtualField
+	for i := 1; i < entityType.NumField(); i++ {
+		field := entityType.Field(i)
+		if !isVirtualField(tags[field.Name]) {
+			continue
+		}
+		computer, has := computers[field.Name]
+		if !has {
+			return nil, fmt.Errorf("missing computer for virtual field '%s' in entity '%s'", field.Name, entityType.String())
+		}
+		fields = append(fields, virtualField{index: i, computer: computer})
+	}
+	return fields, nil
+}
+
+func computeVirtualFields(schema *tableSchema, entity Entity, elem reflect.Value) {
+	for _, virtual := range schema.virtualFields {
+		field := elem.Field(virtual.index)
+		value := virtual.computer(entity)
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		val := reflect.ValueOf(value)
+		if !val.Type().AssignableTo(field.Type()) {
+			if !val.Type().ConvertibleTo(field.Type()) {
+				panic(fmt.Errorf("virtual field '%s' in entity '%s' can't be set to %T", schema.t.Field(virtual.index).Name,
+					schema.t.String(), value))
+			}
+			val = val.Convert(field.Type())
+		}
+		field.Set(val)
+	}
+}
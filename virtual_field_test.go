@@ -0,0 +1,66 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type virtualFieldEntity struct {
+	ORM       `orm:"localCache"`
+	ID        uint
+	FirstName string
+	LastName  string
+	FullName  string `orm:"virtual"`
+	Length    int    `orm:"virtual"`
+}
+
+type virtualFieldMissingEntity struct {
+	ORM
+	ID       uint
+	FullName string `orm:"virtual"`
+}
+
+func TestVirtualFields(t *testing.T) {
+	var entity *virtualFieldEntity
+	registry := &Registry{}
+	registry.RegisterVirtualField(entity, "FullName", func(e Entity) interface{} {
+		entity := e.(*virtualFieldEntity)
+		return entity.FirstName + " " + entity.LastName
+	})
+	registry.RegisterVirtualField(entity, "Length", func(e Entity) interface{} {
+		return len(e.(*virtualFieldEntity).FirstName)
+	})
+	engine := PrepareTables(t, registry, 5, entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	assert.Equal(t, []string{"ID", "FirstName", "LastName"}, schema.GetColumns())
+
+	engine.Flush(&virtualFieldEntity{FirstName: "John", LastName: "Doe", FullName: "ignored"})
+	entity = &virtualFieldEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "John Doe", entity.FullName)
+	assert.Equal(t, 4, entity.Length)
+	assert.False(t, entity.IsDirty())
+
+	var rows []*virtualFieldEntity
+	engine.LoadByIDsLazy([]uint64{1}, &rows)
+	assert.Equal(t, "", rows[0].FullName)
+	rows[0].Fill(engine)
+	assert.Equal(t, "John Doe", rows[0].FullName)
+
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Equal(t, "John Doe", rows[0].FullName)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&virtualFieldMissingEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "missing computer for virtual field 'FullName' in entity 'orm.virtualFieldMissingEntity'")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&virtualFieldMissingEntity{})
+	registry.RegisterVirtualField(&virtualFieldMissingEntity{}, "Name", func(e Entity) interface{} { return nil })
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "virtual field 'Name' not found in entity 'orm.virtualFieldMissingEntity'")
+}
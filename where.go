@@ -1,15 +1,45 @@
 package orm
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"reflect"
 	"strings"
 )
 
+const whereReferencePrefix = "`@ref:"
+const whereEntityPrefix = "`@entity:"
+const whereSpatialPrefix = "`@spatial:"
+
 type Where struct {
 	query      string
 	parameters []interface{}
+	references []string
+	entities   []reflect.Type
+	spatials   []string
+	lock       LockMode
+	maxRows    int
 }
 
+type LockMode string
+
+const (
+	ForUpdate           LockMode = "FOR UPDATE"
+	ForUpdateNoWait     LockMode = "FOR UPDATE NOWAIT"
+	ForUpdateSkipLocked LockMode = "FOR UPDATE SKIP LOCKED"
+	ForShare            LockMode = "FOR SHARE"
+	ForShareNoWait      LockMode = "FOR SHARE NOWAIT"
+	ForShareSkipLocked  LockMode = "FOR SHARE SKIP LOCKED"
+)
+
+type MatchMode string
+
+const (
+	NaturalLanguageMode MatchMode = "IN NATURAL LANGUAGE MODE"
+	BooleanMode         MatchMode = "IN BOOLEAN MODE"
+	QueryExpansionMode  MatchMode = "WITH QUERY EXPANSION"
+)
+
 func (where *Where) String() string {
 	return where.query
 }
@@ -21,16 +51,26 @@ func (where *Where) GetParameters() []interface{} {
 func (where *Where) Append(query string, parameters ...interface{}) {
 	newWhere := NewWhere(query, parameters...)
 	where.query += " " + newWhere.query
-	where.parameters = append(where.parameters, newWhere.parameters...)
+	where.merge(newWhere)
+}
+
+func (where *Where) merge(condition *Where) {
+	where.parameters = append(where.parameters, condition.parameters...)
+	where.references = append(where.references, condition.references...)
+	where.entities = append(where.entities, condition.entities...)
+	where.spatials = append(where.spatials, condition.spatials...)
 }
 
 func NewWhere(query string, parameters ...interface{}) *Where {
 	finalParameters := make([]interface{}, 0, len(parameters))
 	for _, value := range parameters {
-		switch reflect.TypeOf(value).Kind().String() {
-		case "slice", "array":
+		if isWhereInParameter(value) {
 			val := reflect.ValueOf(value)
 			length := val.Len()
+			if length == 0 {
+				query = strings.Replace(query, "IN ?", "IN ("+whereEmptyIn+")", 1)
+				continue
+			}
 			in := strings.Repeat(",?", length)
 			in = strings.TrimLeft(in, ",")
 			query = strings.Replace(query, "IN ?", "IN ("+in+")", 1)
@@ -41,5 +81,296 @@ func NewWhere(query string, parameters ...interface{}) *Where {
 		}
 		finalParameters = append(finalParameters, value)
 	}
-	return &Where{query, finalParameters}
+	return &Where{query: query, parameters: finalParameters}
+}
+
+const whereEmptyIn = "SELECT NULL FROM DUAL WHERE 0"
+
+func isWhereInParameter(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch value.(type) {
+	case []byte, driver.Valuer:
+		return false
+	}
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+func NewWhereNamed(query string, parameters map[string]interface{}) *Where {
+	var builder strings.Builder
+	values := make([]interface{}, 0, len(parameters))
+	var quote byte
+	length := len(query)
+	for i := 0; i < length; i++ {
+		c := query[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			builder.WriteByte(c)
+			continue
+		}
+		if c == '\'' || c == '"' || c == '`' {
+			quote = c
+			builder.WriteByte(c)
+			continue
+		}
+		if c == ':' && i+1 < length && isWhereNamedChar(query[i+1], true) && (i == 0 || query[i-1] != ':') {
+			end := i + 1
+			for end < length && isWhereNamedChar(query[end], false) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, has := parameters[name]
+			if !has {
+				panic(fmt.Errorf("missing named parameter '%s'", name))
+			}
+			values = append(values, value)
+			builder.WriteByte('?')
+			i = end - 1
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return NewWhere(builder.String(), values...)
+}
+
+func isWhereNamedChar(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+type WhereBuilder struct{}
+
+var W = WhereBuilder{}
+
+func (w WhereBuilder) Raw(query string, parameters ...interface{}) *Where {
+	return NewWhere(query, parameters...)
+}
+
+func (w WhereBuilder) Eq(field string, value interface{}) *Where {
+	if value == nil {
+		return w.IsNull(field)
+	}
+	return NewWhere("`"+field+"` = ?", value)
+}
+
+func (w WhereBuilder) NotEq(field string, value interface{}) *Where {
+	if value == nil {
+		return w.IsNotNull(field)
+	}
+	return NewWhere("`"+field+"` != ?", value)
+}
+
+func (w WhereBuilder) Gt(field string, value interface{}) *Where {
+	return NewWhere("`"+field+"` > ?", value)
+}
+
+func (w WhereBuilder) Gte(field string, value interface{}) *Where {
+	return NewWhere("`"+field+"` >= ?", value)
+}
+
+func (w WhereBuilder) Lt(field string, value interface{}) *Where {
+	return NewWhere("`"+field+"` < ?", value)
+}
+
+func (w WhereBuilder) Lte(field string, value interface{}) *Where {
+	return NewWhere("`"+field+"` <= ?", value)
+}
+
+func (w WhereBuilder) Like(field string, value string) *Where {
+	return NewWhere("`"+field+"` LIKE ?", value)
+}
+
+func (w WhereBuilder) In(field string, values interface{}) *Where {
+	return NewWhere("`"+field+"` IN ?", values)
+}
+
+func (w WhereBuilder) NotIn(field string, values interface{}) *Where {
+	return NewWhere("`"+field+"` NOT IN ?", values)
+}
+
+func (w WhereBuilder) IsNull(field string) *Where {
+	return NewWhere("`" + field + "` IS NULL")
+}
+
+func (w WhereBuilder) IsNotNull(field string) *Where {
+	return NewWhere("`" + field + "` IS NOT NULL")
+}
+
+func (w WhereBuilder) InSet(field string, option interface{}) *Where {
+	return NewWhere("FIND_IN_SET(?, `"+field+"`) > 0", option)
+}
+
+func (w WhereBuilder) NotInSet(field string, option interface{}) *Where {
+	return NewWhere("(`"+field+"` IS NULL OR FIND_IN_SET(?, `"+field+"`) = 0)", option)
+}
+
+func (w WhereBuilder) Match(fields string, query string, mode ...MatchMode) *Where {
+	columns := strings.Split(fields, ",")
+	for i, column := range columns {
+		columns[i] = "`" + strings.TrimSpace(column) + "`"
+	}
+	matchMode := NaturalLanguageMode
+	if len(mode) > 0 {
+		matchMode = mode[0]
+	}
+	return NewWhere("MATCH("+strings.Join(columns, ",")+") AGAINST (? "+string(matchMode)+")", query)
+}
+
+func (w WhereBuilder) DistanceSphere(field string, point Point, meters float64) *Where {
+	where := NewWhere("ST_Distance_Sphere(`"+field+"`, "+whereSpatialPrefix+field+"`) <= ?", point.WKT(), meters)
+	where.spatials = []string{field}
+	return where
+}
+
+func (w WhereBuilder) Contains(field string, point Point) *Where {
+	where := NewWhere("ST_Contains(`"+field+"`, "+whereSpatialPrefix+field+"`)", point.WKT())
+	where.spatials = []string{field}
+	return where
+}
+
+func (w WhereBuilder) Within(field string, polygon Polygon) *Where {
+	where := NewWhere("ST_Within(`"+field+"`, "+whereSpatialPrefix+field+"`)", polygon.WKT())
+	where.spatials = []string{field}
+	return where
+}
+
+func (w WhereBuilder) Ref(reference string, condition *Where) *Where {
+	parts := strings.Split(reference, ".")
+	query := strings.ReplaceAll(condition.query, whereReferencePrefix, whereReferencePrefix+reference+".")
+	references := make([]string, 0, len(parts)+len(condition.references))
+	for _, path := range condition.references {
+		references = append(references, reference+"."+path)
+	}
+	for i := len(parts) - 1; i >= 0; i-- {
+		path := strings.Join(parts[0:i+1], ".")
+		query = "`" + parts[i] + "` IN (SELECT `ID` FROM " + whereReferencePrefix + path + "` WHERE " + query + ")"
+		references = append(references, path)
+	}
+	return &Where{query: query, parameters: condition.parameters, references: references, entities: condition.entities, spatials: condition.spatials}
+}
+
+func (w WhereBuilder) RefEq(field string, value interface{}) *Where {
+	reference, column := splitWhereReference(field)
+	return w.Ref(reference, w.Eq(column, value))
+}
+
+func (w WhereBuilder) RefIn(field string, values interface{}) *Where {
+	reference, column := splitWhereReference(field)
+	return w.Ref(reference, w.In(column, values))
+}
+
+func (w WhereBuilder) Exists(entity Entity, query string, parameters ...interface{}) *Where {
+	return w.exists("EXISTS", entity, query, parameters...)
+}
+
+func (w WhereBuilder) NotExists(entity Entity, query string, parameters ...interface{}) *Where {
+	return w.exists("NOT EXISTS", entity, query, parameters...)
+}
+
+func (w WhereBuilder) exists(operator string, entity Entity, query string, parameters ...interface{}) *Where {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	where := NewWhere(operator+" (SELECT 1 FROM "+whereEntityPrefix+t.String()+"` AS `"+t.Name()+"` WHERE "+query+")", parameters...)
+	where.entities = append(where.entities, t)
+	return where
+}
+
+func splitWhereReference(field string) (reference, column string) {
+	pos := strings.LastIndex(field, ".")
+	if pos <= 0 {
+		panic(fmt.Errorf("invalid reference field '%s'", field))
+	}
+	return field[0:pos], field[pos+1:]
+}
+
+func (where *Where) And(conditions ...*Where) *Where {
+	combined := where.copy()
+	combined.query = "(" + combined.query + ")"
+	for _, condition := range conditions {
+		combined.query += " AND (" + condition.query + ")"
+		combined.merge(condition)
+	}
+	return combined
+}
+
+func (where *Where) Or(conditions ...*Where) *Where {
+	combined := where.copy()
+	for _, condition := range conditions {
+		combined.query += " OR " + condition.query
+		combined.merge(condition)
+	}
+	combined.query = "(" + combined.query + ")"
+	return combined
+}
+
+func (where *Where) copy() *Where {
+	copied := *where
+	copied.parameters = append([]interface{}(nil), where.parameters...)
+	copied.references = append([]string(nil), where.references...)
+	copied.entities = append([]reflect.Type(nil), where.entities...)
+	copied.spatials = append([]string(nil), where.spatials...)
+	return &copied
+}
+
+func (where *Where) resolve(registry *validatedRegistry, schema *tableSchema) string {
+	if len(where.references) == 0 && len(where.entities) == 0 && len(where.spatials) == 0 {
+		return where.query
+	}
+	query := where.query
+	for _, field := range where.spatials {
+		placeholder, has := schema.spatials[field]
+		if !has {
+			panic(fmt.Errorf("field '%s' in entity '%s' is not spatial", field, schema.t.String()))
+		}
+		query = strings.ReplaceAll(query, whereSpatialPrefix+field+"`", placeholder)
+	}
+	for _, t := range where.entities {
+		entitySchema := getTableSchema(registry, t)
+		if entitySchema == nil {
+			panic(fmt.Errorf("entity '%s' is not registered", t.String()))
+		}
+		query = strings.ReplaceAll(query, whereEntityPrefix+t.String()+"`", "`"+entitySchema.tableName+"`")
+	}
+	for _, path := range where.references {
+		refSchema := schema
+		for _, field := range strings.Split(path, ".") {
+			refName := refSchema.tags[field]["ref"]
+			if refName == "" {
+				panic(fmt.Errorf("reference '%s' not found in entity '%s'", field, refSchema.t.String()))
+			}
+			refSchema = getTableSchema(registry, registry.entities[refName])
+		}
+		query = strings.ReplaceAll(query, whereReferencePrefix+path+"`", "`"+refSchema.tableName+"`")
+	}
+	return query
+}
+
+func (where *Where) withLock(lock LockMode) *Where {
+	locked := *where
+	locked.lock = lock
+	return &locked
+}
+
+func (where *Where) lockClause(pool *DB) string {
+	if where.lock == "" {
+		return ""
+	}
+	if !pool.inTransaction {
+		panic(fmt.Errorf("%s requires transaction in mysql pool '%s'", where.lock, pool.GetPoolConfig().GetCode()))
+	}
+	if pool.GetPoolConfig().GetVersion() == 5 && where.lock != ForUpdate {
+		if where.lock == ForShare {
+			return " LOCK IN SHARE MODE"
+		}
+		panic(fmt.Errorf("%s is not supported in MySQL 5", where.lock))
+	}
+	return " " + string(where.lock)
 }
@@ -14,3 +14,69 @@ func TestWhere(t *testing.T) {
 	assert.Equal(t, "1 AND Field = ? AND Field2 IN (?,?) AND Field3 = ? AND Field4 IN (?,?)", where.String())
 	assert.Equal(t, []interface{}{2, "a", "b", "c", "d", "e"}, where.GetParameters())
 }
+
+func TestWhereBuilder(t *testing.T) {
+	where := W.Eq("Name", "Tom").And(W.Gt("Age", 18)).Or(W.In("ID", []uint64{1, 2}))
+	assert.Equal(t, "((`Name` = ?) AND (`Age` > ?) OR `ID` IN (?,?))", where.String())
+	assert.Equal(t, []interface{}{"Tom", 18, uint64(1), uint64(2)}, where.GetParameters())
+
+	where = W.Eq("Name", nil).And(W.Lte("Age", 10).Or(W.IsNotNull("City")), W.Like("Code", "a%"))
+	assert.Equal(t, "(`Name` IS NULL) AND ((`Age` <= ? OR `City` IS NOT NULL)) AND (`Code` LIKE ?)", where.String())
+	assert.Equal(t, []interface{}{10, "a%"}, where.GetParameters())
+
+	base := W.Eq("Name", "Tom")
+	adults := base.And(W.Gt("Age", 18))
+	children := base.And(W.Lt("Age", 10))
+	either := base.Or(W.RefEq("Customer.Address.City", "Berlin"))
+	assert.Equal(t, "`Name` = ?", base.String())
+	assert.Equal(t, []interface{}{"Tom"}, base.GetParameters())
+	assert.Equal(t, "(`Name` = ? OR `Age` > ?) AND (`Code` = ?)", NewWhere("`Name` = ? OR `Age` > ?", "a", 1).And(W.Eq("Code", "b")).String())
+	assert.Len(t, base.references, 0)
+	assert.Equal(t, "(`Name` = ?) AND (`Age` > ?)", adults.String())
+	assert.Equal(t, []interface{}{"Tom", 18}, adults.GetParameters())
+	assert.Equal(t, "(`Name` = ?) AND (`Age` < ?)", children.String())
+	assert.Equal(t, []interface{}{"Tom", 10}, children.GetParameters())
+	assert.Equal(t, []interface{}{"Tom", "Berlin"}, either.GetParameters())
+	assert.Equal(t, []string{"Customer.Address", "Customer"}, either.references)
+}
+
+func TestWhereNamed(t *testing.T) {
+	where := NewWhereNamed("`Name` = :name AND `Age` > :age AND `Tag` IN :tags AND `Code` != ':skip' AND `Alias` = :name",
+		map[string]interface{}{"name": "Tom", "age": 18, "tags": []string{"a", "b"}})
+	assert.Equal(t, "`Name` = ? AND `Age` > ? AND `Tag` IN (?,?) AND `Code` != ':skip' AND `Alias` = ?", where.String())
+	assert.Equal(t, []interface{}{"Tom", 18, "a", "b", "Tom"}, where.GetParameters())
+	assert.PanicsWithError(t, "missing named parameter 'age'", func() {
+		NewWhereNamed("`Age` = :age", map[string]interface{}{})
+	})
+}
+
+type whereTestIDs []uint64
+
+func TestWhereIn(t *testing.T) {
+	where := NewWhere("`ID` IN ? AND `Code` IN ? AND `Blob` = ?", whereTestIDs{1, 2, 3}, [2]string{"a", "b"}, []byte("c"))
+	assert.Equal(t, "`ID` IN (?,?,?) AND `Code` IN (?,?) AND `Blob` = ?", where.String())
+	assert.Equal(t, []interface{}{uint64(1), uint64(2), uint64(3), "a", "b", []byte("c")}, where.GetParameters())
+
+	where = NewWhere("`ID` IN ? AND `Name` NOT IN ? AND `Age` = ?", []uint64{}, []string{}, nil)
+	assert.Equal(t, "`ID` IN (SELECT NULL FROM DUAL WHERE 0) AND `Name` NOT IN (SELECT NULL FROM DUAL WHERE 0) AND `Age` = ?", where.String())
+	assert.Equal(t, []interface{}{nil}, where.GetParameters())
+}
+
+func TestWhereReference(t *testing.T) {
+	where := W.Eq("Name", "a").And(W.RefEq("Customer.Address.City", "Berlin"))
+	assert.Equal(t, "(`Name` = ?) AND (`Customer` IN (SELECT `ID` FROM `@ref:Customer` WHERE `Address` IN "+
+		"(SELECT `ID` FROM `@ref:Customer.Address` WHERE `City` = ?)))", where.String())
+	assert.Equal(t, []interface{}{"a", "Berlin"}, where.GetParameters())
+	assert.Equal(t, []string{"Customer.Address", "Customer"}, where.references)
+	assert.PanicsWithError(t, "invalid reference field 'Name'", func() {
+		W.RefEq("Name", "a")
+	})
+}
+
+func TestWhereExists(t *testing.T) {
+	where := W.Exists(&dbEntity{}, "dbEntity.ID = parent.Child AND dbEntity.Name = ?", "a").And(W.NotExists(&dbEntity{}, "1"))
+	assert.Equal(t, "(EXISTS (SELECT 1 FROM `@entity:orm.dbEntity` AS `dbEntity` WHERE dbEntity.ID = parent.Child AND dbEntity.Name = ?)) AND "+
+		"(NOT EXISTS (SELECT 1 FROM `@entity:orm.dbEntity` AS `dbEntity` WHERE 1))", where.String())
+	assert.Equal(t, []interface{}{"a"}, where.GetParameters())
+	assert.Len(t, where.entities, 2)
+}